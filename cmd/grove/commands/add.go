@@ -1,9 +1,11 @@
 package commands
 
 import (
+	"cmp"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -11,24 +13,37 @@ import (
 	"github.com/sqve/grove/internal/fs"
 	"github.com/sqve/grove/internal/git"
 	"github.com/sqve/grove/internal/github"
+	"github.com/sqve/grove/internal/gitlab"
 	"github.com/sqve/grove/internal/hooks"
 	"github.com/sqve/grove/internal/logger"
 	"github.com/sqve/grove/internal/styles"
 	"github.com/sqve/grove/internal/workspace"
 )
 
+// addOptions holds the flags of grove add
+type addOptions struct {
+	switchTo   bool
+	useTmux    bool
+	baseBranch string
+	name       string
+	detach     bool
+	prNumber   int
+	mrNumber   int
+	reset      bool
+	from       string
+	sparse     string
+	stack      bool
+	carry      bool
+}
+
 func NewAddCmd() *cobra.Command {
-	var baseBranch string
-	var name string
-	var detach bool
-	var prNumber int
-	var reset bool
-	var from string
+	var opts addOptions
+	var allRepos bool
 
 	cmd := &cobra.Command{
-		Use:   "add [branch|PR-URL|ref]",
+		Use:   "add [branch|PR-URL|MR-URL|ref]",
 		Short: "Add a new worktree",
-		Long: `Create a worktree from a branch, pull request, or ref.
+		Long: `Create a worktree from a branch, pull request, merge request, or ref.
 
 The directory name derives from the branch name unless --name is specified.
 
@@ -37,25 +52,52 @@ Examples:
   grove add feat/auth --name auth  # Creates ./auth worktree
   grove add main                   # Existing branch
   grove add -s feat/auth           # Add and switch to worktree
+  grove add -s --tmux feat/auth    # Add and open in a tmux window
   grove add --base main feat/auth  # New branch from main
+  grove add --stack feat/auth-ui   # New branch stacked on the current branch
   grove add --detach v1.0.0        # Detached HEAD at tag
   grove add --pr 123               # Creates ./pr-123 worktree
-  grove add --from dev feat/auth   # Preserve files from dev worktree (name or branch)`,
+  grove add --mr 42                # Creates ./mr-42 worktree (GitLab)
+  grove add --from dev feat/auth   # Preserve files from dev worktree (name or branch)
+  grove add --sparse web feat/ui   # Check out only the web sparse profile
+  grove add --carry feat/auth      # Move the current changes into the worktree
+  grove add --all-repos feat/auth  # In every repo of the grove.workspace.toml project`,
 		Args:              cobra.MaximumNArgs(1),
 		ValidArgsFunction: completeAddArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			switchTo, _ := cmd.Flags().GetBool("switch")
-			return runAdd(args, switchTo, baseBranch, name, detach, prNumber, reset, from)
+			opts.switchTo, _ = cmd.Flags().GetBool("switch")
+			opts.useTmux = tmuxEnabled(cmd)
+			if opts.useTmux && cmd.Flags().Changed("tmux") && !opts.switchTo {
+				return fmt.Errorf("--tmux requires --switch")
+			}
+			if allRepos {
+				for _, flag := range []string{"name", "detach", "pr", "mr", "reset", "from", "sparse", "stack", "carry"} {
+					if cmd.Flags().Changed(flag) {
+						return fmt.Errorf("--all-repos cannot be used with --%s", flag)
+					}
+				}
+				if len(args) == 0 {
+					return fmt.Errorf("--all-repos requires a branch")
+				}
+				return runAddAllRepos(args[0], opts.baseBranch, opts.switchTo, opts.useTmux)
+			}
+			return runAdd(args, opts)
 		},
 	}
 
 	cmd.Flags().BoolP("switch", "s", false, "Switch to the worktree after creating it")
-	cmd.Flags().StringVar(&baseBranch, "base", "", "Create new branch from this base instead of HEAD")
-	cmd.Flags().StringVar(&name, "name", "", "Custom directory name for the worktree")
-	cmd.Flags().BoolVarP(&detach, "detach", "d", false, "Create worktree in detached HEAD state")
-	cmd.Flags().IntVar(&prNumber, "pr", 0, "Pull request number to checkout")
-	cmd.Flags().BoolVar(&reset, "reset", false, "Reset diverged PR branch to match remote (discards local commits)")
-	cmd.Flags().StringVar(&from, "from", "", "Source worktree for file preservation (name or branch)")
+	cmd.Flags().Bool("tmux", false, "Focus a tmux window instead of changing directory (with --switch)")
+	cmd.Flags().StringVar(&opts.baseBranch, "base", "", "Create new branch from this base instead of HEAD")
+	cmd.Flags().StringVar(&opts.name, "name", "", "Custom directory name for the worktree")
+	cmd.Flags().BoolVarP(&opts.detach, "detach", "d", false, "Create worktree in detached HEAD state")
+	cmd.Flags().IntVar(&opts.prNumber, "pr", 0, "Pull request number to checkout")
+	cmd.Flags().IntVar(&opts.mrNumber, "mr", 0, "GitLab merge request number to checkout")
+	cmd.Flags().BoolVar(&opts.reset, "reset", false, "Reset diverged PR/MR branch to match remote (discards local commits)")
+	cmd.Flags().StringVar(&opts.from, "from", "", "Source worktree for file preservation (name or branch)")
+	cmd.Flags().StringVar(&opts.sparse, "sparse", "", "Check out only the directories of a sparse profile from .grove.toml")
+	cmd.Flags().BoolVar(&opts.stack, "stack", false, "Track the new branch as stacked on its base (default: the current branch)")
+	cmd.Flags().BoolVar(&opts.carry, "carry", false, "Move the uncommitted changes of the current worktree into the new one")
+	cmd.Flags().BoolVar(&allRepos, "all-repos", false, "Add the branch in every repo of the project and group the worktrees")
 	cmd.Flags().BoolP("help", "h", false, "Help for add")
 
 	_ = cmd.RegisterFlagCompletionFunc("base", completeBaseBranch)
@@ -65,21 +107,36 @@ Examples:
 	_ = cmd.RegisterFlagCompletionFunc("pr", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		return nil, cobra.ShellCompDirectiveNoFileComp
 	})
+	_ = cmd.RegisterFlagCompletionFunc("mr", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	})
 	_ = cmd.RegisterFlagCompletionFunc("from", completeFromWorktree)
+	_ = cmd.RegisterFlagCompletionFunc("sparse", completeSparseProfiles)
 
 	return cmd
 }
 
-func runAdd(args []string, switchTo bool, baseBranch, name string, detach bool, prNumber int, reset bool, from string) error {
-	name = strings.TrimSpace(name)
+func runAdd(args []string, opts addOptions) error {
+	opts.name = strings.TrimSpace(opts.name)
+	opts.sparse = strings.TrimSpace(opts.sparse)
 
 	// Validate --pr value if provided
-	if prNumber < 0 {
+	if opts.prNumber < 0 {
 		return fmt.Errorf("--pr must be a positive number")
 	}
 
-	// Determine if --pr flag is used
-	prFlag := prNumber > 0
+	// Validate --mr value if provided
+	if opts.mrNumber < 0 {
+		return fmt.Errorf("--mr must be a positive number")
+	}
+
+	// Determine if --pr or --mr flag is used
+	prFlag := opts.prNumber > 0
+	mrFlag := opts.mrNumber > 0
+
+	if prFlag && mrFlag {
+		return fmt.Errorf("--pr and --mr cannot be used together")
+	}
 
 	// Get positional argument if provided
 	var branchOrPR string
@@ -87,15 +144,18 @@ func runAdd(args []string, switchTo bool, baseBranch, name string, detach bool,
 		branchOrPR = strings.TrimSpace(args[0])
 	}
 
-	// Validate: must provide either --pr or positional arg
-	if !prFlag && branchOrPR == "" {
-		return fmt.Errorf("requires branch, PR URL, or --pr flag")
+	// Validate: must provide either --pr, --mr or positional arg
+	if !prFlag && !mrFlag && branchOrPR == "" {
+		return fmt.Errorf("requires branch, PR/MR URL, or --pr/--mr flag")
 	}
 
-	// Validate: --pr cannot be combined with positional argument
+	// Validate: --pr/--mr cannot be combined with positional argument
 	if prFlag && branchOrPR != "" {
 		return fmt.Errorf("--pr flag cannot be combined with positional argument")
 	}
+	if mrFlag && branchOrPR != "" {
+		return fmt.Errorf("--mr flag cannot be combined with positional argument")
+	}
 
 	// Helpful error for old #N syntax
 	if strings.HasPrefix(branchOrPR, "#") {
@@ -104,26 +164,43 @@ func runAdd(args []string, switchTo bool, baseBranch, name string, detach bool,
 	}
 
 	// Validate flag combinations early (before filesystem operations)
-	if detach && baseBranch != "" {
+	if opts.detach && opts.baseBranch != "" {
 		return fmt.Errorf("--detach and --base cannot be used together")
 	}
+	if opts.detach && opts.stack {
+		return fmt.Errorf("--detach and --stack cannot be used together")
+	}
+	if opts.detach && opts.carry {
+		return fmt.Errorf("--detach and --carry cannot be used together")
+	}
 
-	// Check if positional arg is a PR URL
+	// Check if positional arg is a PR or MR URL
 	isPRURL := branchOrPR != "" && github.IsPRURL(branchOrPR)
+	isMRURL := branchOrPR != "" && gitlab.IsMRURL(branchOrPR)
+	isRequestRef := prFlag || isPRURL || mrFlag || isMRURL
 
-	// Validate PR-specific flag conflicts
-	if prFlag || isPRURL {
-		if baseBranch != "" {
-			return fmt.Errorf("--base cannot be used with PR references")
+	// Validate PR/MR-specific flag conflicts
+	if isRequestRef {
+		if opts.baseBranch != "" {
+			return fmt.Errorf("--base cannot be used with PR/MR references")
+		}
+		if opts.detach {
+			return fmt.Errorf("--detach cannot be used with PR/MR references")
+		}
+		if opts.sparse != "" {
+			return fmt.Errorf("--sparse cannot be used with PR/MR references")
 		}
-		if detach {
-			return fmt.Errorf("--detach cannot be used with PR references")
+		if opts.stack {
+			return fmt.Errorf("--stack cannot be used with PR/MR references")
+		}
+		if opts.carry {
+			return fmt.Errorf("--carry cannot be used with PR/MR references")
 		}
 	}
 
-	// --reset only makes sense with PR checkout
-	if reset && !prFlag && !isPRURL {
-		return fmt.Errorf("--reset can only be used with PR references")
+	// --reset only makes sense with PR/MR checkout
+	if opts.reset && !isRequestRef {
+		return fmt.Errorf("--reset can only be used with PR/MR references")
 	}
 
 	cwd, err := os.Getwd()
@@ -151,16 +228,16 @@ func runAdd(args []string, switchTo bool, baseBranch, name string, detach bool,
 
 	spin := logger.StartSpinner("Preparing workspace...")
 	var sourceWorktree string
-	if from != "" {
+	if opts.from != "" {
 		infos, err := git.ListWorktreesWithInfo(bareDir, true)
 		if err != nil {
 			spin.StopWithError("Failed to list worktrees")
 			return fmt.Errorf("failed to list worktrees: %w", err)
 		}
-		info := git.FindWorktree(infos, from)
+		info := git.FindWorktree(infos, opts.from)
 		if info == nil {
 			spin.StopWithError("Worktree not found")
-			return fmt.Errorf("worktree %q not found", from)
+			return fmt.Errorf("worktree %q not found", opts.from)
 		}
 		sourceWorktree = info.Path
 		logger.Debug("Using %s as source for file preservation (--from)", sourceWorktree)
@@ -181,32 +258,185 @@ func runAdd(args []string, switchTo bool, baseBranch, name string, detach bool,
 	}
 	spin.Stop()
 
+	var profile *sparseProfile
+	if opts.sparse != "" {
+		profiles, err := resolveSparseProfiles(bareDir, sourceWorktree, []string{opts.sparse})
+		if err != nil {
+			return err
+		}
+		profile = profiles[0]
+	}
+
 	// Handle PR via --pr flag
 	if prFlag {
-		prRef := fmt.Sprintf("#%d", prNumber)
-		return runAddFromPR(prRef, switchTo, name, bareDir, workspaceRoot, sourceWorktree, reset)
+		prRef := fmt.Sprintf("#%d", opts.prNumber)
+		return runAddFromPR(prRef, opts.switchTo, opts.useTmux, opts.name, bareDir, workspaceRoot, sourceWorktree, opts.reset)
 	}
 
 	// Handle PR via URL
 	if isPRURL {
-		return runAddFromPR(branchOrPR, switchTo, name, bareDir, workspaceRoot, sourceWorktree, reset)
+		return runAddFromPR(branchOrPR, opts.switchTo, opts.useTmux, opts.name, bareDir, workspaceRoot, sourceWorktree, opts.reset)
+	}
+
+	// Handle MR via --mr flag
+	if mrFlag {
+		return runAddFromMR(&gitlab.MRRef{Number: opts.mrNumber}, opts.switchTo, opts.useTmux, opts.name, bareDir, workspaceRoot, sourceWorktree, opts.reset)
+	}
+
+	// Handle MR via URL
+	if isMRURL {
+		ref, err := gitlab.ParseMRURL(branchOrPR)
+		if err != nil {
+			return err
+		}
+		return runAddFromMR(ref, opts.switchTo, opts.useTmux, opts.name, bareDir, workspaceRoot, sourceWorktree, opts.reset)
 	}
 
 	// Detached worktree
-	if detach {
-		return runAddDetached(branchOrPR, switchTo, name, bareDir, workspaceRoot, sourceWorktree)
+	if opts.detach {
+		return runAddDetached(branchOrPR, opts.switchTo, opts.useTmux, opts.name, bareDir, workspaceRoot, sourceWorktree, profile)
+	}
+
+	// Stacked branches default to the branch of the current worktree as base
+	if opts.stack && opts.baseBranch == "" {
+		worktreeRoot, err := git.FindWorktreeRoot(cwd)
+		if err != nil {
+			return fmt.Errorf("--stack requires --base outside a worktree")
+		}
+		current, err := git.GetCurrentBranch(worktreeRoot)
+		if err != nil {
+			return fmt.Errorf("--stack requires --base when the current worktree is not on a branch")
+		}
+		opts.baseBranch = current
+	}
+
+	// Record the changes to carry before the new worktree exists
+	var carried *carrySource
+	if opts.carry {
+		worktreeRoot, err := git.FindWorktreeRoot(cwd)
+		if err != nil {
+			return fmt.Errorf("--carry must be run from inside a worktree")
+		}
+		if carried, err = recordCarry(worktreeRoot, filepath.Base(worktreeRoot), nil); err != nil {
+			return err
+		}
 	}
 
 	// Regular branch creation
-	return runAddFromBranch(branchOrPR, switchTo, baseBranch, name, bareDir, workspaceRoot, sourceWorktree)
+	return runAddFromBranch(branchOrPR, opts.switchTo, opts.useTmux, opts.baseBranch, opts.name, bareDir, workspaceRoot, sourceWorktree, profile, opts.stack, carried)
+}
+
+// runAddAllRepos adds a worktree for branch in every repository of the
+// project and links them into the branch's group directory. Repositories
+// that already have a worktree for branch keep it.
+func runAddAllRepos(branch, baseBranch string, switchTo, useTmux bool) error {
+	branch = strings.TrimSpace(branch)
+	if github.IsPRURL(branch) || gitlab.IsMRURL(branch) {
+		return fmt.Errorf("--all-repos cannot be used with PR/MR references")
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	project, err := workspace.FindProject(cwd)
+	if err != nil {
+		return err
+	}
+	repos, err := clonedProjectRepos(project)
+	if err != nil {
+		return err
+	}
+	if _, err := project.GroupPath(branch); err != nil {
+		return err
+	}
+
+	worktrees := make(map[string]string, len(repos))
+	var failed []string
+	for _, repo := range repos {
+		path, err := addRepoWorktree(repo, branch, baseBranch)
+		if err != nil {
+			logger.Error("%s: %v", repo.Name, err)
+			failed = append(failed, repo.Name)
+			continue
+		}
+		worktrees[repo.Name] = path
+	}
+	if len(worktrees) == 0 {
+		return fmt.Errorf("failed to add %s in any repo", branch)
+	}
+
+	groupDir, err := linkProjectGroup(project, branch, worktrees)
+	if err != nil {
+		return err
+	}
+
+	if switchTo {
+		if err := switchToWorktree(groupDir, useTmux); err != nil {
+			logger.Warning("%v", err)
+		}
+	} else {
+		logger.Success("Grouped %s at %s", branch, styles.RenderPath(groupDir))
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to add %s in %s", branch, strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// addRepoWorktree adds the worktree for branch in a repository of a project,
+// or returns the one it already has
+func addRepoWorktree(repo workspace.ProjectRepo, branch, baseBranch string) (string, error) {
+	bareDir := repo.BareDir()
+
+	infos, err := git.ListWorktreesWithInfo(bareDir, true)
+	if err != nil {
+		return "", fmt.Errorf("failed to list worktrees: %w", err)
+	}
+	for _, info := range infos {
+		if info.Branch == branch {
+			logger.Info("%s: using existing worktree %s", repo.Name, styles.RenderPath(info.Path))
+			return info.Path, nil
+		}
+	}
+
+	lockFile := filepath.Join(repo.Path, ".grove-worktree.lock")
+	lockHandle, err := workspace.AcquireWorkspaceLock(lockFile)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = lockHandle.Close()
+		_ = os.Remove(lockFile)
+	}()
+
+	// The branch may exist in some repos already; only new branches start
+	// from the base
+	if exists, err := git.BranchExists(bareDir, branch); err == nil && exists {
+		baseBranch = ""
+	}
+
+	worktreePath, err := newWorktreePath(bareDir, "", branch)
+	if err != nil {
+		return "", err
+	}
+	sourceWorktree := findFallbackSourceWorktree(bareDir)
+	if sourceWorktree == "" {
+		sourceWorktree = findConfigWorktree(bareDir)
+	}
+	if err := runAddFromBranch(branch, false, false, baseBranch, "", bareDir, repo.Path, sourceWorktree, nil, false, nil); err != nil {
+		return "", err
+	}
+	return worktreePath, nil
 }
 
-func runAddFromBranch(branch string, switchTo bool, baseBranch, name, bareDir, workspaceRoot, sourceWorktree string) error {
-	dirName := name
-	if dirName == "" {
-		dirName = workspace.SanitizeBranchName(branch)
+func runAddFromBranch(branch string, switchTo, useTmux bool, baseBranch, name, bareDir, workspaceRoot, sourceWorktree string, profile *sparseProfile, stack bool, carry *carrySource) error {
+	worktreePath, err := newWorktreePath(bareDir, name, branch)
+	if err != nil {
+		return err
 	}
-	worktreePath := filepath.Join(workspaceRoot, dirName)
 
 	infos, err := git.ListWorktreesWithInfo(bareDir, true)
 	if err != nil {
@@ -226,12 +456,31 @@ func runAddFromBranch(branch string, switchTo bool, baseBranch, name, bareDir, w
 	if err != nil {
 		return fmt.Errorf("failed to check branch: %w", err)
 	}
+	if exists && stack {
+		return fmt.Errorf("--stack cannot be used with existing branch %q", branch)
+	}
+	if exists && baseBranch != "" {
+		return fmt.Errorf("--base cannot be used with existing branch %q", branch)
+	}
+
+	hookCtx := &hooks.Context{
+		Command:        "add",
+		Workspace:      workspaceRoot,
+		Worktree:       worktreePath,
+		Branch:         branch,
+		Base:           baseBranch,
+		SourceWorktree: sourceWorktree,
+	}
+	if err := runAddPreHooks(bareDir, workspaceRoot, hookCtx); err != nil {
+		return err
+	}
 
 	if exists {
-		if baseBranch != "" {
-			return fmt.Errorf("--base cannot be used with existing branch %q", branch)
-		}
-		if err := git.CreateWorktree(bareDir, worktreePath, branch, true); err != nil {
+		if profile != nil {
+			if err := createSparseWorktree(bareDir, worktreePath, branch, "", false, profile); err != nil {
+				return err
+			}
+		} else if err := git.CreateWorktree(bareDir, worktreePath, branch, true); err != nil {
 			return git.HintGitTooOld(fmt.Errorf("failed to create worktree: %w", err))
 		}
 		if remoteExists, _ := git.RemoteBranchExists(bareDir, "origin", branch); remoteExists {
@@ -241,22 +490,32 @@ func runAddFromBranch(branch string, switchTo bool, baseBranch, name, bareDir, w
 		}
 	} else {
 		if baseBranch != "" {
-			// Validate base branch exists
-			baseExists, err := git.BranchExists(bareDir, baseBranch)
-			if err != nil {
-				return fmt.Errorf("failed to check base branch: %w", err)
+			if err := validateBaseBranch(bareDir, baseBranch); err != nil {
+				return err
 			}
-			if !baseExists {
-				return fmt.Errorf("base branch %q does not exist", baseBranch)
+		}
+		switch {
+		case profile != nil:
+			if err := createSparseWorktree(bareDir, worktreePath, branch, baseBranch, true, profile); err != nil {
+				return err
 			}
+		case baseBranch != "":
 			if err := git.CreateWorktreeWithNewBranchFrom(bareDir, worktreePath, branch, baseBranch, true); err != nil {
 				return git.HintGitTooOld(fmt.Errorf("failed to create worktree: %w", err))
 			}
-		} else {
+		default:
 			if err := git.CreateWorktreeWithNewBranch(bareDir, worktreePath, branch, true); err != nil {
 				return git.HintGitTooOld(fmt.Errorf("failed to create worktree: %w", err))
 			}
 		}
+
+		if stack {
+			if base, err := git.RevParse(bareDir, baseBranch); err != nil {
+				logger.Warning("Failed to record stack parent: %v", err)
+			} else if err := git.SetBranchParent(bareDir, branch, baseBranch, base); err != nil {
+				logger.Warning("Failed to record stack parent: %v", err)
+			}
+		}
 	}
 
 	// Auto-lock if branch matches auto-lock patterns
@@ -268,30 +527,43 @@ func runAddFromBranch(branch string, switchTo bool, baseBranch, name, bareDir, w
 		}
 	}
 
+	// Carried changes arrive before hooks, which may depend on them
+	carryResult := moveCarry(carry, worktreePath)
+
 	spin := logger.StartSpinner("Setting up worktree...")
 	configWorktree := findConfigWorktree(bareDir)
 	preserveResult := preserveFilesFromSource(sourceWorktree, worktreePath, configWorktree)
 	linkResult := linkDirectoriesFromSource(sourceWorktree, worktreePath, configWorktree)
+	seeded := seedDirectoriesFromSiblings(bareDir, sourceWorktree, worktreePath, configWorktree)
+	hookCtx.Vars = allocateWorktreeEnv(bareDir, worktreePath, hookCtx.Branch, configWorktree)
+	gitProfiles := applyGitProfiles(worktreePath, hookCtx.Branch, configWorktree)
 	spin.Stop()
-	hookResult := runAddHooks(sourceWorktree, worktreePath)
+	hookResult := runAddHooks(bareDir, hookCtx)
 
+	logger.Emit(logger.Event{Type: logger.EventWorktreeCreated, Worktree: worktreePath, Branch: hookCtx.Branch})
 	if switchTo {
-		fmt.Println(worktreePath) // Raw path for shell wrapper to cd into
+		if err := switchToWorktree(worktreePath, useTmux); err != nil {
+			logger.Warning("%v", err)
+		}
 	} else {
 		logger.Success("Created worktree at %s", styles.RenderPath(worktreePath))
 	}
+	logSparseProfile(profile)
+	logCarryResult(carryResult)
 	logPreserveResult(preserveResult)
 	logLinkResult(linkResult)
+	logSeedResult(seeded)
+	logEnvResult(hookCtx.Vars)
+	logGitProfiles(gitProfiles)
 	logHookResult(hookResult)
 	return nil
 }
 
-func runAddDetached(ref string, switchTo bool, name, bareDir, workspaceRoot, sourceWorktree string) error {
-	dirName := name
-	if dirName == "" {
-		dirName = workspace.SanitizeBranchName(ref)
+func runAddDetached(ref string, switchTo, useTmux bool, name, bareDir, workspaceRoot, sourceWorktree string, profile *sparseProfile) error {
+	worktreePath, err := newWorktreePath(bareDir, name, ref)
+	if err != nil {
+		return err
 	}
-	worktreePath := filepath.Join(workspaceRoot, dirName)
 
 	// Check directory doesn't already exist
 	if _, err := os.Stat(worktreePath); err == nil {
@@ -303,7 +575,23 @@ func runAddDetached(ref string, switchTo bool, name, bareDir, workspaceRoot, sou
 		return fmt.Errorf("ref %q does not exist", ref)
 	}
 
-	if err := git.CreateWorktreeDetached(bareDir, worktreePath, ref, true); err != nil {
+	hookCtx := &hooks.Context{
+		Command:        "add",
+		Workspace:      workspaceRoot,
+		Worktree:       worktreePath,
+		Base:           ref,
+		SourceWorktree: sourceWorktree,
+	}
+	if err := runAddPreHooks(bareDir, workspaceRoot, hookCtx); err != nil {
+		return err
+	}
+
+	if profile != nil {
+		if err := git.CreateSparseWorktree(bareDir, worktreePath, ref, true, profile.dirs); err != nil {
+			return git.HintGitTooOld(fmt.Errorf("failed to create detached worktree: %w", err))
+		}
+		recordSparseProfiles(worktreePath, []string{profile.name})
+	} else if err := git.CreateWorktreeDetached(bareDir, worktreePath, ref, true); err != nil {
 		return git.HintGitTooOld(fmt.Errorf("failed to create detached worktree: %w", err))
 	}
 
@@ -313,57 +601,45 @@ func runAddDetached(ref string, switchTo bool, name, bareDir, workspaceRoot, sou
 	configWorktree := findConfigWorktree(bareDir)
 	preserveResult := preserveFilesFromSource(sourceWorktree, worktreePath, configWorktree)
 	linkResult := linkDirectoriesFromSource(sourceWorktree, worktreePath, configWorktree)
+	seeded := seedDirectoriesFromSiblings(bareDir, sourceWorktree, worktreePath, configWorktree)
+	hookCtx.Vars = allocateWorktreeEnv(bareDir, worktreePath, hookCtx.Branch, configWorktree)
 	spin.Stop()
-	hookResult := runAddHooks(sourceWorktree, worktreePath)
+	hookResult := runAddHooks(bareDir, hookCtx)
 
+	logger.Emit(logger.Event{Type: logger.EventWorktreeCreated, Worktree: worktreePath, Branch: hookCtx.Branch})
 	if switchTo {
-		fmt.Println(worktreePath) // Raw path for shell wrapper to cd into
+		if err := switchToWorktree(worktreePath, useTmux); err != nil {
+			logger.Warning("%v", err)
+		}
 	} else {
 		logger.Success("Created detached worktree at %s", styles.RenderPath(worktreePath))
 	}
+	logSparseProfile(profile)
 	logPreserveResult(preserveResult)
 	logLinkResult(linkResult)
+	logSeedResult(seeded)
+	logEnvResult(hookCtx.Vars)
 	logHookResult(hookResult)
 	return nil
 }
 
-func runAddFromPR(prRef string, switchTo bool, name, bareDir, workspaceRoot, sourceWorktree string, reset bool) error {
-	// Check gh is available
-	if err := github.CheckGhAvailable(); err != nil {
-		return err
-	}
-
+func runAddFromPR(prRef string, switchTo, useTmux bool, name, bareDir, workspaceRoot, sourceWorktree string, reset bool) error {
 	// Parse PR reference
 	ref, err := github.ParsePRReference(prRef)
 	if err != nil {
 		return err
 	}
 
-	// If no owner/repo in ref, get from workspace's origin
-	owner, repo := ref.Owner, ref.Repo
-	if owner == "" || repo == "" {
-		repoRef, err := getRepoFromOrigin(bareDir)
-		if err != nil {
-			return fmt.Errorf("PR number requires workspace context: %w", err)
-		}
-		owner, repo = repoRef.Owner, repoRef.Repo
-	}
-
-	// Fetch PR info
-	spin := logger.StartSpinner(fmt.Sprintf("Fetching PR #%d from %s/%s...", ref.Number, owner, repo))
-	prInfo, err := github.FetchPRInfo(owner, repo, ref.Number)
+	prInfo, err := resolvePRInfo(bareDir, ref)
 	if err != nil {
-		spin.StopWithError("Failed to fetch PR info")
 		return err
 	}
-	spin.Stop()
 
-	branch := prInfo.HeadRef
-	dirName := name
-	if dirName == "" {
-		dirName = fmt.Sprintf("pr-%d", ref.Number)
+	branch := prBranchName(ref.Number, prInfo)
+	worktreePath, err := newWorktreePath(bareDir, name, fmt.Sprintf("pr-%d", ref.Number))
+	if err != nil {
+		return err
 	}
-	worktreePath := filepath.Join(workspaceRoot, dirName)
 
 	// Check if worktree already exists
 	infos, err := git.ListWorktreesWithInfo(bareDir, true)
@@ -380,8 +656,25 @@ func runAddFromPR(prRef string, switchTo bool, name, bareDir, workspaceRoot, sou
 		return fmt.Errorf("directory already exists: %s", worktreePath)
 	}
 
-	// Handle fork PRs: add remote and fetch
-	if prInfo.IsFork {
+	hookCtx := &hooks.Context{
+		Command:        "add",
+		Workspace:      workspaceRoot,
+		Worktree:       worktreePath,
+		Branch:         branch,
+		SourceWorktree: sourceWorktree,
+	}
+	if err := runAddPreHooks(bareDir, workspaceRoot, hookCtx); err != nil {
+		return err
+	}
+
+	switch {
+	case prInfo == nil:
+		// No gh: check out the pull ref from origin, without fork or upstream setup
+		if err := checkoutFetchedRef(bareDir, worktreePath, branch, "origin", github.PRHeadRef(ref.Number), fmt.Sprintf("PR #%d", ref.Number), reset); err != nil {
+			return err
+		}
+	case prInfo.IsFork:
+		// Fork PR: add remote and fetch
 		remoteName := fmt.Sprintf("pr-%d-%s", ref.Number, prInfo.HeadOwner)
 
 		// Check if remote already exists
@@ -399,7 +692,7 @@ func runAddFromPR(prRef string, switchTo bool, name, bareDir, workspaceRoot, sou
 				return fmt.Errorf("failed to get fork URL: %w", err)
 			}
 
-			spin = logger.StartSpinner(fmt.Sprintf("Adding remote %s for fork...", remoteName))
+			spin := logger.StartSpinner(fmt.Sprintf("Adding remote %s for fork...", remoteName))
 			if err := git.AddRemote(bareDir, remoteName, remoteURL); err != nil {
 				spin.StopWithError("Failed to add remote")
 				return fmt.Errorf("failed to add fork remote: %w", err)
@@ -416,7 +709,7 @@ func runAddFromPR(prRef string, switchTo bool, name, bareDir, workspaceRoot, sou
 			}
 		}
 
-		spin = logger.StartSpinner(fmt.Sprintf("Fetching branch %s from fork...", branch))
+		spin := logger.StartSpinner(fmt.Sprintf("Fetching branch %s from fork...", branch))
 		if err := git.FetchBranch(bareDir, remoteName, branch); err != nil {
 			spin.StopWithError("Failed to fetch branch")
 			cleanupRemote()
@@ -433,56 +726,139 @@ func runAddFromPR(prRef string, switchTo bool, name, bareDir, workspaceRoot, sou
 		if err := git.SetUpstreamBranch(worktreePath, trackingRef); err != nil {
 			logger.Debug("Failed to set upstream for %s: %v", branch, err)
 		}
-	} else {
+	default:
 		// Same-repo PR: fetch and create worktree
-		spin = logger.StartSpinner(fmt.Sprintf("Fetching branch %s...", branch))
-		if err := git.FetchBranch(bareDir, "origin", branch); err != nil {
-			spin.StopWithError("Failed to fetch branch")
-			return fmt.Errorf("failed to fetch branch: %w", err)
+		if err := checkoutFetchedRef(bareDir, worktreePath, branch, "origin", branch, "branch "+branch, reset); err != nil {
+			return err
 		}
-		spin.Stop()
-
-		// Resolve FETCH_HEAD to a commit hash immediately to avoid race conditions.
-		// Another fetch could overwrite FETCH_HEAD between our fetch and comparison.
-		fetchedHash, err := git.RevParse(bareDir, "FETCH_HEAD")
-		if err != nil {
-			return fmt.Errorf("failed to resolve fetched commit: %w", err)
+		if err := git.SetUpstreamBranch(worktreePath, "origin/"+branch); err != nil {
+			logger.Debug("Failed to set upstream for %s: %v", branch, err)
 		}
+	}
 
-		// Check for diverged local branch before creating worktree.
-		// If local branch exists and has commits not on remote, fail unless --reset is used.
-		localExists, err := git.LocalBranchExists(bareDir, branch)
-		if err != nil {
-			return fmt.Errorf("failed to check local branch: %w", err)
+	// Auto-lock if branch matches auto-lock patterns
+	if config.ShouldAutoLock(branch) {
+		if err := git.LockWorktree(bareDir, worktreePath, "Auto-locked (grove.autoLock)"); err != nil {
+			logger.Debug("Failed to auto-lock worktree: %v", err)
+		} else {
+			logger.Debug("Auto-locked worktree for branch %s", branch)
 		}
+	}
 
-		if localExists {
-			ahead, _, err := git.CompareBranchRefs(bareDir, branch, fetchedHash)
-			if err != nil {
-				return fmt.Errorf("failed to compare branches: %w", err)
-			}
+	setupSpin := logger.StartSpinner("Setting up worktree...")
+	configWorktree := findConfigWorktree(bareDir)
+	preserveResult := preserveFilesFromSource(sourceWorktree, worktreePath, configWorktree)
+	linkResult := linkDirectoriesFromSource(sourceWorktree, worktreePath, configWorktree)
+	seeded := seedDirectoriesFromSiblings(bareDir, sourceWorktree, worktreePath, configWorktree)
+	hookCtx.Vars = allocateWorktreeEnv(bareDir, worktreePath, hookCtx.Branch, configWorktree)
+	gitProfiles := applyGitProfiles(worktreePath, hookCtx.Branch, configWorktree)
+	setupSpin.Stop()
+	hookResult := runAddHooks(bareDir, hookCtx)
 
-			if ahead > 0 {
-				if !reset {
-					return fmt.Errorf("local branch %q has %d commit(s) not on remote (PR may have been rebased); use --reset to discard local commits and sync with remote", branch, ahead)
-				}
-				spin = logger.StartSpinner(fmt.Sprintf("Resetting %s to match remote (discarding %d local commits)...", branch, ahead))
-				if err := git.UpdateBranchRef(bareDir, branch, fetchedHash); err != nil {
-					spin.StopWithError("Failed to reset branch")
-					return fmt.Errorf("failed to reset branch: %w", err)
-				}
-				spin.Stop()
+	logger.Emit(logger.Event{Type: logger.EventWorktreeCreated, Worktree: worktreePath, Branch: hookCtx.Branch})
+	if switchTo {
+		if err := switchToWorktree(worktreePath, useTmux); err != nil {
+			logger.Warning("%v", err)
+		}
+	} else {
+		logger.Success("Created worktree for PR #%d at %s", ref.Number, styles.RenderPath(worktreePath))
+	}
+	logPreserveResult(preserveResult)
+	logLinkResult(linkResult)
+	logSeedResult(seeded)
+	logEnvResult(hookCtx.Vars)
+	logGitProfiles(gitProfiles)
+	logHookResult(hookResult)
+	return nil
+}
+
+// resolvePRInfo looks up a pull request through gh. Returns nil info without
+// an error when gh is missing or not authenticated; callers then fall back to
+// the refs/pull/<n>/head ref on origin, which needs no API access.
+func resolvePRInfo(bareDir string, ref *github.PRRef) (*github.PRInfo, error) {
+	if err := github.CheckGhAvailable(); err != nil {
+		// A PR URL for another repository cannot be served from origin
+		if ref.Owner != "" {
+			if origin, originErr := getRepoFromOrigin(bareDir); originErr == nil &&
+				!strings.EqualFold(origin.Owner+"/"+origin.Repo, ref.Owner+"/"+ref.Repo) {
+				return nil, fmt.Errorf("PR #%d belongs to %s/%s, not origin (%s/%s): %w", ref.Number, ref.Owner, ref.Repo, origin.Owner, origin.Repo, err)
 			}
 		}
+		logger.Info("%v; fetching %s from origin instead", err, github.PRHeadRef(ref.Number))
+		return nil, nil
+	}
 
-		if err := git.CreateWorktree(bareDir, worktreePath, branch, true); err != nil {
-			return git.HintGitTooOld(fmt.Errorf("failed to create worktree: %w", err))
+	// If no owner/repo in ref, get from workspace's origin
+	owner, repo := ref.Owner, ref.Repo
+	if owner == "" || repo == "" {
+		repoRef, err := getRepoFromOrigin(bareDir)
+		if err != nil {
+			return nil, fmt.Errorf("PR number requires workspace context: %w", err)
 		}
-		if err := git.SetUpstreamBranch(worktreePath, "origin/"+branch); err != nil {
-			logger.Debug("Failed to set upstream for %s: %v", branch, err)
+		owner, repo = repoRef.Owner, repoRef.Repo
+	}
+
+	spin := logger.StartSpinner(fmt.Sprintf("Fetching PR #%d from %s/%s...", ref.Number, owner, repo))
+	prInfo, err := github.FetchPRInfo(owner, repo, ref.Number)
+	if err != nil {
+		spin.StopWithError("Failed to fetch PR info")
+		return nil, err
+	}
+	spin.Stop()
+	return prInfo, nil
+}
+
+// prBranchName returns the local branch for a pull request: its head branch
+// when gh could look it up, otherwise pr-<number>.
+func prBranchName(number int, info *github.PRInfo) string {
+	if info != nil {
+		return info.HeadRef
+	}
+	return fmt.Sprintf("pr-%d", number)
+}
+
+func runAddFromMR(ref *gitlab.MRRef, switchTo, useTmux bool, name, bareDir, workspaceRoot, sourceWorktree string, reset bool) error {
+	info, err := resolveMRInfo(bareDir, ref)
+	if err != nil {
+		return err
+	}
+
+	branch := mrBranchName(ref.Number, info)
+	worktreePath, err := newWorktreePath(bareDir, name, fmt.Sprintf("mr-%d", ref.Number))
+	if err != nil {
+		return err
+	}
+
+	// Check if worktree already exists
+	infos, err := git.ListWorktreesWithInfo(bareDir, true)
+	if err != nil {
+		return fmt.Errorf("failed to list worktrees: %w", err)
+	}
+	for _, info := range infos {
+		if info.Branch == branch {
+			return fmt.Errorf("worktree already exists for branch %q at %s\n\nHint: Use 'grove list' to see existing worktrees, or use --name to choose a different directory", branch, info.Path)
 		}
 	}
 
+	if _, err := os.Stat(worktreePath); err == nil {
+		return fmt.Errorf("directory already exists: %s", worktreePath)
+	}
+
+	hookCtx := &hooks.Context{
+		Command:        "add",
+		Workspace:      workspaceRoot,
+		Worktree:       worktreePath,
+		Branch:         branch,
+		SourceWorktree: sourceWorktree,
+	}
+	if err := runAddPreHooks(bareDir, workspaceRoot, hookCtx); err != nil {
+		return err
+	}
+
+	if err := checkoutMR(bareDir, worktreePath, branch, ref.Number, info, reset); err != nil {
+		return err
+	}
+
 	// Auto-lock if branch matches auto-lock patterns
 	if config.ShouldAutoLock(branch) {
 		if err := git.LockWorktree(bareDir, worktreePath, "Auto-locked (grove.autoLock)"); err != nil {
@@ -492,24 +868,160 @@ func runAddFromPR(prRef string, switchTo bool, name, bareDir, workspaceRoot, sou
 		}
 	}
 
-	setupSpin := logger.StartSpinner("Setting up worktree...")
+	spin := logger.StartSpinner("Setting up worktree...")
 	configWorktree := findConfigWorktree(bareDir)
 	preserveResult := preserveFilesFromSource(sourceWorktree, worktreePath, configWorktree)
 	linkResult := linkDirectoriesFromSource(sourceWorktree, worktreePath, configWorktree)
-	setupSpin.Stop()
-	hookResult := runAddHooks(sourceWorktree, worktreePath)
+	seeded := seedDirectoriesFromSiblings(bareDir, sourceWorktree, worktreePath, configWorktree)
+	hookCtx.Vars = allocateWorktreeEnv(bareDir, worktreePath, hookCtx.Branch, configWorktree)
+	gitProfiles := applyGitProfiles(worktreePath, hookCtx.Branch, configWorktree)
+	spin.Stop()
+	hookResult := runAddHooks(bareDir, hookCtx)
 
+	logger.Emit(logger.Event{Type: logger.EventWorktreeCreated, Worktree: worktreePath, Branch: hookCtx.Branch})
 	if switchTo {
-		fmt.Println(worktreePath) // Raw path for shell wrapper to cd into
+		if err := switchToWorktree(worktreePath, useTmux); err != nil {
+			logger.Warning("%v", err)
+		}
 	} else {
-		logger.Success("Created worktree for PR #%d at %s", ref.Number, styles.RenderPath(worktreePath))
+		logger.Success("Created worktree for MR !%d at %s", ref.Number, styles.RenderPath(worktreePath))
 	}
 	logPreserveResult(preserveResult)
 	logLinkResult(linkResult)
+	logSeedResult(seeded)
+	logEnvResult(hookCtx.Vars)
+	logGitProfiles(gitProfiles)
 	logHookResult(hookResult)
 	return nil
 }
 
+// resolveMRInfo looks up a merge request through glab. Returns nil info
+// without an error when glab is missing or the project cannot be determined;
+// callers then fall back to the MR head ref, which needs no API access.
+func resolveMRInfo(bareDir string, ref *gitlab.MRRef) (*gitlab.MRInfo, error) {
+	origin, originErr := getGitLabRepoFromOrigin(bareDir)
+
+	// Both the MR head ref and same-project source branches are fetched from
+	// origin, so an MR URL for another project cannot be served
+	if ref.Host != "" && ref.Project != "" && originErr == nil &&
+		!origin.SameProject(&gitlab.RepoRef{Host: ref.Host, Project: ref.Project}) {
+		return nil, fmt.Errorf("MR !%d belongs to %s/%s, not origin (%s/%s)", ref.Number, ref.Host, ref.Project, origin.Host, origin.Project)
+	}
+
+	if err := gitlab.CheckGlabAvailable(); err != nil {
+		logger.Debug("%v, fetching MR head ref instead", err)
+		return nil, nil
+	}
+
+	repo := &gitlab.RepoRef{Host: ref.Host, Project: ref.Project}
+	if repo.Host == "" || repo.Project == "" {
+		if originErr != nil {
+			logger.Debug("Cannot determine GitLab project from origin (%v), fetching MR head ref instead", originErr)
+			return nil, nil
+		}
+		repo = origin
+	}
+
+	spin := logger.StartSpinner(fmt.Sprintf("Fetching MR !%d from %s...", ref.Number, repo.Project))
+	info, err := gitlab.FetchMRInfo(repo, ref.Number)
+	if err != nil {
+		spin.StopWithError("Failed to fetch MR info")
+		return nil, err
+	}
+	spin.Stop()
+	return info, nil
+}
+
+// mrBranchName returns the local branch for a merge request: its source
+// branch when it lives in the same project, otherwise mr-<number>.
+func mrBranchName(number int, info *gitlab.MRInfo) string {
+	if info != nil && !info.IsFork {
+		return info.SourceBranch
+	}
+	return fmt.Sprintf("mr-%d", number)
+}
+
+// checkoutMR creates the worktree for a merge request. Same-project MRs track
+// their source branch on origin. Fork MRs, and any MR when glab is unavailable,
+// are checked out from the refs/merge-requests/<n>/head ref GitLab publishes.
+func checkoutMR(bareDir, worktreePath, branch string, number int, info *gitlab.MRInfo, reset bool) error {
+	if info != nil && !info.IsFork {
+		if err := checkoutFetchedRef(bareDir, worktreePath, branch, "origin", branch, "branch "+branch, reset); err != nil {
+			return err
+		}
+		if err := git.SetUpstreamBranch(worktreePath, "origin/"+branch); err != nil {
+			logger.Debug("Failed to set upstream for %s: %v", branch, err)
+		}
+		return nil
+	}
+
+	return checkoutFetchedRef(bareDir, worktreePath, branch, "origin", gitlab.MRHeadRef(number), fmt.Sprintf("MR !%d", number), reset)
+}
+
+// validateBaseBranch checks that the --base branch exists
+func validateBaseBranch(bareDir, baseBranch string) error {
+	baseExists, err := git.BranchExists(bareDir, baseBranch)
+	if err != nil {
+		return fmt.Errorf("failed to check base branch: %w", err)
+	}
+	if !baseExists {
+		return fmt.Errorf("base branch %q does not exist", baseBranch)
+	}
+	return nil
+}
+
+// checkoutFetchedRef fetches ref from remote and creates a worktree for branch
+// at the fetched commit. An existing local branch is reused, unless it has
+// commits the fetched ref lacks; those are only discarded with reset.
+func checkoutFetchedRef(bareDir, worktreePath, branch, remote, ref, label string, reset bool) error {
+	spin := logger.StartSpinner(fmt.Sprintf("Fetching %s...", label))
+	if err := git.FetchBranch(bareDir, remote, ref); err != nil {
+		spin.StopWithError("Failed to fetch branch")
+		return fmt.Errorf("failed to fetch branch: %w", err)
+	}
+	spin.Stop()
+
+	// Resolve FETCH_HEAD to a commit hash immediately to avoid race conditions.
+	// Another fetch could overwrite FETCH_HEAD between our fetch and comparison.
+	fetchedHash, err := git.RevParse(bareDir, "FETCH_HEAD")
+	if err != nil {
+		return fmt.Errorf("failed to resolve fetched commit: %w", err)
+	}
+
+	// Check for diverged local branch before creating worktree.
+	// If local branch exists and has commits not on remote, fail unless --reset is used.
+	localExists, err := git.LocalBranchExists(bareDir, branch)
+	if err != nil {
+		return fmt.Errorf("failed to check local branch: %w", err)
+	}
+
+	if localExists {
+		ahead, _, err := git.CompareBranchRefs(bareDir, branch, fetchedHash)
+		if err != nil {
+			return fmt.Errorf("failed to compare branches: %w", err)
+		}
+
+		if ahead > 0 {
+			if !reset {
+				return fmt.Errorf("local branch %q has %d commit(s) not on remote (it may have been rebased); use --reset to discard local commits and sync with remote", branch, ahead)
+			}
+			spin = logger.StartSpinner(fmt.Sprintf("Resetting %s to match remote (discarding %d local commits)...", branch, ahead))
+			if err := git.UpdateBranchRef(bareDir, branch, fetchedHash); err != nil {
+				spin.StopWithError("Failed to reset branch")
+				return fmt.Errorf("failed to reset branch: %w", err)
+			}
+			spin.Stop()
+		}
+	} else if err := git.UpdateBranchRef(bareDir, branch, fetchedHash); err != nil {
+		return fmt.Errorf("failed to create branch: %w", err)
+	}
+
+	if err := git.CreateWorktree(bareDir, worktreePath, branch, true); err != nil {
+		return git.HintGitTooOld(fmt.Errorf("failed to create worktree: %w", err))
+	}
+	return nil
+}
+
 // getRepoFromOrigin extracts owner/repo from the origin remote URL.
 func getRepoFromOrigin(bareDir string) (*github.RepoRef, error) {
 	url, err := git.GetRemoteURL(bareDir, "origin")
@@ -519,6 +1031,15 @@ func getRepoFromOrigin(bareDir string) (*github.RepoRef, error) {
 	return github.ParseRepoURL(url)
 }
 
+// getGitLabRepoFromOrigin parses the GitLab project of the origin remote
+func getGitLabRepoFromOrigin(bareDir string) (*gitlab.RepoRef, error) {
+	url, err := git.GetRemoteURL(bareDir, "origin")
+	if err != nil {
+		return nil, err
+	}
+	return gitlab.ParseRepoURL(url)
+}
+
 func findSourceWorktree(cwd, workspaceRoot string) string {
 	if fs.PathsEqual(cwd, workspaceRoot) {
 		return ""
@@ -587,15 +1108,43 @@ func findFallbackSourceWorktree(bareDir string) string {
 }
 
 // findConfigWorktree returns a worktree containing .grove.toml. Prefers the
-// configured default branch worktree, then main/master by branch, then a
-// worktree with a directory named main/master. Falls back to any worktree
-// containing .grove.toml. Returns "" when no worktree has the file.
+// default branch worktree found by findDefaultConfigWorktree, then falls back
+// to any worktree containing .grove.toml. Returns "" when no worktree has the
+// file.
 func findConfigWorktree(bareDir string) string {
 	infos, err := git.ListWorktreesWithInfo(bareDir, true)
 	if err != nil || len(infos) == 0 {
 		return ""
 	}
 
+	if path := defaultConfigWorktree(bareDir, infos); path != "" {
+		return path
+	}
+
+	for _, info := range infos {
+		if config.FileConfigExists(info.Path) {
+			return info.Path
+		}
+	}
+
+	return ""
+}
+
+// findDefaultConfigWorktree returns the default branch worktree when it
+// contains .grove.toml: the configured default branch, then main/master by
+// branch, then a worktree with a directory named after one of them. Unlike
+// findConfigWorktree it never falls back to other worktrees, whose files may
+// be a contributor's content.
+func findDefaultConfigWorktree(bareDir string) string {
+	infos, err := git.ListWorktreesWithInfo(bareDir, true)
+	if err != nil {
+		return ""
+	}
+	return defaultConfigWorktree(bareDir, infos)
+}
+
+func defaultConfigWorktree(bareDir string, infos []*git.WorktreeInfo) string {
+
 	// Build candidate list: default branch first, then main/master as fallbacks.
 	// The candidates[0] check only deduplicates against the default branch entry;
 	// "main" and "master" are intentionally both kept when default differs.
@@ -625,12 +1174,6 @@ func findConfigWorktree(bareDir string) string {
 		}
 	}
 
-	for _, info := range infos {
-		if config.FileConfigExists(info.Path) {
-			return info.Path
-		}
-	}
-
 	return ""
 }
 
@@ -645,6 +1188,7 @@ func preserveFilesFromSource(sourceWorktree, destWorktree, configWorktree string
 	}
 
 	result := &workspace.PreserveResult{}
+	strategy := workspace.GetPreserveCopyStrategy(configWorktree)
 
 	ignoredFiles, err := workspace.FindIgnoredFilesInWorktree(sourceWorktree)
 	if err != nil {
@@ -652,26 +1196,28 @@ func preserveFilesFromSource(sourceWorktree, destWorktree, configWorktree string
 	} else if len(ignoredFiles) > 0 {
 		patterns := config.GetMergedPreservePatterns(configWorktree)
 		excludePatterns := config.GetMergedPreserveExcludePatterns(configWorktree)
-		fileResult, err := workspace.PreserveFilesToWorktree(sourceWorktree, destWorktree, patterns, ignoredFiles, excludePatterns)
+		fileResult, err := workspace.PreserveFilesToWorktree(sourceWorktree, destWorktree, patterns, ignoredFiles, excludePatterns, strategy)
 		if err != nil {
-			logger.Debug("Failed to preserve files: %v", err)
+			logPreserveError("files", strategy, err)
 		} else {
-			result.Copied = append(result.Copied, fileResult.Copied...)
-			result.Skipped = append(result.Skipped, fileResult.Skipped...)
+			result.Merge(fileResult)
 		}
 	}
 
 	directories := config.GetMergedPreserveDirectories(configWorktree)
 	if len(directories) > 0 {
-		dirResult, err := workspace.PreserveDirectoriesToWorktree(sourceWorktree, destWorktree, directories)
+		dirResult, err := workspace.PreserveDirectoriesToWorktree(sourceWorktree, destWorktree, directories, strategy)
 		if err != nil {
-			logger.Debug("Failed to preserve directories: %v", err)
+			logPreserveError("directories", strategy, err)
 		} else {
-			result.Copied = append(result.Copied, dirResult.Copied...)
-			result.Skipped = append(result.Skipped, dirResult.Skipped...)
+			result.Merge(dirResult)
 		}
 	}
 
+	for _, file := range result.Copied {
+		logger.Emit(logger.Event{Type: logger.EventFilePreserved, Worktree: destWorktree, Path: file})
+	}
+
 	if len(result.Copied) == 0 && len(result.Skipped) == 0 {
 		return nil
 	}
@@ -684,9 +1230,9 @@ func logPreserveResult(result *workspace.PreserveResult) {
 	}
 
 	if len(result.Copied) > 0 {
-		header := fmt.Sprintf("preserved %d files:", len(result.Copied))
+		header := fmt.Sprintf("preserved %d files%s:", len(result.Copied), preserveStrategySuffix(result))
 		if len(result.Copied) == 1 {
-			header = "preserved 1 file:"
+			header = fmt.Sprintf("preserved 1 file%s:", preserveStrategySuffix(result))
 		}
 		logger.ListItemGroup(header, result.Copied)
 	}
@@ -704,6 +1250,31 @@ func logPreserveResult(result *workspace.PreserveResult) {
 	}
 }
 
+// preserveStrategySuffix describes how preserved files were copied, e.g.
+// " (reflink, 1.2 GB saved)". Full copies need no mention.
+func preserveStrategySuffix(result *workspace.PreserveResult) string {
+	if len(result.Strategies) == 0 || slices.Equal(result.Strategies, []fs.CopyStrategy{fs.CopyStrategyCopy}) {
+		return ""
+	}
+
+	names := make([]string, 0, len(result.Strategies))
+	for _, strategy := range result.Strategies {
+		names = append(names, string(strategy))
+	}
+	return fmt.Sprintf(" (%s, %s saved)", strings.Join(names, "+"), strings.TrimSpace(formatSize(result.SavedBytes)))
+}
+
+// logPreserveError reports a failure to preserve files. Errors are only
+// logged in debug mode, except for reflink and hardlink strategies, which
+// fail where the filesystem cannot share files.
+func logPreserveError(what string, strategy fs.CopyStrategy, err error) {
+	if strategy == fs.CopyStrategyReflink || strategy == fs.CopyStrategyHardlink {
+		logger.Warning("Failed to preserve %s with %s copy strategy: %v (use auto to fall back to copying)", what, strategy, err)
+		return
+	}
+	logger.Debug("Failed to preserve %s: %v", what, err)
+}
+
 func linkDirectoriesFromSource(sourceWorktree, destWorktree, configWorktree string) *workspace.LinkResult {
 	if sourceWorktree == "" {
 		logger.Debug("No source worktree, skipping directory linking")
@@ -756,19 +1327,126 @@ func logLinkResult(result *workspace.LinkResult) {
 	}
 }
 
-func runAddHooks(sourceWorktree, destWorktree string) *hooks.RunResult {
-	var addHooks []string
-	if sourceWorktree != "" {
-		addHooks = hooks.GetAddHooks(sourceWorktree)
+// seededDirectory is a directory copied from a sibling worktree with a
+// matching lockfile
+type seededDirectory struct {
+	dir    string
+	source string
+	result *workspace.PreserveResult
+}
+
+// seedDirectoriesFromSiblings copies the [seed] directories of .grove.toml into
+// a new worktree from the closest worktree whose lockfile has the same content,
+// so that dependency installs in add hooks have little left to do. The source
+// worktree is preferred, then the worktree whose directory changed last.
+func seedDirectoriesFromSiblings(bareDir, sourceWorktree, destWorktree, configWorktree string) []seededDirectory {
+	configDir := trustedConfigDir(destWorktree, configWorktree)
+	if configDir == "" {
+		return nil
+	}
+
+	rules, err := config.GetSeedRules(configDir)
+	if err != nil {
+		logger.Warning("Failed to seed directories: %v", err)
+		return nil
+	}
+	if len(rules) == 0 {
+		return nil
+	}
+
+	worktrees, err := git.ListWorktrees(bareDir)
+	if err != nil {
+		logger.Debug("Failed to list worktrees for seeding: %v", err)
+		return nil
 	}
 
+	strategy := workspace.GetPreserveCopyStrategy(configDir)
+	var seeded []seededDirectory
+	for _, rule := range rules {
+		candidates := workspace.SortSeedCandidates(worktrees, sourceWorktree, rule.Directory)
+		source, err := workspace.FindSeedSource(candidates, destWorktree, rule.Lockfile, rule.Directory)
+		if err != nil {
+			logger.Debug("Failed to find seed source for %s: %v", rule.Directory, err)
+			continue
+		}
+		if source == "" {
+			logger.Debug("No worktree with matching %s to seed %s from", rule.Lockfile, rule.Directory)
+			continue
+		}
+
+		result, err := workspace.SeedDirectory(source, destWorktree, rule.Directory, strategy)
+		if err != nil {
+			logger.Warning("%v", err)
+			continue
+		}
+		if len(result.Copied) > 0 {
+			seeded = append(seeded, seededDirectory{dir: rule.Directory, source: source, result: result})
+		}
+	}
+	return seeded
+}
+
+func logSeedResult(seeded []seededDirectory) {
+	for _, s := range seeded {
+		logger.ListSubItem("seeded %s from %s%s", s.dir, filepath.Base(s.source), preserveStrategySuffix(s.result))
+	}
+}
+
+// newWorktreePath returns where a new worktree belongs. A directory name given
+// with --name is used as is; otherwise ref, usually the branch, is placed
+// according to the configured worktree path template.
+func newWorktreePath(bareDir, name, ref string) (string, error) {
+	path := filepath.Join(filepath.Dir(bareDir), name)
+	if name == "" {
+		tmpl := config.GetMergedWorktreePathTemplate(cmp.Or(findConfigWorktree(bareDir), bareDir))
+		var err error
+		if path, err = workspace.WorktreePath(filepath.Dir(bareDir), tmpl, ref); err != nil {
+			return "", err
+		}
+	}
+
+	worktrees, err := git.ListWorktrees(bareDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to list worktrees: %w", err)
+	}
+	for _, worktree := range worktrees {
+		if fs.PathHasPrefix(path, worktree) {
+			return "", fmt.Errorf("worktree path %s is inside worktree %s; check grove.worktreePathTemplate", path, worktree)
+		}
+	}
+	return path, nil
+}
+
+// trustedConfigDir returns the directory whose .grove.toml configures a new
+// worktree. Like hooks and preserve patterns, it never comes from the new
+// worktree itself: for PRs and MRs that file is the contributor's content.
+func trustedConfigDir(destWorktree, configWorktree string) string {
+	if configWorktree == "" || fs.PathsEqual(configWorktree, destWorktree) {
+		return ""
+	}
+	return configWorktree
+}
+
+// runAddPreHooks runs pre-add hooks in the workspace root, since the new
+// worktree does not exist yet.
+func runAddPreHooks(bareDir, workspaceRoot string, hookCtx *hooks.Context) error {
+	preCtx := *hookCtx
+	preCtx.Event = hooks.EventPreAdd
+	return runPreHooks(bareDir, loadHooks(bareDir, hooks.EventPreAdd, hookCtx.Worktree), workspaceRoot, &preCtx)
+}
+
+func runAddHooks(bareDir string, hookCtx *hooks.Context) *hooks.RunResult {
+	addHooks := loadHooks(bareDir, hooks.EventPostAdd, hookCtx.Worktree)
 	if len(addHooks) == 0 {
 		logger.Debug("No add hooks configured")
 		return nil
 	}
 
+	postCtx := *hookCtx
+	postCtx.Event = hooks.EventPostAdd
+
 	logger.Info("Running %d hook(s)...", len(addHooks))
-	return hooks.RunAddHooksStreaming(destWorktree, addHooks, os.Stderr)
+	return hooks.RunStreaming(hookCtx.Worktree, addHooks, postCtx.Env(), os.Stderr)
 }
 
 func logHookResult(result *hooks.RunResult) {
@@ -864,7 +1542,7 @@ func completeFromWorktree(cmd *cobra.Command, args []string, toComplete string)
 
 	var completions []string
 	for _, info := range infos {
-		name := filepath.Base(info.Path)
+		name := git.WorktreeName(infos, info)
 		if strings.HasPrefix(name, toComplete) {
 			completions = append(completions, name)
 		}
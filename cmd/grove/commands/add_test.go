@@ -18,7 +18,7 @@ func TestNewAddCmd(t *testing.T) {
 	cmd := NewAddCmd()
 
 	// Verify command structure
-	if cmd.Use != "add [branch|PR-URL|ref]" {
+	if cmd.Use != "add [branch|PR-URL|MR-URL|ref]" {
 		t.Errorf("unexpected Use: %q", cmd.Use)
 	}
 	if cmd.Short == "" {
@@ -37,6 +37,7 @@ func TestNewAddCmd(t *testing.T) {
 		{"detach", "d", "false", "bool"},
 		{"name", "", "", "string"},
 		{"pr", "", "0", "int"},
+		{"mr", "", "0", "int"},
 		{"from", "", "", "string"},
 	}
 
@@ -75,7 +76,7 @@ func TestRunAdd_NotInWorkspace(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	err = runAdd([]string{"feature-test"}, false, "", "", false, 0, false, "")
+	err = runAdd([]string{"feature-test"}, addOptions{})
 	if !errors.Is(err, workspace.ErrNotInWorkspace) {
 		t.Errorf("expected ErrNotInWorkspace, got %v", err)
 	}
@@ -94,57 +95,106 @@ func TestRunAdd_PRValidation(t *testing.T) {
 	}
 
 	t.Run("base flag cannot be used with --pr", func(t *testing.T) {
-		err := runAdd(nil, false, "main", "", false, 123, false, "")
+		err := runAdd(nil, addOptions{baseBranch: "main", prNumber: 123})
 		if err == nil || !strings.Contains(err.Error(), "--base cannot be used with PR") {
 			t.Errorf("expected base/PR error, got %v", err)
 		}
 	})
 
 	t.Run("detach flag cannot be used with --pr", func(t *testing.T) {
-		err := runAdd(nil, false, "", "", true, 123, false, "")
+		err := runAdd(nil, addOptions{detach: true, prNumber: 123})
 		if err == nil || !strings.Contains(err.Error(), "--detach cannot be used with PR") {
 			t.Errorf("expected detach/PR error, got %v", err)
 		}
 	})
 
+	t.Run("sparse flag cannot be used with --pr", func(t *testing.T) {
+		err := runAdd(nil, addOptions{prNumber: 123, sparse: "web"})
+		if err == nil || !strings.Contains(err.Error(), "--sparse cannot be used with PR") {
+			t.Errorf("expected sparse/PR error, got %v", err)
+		}
+	})
+
+	t.Run("carry flag cannot be used with --pr", func(t *testing.T) {
+		err := runAdd(nil, addOptions{prNumber: 123, carry: true})
+		if err == nil || !strings.Contains(err.Error(), "--carry cannot be used with PR") {
+			t.Errorf("expected carry/PR error, got %v", err)
+		}
+	})
+
+	t.Run("detach flag cannot be used with --carry", func(t *testing.T) {
+		err := runAdd([]string{"v1.0.0"}, addOptions{detach: true, carry: true})
+		if err == nil || !strings.Contains(err.Error(), "--detach and --carry cannot be used together") {
+			t.Errorf("expected detach/carry error, got %v", err)
+		}
+	})
+
 	t.Run("negative --pr gives clear error", func(t *testing.T) {
-		err := runAdd(nil, false, "", "", false, -5, false, "")
+		err := runAdd(nil, addOptions{prNumber: -5})
 		if err == nil || !strings.Contains(err.Error(), "--pr must be a positive number") {
 			t.Errorf("expected positive number error, got %v", err)
 		}
 	})
 
 	t.Run("--pr cannot be combined with positional argument", func(t *testing.T) {
-		err := runAdd([]string{"feature"}, false, "", "", false, 123, false, "")
+		err := runAdd([]string{"feature"}, addOptions{prNumber: 123})
 		if err == nil || !strings.Contains(err.Error(), "--pr flag cannot be combined with positional argument") {
 			t.Errorf("expected --pr/positional conflict error, got %v", err)
 		}
 	})
 
 	t.Run("old #N syntax gives helpful error", func(t *testing.T) {
-		err := runAdd([]string{"#123"}, false, "", "", false, 0, false, "")
+		err := runAdd([]string{"#123"}, addOptions{})
 		if err == nil || !strings.Contains(err.Error(), "syntax no longer supported") {
 			t.Errorf("expected helpful migration error, got %v", err)
 		}
 	})
 
 	t.Run("base flag cannot be used with PR URL", func(t *testing.T) {
-		err := runAdd([]string{"https://github.com/owner/repo/pull/456"}, false, "main", "", false, 0, false, "")
+		err := runAdd([]string{"https://github.com/owner/repo/pull/456"}, addOptions{baseBranch: "main"})
 		if err == nil || !strings.Contains(err.Error(), "--base cannot be used with PR") {
 			t.Errorf("expected base/PR error, got %v", err)
 		}
 	})
 
 	t.Run("detach flag cannot be used with PR URL", func(t *testing.T) {
-		err := runAdd([]string{"https://github.com/owner/repo/pull/456"}, false, "", "", true, 0, false, "")
+		err := runAdd([]string{"https://github.com/owner/repo/pull/456"}, addOptions{detach: true})
 		if err == nil || !strings.Contains(err.Error(), "--detach cannot be used with PR") {
 			t.Errorf("expected detach/PR error, got %v", err)
 		}
 	})
 
+	t.Run("--pr and --mr cannot be used together", func(t *testing.T) {
+		err := runAdd(nil, addOptions{prNumber: 1, mrNumber: 2})
+		if err == nil || !strings.Contains(err.Error(), "--pr and --mr cannot be used together") {
+			t.Errorf("expected --pr/--mr conflict error, got %v", err)
+		}
+	})
+
+	t.Run("negative --mr gives clear error", func(t *testing.T) {
+		err := runAdd(nil, addOptions{mrNumber: -5})
+		if err == nil || !strings.Contains(err.Error(), "--mr must be a positive number") {
+			t.Errorf("expected positive number error, got %v", err)
+		}
+	})
+
+	t.Run("--mr cannot be combined with positional argument", func(t *testing.T) {
+		err := runAdd([]string{"feature"}, addOptions{mrNumber: 42})
+		if err == nil || !strings.Contains(err.Error(), "--mr flag cannot be combined with positional argument") {
+			t.Errorf("expected --mr/positional conflict error, got %v", err)
+		}
+	})
+
+	t.Run("detach flag cannot be used with MR URL", func(t *testing.T) {
+		err := runAdd([]string{"https://gitlab.com/owner/repo/-/merge_requests/42"}, addOptions{detach: true})
+		if err == nil || !strings.Contains(err.Error(), "--detach cannot be used with PR/MR") {
+			t.Errorf("expected detach/MR error, got %v", err)
+		}
+	})
+
 	t.Run("reset flag can only be used with PR references", func(t *testing.T) {
-		err := runAdd([]string{"feature-branch"}, false, "", "", false, 0, true, "")
-		if err == nil || !strings.Contains(err.Error(), "--reset can only be used with PR references") {
+		err := runAdd([]string{"feature-branch"}, addOptions{reset: true})
+		if err == nil || !strings.Contains(err.Error(), "--reset can only be used with PR/MR references") {
 			t.Errorf("expected --reset/PR error, got %v", err)
 		}
 	})
@@ -152,7 +202,7 @@ func TestRunAdd_PRValidation(t *testing.T) {
 
 func TestRunAdd_DetachBaseValidation(t *testing.T) {
 	t.Run("detach and base cannot be used together", func(t *testing.T) {
-		err := runAdd([]string{"v1.0.0"}, false, "main", "", true, 0, false, "")
+		err := runAdd([]string{"v1.0.0"}, addOptions{baseBranch: "main", detach: true})
 		if err == nil || err.Error() != "--detach and --base cannot be used together" {
 			t.Errorf("expected detach/base error, got %v", err)
 		}
@@ -175,14 +225,14 @@ func TestRunAdd_InputValidation(t *testing.T) {
 	t.Run("whitespace-only branch name", func(t *testing.T) {
 		// Whitespace is trimmed, resulting in empty string
 		// This should fail with "requires branch" error
-		err := runAdd([]string{"   "}, false, "", "", false, 0, false, "")
+		err := runAdd([]string{"   "}, addOptions{})
 		if err == nil || !strings.Contains(err.Error(), "requires branch") {
 			t.Errorf("expected 'requires branch' error for whitespace-only branch name, got %v", err)
 		}
 	})
 
 	t.Run("no args and no --pr flag", func(t *testing.T) {
-		err := runAdd(nil, false, "", "", false, 0, false, "")
+		err := runAdd(nil, addOptions{})
 		if err == nil || !strings.Contains(err.Error(), "requires branch") {
 			t.Errorf("expected 'requires branch' error, got %v", err)
 		}
@@ -192,7 +242,7 @@ func TestRunAdd_InputValidation(t *testing.T) {
 		// The trimming happens, then workspace detection runs
 		// We're not in a workspace, so we'll get that error
 		// But this verifies the trim doesn't crash
-		err := runAdd([]string{"  feature-test  "}, false, "", "", false, 0, false, "")
+		err := runAdd([]string{"  feature-test  "}, addOptions{})
 		if !errors.Is(err, workspace.ErrNotInWorkspace) {
 			t.Errorf("expected ErrNotInWorkspace after trimming, got %v", err)
 		}
@@ -201,14 +251,14 @@ func TestRunAdd_InputValidation(t *testing.T) {
 	t.Run("PR URL with /files suffix works", func(t *testing.T) {
 		// PR URLs with /files suffix should be detected as PR references
 		// Flag validation happens before workspace detection
-		err := runAdd([]string{"https://github.com/owner/repo/pull/123/files"}, false, "main", "", false, 0, false, "")
+		err := runAdd([]string{"https://github.com/owner/repo/pull/123/files"}, addOptions{baseBranch: "main"})
 		if err == nil || !strings.Contains(err.Error(), "--base cannot be used with PR") {
 			t.Errorf("expected base/PR error for URL with /files suffix, got %v", err)
 		}
 	})
 
 	t.Run("PR URL with query params works", func(t *testing.T) {
-		err := runAdd([]string{"https://github.com/owner/repo/pull/123?diff=split"}, false, "", "", true, 0, false, "")
+		err := runAdd([]string{"https://github.com/owner/repo/pull/123?diff=split"}, addOptions{detach: true})
 		if err == nil || !strings.Contains(err.Error(), "--detach cannot be used with PR") {
 			t.Errorf("expected detach/PR error for URL with query params, got %v", err)
 		}
@@ -712,6 +762,9 @@ func TestFindConfigWorktree(t *testing.T) {
 		if got != featDir {
 			t.Errorf("expected %q, got %q", featDir, got)
 		}
+		if got := findDefaultConfigWorktree(bareDir); got != "" {
+			t.Errorf("expected no default config worktree, got %q", got)
+		}
 	})
 
 	t.Run("returns empty when no worktree has .grove.toml", func(t *testing.T) {
@@ -820,7 +873,7 @@ func TestRunAdd_FromValidation(t *testing.T) {
 			t.Fatal(err)
 		}
 
-		err := runAdd([]string{"feature-test"}, false, "", "", false, 0, false, "nonexistent")
+		err := runAdd([]string{"feature-test"}, addOptions{from: "nonexistent"})
 		if err == nil {
 			t.Fatal("expected error for nonexistent --from worktree")
 		}
@@ -857,7 +910,7 @@ func TestRunAdd_FromValidation(t *testing.T) {
 		})
 
 		// Create a new worktree with --from pointing to source
-		err := runAdd([]string{"feature-from-test"}, false, "", "", false, 0, false, "source")
+		err := runAdd([]string{"feature-from-test"}, addOptions{from: "source"})
 		if err != nil {
 			t.Errorf("expected success with valid --from, got %v", err)
 		}
@@ -938,7 +991,7 @@ func TestRunAddFromBranch_WorktreeExistsHint(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	err = runAdd([]string{"main"}, false, "", "", false, 0, false, "")
+	err = runAdd([]string{"main"}, addOptions{})
 	if err == nil {
 		t.Fatal("expected error for existing worktree")
 	}
@@ -1014,7 +1067,7 @@ func TestRunAdd_LinkPatternsAppliedFromOutsideWorktree(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	if err := runAdd([]string{"feat"}, false, "", "", false, 0, false, ""); err != nil {
+	if err := runAdd([]string{"feat"}, addOptions{}); err != nil {
 		t.Fatalf("runAdd: %v", err)
 	}
 
@@ -1096,7 +1149,7 @@ func TestRunAdd_LinkAppliedWhenOnlyNonMainWorktreeHasConfig(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	if err := runAdd([]string{"newwork"}, false, "", "", false, 0, false, ""); err != nil {
+	if err := runAdd([]string{"newwork"}, addOptions{}); err != nil {
 		t.Fatalf("runAdd: %v", err)
 	}
 
@@ -1230,3 +1283,45 @@ func TestCompleteFromWorktree(t *testing.T) {
 		}
 	})
 }
+
+func TestPreserveStrategySuffix(t *testing.T) {
+	tests := []struct {
+		name   string
+		result *workspace.PreserveResult
+		want   string
+	}{
+		{"full copies", &workspace.PreserveResult{Strategies: []fs.CopyStrategy{fs.CopyStrategyCopy}}, ""},
+		{"nothing copied", &workspace.PreserveResult{}, ""},
+		{"reflinks", &workspace.PreserveResult{Strategies: []fs.CopyStrategy{fs.CopyStrategyReflink}, SavedBytes: 1536}, " (reflink, 1.5 KB saved)"},
+		{"mixed", &workspace.PreserveResult{Strategies: []fs.CopyStrategy{fs.CopyStrategyReflink, fs.CopyStrategyCopy}, SavedBytes: 512}, " (reflink+copy, 512 B saved)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := preserveStrategySuffix(tt.result); got != tt.want {
+				t.Errorf("preserveStrategySuffix() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTrustedConfigDir(t *testing.T) {
+	tests := []struct {
+		name           string
+		destWorktree   string
+		configWorktree string
+		want           string
+	}{
+		{"config worktree", "/ws/feature", "/ws/main", "/ws/main"},
+		{"no config worktree", "/ws/feature", "", ""},
+		{"new worktree is the only config worktree", "/ws/pr-7", "/ws/pr-7", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := trustedConfigDir(tt.destWorktree, tt.configWorktree); got != tt.want {
+				t.Errorf("trustedConfigDir(%q, %q) = %q, want %q", tt.destWorktree, tt.configWorktree, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,547 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/sqve/grove/internal/fs"
+	"github.com/sqve/grove/internal/git"
+	"github.com/sqve/grove/internal/logger"
+	"github.com/sqve/grove/internal/styles"
+	"github.com/sqve/grove/internal/workspace"
+)
+
+// adoptKind is how grove adopt brings a checkout into the workspace
+type adoptKind int
+
+const (
+	// adoptMove moves a linked worktree of the workspace into its layout
+	adoptMove adoptKind = iota
+	// adoptImport copies the branches and work of another clone into a new
+	// worktree, leaving the clone untouched
+	adoptImport
+)
+
+// adoptBranchAction is what adopting a clone does with one of its branches
+type adoptBranchAction string
+
+const (
+	adoptBranchAdd         adoptBranchAction = "add"
+	adoptBranchFastForward adoptBranchAction = "fast-forward"
+	adoptBranchKeep        adoptBranchAction = "keep"
+	adoptBranchSkip        adoptBranchAction = "skip"
+)
+
+// adoptBranch is a local branch of an adopted clone
+type adoptBranch struct {
+	name   string
+	commit string
+	action adoptBranchAction
+	reason string // Why the branch is skipped
+}
+
+// adoptPlan describes what grove adopt does with a checkout
+type adoptPlan struct {
+	kind      adoptKind
+	source    string
+	target    string
+	branch    string // Checked out branch, empty when detached
+	branches  []adoptBranch
+	stashes   []git.Stash
+	changes   bool // Tracked files have staged or unstaged changes
+	untracked []string
+}
+
+// NewAdoptCmd creates the adopt command
+func NewAdoptCmd() *cobra.Command {
+	var commit bool
+
+	cmd := &cobra.Command{
+		Use:   "adopt <path>",
+		Short: "Bring an existing worktree or clone into the workspace",
+		Long: `Bring a checkout made outside grove into the workspace.
+
+A worktree created with 'git worktree add' in another directory is moved into
+the workspace layout, and its gitdir pointers are fixed with 'git worktree
+repair'. This also reconnects worktrees whose repository was moved.
+
+A separate clone of the repository is copied into a new worktree for its
+current branch. Its local branches are added to the workspace, or
+fast-forwarded when the workspace is behind; branches that diverged are
+skipped. Stashes, staged and unstaged changes, and untracked files come
+along. The clone itself is left untouched.
+
+Shows what would happen unless --commit is given.
+
+Examples:
+  grove adopt ../repo-hotfix            # Dry-run: show what would be adopted
+  grove adopt ../repo-hotfix --commit   # Adopt it`,
+		Args: cobra.ExactArgs(1),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) > 0 {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+			return nil, cobra.ShellCompDirectiveFilterDirs
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runAdopt(args[0], commit)
+		},
+	}
+
+	cmd.Flags().BoolVar(&commit, "commit", false, "Adopt the checkout (dry-run without this flag)")
+	cmd.Flags().BoolP("help", "h", false, "Help for adopt")
+
+	return cmd
+}
+
+func runAdopt(path string, commit bool) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	bareDir, err := workspace.FindBareDir(cwd)
+	if err != nil {
+		return err
+	}
+
+	source, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+
+	plan, err := planAdopt(bareDir, source)
+	if err != nil {
+		return err
+	}
+	if fs.PathsEqual(cwd, source) || fs.PathHasPrefix(cwd, source) {
+		return fmt.Errorf("cannot adopt the current directory; run from a worktree of the workspace")
+	}
+
+	if !commit {
+		displayAdoptPlan(plan)
+		return nil
+	}
+
+	workspaceRoot := filepath.Dir(bareDir)
+	lockFile := filepath.Join(workspaceRoot, ".grove-worktree.lock")
+	lockHandle, err := workspace.AcquireWorkspaceLock(lockFile)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = lockHandle.Close()
+		_ = os.Remove(lockFile)
+	}()
+
+	if plan.kind == adoptMove {
+		return executeAdoptMove(bareDir, plan)
+	}
+	return executeAdoptImport(bareDir, plan)
+}
+
+// planAdopt works out how source joins the workspace, without changing
+// anything
+func planAdopt(bareDir, source string) (*adoptPlan, error) {
+	workspaceRoot := filepath.Dir(bareDir)
+	if fs.PathsEqual(source, workspaceRoot) || fs.PathsEqual(source, bareDir) || fs.PathHasPrefix(source, bareDir) {
+		return nil, fmt.Errorf("%s is the workspace itself", source)
+	}
+	if !fs.PathExists(filepath.Join(source, ".git")) {
+		return nil, fmt.Errorf("%s is not the top directory of a git checkout", source)
+	}
+
+	if entry := workspaceWorktreeEntry(bareDir, source); entry != "" {
+		return planAdoptMove(bareDir, source, entry)
+	}
+	return planAdoptImport(bareDir, source)
+}
+
+// workspaceWorktreeEntry returns the directory in .bare/worktrees of the
+// linked worktree at path, or an empty string when it belongs to another
+// repository. A worktree whose repository moved points to a directory that no
+// longer exists, so it is matched by the location its entry records instead.
+func workspaceWorktreeEntry(bareDir, path string) string {
+	gitDir, err := git.GetWorktreeGitDir(path)
+	if err != nil || gitDir == "" {
+		return ""
+	}
+
+	worktreesDir := filepath.Join(bareDir, "worktrees")
+	if fs.PathsEqual(filepath.Dir(gitDir), worktreesDir) {
+		if !fs.DirectoryExists(gitDir) {
+			return ""
+		}
+		return gitDir
+	}
+
+	entry := filepath.Join(worktreesDir, filepath.Base(gitDir))
+	recorded, err := os.ReadFile(filepath.Join(entry, "gitdir")) //nolint:gosec // Path derived from the workspace
+	if err != nil {
+		return ""
+	}
+	location := strings.TrimSpace(string(recorded))
+	if !filepath.IsAbs(location) {
+		location = filepath.Join(entry, location)
+	}
+	if !fs.PathsEqual(location, filepath.Join(path, ".git")) {
+		return ""
+	}
+	return entry
+}
+
+func planAdoptMove(bareDir, source, entry string) (*adoptPlan, error) {
+	// Read HEAD from the entry, since git cannot run in a worktree whose
+	// pointers are broken
+	head, err := os.ReadFile(filepath.Join(entry, "HEAD")) //nolint:gosec // Path derived from the workspace
+	if err != nil {
+		return nil, fmt.Errorf("failed to read HEAD of %s: %w", source, err)
+	}
+	branch, _ := strings.CutPrefix(strings.TrimSpace(string(head)), "ref: refs/heads/")
+	name := ""
+	if branch == strings.TrimSpace(string(head)) {
+		branch = ""
+		name = filepath.Base(source) // Detached worktrees keep their directory name
+	}
+
+	target, err := newWorktreePath(bareDir, name, branch)
+	if err != nil {
+		return nil, err
+	}
+	if fs.PathsEqual(source, target) {
+		return nil, fmt.Errorf("%s is already in the workspace layout", source)
+	}
+	if fs.PathExists(target) {
+		return nil, fmt.Errorf("directory %q already exists", target)
+	}
+
+	return &adoptPlan{kind: adoptMove, source: source, target: target, branch: branch}, nil
+}
+
+func planAdoptImport(bareDir, source string) (*adoptPlan, error) {
+	hasOngoing, err := git.HasOngoingOperation(source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check for ongoing operations: %w", err)
+	}
+	if hasOngoing {
+		return nil, fmt.Errorf("cannot adopt: %s has an ongoing merge/rebase/cherry-pick", source)
+	}
+
+	hasConflicts, err := git.HasUnresolvedConflicts(source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check for unresolved conflicts: %w", err)
+	}
+	if hasConflicts {
+		return nil, fmt.Errorf("cannot adopt: %s has unresolved conflicts", source)
+	}
+
+	branch, detached, err := git.GetCurrentBranchOrDetached(source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current branch: %w", err)
+	}
+	if detached {
+		return nil, fmt.Errorf("cannot adopt: %s is in detached HEAD state; check out a branch first", source)
+	}
+
+	sourceCommits, err := git.ListBranchCommits(source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list branches of %s: %w", source, err)
+	}
+	if _, ok := sourceCommits[branch]; !ok {
+		return nil, fmt.Errorf("cannot adopt: %s has no commits", source)
+	}
+	workspaceCommits, err := git.ListBranchCommits(bareDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list branches: %w", err)
+	}
+
+	infos, err := git.ListWorktreesWithInfo(bareDir, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list worktrees: %w", err)
+	}
+	checkedOut := make(map[string]string, len(infos))
+	for _, info := range infos {
+		if info.Branch != "" {
+			checkedOut[info.Branch] = info.Path
+		}
+	}
+	if path, ok := checkedOut[branch]; ok {
+		return nil, fmt.Errorf("cannot adopt: branch %s is already checked out at %s", branch, path)
+	}
+
+	plan := &adoptPlan{kind: adoptImport, source: source, branch: branch}
+	for _, name := range slices.Sorted(maps.Keys(sourceCommits)) {
+		b := planAdoptBranch(bareDir, source, name, sourceCommits[name], workspaceCommits, checkedOut)
+		if name == branch && b.action == adoptBranchSkip {
+			return nil, fmt.Errorf("cannot adopt: branch %s %s", branch, b.reason)
+		}
+		plan.branches = append(plan.branches, b)
+	}
+
+	if plan.target, err = newWorktreePath(bareDir, "", branch); err != nil {
+		return nil, err
+	}
+	if fs.PathExists(plan.target) {
+		return nil, fmt.Errorf("directory %q already exists", plan.target)
+	}
+
+	if plan.stashes, err = git.ListStashes(source); err != nil {
+		return nil, fmt.Errorf("failed to list stashes: %w", err)
+	}
+	if _, plan.changes, err = git.CheckGitChanges(source); err != nil {
+		return nil, fmt.Errorf("failed to check for uncommitted changes: %w", err)
+	}
+	if plan.untracked, err = git.ListUntrackedFiles(source); err != nil {
+		return nil, err
+	}
+
+	return plan, nil
+}
+
+// planAdoptBranch decides what happens to a branch of the clone. Commits
+// the workspace lacks are looked up in the clone, and the other way around,
+// so nothing needs to be fetched to decide.
+func planAdoptBranch(bareDir, source, name, commit string, workspaceCommits, checkedOut map[string]string) adoptBranch {
+	b := adoptBranch{name: name, commit: commit}
+	current, ok := workspaceCommits[name]
+	switch {
+	case !ok:
+		b.action = adoptBranchAdd
+	case current == commit:
+		b.action = adoptBranchKeep
+	case git.IsAncestor(source, current, commit):
+		if path, ok := checkedOut[name]; ok {
+			b.action = adoptBranchSkip
+			b.reason = fmt.Sprintf("is checked out at %s", path)
+		} else {
+			b.action = adoptBranchFastForward
+		}
+	case git.IsAncestor(bareDir, commit, current):
+		b.action = adoptBranchKeep
+	default:
+		b.action = adoptBranchSkip
+		b.reason = "has diverged from the workspace"
+	}
+	return b
+}
+
+func displayAdoptPlan(plan *adoptPlan) {
+	if plan.kind == adoptMove {
+		logger.Info("Would move worktree %s to %s", styles.RenderPath(plan.source), styles.RenderPath(plan.target))
+		fmt.Println()
+		logger.Info("Run with --commit to adopt.")
+		return
+	}
+
+	logger.Info("Would adopt %s as worktree %s", styles.RenderPath(plan.source), styles.RenderPath(plan.target))
+
+	groups := []struct {
+		action adoptBranchAction
+		verb   string
+	}{
+		{adoptBranchAdd, "add"},
+		{adoptBranchFastForward, "fast-forward"},
+		{adoptBranchSkip, "skip"},
+	}
+	for _, group := range groups {
+		var items []string
+		for _, b := range plan.branches {
+			if b.action != group.action {
+				continue
+			}
+			if b.reason != "" {
+				items = append(items, fmt.Sprintf("%s (%s)", b.name, b.reason))
+			} else {
+				items = append(items, b.name)
+			}
+		}
+		if len(items) == 0 {
+			continue
+		}
+		if group.action == adoptBranchSkip {
+			logger.Warning("Would %s %s:", group.verb, countNoun(len(items), "branch", "branches"))
+		} else {
+			logger.Info("Would %s %s:", group.verb, countNoun(len(items), "branch", "branches"))
+		}
+		for _, item := range items {
+			logger.Dimmed("    %s", item)
+		}
+	}
+
+	if len(plan.stashes) > 0 {
+		logger.Info("Would copy %s", countNoun(len(plan.stashes), "stash", "stashes"))
+	}
+	switch {
+	case plan.changes && len(plan.untracked) > 0:
+		logger.Info("Would carry over uncommitted changes and %s", countNoun(len(plan.untracked), "untracked file", "untracked files"))
+	case plan.changes:
+		logger.Info("Would carry over uncommitted changes")
+	case len(plan.untracked) > 0:
+		logger.Info("Would carry over %s", countNoun(len(plan.untracked), "untracked file", "untracked files"))
+	}
+
+	fmt.Println()
+	logger.Info("Run with --commit to adopt. %s is left unchanged.", styles.RenderPath(plan.source))
+}
+
+func countNoun(n int, singular, plural string) string {
+	if n == 1 {
+		return fmt.Sprintf("1 %s", singular)
+	}
+	return fmt.Sprintf("%d %s", n, plural)
+}
+
+func executeAdoptMove(bareDir string, plan *adoptPlan) error {
+	workspaceRoot := filepath.Dir(bareDir)
+
+	if err := os.MkdirAll(filepath.Dir(plan.target), fs.DirGit); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(plan.target), err)
+	}
+	if err := fs.RenameWithFallback(plan.source, plan.target); err != nil {
+		return fmt.Errorf("failed to move worktree directory: %w", err)
+	}
+
+	if err := git.RepairWorktree(bareDir, plan.target); err != nil {
+		// Move it back, so the worktree is where its pointers expect it
+		if restoreErr := fs.RenameWithFallback(plan.target, plan.source); restoreErr != nil {
+			logger.Error("Failed to restore directory: %v", restoreErr)
+		}
+		workspace.RemoveEmptyParents(plan.target, workspaceRoot)
+		return fmt.Errorf("failed to repair worktree: %w", err)
+	}
+
+	logger.Success("Moved worktree %s to %s", styles.RenderPath(plan.source), styles.RenderPath(plan.target))
+	return nil
+}
+
+func executeAdoptImport(bareDir string, plan *adoptPlan) error {
+	var refspecs []string
+	var added []string
+	for _, b := range plan.branches {
+		switch b.action {
+		case adoptBranchAdd:
+			added = append(added, b.name)
+		case adoptBranchFastForward:
+		default:
+			continue
+		}
+		refspecs = append(refspecs, fmt.Sprintf("refs/heads/%s:refs/heads/%s", b.name, b.name))
+	}
+
+	spin := logger.StartSpinner(fmt.Sprintf("Fetching from %s...", plan.source))
+
+	if err := git.FetchRefs(bareDir, plan.source, refspecs); err != nil {
+		spin.StopWithError("Failed to fetch branches")
+		return fmt.Errorf("failed to fetch branches: %w", err)
+	}
+	for _, name := range added {
+		copyAdoptedUpstream(bareDir, plan.source, name)
+	}
+
+	// Uncommitted changes travel as a stash commit, like the stashes
+	// themselves, without touching the clone
+	work := ""
+	if plan.changes {
+		var err error
+		if work, err = git.CreateStash(plan.source); err != nil {
+			spin.StopWithError("Failed to record uncommitted changes")
+			return fmt.Errorf("failed to record uncommitted changes: %w", err)
+		}
+	}
+	var commits []string
+	for _, stash := range plan.stashes {
+		commits = append(commits, stash.Commit)
+	}
+	if work != "" {
+		commits = append(commits, work)
+	}
+	if err := git.FetchRefs(bareDir, plan.source, commits); err != nil {
+		spin.StopWithError("Failed to fetch stashes")
+		return fmt.Errorf("failed to fetch stashes: %w", err)
+	}
+	spin.Stop()
+
+	if err := git.CreateWorktree(bareDir, plan.target, plan.branch, true); err != nil {
+		return fmt.Errorf("failed to create worktree: %w", err)
+	}
+
+	// Oldest first, so the stash list keeps its order
+	for i := len(plan.stashes) - 1; i >= 0; i-- {
+		stash := plan.stashes[i]
+		if err := git.StoreStash(plan.target, stash.Commit, stash.Message); err != nil {
+			logger.Warning("Failed to copy stash %q: %v", stash.Message, err)
+		}
+	}
+
+	if work != "" {
+		if err := git.ApplyStash(plan.target, work); err != nil {
+			message := fmt.Sprintf("grove adopt: uncommitted changes from %s", plan.source)
+			if storeErr := git.StoreStash(plan.target, work, message); storeErr != nil {
+				return fmt.Errorf("failed to carry over uncommitted changes: %w", err)
+			}
+			logger.Warning("Could not apply uncommitted changes, kept them as a stash: %v", err)
+		}
+	}
+
+	if failed := copyUntrackedFiles(plan.source, plan.target, plan.untracked); failed > 0 {
+		logger.Warning("Failed to copy %s", countNoun(failed, "untracked file", "untracked files"))
+	}
+	logPreserveResult(preserveFilesFromSource(plan.source, plan.target, findConfigWorktree(bareDir)))
+
+	logger.Emit(logger.Event{Type: logger.EventWorktreeCreated, Worktree: plan.target, Branch: plan.branch})
+	logger.Success("Adopted %s as worktree %s", styles.RenderPath(plan.source), styles.RenderPath(plan.target))
+	logger.Info("%s is left unchanged; delete it once you have checked the new worktree.", styles.RenderPath(plan.source))
+	return nil
+}
+
+// copyAdoptedUpstream tracks the same upstream as the clone did, when the
+// workspace has a remote of that name
+func copyAdoptedUpstream(bareDir, source, branch string) {
+	remote, merge := git.GetBranchUpstream(source, branch)
+	if remote == "" || merge == "" {
+		return
+	}
+	if exists, err := git.RemoteExists(bareDir, remote); err != nil || !exists {
+		logger.Debug("Not tracking %s for %s: no remote %q in the workspace", merge, branch, remote)
+		return
+	}
+	if err := git.SetBranchUpstream(bareDir, branch, remote, merge); err != nil {
+		logger.Warning("Failed to set upstream of %s: %v", branch, err)
+	}
+}
+
+// copyUntrackedFiles copies files from source to the same paths in dest,
+// keeping files dest already has. Returns the number of files that failed.
+func copyUntrackedFiles(source, dest string, files []string) int {
+	failed := 0
+	for _, file := range files {
+		src := filepath.Join(source, file)
+		dst := filepath.Join(dest, file)
+
+		info, err := os.Lstat(src)
+		if err == nil {
+			err = os.MkdirAll(filepath.Dir(dst), fs.DirGit)
+		}
+		if err == nil {
+			if info.Mode()&os.ModeSymlink != 0 {
+				var target string
+				if target, err = os.Readlink(src); err == nil {
+					err = os.Symlink(target, dst)
+				}
+			} else {
+				err = fs.CopyFileExclusive(src, dst, info.Mode().Perm())
+			}
+		}
+		if err != nil && !errors.Is(err, os.ErrExist) {
+			logger.Debug("Failed to copy untracked file %s: %v", file, err)
+			failed++
+		}
+	}
+	return failed
+}
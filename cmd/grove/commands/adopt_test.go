@@ -0,0 +1,182 @@
+package commands
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sqve/grove/internal/fs"
+	"github.com/sqve/grove/internal/testutil"
+	testgit "github.com/sqve/grove/internal/testutil/git"
+	"github.com/sqve/grove/internal/workspace"
+)
+
+func TestNewAdoptCmd(t *testing.T) {
+	cmd := NewAdoptCmd()
+
+	if cmd.Use != "adopt <path>" {
+		t.Errorf("expected Use 'adopt <path>', got %q", cmd.Use)
+	}
+	if cmd.Short == "" {
+		t.Error("expected Short description")
+	}
+	if err := cmd.Args(cmd, []string{}); err == nil {
+		t.Error("expected error without a path")
+	}
+	if cmd.Flags().Lookup("commit") == nil {
+		t.Error("expected --commit flag")
+	}
+}
+
+func TestRunAdopt_NotInWorkspace(t *testing.T) {
+	defer testutil.SaveCwd(t)()
+	testutil.Chdir(t, testutil.TempDir(t))
+
+	err := runAdopt("elsewhere", false)
+	if !errors.Is(err, workspace.ErrNotInWorkspace) {
+		t.Errorf("expected ErrNotInWorkspace, got %v", err)
+	}
+}
+
+func TestPlanAdopt_Errors(t *testing.T) {
+	ws := testgit.NewGroveWorkspace(t, "main")
+
+	plain := filepath.Join(testutil.TempDir(t), "plain")
+	if err := os.MkdirAll(plain, fs.DirGit); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		source  string
+		wantErr string
+	}{
+		{"workspace root", ws.Dir, "is the workspace itself"},
+		{"bare repository", ws.BareDir, "is the workspace itself"},
+		{"not a checkout", plain, "is not the top directory of a git checkout"},
+		{"worktree in layout", ws.WorktreePath("main"), "is already in the workspace layout"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := planAdopt(ws.BareDir, tt.source)
+			testutil.AssertErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestPlanAdopt_ScatteredWorktree(t *testing.T) {
+	ws := testgit.NewGroveWorkspace(t, "main")
+
+	source := filepath.Join(testutil.TempDir(t), "elsewhere")
+	ws.RunOutput("worktree", "add", "-b", "feat/scattered", source)
+	testgit.CleanupWorktree(t, ws.BareDir, source)
+
+	plan, err := planAdopt(ws.BareDir, source)
+	if err != nil {
+		t.Fatalf("planAdopt failed: %v", err)
+	}
+	if plan.kind != adoptMove {
+		t.Errorf("expected a move, got kind %d", plan.kind)
+	}
+	if plan.branch != "feat/scattered" {
+		t.Errorf("expected branch feat/scattered, got %q", plan.branch)
+	}
+	if want := filepath.Join(ws.Dir, "feat-scattered"); !fs.PathsEqual(plan.target, want) {
+		t.Errorf("expected target %s, got %s", want, plan.target)
+	}
+}
+
+func TestPlanAdopt_Clone(t *testing.T) {
+	ws := testgit.NewGroveWorkspace(t, "main", "diverged")
+	ws.RunOutput("branch", "shared", "main")
+	mainPath := ws.WorktreePath("main")
+
+	clone := filepath.Join(testutil.TempDir(t), "clone")
+	testutil.MustExec(t, mainPath, "git", "clone", "--quiet", ws.BareDir, clone)
+	testutil.MustExec(t, clone, "git", "config", "user.email", "test@example.com")
+	testutil.MustExec(t, clone, "git", "config", "user.name", "Test User")
+	testutil.MustExec(t, clone, "git", "config", "commit.gpgsign", "false")
+
+	// shared has no worktree in the workspace, so it can be fast-forwarded
+	testutil.MustExec(t, clone, "git", "checkout", "--quiet", "-b", "shared", "origin/shared")
+	testutil.MustExec(t, clone, "git", "commit", "--quiet", "--allow-empty", "-m", "ahead")
+
+	// diverged gets a different commit on each side
+	testutil.MustExec(t, clone, "git", "checkout", "--quiet", "-b", "diverged", "origin/diverged")
+	testutil.MustExec(t, clone, "git", "commit", "--quiet", "--allow-empty", "-m", "clone")
+	testutil.MustExec(t, ws.WorktreePath("diverged"), "git", "commit", "--quiet", "--allow-empty", "-m", "workspace")
+
+	testutil.MustExec(t, clone, "git", "checkout", "--quiet", "-b", "feat/clone")
+	testutil.WriteFile(t, filepath.Join(clone, "notes.txt"), "untracked")
+
+	plan, err := planAdopt(ws.BareDir, clone)
+	if err != nil {
+		t.Fatalf("planAdopt failed: %v", err)
+	}
+	if plan.kind != adoptImport {
+		t.Errorf("expected an import, got kind %d", plan.kind)
+	}
+	if want := filepath.Join(ws.Dir, "feat-clone"); !fs.PathsEqual(plan.target, want) {
+		t.Errorf("expected target %s, got %s", want, plan.target)
+	}
+
+	want := map[string]adoptBranchAction{
+		"diverged":   adoptBranchSkip,
+		"feat/clone": adoptBranchAdd,
+		"main":       adoptBranchKeep,
+		"shared":     adoptBranchFastForward,
+	}
+	got := make(map[string]adoptBranchAction)
+	for _, b := range plan.branches {
+		got[b.name] = b.action
+	}
+	for name, action := range want {
+		if got[name] != action {
+			t.Errorf("branch %s: expected %s, got %s", name, action, got[name])
+		}
+	}
+
+	if len(plan.untracked) != 1 || plan.untracked[0] != "notes.txt" {
+		t.Errorf("expected untracked notes.txt, got %v", plan.untracked)
+	}
+	if plan.changes {
+		t.Error("expected no uncommitted changes")
+	}
+}
+
+func TestCountNoun(t *testing.T) {
+	tests := []struct {
+		n    int
+		want string
+	}{
+		{0, "0 branches"},
+		{1, "1 branch"},
+		{3, "3 branches"},
+	}
+
+	for _, tt := range tests {
+		if got := countNoun(tt.n, "branch", "branches"); got != tt.want {
+			t.Errorf("countNoun(%d) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestCopyUntrackedFiles(t *testing.T) {
+	source := testutil.TempDir(t)
+	dest := testutil.TempDir(t)
+
+	testutil.WriteFile(t, filepath.Join(source, "notes.txt"), "from source")
+	testutil.WriteFile(t, filepath.Join(source, "nested", "todo.txt"), "nested")
+	testutil.WriteFile(t, filepath.Join(source, "kept.txt"), "from source")
+	testutil.WriteFile(t, filepath.Join(dest, "kept.txt"), "already there")
+
+	if failed := copyUntrackedFiles(source, dest, []string{"notes.txt", "nested/todo.txt", "kept.txt", "missing.txt"}); failed != 1 {
+		t.Errorf("expected 1 failed file, got %d", failed)
+	}
+
+	testutil.AssertFileContent(t, filepath.Join(dest, "notes.txt"), "from source")
+	testutil.AssertFileContent(t, filepath.Join(dest, "nested", "todo.txt"), "nested")
+	testutil.AssertFileContent(t, filepath.Join(dest, "kept.txt"), "already there")
+}
@@ -0,0 +1,210 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/sqve/grove/internal/git"
+	"github.com/sqve/grove/internal/logger"
+	"github.com/sqve/grove/internal/styles"
+	"github.com/sqve/grove/internal/workspace"
+)
+
+// carrySource is the uncommitted changes of a worktree, recorded as a stash
+// commit to merge into another worktree
+type carrySource struct {
+	path   string
+	name   string
+	paths  []string
+	commit string
+	files  int
+}
+
+// carryResult is the outcome of moving changes into a new worktree
+type carryResult struct {
+	source    *carrySource
+	target    string
+	conflicts []string
+	err       error
+}
+
+// NewCarryCmd creates the carry command
+func NewCarryCmd() *cobra.Command {
+	var move bool
+
+	cmd := &cobra.Command{
+		Use:   "carry <from> <to> [paths...]",
+		Short: "Carry uncommitted changes to another worktree",
+		Long: `Carry the uncommitted changes of one worktree to another.
+
+Tracked and untracked changes are merged into the target with a three-way
+merge against the commit they were made on, so they apply across branches.
+Staged changes are staged again and untracked files stay untracked.
+Paths, relative to the worktree root, limit which changes are carried.
+Ignored files stay behind.
+
+Conflicts are left as conflict markers in the target and listed. With
+--move, the changes are removed from the source once they apply without
+conflicts.
+
+To start a new worktree from the current changes, use 'grove add --carry'.
+
+Examples:
+  grove carry main feat-auth              # Copy all changes
+  grove carry main feat-auth --move       # Move them
+  grove carry main feat-auth src/auth.go  # Only changes to these paths
+  grove add feat/auth --carry             # Move them into a new worktree`,
+		Args: cobra.MinimumNArgs(2),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) >= 2 {
+				return nil, cobra.ShellCompDirectiveDefault
+			}
+			return completeCompareArgs(cmd, args, toComplete)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runCarry(args[0], args[1], args[2:], move)
+		},
+	}
+
+	cmd.Flags().BoolVar(&move, "move", false, "Remove the changes from the source once carried")
+	cmd.Flags().BoolP("help", "h", false, "Help for carry")
+
+	return cmd
+}
+
+func runCarry(fromTarget, toTarget string, paths []string, move bool) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	bareDir, err := workspace.FindBareDir(cwd)
+	if err != nil {
+		return err
+	}
+
+	infos, err := git.ListWorktreesWithInfo(bareDir, true)
+	if err != nil {
+		return fmt.Errorf("failed to list worktrees: %w", err)
+	}
+	from, to, err := findWorktreePair(infos, fromTarget, toTarget)
+	if err != nil {
+		return err
+	}
+
+	source, err := recordCarry(from.Path, git.WorktreeName(infos, from), paths)
+	if err != nil {
+		return err
+	}
+	toName := git.WorktreeName(infos, to)
+	conflicts, err := applyCarry(source, to.Path)
+	if err != nil {
+		return fmt.Errorf("failed to carry changes to %s: %w", toName, err)
+	}
+	if len(conflicts) > 0 {
+		reportCarryConflicts(source, toName, conflicts, move)
+		return fmt.Errorf("resolve the conflicts in %s", styles.RenderPath(to.Path))
+	}
+
+	if !move {
+		logger.Success("Carried %s from %s to %s", countNoun(source.files, "changed file", "changed files"), source.name, toName)
+		return nil
+	}
+	if err := git.DiscardChanges(source.path, source.paths); err != nil {
+		return fmt.Errorf("carried changes to %s, but failed to remove them from %s: %w", toName, source.name, err)
+	}
+	logger.Success("Moved %s from %s to %s", countNoun(source.files, "changed file", "changed files"), source.name, toName)
+	return nil
+}
+
+// recordCarry records the uncommitted changes of a worktree, or only those
+// under paths, without touching it
+func recordCarry(worktreePath, name string, paths []string) (*carrySource, error) {
+	message := "grove carry from " + name
+	var commit string
+	var err error
+	if len(paths) > 0 {
+		commit, err = git.CreatePathSnapshot(worktreePath, message, paths)
+	} else {
+		commit, err = git.CreateSnapshot(worktreePath, message, nil)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to record changes of %s: %w", name, err)
+	}
+	if commit == "" {
+		return nil, fmt.Errorf("no uncommitted changes to carry in %s", name)
+	}
+
+	source := &carrySource{path: worktreePath, name: name, paths: paths, commit: commit}
+	if files, err := git.SnapshotFiles(worktreePath, commit); err == nil {
+		source.files = len(files)
+	}
+	return source, nil
+}
+
+// applyCarry merges recorded changes into a worktree and returns the files
+// left with conflict markers. Staged changes are staged again unless they
+// conflict with the index of the worktree, and untracked files stay
+// untracked.
+func applyCarry(source *carrySource, worktreePath string) ([]string, error) {
+	if conflicted, err := git.HasUnresolvedConflicts(worktreePath); err == nil && conflicted {
+		return nil, fmt.Errorf("it has unresolved conflicts")
+	}
+
+	apply := git.ApplyStash
+	if !git.StashIndexApplies(worktreePath, source.commit) {
+		logger.Warning("Staged changes from %s conflict with the index of %s; carrying them unstaged", source.name, filepath.Base(worktreePath))
+		apply = git.MergeStash
+	}
+	if err := apply(worktreePath, source.commit); err != nil {
+		conflicts, listErr := git.ListConflictedFiles(worktreePath)
+		if listErr != nil || len(conflicts) == 0 {
+			return nil, err
+		}
+		return conflicts, nil
+	}
+	return nil, nil
+}
+
+func reportCarryConflicts(source *carrySource, toName string, conflicts []string, move bool) {
+	logger.Warning("Carried %s from %s to %s with conflicts in:", countNoun(source.files, "changed file", "changed files"), source.name, toName)
+	for _, file := range conflicts {
+		logger.ListSubItem("%s", file)
+	}
+	if move {
+		logger.Info("Left the changes in %s as well, since they did not apply cleanly", source.name)
+	}
+}
+
+// moveCarry merges recorded changes into a new worktree and removes them from
+// their source when they apply cleanly. Failures are reported rather than
+// returned, since the worktree exists by then and the source keeps its
+// changes. Returns nil without changes to carry.
+func moveCarry(source *carrySource, worktreePath string) *carryResult {
+	if source == nil {
+		return nil
+	}
+
+	result := &carryResult{source: source, target: filepath.Base(worktreePath)}
+	if result.conflicts, result.err = applyCarry(source, worktreePath); result.err != nil || len(result.conflicts) > 0 {
+		return result
+	}
+	if err := git.DiscardChanges(source.path, source.paths); err != nil {
+		result.err = fmt.Errorf("carried them, but failed to remove them from %s: %w", source.name, err)
+	}
+	return result
+}
+
+func logCarryResult(result *carryResult) {
+	switch {
+	case result == nil:
+	case result.err != nil:
+		logger.Warning("Failed to carry changes from %s: %v", result.source.name, result.err)
+	case len(result.conflicts) > 0:
+		reportCarryConflicts(result.source, result.target, result.conflicts, true)
+	default:
+		logger.ListSubItem("moved %s from %s", countNoun(result.source.files, "changed file", "changed files"), result.source.name)
+	}
+}
@@ -0,0 +1,41 @@
+package commands
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/sqve/grove/internal/testutil"
+	"github.com/sqve/grove/internal/workspace"
+)
+
+func TestNewCarryCmd(t *testing.T) {
+	cmd := NewCarryCmd()
+
+	if cmd.Use != "carry <from> <to> [paths...]" {
+		t.Errorf("expected Use 'carry <from> <to> [paths...]', got %q", cmd.Use)
+	}
+	if err := cmd.Args(cmd, []string{"main"}); err == nil {
+		t.Error("expected error for a single worktree")
+	}
+	if err := cmd.Args(cmd, []string{"main", "feat-auth", "src"}); err != nil {
+		t.Errorf("expected paths to be accepted, got %v", err)
+	}
+	if cmd.Flags().Lookup("move") == nil {
+		t.Error("expected --move flag")
+	}
+}
+
+func TestRunCarry_NotInWorkspace(t *testing.T) {
+	defer testutil.SaveCwd(t)()
+	testutil.Chdir(t, testutil.TempDir(t))
+
+	if err := runCarry("main", "feat-auth", nil, false); !errors.Is(err, workspace.ErrNotInWorkspace) {
+		t.Errorf("expected ErrNotInWorkspace, got %v", err)
+	}
+}
+
+func TestMoveCarry_NoSource(t *testing.T) {
+	if result := moveCarry(nil, testutil.TempDir(t)); result != nil {
+		t.Errorf("expected no result without changes to carry, got %+v", result)
+	}
+}
@@ -13,6 +13,8 @@ import (
 	"github.com/sqve/grove/internal/fs"
 	"github.com/sqve/grove/internal/git"
 	"github.com/sqve/grove/internal/github"
+	"github.com/sqve/grove/internal/gitlab"
+	"github.com/sqve/grove/internal/hooks"
 	"github.com/sqve/grove/internal/logger"
 	"github.com/sqve/grove/internal/styles"
 	"github.com/sqve/grove/internal/workspace"
@@ -30,21 +32,35 @@ func NewCloneCmd() *cobra.Command {
 	var branches string
 	var verbose bool
 	var shallow bool
+	var manifest string
 
 	cloneCmd := &cobra.Command{
-		Use:   "clone <url|PR-URL> [directory]",
+		Use:   "clone <url|PR-URL|MR-URL> [directory]",
 		Short: "Clone a repository and create a grove workspace",
 		Long: `Clone a repository into a grove workspace.
 
-Clones from a repository URL or GitHub pull request URL.
-From a PR URL, creates a worktree for the PR's branch.
+Clones from a repository URL, GitHub pull request URL, or GitLab merge
+request URL. From a PR or MR URL, creates a worktree for its branch.
+
+With --manifest, clones every repo listed in a grove.workspace.toml into its
+directory of the project, skipping repos that are already cloned.
 
 Examples:
   grove clone https://github.com/owner/repo                  # Clone repo
   grove clone https://github.com/owner/repo my-project       # Clone to directory
-  grove clone https://github.com/owner/repo/pull/123         # Clone and checkout PR`,
-		Args: cobra.RangeArgs(1, 2),
+  grove clone https://github.com/owner/repo/pull/123         # Clone and checkout PR
+  grove clone https://gitlab.com/group/repo/-/merge_requests/42  # Clone and checkout MR
+  grove clone --manifest grove.workspace.toml                # Clone a project's repos`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if cmd.Flags().Changed("manifest") {
+				return cobra.NoArgs(cmd, args)
+			}
+			return cobra.RangeArgs(1, 2)(cmd, args)
+		},
 		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if cmd.Flags().Changed("branches") && cmd.Flags().Changed("manifest") {
+				return fmt.Errorf("--branches cannot be used with --manifest")
+			}
 			if cmd.Flags().Changed("branches") && len(args) == 0 {
 				return fmt.Errorf("--branches requires a repository URL to be specified")
 			}
@@ -60,6 +76,9 @@ Examples:
 			if cmd.Flags().Changed("branches") && (branches == "" || branches == `""`) {
 				return fmt.Errorf("no branches specified")
 			}
+			if manifest != "" {
+				return runCloneManifest(manifest, verbose, shallow)
+			}
 
 			urlOrPR := args[0]
 
@@ -73,32 +92,18 @@ Examples:
 				return runCloneFromPR(urlOrPR, targetDir, verbose, shallow)
 			}
 
-			// Check if this is a GitHub URL and gh is available - use gh for protocol preference
-			if github.IsGitHubURL(urlOrPR) {
-				if err := github.CheckGhAvailable(); err == nil {
-					ref, err := github.ParseRepoURL(urlOrPR)
-					if err != nil {
-						return err
-					}
-
-					return runCloneFromGitHub(ref.Owner, ref.Repo, targetDir, branches, verbose, shallow)
-				}
-
-				logger.Debug("gh CLI not available, using direct clone (may not respect protocol preference)")
-			}
-
-			// Regular clone (non-GitHub URLs or GitHub without gh)
-			if err := workspace.CloneAndInitialize(urlOrPR, targetDir, branches, verbose, shallow); err != nil {
-				return err
+			// Check if this is a GitLab MR URL
+			if gitlab.IsMRURL(urlOrPR) {
+				return runCloneFromMR(urlOrPR, targetDir, verbose, shallow)
 			}
 
-			logger.Success("Cloned repository to %s", styles.RenderPath(targetDir))
-			return nil
+			return runCloneRepository(urlOrPR, targetDir, branches, verbose, shallow)
 		},
 	}
 	cloneCmd.Flags().StringVar(&branches, "branches", "", "Comma-separated list of branches to create worktrees for")
 	cloneCmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Show git output")
 	cloneCmd.Flags().BoolVar(&shallow, "shallow", false, "Create a shallow clone (depth=1)")
+	cloneCmd.Flags().StringVar(&manifest, "manifest", "", "Clone the repos of a grove.workspace.toml (file or its directory)")
 	cloneCmd.Flags().BoolP("help", "h", false, "Help for clone")
 
 	_ = cloneCmd.RegisterFlagCompletionFunc("branches", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -108,12 +113,80 @@ Examples:
 	return cloneCmd
 }
 
-func runCloneFromPR(prURL, targetDir string, verbose, shallow bool) error {
-	// Check gh is available
-	if err := github.CheckGhAvailable(); err != nil {
+// runCloneRepository clones a repository URL into a new workspace at
+// targetDir
+func runCloneRepository(url, targetDir, branches string, verbose, shallow bool) error {
+	// Check if this is a GitHub URL and gh is available - use gh for protocol preference
+	if github.IsGitHubURL(url) {
+		if err := github.CheckGhAvailable(); err == nil {
+			ref, err := github.ParseRepoURL(url)
+			if err != nil {
+				return err
+			}
+
+			return runCloneFromGitHub(ref.Owner, ref.Repo, targetDir, branches, verbose, shallow)
+		}
+
+		logger.Debug("gh CLI not available, using direct clone (may not respect protocol preference)")
+	}
+
+	// Regular clone (non-GitHub URLs or GitHub without gh)
+	if err := workspace.CloneAndInitialize(url, targetDir, branches, verbose, shallow); err != nil {
 		return err
 	}
 
+	logger.Success("Cloned repository to %s", styles.RenderPath(targetDir))
+	runCloneHooks(targetDir, "", "")
+	return nil
+}
+
+// runCloneManifest clones the repositories of a project that are not cloned
+// yet. A failed clone does not stop the others.
+func runCloneManifest(path string, verbose, shallow bool) error {
+	root, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	if !fs.DirectoryExists(root) {
+		if filepath.Base(root) != workspace.ManifestFileName {
+			return fmt.Errorf("%s is not a %s file", path, workspace.ManifestFileName)
+		}
+		root = filepath.Dir(root)
+	}
+
+	project, err := workspace.LoadProject(root)
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, repo := range project.Repos {
+		switch {
+		case repo.IsCloned():
+			logger.Info("Skipping %s: already cloned at %s", repo.Name, styles.RenderPath(repo.Path))
+			continue
+		case repo.URL == "":
+			logger.Warning("Skipping %s: no url in %s", repo.Name, workspace.ManifestFileName)
+			continue
+		case github.IsPRURL(repo.URL) || gitlab.IsMRURL(repo.URL):
+			logger.Error("%s: url must be a repository, not a pull or merge request", repo.Name)
+			failed++
+			continue
+		}
+
+		if err := runCloneRepository(repo.URL, repo.Path, "", verbose, shallow); err != nil {
+			logger.Error("%s: %v", repo.Name, err)
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to clone %d repo(s)", failed)
+	}
+	return nil
+}
+
+func runCloneFromPR(prURL, targetDir string, verbose, shallow bool) error {
 	// Parse PR URL
 	ref, err := github.ParsePRReference(prURL)
 	if err != nil {
@@ -125,6 +198,12 @@ func runCloneFromPR(prURL, targetDir string, verbose, shallow bool) error {
 		return fmt.Errorf("PR URL must include owner and repo")
 	}
 
+	// Without gh, clone over https and check out the pull ref instead
+	if err := github.CheckGhAvailable(); err != nil {
+		logger.Info("%v; fetching %s from origin instead", err, github.PRHeadRef(ref.Number))
+		return runCloneFromPRHeadRef(ref, targetDir, verbose, shallow)
+	}
+
 	// Determine workspace directory
 	workspaceDir := targetDir
 	if workspaceDir == "" {
@@ -265,8 +344,61 @@ func runCloneFromPR(prURL, targetDir string, verbose, shallow bool) error {
 		}
 	}
 
+	logger.Emit(logger.Event{Type: logger.EventWorktreeCreated, Worktree: worktreePath, Branch: branch})
 	logger.Success("Cloned repository to %s", styles.RenderPath(workspaceDir))
 	logger.ListSubItem("fetched PR #%d", ref.Number)
+	runCloneHooks(workspaceDir, worktreePath, branch)
+	return nil
+}
+
+// runCloneFromPRHeadRef clones a PR's repository without gh. The PR is checked
+// out from refs/pull/<n>/head as pr-<n>, with no fork remote or upstream.
+func runCloneFromPRHeadRef(ref *github.PRRef, targetDir string, verbose, shallow bool) error {
+	if err := workspace.CloneAndInitialize(github.CloneURL(ref.Owner, ref.Repo), targetDir, "", verbose, shallow); err != nil {
+		return err
+	}
+
+	bareDir := filepath.Join(targetDir, ".bare")
+	branch := prBranchName(ref.Number, nil)
+	worktreePath := filepath.Join(targetDir, branch)
+	if err := checkoutFetchedRef(bareDir, worktreePath, branch, "origin", github.PRHeadRef(ref.Number), fmt.Sprintf("PR #%d", ref.Number), false); err != nil {
+		return fmt.Errorf("cloned repository to %s, but failed to check out PR #%d: %w", targetDir, ref.Number, err)
+	}
+
+	logger.Emit(logger.Event{Type: logger.EventWorktreeCreated, Worktree: worktreePath, Branch: branch})
+	logger.Success("Cloned repository to %s", styles.RenderPath(targetDir))
+	logger.ListSubItem("fetched PR #%d", ref.Number)
+	runCloneHooks(targetDir, worktreePath, branch)
+	return nil
+}
+
+func runCloneFromMR(mrURL, targetDir string, verbose, shallow bool) error {
+	ref, err := gitlab.ParseMRURL(mrURL)
+	if err != nil {
+		return err
+	}
+
+	repo := &gitlab.RepoRef{Host: ref.Host, Project: ref.Project}
+	if err := workspace.CloneAndInitialize(repo.CloneURL(), targetDir, "", verbose, shallow); err != nil {
+		return err
+	}
+
+	bareDir := filepath.Join(targetDir, ".bare")
+	info, err := resolveMRInfo(bareDir, ref)
+	if err != nil {
+		return fmt.Errorf("cloned repository to %s, but failed to fetch MR !%d: %w", targetDir, ref.Number, err)
+	}
+
+	branch := mrBranchName(ref.Number, info)
+	worktreePath := filepath.Join(targetDir, fmt.Sprintf("mr-%d", ref.Number))
+	if err := checkoutMR(bareDir, worktreePath, branch, ref.Number, info, false); err != nil {
+		return fmt.Errorf("cloned repository to %s, but failed to check out MR !%d: %w", targetDir, ref.Number, err)
+	}
+
+	logger.Emit(logger.Event{Type: logger.EventWorktreeCreated, Worktree: worktreePath, Branch: branch})
+	logger.Success("Cloned repository to %s", styles.RenderPath(targetDir))
+	logger.ListSubItem("fetched MR !%d", ref.Number)
+	runCloneHooks(targetDir, worktreePath, branch)
 	return nil
 }
 
@@ -282,9 +414,22 @@ func runCloneFromGitHub(owner, repo, targetDir, branches string, verbose, shallo
 	}
 
 	logger.Success("Cloned repository to %s", styles.RenderPath(targetDir))
+	runCloneHooks(targetDir, "", "")
 	return nil
 }
 
+// runCloneHooks runs post-clone hooks in the new workspace root. They are read
+// from git config rather than the cloned .grove.toml, which is not yet trusted.
+func runCloneHooks(workspaceDir, worktreePath, branch string) {
+	runPostHooks("", loadHooks("", hooks.EventPostClone, ""), workspaceDir, &hooks.Context{
+		Event:     hooks.EventPostClone,
+		Command:   "clone",
+		Workspace: workspaceDir,
+		Worktree:  worktreePath,
+		Branch:    branch,
+	})
+}
+
 // cloneWithGh clones a repository using the gh CLI, which respects the user's protocol preference.
 func cloneWithGh(repoSpec, bareDir string, verbose, shallow bool) error {
 	spin := logger.StartSpinner(fmt.Sprintf("Cloning %s...", repoSpec))
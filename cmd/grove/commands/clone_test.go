@@ -1,17 +1,19 @@
 package commands
 
 import (
+	"path/filepath"
 	"testing"
 
 	"github.com/spf13/cobra"
 	"github.com/sqve/grove/internal/github"
+	"github.com/sqve/grove/internal/testutil"
 )
 
 func TestNewCloneCmd(t *testing.T) {
 	cmd := NewCloneCmd()
 
-	if cmd.Use != "clone <url|PR-URL> [directory]" {
-		t.Errorf("expected Use 'clone <url|PR-URL> [directory]', got '%s'", cmd.Use)
+	if cmd.Use != "clone <url|PR-URL|MR-URL> [directory]" {
+		t.Errorf("expected Use 'clone <url|PR-URL|MR-URL> [directory]', got '%s'", cmd.Use)
 	}
 
 	if cmd.Flags().Lookup("branches") == nil {
@@ -142,3 +144,32 @@ func TestGitHubURLClassification(t *testing.T) {
 		})
 	}
 }
+
+func TestNewCloneCmd_Manifest(t *testing.T) {
+	t.Run("takes no arguments", func(t *testing.T) {
+		cmd := NewCloneCmd()
+		_ = cmd.Flags().Set("manifest", "grove.workspace.toml")
+
+		if err := cmd.Args(cmd, []string{}); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if err := cmd.Args(cmd, []string{"https://github.com/owner/repo"}); err == nil {
+			t.Error("expected error for URL with --manifest")
+		}
+	})
+
+	t.Run("rejects branches flag", func(t *testing.T) {
+		cmd := NewCloneCmd()
+		_ = cmd.Flags().Set("manifest", "grove.workspace.toml")
+		_ = cmd.Flags().Set("branches", "main")
+
+		if err := cmd.PreRunE(cmd, []string{}); err == nil {
+			t.Error("expected error for --branches with --manifest")
+		}
+	})
+
+	t.Run("rejects other files", func(t *testing.T) {
+		err := runCloneManifest(filepath.Join(testutil.TempDir(t), "repos.toml"), false, false)
+		testutil.AssertErrorContains(t, err, "is not a grove.workspace.toml file")
+	})
+}
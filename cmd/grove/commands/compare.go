@@ -0,0 +1,252 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/sqve/grove/internal/git"
+	"github.com/sqve/grove/internal/styles"
+	"github.com/sqve/grove/internal/workspace"
+)
+
+// compareSide is one of the worktrees being compared
+type compareSide struct {
+	Worktree    string         `json:"worktree"`
+	Branch      string         `json:"branch"`
+	Path        string         `json:"path"`
+	Commit      string         `json:"commit"`
+	Uncommitted []git.FileStat `json:"uncommitted,omitempty"`
+	Untracked   []string       `json:"untracked,omitempty"`
+}
+
+// compareResult is the difference between two worktrees
+type compareResult struct {
+	Base   compareSide    `json:"base"`
+	Head   compareSide    `json:"head"`
+	Ahead  int            `json:"ahead"`
+	Behind int            `json:"behind"`
+	Files  []git.FileStat `json:"files"`
+}
+
+// NewCompareCmd creates the compare command
+func NewCompareCmd() *cobra.Command {
+	var uncommitted bool
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "compare <base> <head>",
+		Short: "Compare the commits of two worktrees",
+		Long: `Compare the commits checked out in two worktrees.
+
+Shows how many commits head is ahead of and behind base, and a diffstat of
+what head changed since the two diverged. With --uncommitted, also lists the
+uncommitted changes of each worktree. Use 'grove diff' to see the changes
+themselves.
+
+Examples:
+  grove compare main feat-auth                # Commits of feat-auth vs main
+  grove compare main feat/auth --uncommitted  # With uncommitted changes
+  grove compare main feat-auth --json         # Output as JSON`,
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: completeCompareArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runCompare(args[0], args[1], uncommitted, jsonOutput)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&uncommitted, "uncommitted", "u", false, "Include uncommitted changes of each worktree")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output as JSON")
+	cmd.Flags().BoolP("help", "h", false, "Help for compare")
+
+	return cmd
+}
+
+func runCompare(baseTarget, headTarget string, uncommitted, jsonOutput bool) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	bareDir, err := workspace.FindBareDir(cwd)
+	if err != nil {
+		return err
+	}
+
+	infos, err := git.ListWorktreesWithInfo(bareDir, true)
+	if err != nil {
+		return fmt.Errorf("failed to list worktrees: %w", err)
+	}
+	base, head, err := findWorktreePair(infos, baseTarget, headTarget)
+	if err != nil {
+		return err
+	}
+
+	result := &compareResult{
+		Base: compareSide{Worktree: git.WorktreeName(infos, base), Branch: base.Branch, Path: base.Path},
+		Head: compareSide{Worktree: git.WorktreeName(infos, head), Branch: head.Branch, Path: head.Path},
+	}
+	if result.Base.Commit, err = git.RevParse(base.Path, "HEAD"); err != nil {
+		return fmt.Errorf("worktree %s has no commits", result.Base.Worktree)
+	}
+	if result.Head.Commit, err = git.RevParse(head.Path, "HEAD"); err != nil {
+		return fmt.Errorf("worktree %s has no commits", result.Head.Worktree)
+	}
+
+	if result.Ahead, result.Behind, err = git.CompareBranchRefs(bareDir, result.Head.Commit, result.Base.Commit); err != nil {
+		return err
+	}
+	if result.Files, err = git.DiffStat(bareDir, result.Base.Commit+"..."+result.Head.Commit); err != nil {
+		return err
+	}
+
+	if uncommitted {
+		for _, side := range []*compareSide{&result.Base, &result.Head} {
+			if side.Uncommitted, err = git.DiffStat(side.Path, "HEAD"); err != nil {
+				return err
+			}
+			if side.Untracked, err = git.ListUntrackedFiles(side.Path); err != nil {
+				return err
+			}
+		}
+	}
+
+	if jsonOutput {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(result)
+	}
+
+	printCompare(result, uncommitted)
+	return nil
+}
+
+// findWorktreePair resolves two different worktrees, such as the sides of a
+// comparison
+func findWorktreePair(infos []*git.WorktreeInfo, a, b string) (*git.WorktreeInfo, *git.WorktreeInfo, error) {
+	first := git.FindWorktree(infos, a)
+	if first == nil {
+		return nil, nil, fmt.Errorf("worktree not found: %s", a)
+	}
+	second := git.FindWorktree(infos, b)
+	if second == nil {
+		return nil, nil, fmt.Errorf("worktree not found: %s", b)
+	}
+	if first == second {
+		return nil, nil, fmt.Errorf("%s and %s are the same worktree", a, b)
+	}
+	return first, second, nil
+}
+
+func printCompare(result *compareResult, uncommitted bool) {
+	base := styles.Render(&styles.Worktree, result.Base.Worktree)
+	head := styles.Render(&styles.Worktree, result.Head.Worktree)
+
+	if result.Ahead == 0 && result.Behind == 0 {
+		fmt.Printf("%s and %s are at the same commit\n", head, base)
+	} else {
+		fmt.Printf("%s is %s and %s %s\n", head,
+			countNoun(result.Ahead, "commit ahead", "commits ahead"),
+			countNoun(result.Behind, "commit behind", "commits behind"), base)
+	}
+
+	if len(result.Files) > 0 {
+		fmt.Printf("\nChanged in %s since it diverged from %s:\n", head, base)
+		printDiffStat(result.Files, nil)
+	}
+
+	if !uncommitted {
+		return
+	}
+	for _, side := range []compareSide{result.Base, result.Head} {
+		name := styles.Render(&styles.Worktree, side.Worktree)
+		if len(side.Uncommitted) == 0 && len(side.Untracked) == 0 {
+			fmt.Printf("\nNo uncommitted changes in %s\n", name)
+			continue
+		}
+		fmt.Printf("\nUncommitted in %s:\n", name)
+		printDiffStat(side.Uncommitted, side.Untracked)
+	}
+}
+
+// printDiffStat prints one aligned line per changed file followed by a
+// summary, like git diff --stat
+func printDiffStat(files []git.FileStat, untracked []string) {
+	names := make([]string, 0, len(files)+len(untracked))
+	for _, file := range files {
+		name := file.Path
+		if file.OldPath != "" {
+			name = file.OldPath + " => " + file.Path
+		}
+		names = append(names, name)
+	}
+	names = append(names, untracked...)
+	width := 0
+	for _, name := range names {
+		width = max(width, len(name))
+	}
+
+	added, deleted := 0, 0
+	for i, file := range files {
+		change := styles.Render(&styles.Dimmed, "binary")
+		if !file.Binary {
+			change = fmt.Sprintf("%s %s",
+				styles.Render(&styles.Success, fmt.Sprintf("+%d", file.Added)),
+				styles.Render(&styles.Error, fmt.Sprintf("-%d", file.Deleted)))
+		}
+		fmt.Printf("  %-*s  %s\n", width, names[i], change)
+		added += file.Added
+		deleted += file.Deleted
+	}
+	for _, name := range untracked {
+		fmt.Printf("  %-*s  %s\n", width, name, styles.Render(&styles.Dimmed, "untracked"))
+	}
+
+	var summary []string
+	if len(files) > 0 {
+		summary = append(summary, countNoun(len(files), "file changed", "files changed"))
+	}
+	if added > 0 || deleted > 0 {
+		summary = append(summary,
+			countNoun(added, "insertion(+)", "insertions(+)"),
+			countNoun(deleted, "deletion(-)", "deletions(-)"))
+	}
+	if len(untracked) > 0 {
+		summary = append(summary, countNoun(len(untracked), "untracked file", "untracked files"))
+	}
+	fmt.Printf("  %s\n", styles.Render(&styles.Dimmed, strings.Join(summary, ", ")))
+}
+
+// completeCompareArgs completes the two worktrees of compare and diff
+func completeCompareArgs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) >= 2 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	bareDir, err := workspace.FindBareDir(cwd)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	infos, err := git.ListWorktreesWithInfo(bareDir, true)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	var completions []string
+	for _, info := range infos {
+		name := git.WorktreeName(infos, info)
+		if !slices.Contains(args, name) {
+			completions = append(completions, name)
+		}
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
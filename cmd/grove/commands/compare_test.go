@@ -0,0 +1,72 @@
+package commands
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/sqve/grove/internal/git"
+	"github.com/sqve/grove/internal/testutil"
+	"github.com/sqve/grove/internal/workspace"
+)
+
+func TestNewCompareCmd(t *testing.T) {
+	cmd := NewCompareCmd()
+
+	if cmd.Use != "compare <base> <head>" {
+		t.Errorf("expected Use 'compare <base> <head>', got %q", cmd.Use)
+	}
+	if err := cmd.Args(cmd, []string{"main"}); err == nil {
+		t.Error("expected error for a single worktree")
+	}
+	for _, name := range []string{"uncommitted", "json"} {
+		if cmd.Flags().Lookup(name) == nil {
+			t.Errorf("expected --%s flag", name)
+		}
+	}
+}
+
+func TestRunCompare_NotInWorkspace(t *testing.T) {
+	defer testutil.SaveCwd(t)()
+	testutil.Chdir(t, testutil.TempDir(t))
+
+	if err := runCompare("main", "feat-auth", false, false); !errors.Is(err, workspace.ErrNotInWorkspace) {
+		t.Errorf("expected ErrNotInWorkspace, got %v", err)
+	}
+}
+
+func TestFindWorktreePair(t *testing.T) {
+	root := filepath.FromSlash("/work/repo")
+	infos := []*git.WorktreeInfo{
+		{Path: filepath.Join(root, "main"), Branch: "main"},
+		{Path: filepath.Join(root, "feat-auth"), Branch: "feat/auth"},
+	}
+
+	tests := []struct {
+		name    string
+		a, b    string
+		wantErr string
+	}{
+		{"by directory", "main", "feat-auth", ""},
+		{"by branch", "main", "feat/auth", ""},
+		{"unknown first", "nope", "main", "worktree not found: nope"},
+		{"unknown second", "main", "nope", "worktree not found: nope"},
+		{"same worktree", "feat-auth", "feat/auth", "feat-auth and feat/auth are the same worktree"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			first, second, err := findWorktreePair(infos, tt.a, tt.b)
+			if tt.wantErr != "" {
+				testutil.AssertErrorContains(t, err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				t.Fatalf("findWorktreePair failed: %v", err)
+			}
+			if first != infos[0] || second != infos[1] {
+				t.Errorf("expected main and feat-auth, got %s and %s", first.Path, second.Path)
+			}
+		})
+	}
+}
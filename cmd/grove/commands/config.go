@@ -9,28 +9,74 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/sqve/grove/internal/branchmatch"
 	"github.com/sqve/grove/internal/config"
+	"github.com/sqve/grove/internal/fs"
 	"github.com/sqve/grove/internal/git"
 	"github.com/sqve/grove/internal/logger"
+	"github.com/sqve/grove/internal/tmux"
 	"github.com/sqve/grove/internal/workspace"
 )
 
 const (
-	configKeyPlain     = "grove.plain"
-	configKeyDebug     = "grove.debug"
-	configKeyNerdFonts = "grove.nerdFonts"
-	configKeyPreserve  = "grove.preserve"
-	configKeyHooksAdd  = "hooks.add"
-	tomlKeyPlain       = "plain"
-	tomlKeyDebug       = "debug"
-	tomlKeyPreserve    = "preserve.patterns"
+	configKeyPlain        = "grove.plain"
+	configKeyDebug        = "grove.debug"
+	configKeyNerdFonts    = "grove.nerdFonts"
+	configKeyPreserve     = "grove.preserve"
+	configKeyCopyStrategy = "grove.preserveCopyStrategy"
+	configKeyTmux         = "grove.tmux"
+	configKeyTmuxMode     = "grove.tmuxMode"
+	configKeyGitLabHost   = "grove.gitlabHost"
+	configKeyPathTemplate = "grove.worktreePathTemplate"
+	configKeyAutoLock     = "grove.autoLock"
+	configKeyHooksAdd     = "hooks.add"
+	tomlKeyPlain          = "plain"
+	tomlKeyDebug          = "debug"
+	tomlKeyPreserve       = "preserve.patterns"
 )
 
 var (
-	allConfigKeys     = []string{configKeyPlain, configKeyDebug, configKeyNerdFonts, configKeyPreserve}
-	booleanConfigKeys = []string{configKeyPlain, configKeyDebug, configKeyNerdFonts}
+	allConfigKeys     = []string{configKeyPlain, configKeyDebug, configKeyNerdFonts, configKeyPreserve, configKeyCopyStrategy, configKeyTmux, configKeyTmuxMode, configKeyGitLabHost, configKeyPathTemplate}
+	booleanConfigKeys = []string{configKeyPlain, configKeyDebug, configKeyNerdFonts, configKeyTmux}
 )
 
+// hookConfigEntry pairs a .grove.toml hook key with its command list.
+type hookConfigEntry struct {
+	key      string
+	commands *[]string
+}
+
+// hookConfigEntries returns all hook keys of cfg in display order.
+func hookConfigEntries(cfg *config.FileConfig) []hookConfigEntry {
+	return []hookConfigEntry{
+		{"hooks.pre_add", &cfg.Hooks.PreAdd},
+		{configKeyHooksAdd, &cfg.Hooks.Add},
+		{"hooks.post_switch", &cfg.Hooks.PostSwitch},
+		{"hooks.pre_remove", &cfg.Hooks.PreRemove},
+		{"hooks.post_remove", &cfg.Hooks.PostRemove},
+		{"hooks.post_move", &cfg.Hooks.PostMove},
+	}
+}
+
+// findHookConfigEntry returns the command list for a hooks.* key, or nil if unknown.
+func findHookConfigEntry(cfg *config.FileConfig, key string) *[]string {
+	for _, entry := range hookConfigEntries(cfg) {
+		if entry.key == key {
+			return entry.commands
+		}
+	}
+	return nil
+}
+
+// printHooks prints every configured hook as key=command.
+func printHooks(cfg *config.FileConfig) {
+	for _, entry := range hookConfigEntries(cfg) {
+		for _, h := range *entry.commands {
+			fmt.Printf("%s=%s\n", entry.key, h)
+		}
+	}
+}
+
 // isValidConfigKey validates that key is in grove.* namespace
 func isValidConfigKey(key string) bool {
 	if key == "" {
@@ -109,6 +155,28 @@ func getBooleanCompletions(toComplete string) []string {
 	return completions
 }
 
+// getTmuxModeCompletions returns completion suggestions for grove.tmuxMode
+func getTmuxModeCompletions(toComplete string) []string {
+	var completions []string
+	for _, mode := range []tmux.Mode{tmux.ModeWindow, tmux.ModeSession} {
+		if strings.HasPrefix(string(mode), toComplete) {
+			completions = append(completions, string(mode))
+		}
+	}
+	return completions
+}
+
+// getCopyStrategyCompletions returns completion suggestions for grove.preserveCopyStrategy
+func getCopyStrategyCompletions(toComplete string) []string {
+	var completions []string
+	for _, strategy := range fs.CopyStrategies() {
+		if strings.HasPrefix(string(strategy), toComplete) {
+			completions = append(completions, string(strategy))
+		}
+	}
+	return completions
+}
+
 // isBooleanKey returns true if the key expects boolean values
 func isBooleanKey(key string) bool {
 	return slices.ContainsFunc(booleanConfigKeys, func(k string) bool {
@@ -133,6 +201,32 @@ func findWorktreeDir() string {
 	return dir
 }
 
+// validateConfigScope rejects combinations of --shared, --global and --worktree
+func validateConfigScope(shared, global, worktree bool) error {
+	if shared && global {
+		return errors.New("--shared and --global cannot be used together")
+	}
+	if worktree && (shared || global) {
+		return errors.New("--worktree cannot be used with --shared or --global")
+	}
+	return nil
+}
+
+// currentWorktreeRoot returns the root of the worktree containing the current
+// directory, whose per-worktree config --worktree reads and writes
+func currentWorktreeRoot() (string, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	worktreeRoot, err := git.FindWorktreeRoot(cwd)
+	if err != nil {
+		return "", errors.New("--worktree must be run from inside a worktree")
+	}
+	return worktreeRoot, nil
+}
+
 // NewConfigCmd creates the config command with all subcommands
 func NewConfigCmd() *cobra.Command {
 	configCmd := &cobra.Command{
@@ -145,7 +239,8 @@ Precedence (highest to lowest):
   Team settings (preserve, hooks): .grove.toml > global git config
   Personal settings (plain, debug): global git config > .grove.toml
 
-Use --shared for .grove.toml, --global for git config.`,
+Use --shared for .grove.toml, --global for git config, --worktree for the
+git config of the current worktree only.`,
 		Args: cobra.NoArgs,
 		Run: func(cmd *cobra.Command, args []string) {
 			_ = cmd.Help()
@@ -160,11 +255,13 @@ Use --shared for .grove.toml, --global for git config.`,
 Without flags: effective (merged) values.
 --shared: .grove.toml only.
 --global: git config only.
+--worktree: git config of the current worktree only (config.worktree).
 
 Examples:
-  grove config list            # Show effective config
-  grove config list --shared   # Show .grove.toml settings
-  grove config list --global   # Show git config settings`,
+  grove config list              # Show effective config
+  grove config list --shared     # Show .grove.toml settings
+  grove config list --global     # Show git config settings
+  grove config list --worktree   # Show settings of this worktree`,
 		Args: cobra.NoArgs,
 		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 			return nil, cobra.ShellCompDirectiveNoFileComp
@@ -172,11 +269,13 @@ Examples:
 		RunE: func(cmd *cobra.Command, args []string) error {
 			shared, _ := cmd.Flags().GetBool("shared")
 			global, _ := cmd.Flags().GetBool("global")
-			return runConfigList(shared, global)
+			worktree, _ := cmd.Flags().GetBool("worktree")
+			return runConfigList(shared, global, worktree)
 		},
 	}
 	listCmd.Flags().Bool("shared", false, "List only .grove.toml settings")
 	listCmd.Flags().Bool("global", false, "List only global git config settings")
+	listCmd.Flags().Bool("worktree", false, "List only git config settings of the current worktree")
 
 	getCmd := &cobra.Command{
 		Use:   "get <key>",
@@ -186,10 +285,12 @@ Examples:
 Without flags: effective (merged) value.
 --shared: .grove.toml only.
 --global: git config only.
+--worktree: git config of the current worktree only, for any git key.
 
 Examples:
-  grove config get grove.plain           # Get effective value
-  grove config get grove.debug --global  # Get from git config`,
+  grove config get grove.plain              # Get effective value
+  grove config get grove.debug --global     # Get from git config
+  grove config get user.email --worktree    # Get from this worktree`,
 		Args: cobra.ExactArgs(1),
 		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 			if len(args) == 0 {
@@ -200,23 +301,34 @@ Examples:
 		RunE: func(cmd *cobra.Command, args []string) error {
 			shared, _ := cmd.Flags().GetBool("shared")
 			global, _ := cmd.Flags().GetBool("global")
-			return runConfigGet(args[0], shared, global)
+			worktree, _ := cmd.Flags().GetBool("worktree")
+			return runConfigGet(args[0], shared, global, worktree)
 		},
 	}
 	getCmd.Flags().Bool("shared", false, "Get from .grove.toml")
 	getCmd.Flags().Bool("global", false, "Get from global git config")
+	getCmd.Flags().Bool("worktree", false, "Get from the git config of the current worktree")
 
 	setCmd := &cobra.Command{
 		Use:   "set <key> <value>",
 		Short: "Set a configuration value",
 		Long: `Set a configuration value.
 
-Requires --shared (.grove.toml) or --global (git config).
-Array values (preserve.patterns, hooks.add) must be edited in .grove.toml directly.
+Requires --shared (.grove.toml), --global (git config) or --worktree.
+Array values (preserve.patterns, hooks.*) must be edited in .grove.toml directly.
+
+--add adds a value to a multi-value git config key such as grove.autoLock,
+keeping its other values, where set would replace them.
+
+--worktree writes any git key, such as user.email or core.hooksPath, to the
+config.worktree of the current worktree, where it overrides the config
+shared by all worktrees. It enables extensions.worktreeConfig if needed.
 
 Examples:
   grove config set grove.plain true --global   # Enable plain mode globally
-  grove config set grove.debug true --shared   # Enable debug in .grove.toml`,
+  grove config set grove.debug true --shared   # Enable debug in .grove.toml
+  grove config set user.email me@example.com --worktree  # Only this worktree
+  grove config set grove.autoLock 'release/**' --global --add  # Lock release branches too`,
 		Args: cobra.ExactArgs(2),
 		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 			if len(args) == 0 {
@@ -225,28 +337,39 @@ Examples:
 			if len(args) == 1 && isBooleanKey(args[0]) {
 				return getBooleanCompletions(toComplete), cobra.ShellCompDirectiveNoFileComp
 			}
+			if len(args) == 1 && strings.EqualFold(args[0], configKeyTmuxMode) {
+				return getTmuxModeCompletions(toComplete), cobra.ShellCompDirectiveNoFileComp
+			}
+			if len(args) == 1 && strings.EqualFold(args[0], configKeyCopyStrategy) {
+				return getCopyStrategyCompletions(toComplete), cobra.ShellCompDirectiveNoFileComp
+			}
 			return nil, cobra.ShellCompDirectiveNoFileComp
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			shared, _ := cmd.Flags().GetBool("shared")
 			global, _ := cmd.Flags().GetBool("global")
-			return runConfigSet(args[0], args[1], shared, global)
+			worktree, _ := cmd.Flags().GetBool("worktree")
+			add, _ := cmd.Flags().GetBool("add")
+			return runConfigSet(args[0], args[1], shared, global, worktree, add)
 		},
 	}
 	setCmd.Flags().Bool("shared", false, "Write to .grove.toml")
 	setCmd.Flags().Bool("global", false, "Write to global git config")
+	setCmd.Flags().Bool("worktree", false, "Write to the git config of the current worktree")
+	setCmd.Flags().Bool("add", false, "Add a value to a multi-value git config key instead of replacing it")
 
 	unsetCmd := &cobra.Command{
 		Use:   "unset <key> [<value>]",
 		Short: "Remove a configuration setting",
 		Long: `Remove a configuration setting.
 
-Requires --shared (.grove.toml) or --global (git config).
+Requires --shared (.grove.toml), --global (git config) or --worktree.
 For multi-value keys, specify a value to remove only that value.
 
 Examples:
   grove config unset grove.plain --global           # Remove from git config
-  grove config unset grove.preserve "*.log" --shared # Remove specific pattern`,
+  grove config unset grove.preserve "*.log" --shared # Remove specific pattern
+  grove config unset user.email --worktree          # Remove from this worktree`,
 		Args: cobra.RangeArgs(1, 2),
 		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 			global, _ := cmd.Flags().GetBool("global")
@@ -261,15 +384,17 @@ Examples:
 		RunE: func(cmd *cobra.Command, args []string) error {
 			shared, _ := cmd.Flags().GetBool("shared")
 			global, _ := cmd.Flags().GetBool("global")
+			worktree, _ := cmd.Flags().GetBool("worktree")
 			var value string
 			if len(args) > 1 {
 				value = args[1]
 			}
-			return runConfigUnset(args[0], value, shared, global)
+			return runConfigUnset(args[0], value, shared, global, worktree)
 		},
 	}
 	unsetCmd.Flags().Bool("shared", false, "Remove from .grove.toml")
 	unsetCmd.Flags().Bool("global", false, "Remove from global git config")
+	unsetCmd.Flags().Bool("worktree", false, "Remove from the git config of the current worktree")
 
 	initCmd := &cobra.Command{
 		Use:   "init",
@@ -293,15 +418,19 @@ Examples:
 	return configCmd
 }
 
-func runConfigList(shared, global bool) error {
-	if shared && global {
-		return errors.New("--shared and --global cannot be used together")
+func runConfigList(shared, global, worktree bool) error {
+	if err := validateConfigScope(shared, global, worktree); err != nil {
+		return err
 	}
 
 	if shared {
 		return runConfigListShared()
 	}
 
+	if worktree {
+		return runConfigListWorktree()
+	}
+
 	if global {
 		return runConfigListGlobal()
 	}
@@ -331,9 +460,7 @@ func runConfigListShared() error {
 	for _, p := range cfg.Preserve.Patterns {
 		fmt.Printf("preserve.patterns=%s\n", p)
 	}
-	for _, h := range cfg.Hooks.Add {
-		fmt.Printf("hooks.add=%s\n", h)
-	}
+	printHooks(&cfg)
 
 	return nil
 }
@@ -364,6 +491,32 @@ func runConfigListGlobal() error {
 	return nil
 }
 
+func runConfigListWorktree() error {
+	worktreeRoot, err := currentWorktreeRoot()
+	if err != nil {
+		return err
+	}
+
+	configs, err := git.GetWorktreeConfigs(worktreeRoot)
+	if err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(configs))
+	for key := range configs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		for _, value := range configs[key] {
+			fmt.Printf("%s=%s\n", key, value)
+		}
+	}
+
+	return nil
+}
+
 func runConfigListEffective() error {
 	worktreeDir := findWorktreeDir()
 
@@ -389,22 +542,24 @@ func runConfigListEffective() error {
 	if worktreeDir != "" {
 		cfg, err := config.LoadFromFile(worktreeDir)
 		if err == nil {
-			for _, h := range cfg.Hooks.Add {
-				fmt.Printf("hooks.add=%s\n", h)
-			}
+			printHooks(&cfg)
 		}
 	}
 
 	return nil
 }
 
-func runConfigGet(key string, shared, global bool) error {
-	if !isValidConfigKey(key) && !strings.HasPrefix(key, "preserve.") && !strings.HasPrefix(key, "hooks.") {
-		return errors.New("only grove.* settings are supported")
+func runConfigGet(key string, shared, global, worktree bool) error {
+	if err := validateConfigScope(shared, global, worktree); err != nil {
+		return err
 	}
 
-	if shared && global {
-		return errors.New("--shared and --global cannot be used together")
+	if worktree {
+		return runConfigGetWorktree(key)
+	}
+
+	if !isValidConfigKey(key) && !strings.HasPrefix(key, "preserve.") && !strings.HasPrefix(key, "hooks.") {
+		return errors.New("only grove.* settings are supported")
 	}
 
 	if shared {
@@ -443,12 +598,14 @@ func runConfigGetShared(key string) error {
 		for _, p := range cfg.Preserve.Patterns {
 			fmt.Println(p)
 		}
-	case configKeyHooksAdd:
-		for _, h := range cfg.Hooks.Add {
+	default:
+		commands := findHookConfigEntry(&cfg, strings.ToLower(key))
+		if commands == nil {
+			return fmt.Errorf("unknown key: %s", key)
+		}
+		for _, h := range *commands {
 			fmt.Println(h)
 		}
-	default:
-		return fmt.Errorf("unknown key: %s", key)
 	}
 
 	return nil
@@ -471,6 +628,24 @@ func runConfigGetGlobal(key string) error {
 	return nil
 }
 
+func runConfigGetWorktree(key string) error {
+	worktreeRoot, err := currentWorktreeRoot()
+	if err != nil {
+		return err
+	}
+
+	value, err := git.GetWorktreeConfig(worktreeRoot, key)
+	if err != nil {
+		if git.IsConfigNotFoundError(err) {
+			return errors.New("config key not found")
+		}
+		return err
+	}
+
+	fmt.Println(value)
+	return nil
+}
+
 func runConfigGetEffective(key string) error {
 	worktreeDir := findWorktreeDir()
 
@@ -483,16 +658,26 @@ func runConfigGetEffective(key string) error {
 		for _, p := range config.GetMergedPreservePatterns(worktreeDir) {
 			fmt.Println(p)
 		}
-	case configKeyHooksAdd:
-		if worktreeDir != "" {
+	default:
+		if strings.HasPrefix(strings.ToLower(key), "hooks.") {
+			// Hooks are read from TOML only
+			if worktreeDir == "" {
+				return nil
+			}
 			cfg, err := config.LoadFromFile(worktreeDir)
-			if err == nil {
-				for _, h := range cfg.Hooks.Add {
-					fmt.Println(h)
-				}
+			if err != nil {
+				return nil
+			}
+			commands := findHookConfigEntry(&cfg, strings.ToLower(key))
+			if commands == nil {
+				return fmt.Errorf("unknown key: %s", key)
+			}
+			for _, h := range *commands {
+				fmt.Println(h)
 			}
+			return nil
 		}
-	default:
+
 		// Try git config for unknown keys
 		value, err := git.GetConfig(key, true)
 		if err != nil {
@@ -507,20 +692,27 @@ func runConfigGetEffective(key string) error {
 	return nil
 }
 
-func runConfigSet(key, value string, shared, global bool) error {
-	if !shared && !global {
-		return errors.New("must specify --shared or --global")
+func runConfigSet(key, value string, shared, global, worktree, add bool) error {
+	if !shared && !global && !worktree {
+		return errors.New("must specify --shared, --global or --worktree")
 	}
 
-	if shared && global {
-		return errors.New("--shared and --global cannot be used together")
+	if err := validateConfigScope(shared, global, worktree); err != nil {
+		return err
 	}
 
 	if shared {
+		if add {
+			return errors.New("--add cannot be used with --shared (edit arrays in .grove.toml directly)")
+		}
 		return runConfigSetShared(key, value)
 	}
 
-	return runConfigSetGlobal(key, value)
+	if worktree {
+		return runConfigSetWorktree(key, value, add)
+	}
+
+	return runConfigSetGlobal(key, value, add)
 }
 
 func runConfigSetShared(key, value string) error {
@@ -557,31 +749,90 @@ func runConfigSetShared(key, value string) error {
 	return config.WriteToFile(worktreeDir, &cfg)
 }
 
-func runConfigSetGlobal(key, value string) error {
+func runConfigSetGlobal(key, value string, add bool) error {
 	if !isValidConfigKey(key) {
 		return errors.New("only grove.* settings are supported")
 	}
 
+	if err := validateConfigValue(key, value); err != nil {
+		return err
+	}
+
+	if add {
+		return git.AddConfig(key, value, true)
+	}
+	return git.SetConfig(key, value, true)
+}
+
+// runConfigSetWorktree writes any git key to the config of the current
+// worktree, validating grove.* values as for --global
+func runConfigSetWorktree(key, value string, add bool) error {
+	worktreeRoot, err := currentWorktreeRoot()
+	if err != nil {
+		return err
+	}
+
+	if err := validateConfigValue(key, value); err != nil {
+		return err
+	}
+
+	if add {
+		return git.AddWorktreeConfig(worktreeRoot, key, value)
+	}
+	return git.SetWorktreeConfig(worktreeRoot, key, value)
+}
+
+// validateConfigValue checks the value of grove.* keys that accept a fixed
+// set of values
+func validateConfigValue(key, value string) error {
 	if isBooleanKey(key) && !isValidBooleanValue(value) {
 		return fmt.Errorf("invalid boolean value '%s' for key '%s'", value, key)
 	}
 
-	return git.SetConfig(key, value, true)
+	if strings.EqualFold(key, configKeyTmuxMode) {
+		if _, ok := tmux.ParseMode(value); !ok {
+			return fmt.Errorf("invalid value '%s' for key '%s' (expected window or session)", value, key)
+		}
+	}
+
+	if strings.EqualFold(key, configKeyCopyStrategy) {
+		if _, ok := fs.ParseCopyStrategy(value); !ok {
+			return fmt.Errorf("invalid value '%s' for key '%s' (expected auto, copy, reflink or hardlink)", value, key)
+		}
+	}
+
+	if strings.EqualFold(key, configKeyPathTemplate) {
+		if err := workspace.ValidatePathTemplate(value); err != nil {
+			return err
+		}
+	}
+
+	if strings.EqualFold(key, configKeyAutoLock) {
+		if err := branchmatch.Validate(value); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
-func runConfigUnset(key, value string, shared, global bool) error {
-	if !shared && !global {
-		return errors.New("must specify --shared or --global")
+func runConfigUnset(key, value string, shared, global, worktree bool) error {
+	if !shared && !global && !worktree {
+		return errors.New("must specify --shared, --global or --worktree")
 	}
 
-	if shared && global {
-		return errors.New("--shared and --global cannot be used together")
+	if err := validateConfigScope(shared, global, worktree); err != nil {
+		return err
 	}
 
 	if shared {
 		return runConfigUnsetShared(key)
 	}
 
+	if worktree {
+		return runConfigUnsetWorktree(key, value)
+	}
+
 	return runConfigUnsetGlobal(key, value)
 }
 
@@ -604,10 +855,12 @@ func runConfigUnsetShared(key string) error {
 		cfg.Debug = nil
 	case "grove.preserve", "preserve.patterns":
 		cfg.Preserve.Patterns = nil
-	case "hooks.add":
-		cfg.Hooks.Add = nil
 	default:
-		return fmt.Errorf("unknown key: %s", key)
+		commands := findHookConfigEntry(&cfg, normalizedKey)
+		if commands == nil {
+			return fmt.Errorf("unknown key: %s", key)
+		}
+		*commands = nil
 	}
 
 	return config.WriteToFile(worktreeDir, &cfg)
@@ -633,6 +886,19 @@ func runConfigUnsetGlobal(key, value string) error {
 	return err
 }
 
+func runConfigUnsetWorktree(key, value string) error {
+	worktreeRoot, err := currentWorktreeRoot()
+	if err != nil {
+		return err
+	}
+
+	err = git.UnsetWorktreeConfig(worktreeRoot, key, value)
+	if git.IsConfigNotFoundError(err) {
+		return nil // Unsetting nonexistent key is a no-op
+	}
+	return err
+}
+
 func runConfigInit(force bool) error {
 	worktreeDir := findWorktreeDir()
 	if worktreeDir == "" {
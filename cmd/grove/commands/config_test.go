@@ -148,12 +148,12 @@ func TestGetConfigCompletions(t *testing.T) {
 		{
 			name:       "empty completion shows all keys",
 			toComplete: "",
-			want:       []string{"grove.debug", "grove.nerdFonts", "grove.plain", "grove.preserve"},
+			want:       []string{"grove.debug", "grove.nerdFonts", "grove.plain", "grove.preserve", "grove.preserveCopyStrategy", "grove.tmux", "grove.tmuxMode", "grove.gitlabHost", "grove.worktreePathTemplate"},
 		},
 		{
 			name:       "partial grove.p completion",
 			toComplete: "grove.p",
-			want:       []string{"grove.plain", "grove.preserve"},
+			want:       []string{"grove.plain", "grove.preserve", "grove.preserveCopyStrategy"},
 		},
 		{
 			name:       "partial grove.d completion",
@@ -238,3 +238,105 @@ func TestGetBooleanCompletions(t *testing.T) {
 		})
 	}
 }
+
+func TestGetTmuxModeCompletions(t *testing.T) {
+	tests := []struct {
+		name       string
+		toComplete string
+		want       []string
+	}{
+		{"empty completion shows all modes", "", []string{"window", "session"}},
+		{"partial completion", "s", []string{"session"}},
+		{"no matches returns empty", "xyz", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := getTmuxModeCompletions(tt.toComplete)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("getTmuxModeCompletions(%q) = %v, want %v", tt.toComplete, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetCopyStrategyCompletions(t *testing.T) {
+	tests := []struct {
+		name       string
+		toComplete string
+		want       []string
+	}{
+		{"empty completion shows all strategies", "", []string{"auto", "copy", "reflink", "hardlink"}},
+		{"partial completion", "h", []string{"hardlink"}},
+		{"no matches returns empty", "xyz", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := getCopyStrategyCompletions(tt.toComplete)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("getCopyStrategyCompletions(%q) = %v, want %v", tt.toComplete, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateConfigScope(t *testing.T) {
+	tests := []struct {
+		name                     string
+		shared, global, worktree bool
+		wantErr                  string
+	}{
+		{"none", false, false, false, ""},
+		{"worktree", false, false, true, ""},
+		{"shared and global", true, true, false, "--shared and --global cannot be used together"},
+		{"worktree and shared", true, false, true, "--worktree cannot be used with --shared or --global"},
+		{"worktree and global", false, true, true, "--worktree cannot be used with --shared or --global"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateConfigScope(tt.shared, tt.global, tt.worktree)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("expected %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestValidateConfigValue(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		value   string
+		wantErr bool
+	}{
+		{"valid boolean", "grove.plain", "true", false},
+		{"invalid boolean", "grove.plain", "maybe", true},
+		{"valid tmux mode", "grove.tmuxMode", "session", false},
+		{"invalid tmux mode", "grove.tmuxMode", "pane", true},
+		{"valid autolock glob", "grove.autoLock", "release/**", false},
+		{"valid autolock negation", "grove.autoLock", "!release/old", false},
+		{"valid autolock regexp", "grove.autoLock", `re:^v\d+$`, false},
+		{"autolock key case ignored", "grove.autolock", "[main", true},
+		{"invalid autolock class", "grove.autoLock", "release/[0-9", true},
+		{"invalid autolock regexp", "grove.autoLock", "re:(", true},
+		{"empty autolock negation", "grove.autoLock", "!", true},
+		{"unchecked key", "grove.gitlabHost", "gitlab.example.com", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateConfigValue(tt.key, tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateConfigValue(%q, %q) error = %v, wantErr %v", tt.key, tt.value, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,309 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/sqve/grove/internal/daemon"
+	"github.com/sqve/grove/internal/fs"
+	"github.com/sqve/grove/internal/git"
+	"github.com/sqve/grove/internal/logger"
+	"github.com/sqve/grove/internal/styles"
+	"github.com/sqve/grove/internal/workspace"
+)
+
+// daemonReadyTimeout bounds how long start and stop wait for the daemon
+const daemonReadyTimeout = 10 * time.Second
+
+// NewDaemonCmd creates the daemon command with all subcommands
+func NewDaemonCmd() *cobra.Command {
+	daemonCmd := &cobra.Command{
+		Use:   "daemon",
+		Short: "Cache worktree status for editors and status bars",
+		Long: `Keep worktree status up to date in a background process, so tools that poll
+it do not each run git in every worktree.
+
+The daemon watches .bare for added and removed worktrees and for changes to
+each worktree's HEAD, index and refs, and checks for uncommitted changes on
+every request. 'grove list' and 'grove status' use it while it is running and
+read worktrees directly otherwise. Other tools can
+query it over a Unix socket with JSON-RPC 2.0 (list, status, subscribe).`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			_ = cmd.Help()
+		},
+	}
+
+	noArgs := func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	runCmd := &cobra.Command{
+		Use:   "run",
+		Short: "Run the daemon in the foreground",
+		Long: `Run the daemon in the foreground until interrupted, for use with a service
+manager or a tmux pane.
+
+Examples:
+  grove daemon run`,
+		Args:              cobra.NoArgs,
+		ValidArgsFunction: noArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDaemonRun()
+		},
+	}
+
+	startCmd := &cobra.Command{
+		Use:   "start",
+		Short: "Start the daemon in the background",
+		Long: `Start the daemon in the background and wait until it serves requests. Its
+output is written to .bare/grove/daemon.log.
+
+Examples:
+  grove daemon start`,
+		Args:              cobra.NoArgs,
+		ValidArgsFunction: noArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDaemonStart()
+		},
+	}
+
+	stopCmd := &cobra.Command{
+		Use:   "stop",
+		Short: "Stop the daemon",
+		Long: `Stop the daemon of the current workspace.
+
+Examples:
+  grove daemon stop`,
+		Args:              cobra.NoArgs,
+		ValidArgsFunction: noArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDaemonStop()
+		},
+	}
+
+	statusCmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show whether the daemon is running",
+		Long: `Show whether the daemon of the current workspace is running, and the socket
+it listens on. Exits with an error when it is not running.
+
+Examples:
+  grove daemon status`,
+		Args:              cobra.NoArgs,
+		ValidArgsFunction: noArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDaemonStatus()
+		},
+	}
+
+	daemonCmd.AddCommand(runCmd, startCmd, stopCmd, statusCmd)
+	return daemonCmd
+}
+
+func findDaemonBareDir() (string, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("failed to get current directory: %w", err)
+	}
+	return workspace.FindBareDir(cwd)
+}
+
+func runDaemonRun() error {
+	bareDir, err := findDaemonBareDir()
+	if err != nil {
+		return err
+	}
+
+	// git status refreshes the index when it can, which would wake the
+	// watcher again after every refresh
+	if err := os.Setenv("GIT_OPTIONAL_LOCKS", "0"); err != nil {
+		return err
+	}
+
+	server := daemon.NewServer(bareDir,
+		func() ([]*git.WorktreeInfo, error) {
+			return git.ListWorktreesWithInfo(bareDir, false)
+		},
+		func(path string) (any, error) {
+			info, err := gatherStatusInfo(path)
+			if err != nil {
+				return nil, err
+			}
+			return info, nil
+		},
+		func(path string) (bool, error) {
+			hasChanges, _, err := git.CheckGitChanges(path)
+			return hasChanges, err
+		},
+	)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	logger.Info("Serving worktree status on %s", daemon.SocketPath(bareDir))
+	if err := server.Serve(ctx); err != nil {
+		return err
+	}
+	logger.Info("Daemon stopped")
+	return nil
+}
+
+func runDaemonStart() error {
+	bareDir, err := findDaemonBareDir()
+	if err != nil {
+		return err
+	}
+
+	if client, err := daemon.Dial(bareDir); err == nil {
+		_ = client.Close()
+		logger.Info("Daemon is already running")
+		return nil
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to find grove executable: %w", err)
+	}
+
+	logPath := daemon.LogPath(bareDir)
+	if err := os.MkdirAll(filepath.Dir(logPath), fs.DirGit); err != nil {
+		return err
+	}
+	logFile, err := os.Create(logPath) //nolint:gosec // Path derived from the workspace
+	if err != nil {
+		return fmt.Errorf("failed to create daemon log: %w", err)
+	}
+	defer func() { _ = logFile.Close() }()
+
+	cmd := exec.Command(exe, "daemon", "run") //nolint:gosec // Runs grove itself
+	cmd.Dir = filepath.Dir(bareDir)
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	detachProcess(cmd)
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start daemon: %w", err)
+	}
+
+	exited := make(chan struct{})
+	go func() {
+		_ = cmd.Wait()
+		close(exited)
+	}()
+
+	deadline := time.After(daemonReadyTimeout)
+	for {
+		if client, err := daemon.Dial(bareDir); err == nil {
+			_ = client.Close()
+			break
+		}
+		select {
+		case <-exited:
+			return fmt.Errorf("daemon exited during startup, see %s", logPath)
+		case <-deadline:
+			return fmt.Errorf("daemon did not start within %s, see %s", daemonReadyTimeout, logPath)
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+
+	logger.Success("Started daemon for %s", styles.RenderPath(filepath.Dir(bareDir)))
+	return nil
+}
+
+func runDaemonStop() error {
+	bareDir, err := findDaemonBareDir()
+	if err != nil {
+		return err
+	}
+
+	client, err := daemon.Dial(bareDir)
+	if err != nil {
+		logger.Info("Daemon is not running")
+		return nil
+	}
+	defer func() { _ = client.Close() }()
+
+	if err := client.Shutdown(); err != nil {
+		return fmt.Errorf("failed to stop daemon: %w", err)
+	}
+
+	// Wait for the socket to go away, so a following start does not find
+	// the stopping daemon
+	deadline := time.Now().Add(daemonReadyTimeout)
+	for time.Now().Before(deadline) {
+		probe, err := daemon.Dial(bareDir)
+		if err != nil {
+			logger.Success("Stopped daemon")
+			return nil
+		}
+		_ = probe.Close()
+		time.Sleep(50 * time.Millisecond)
+	}
+	return errors.New("daemon did not stop")
+}
+
+func runDaemonStatus() error {
+	bareDir, err := findDaemonBareDir()
+	if err != nil {
+		return err
+	}
+
+	client, err := daemon.Dial(bareDir)
+	if err != nil {
+		return errors.New("daemon is not running")
+	}
+	defer func() { _ = client.Close() }()
+
+	worktrees, err := client.List()
+	if err != nil {
+		return fmt.Errorf("daemon is not responding: %w", err)
+	}
+
+	logger.Success("Daemon is running with %d worktrees", len(worktrees))
+	logger.ListSubItem("socket %s", daemon.SocketPath(bareDir))
+	return nil
+}
+
+// daemonWorktrees returns the worktrees cached by the daemon, or false when
+// no daemon is running and callers should read them directly
+func daemonWorktrees(bareDir string) ([]*git.WorktreeInfo, bool) {
+	client, err := daemon.Dial(bareDir)
+	if err != nil {
+		return nil, false
+	}
+	defer func() { _ = client.Close() }()
+
+	infos, err := client.List()
+	if err != nil {
+		logger.Debug("Daemon failed to list worktrees: %v", err)
+		return nil, false
+	}
+	logger.Debug("Using worktree info from daemon")
+	return infos, true
+}
+
+// daemonStatus returns the status of worktreePath cached by the daemon, or
+// false when no daemon is running and callers should read it directly
+func daemonStatus(bareDir, worktreePath string) (*StatusInfo, bool) {
+	client, err := daemon.Dial(bareDir)
+	if err != nil {
+		return nil, false
+	}
+	defer func() { _ = client.Close() }()
+
+	var info StatusInfo
+	if err := client.Status(worktreePath, &info); err != nil {
+		logger.Debug("Daemon failed to read status: %v", err)
+		return nil, false
+	}
+	logger.Debug("Using worktree status from daemon")
+	return &info, true
+}
@@ -0,0 +1,50 @@
+package commands
+
+import (
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/sqve/grove/internal/testutil"
+	"github.com/sqve/grove/internal/workspace"
+)
+
+func TestNewDaemonCmd(t *testing.T) {
+	cmd := NewDaemonCmd()
+
+	if cmd.Use != "daemon" {
+		t.Errorf("expected Use 'daemon', got %q", cmd.Use)
+	}
+
+	var names []string
+	for _, sub := range cmd.Commands() {
+		names = append(names, sub.Name())
+	}
+	for _, want := range []string{"run", "start", "stop", "status"} {
+		if !slices.Contains(names, want) {
+			t.Errorf("expected subcommand %q, got %v", want, names)
+		}
+	}
+}
+
+func TestRunDaemonStatus_NotInWorkspace(t *testing.T) {
+	defer testutil.SaveCwd(t)()
+	testutil.Chdir(t, testutil.TempDir(t))
+
+	err := runDaemonStatus()
+	if !errors.Is(err, workspace.ErrNotInWorkspace) {
+		t.Errorf("expected ErrNotInWorkspace, got %v", err)
+	}
+}
+
+func TestDaemonFallback(t *testing.T) {
+	bareDir := filepath.Join(testutil.TempDir(t), ".bare")
+
+	if _, ok := daemonWorktrees(bareDir); ok {
+		t.Error("expected no worktrees without a daemon")
+	}
+	if _, ok := daemonStatus(bareDir, filepath.Dir(bareDir)); ok {
+		t.Error("expected no status without a daemon")
+	}
+}
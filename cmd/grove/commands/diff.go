@@ -0,0 +1,402 @@
+package commands
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/sqve/grove/internal/config"
+	"github.com/sqve/grove/internal/fs"
+	"github.com/sqve/grove/internal/git"
+	"github.com/sqve/grove/internal/logger"
+	"github.com/sqve/grove/internal/workspace"
+)
+
+// diffFile is a file that differs between two worktrees
+type diffFile struct {
+	Path     string `json:"path"`
+	Status   string `json:"status"` // added, deleted or modified
+	Added    int    `json:"added"`
+	Deleted  int    `json:"deleted"`
+	Binary   bool   `json:"binary,omitempty"`
+	Ignored  bool   `json:"ignored,omitempty"`
+	Redacted bool   `json:"redacted,omitempty"`
+	Patch    string `json:"patch"`
+}
+
+type diffWorktree struct {
+	Worktree string `json:"worktree"`
+	Path     string `json:"path"`
+}
+
+type diffResult struct {
+	From  diffWorktree `json:"from"`
+	To    diffWorktree `json:"to"`
+	Files []diffFile   `json:"files"`
+}
+
+// assignmentPattern matches KEY=value, export KEY=value and key: value lines,
+// capturing the key and the value
+var assignmentPattern = regexp.MustCompile(`^(\s*(?:export\s+)?["']?([A-Za-z_][A-Za-z0-9_.-]*)["']?\s*[=:]\s*)(\S.*?)(\s*)$`)
+
+// NewDiffCmd creates the diff command
+func NewDiffCmd() *cobra.Command {
+	var jsonOutput bool
+	var noRedact bool
+
+	cmd := &cobra.Command{
+		Use:   "diff <from> <to> [-- <path>...]",
+		Short: "Diff the files of two worktrees",
+		Long: `Diff the working-tree files of two worktrees, as they are on disk.
+
+Compares tracked and untracked files, including uncommitted changes, and
+ignored files matching the preserve patterns, such as .env. Paths after --
+limit the diff, relative to the worktree root, and include every ignored
+file under them.
+
+Values of ignored files whose names match the diff.redact patterns are
+redacted, such as API_KEY in API_KEY=value. Redacted values carry a hash
+that only holds for one run, so changed values still show as changes. Use
+--no-redact to show them.
+
+Examples:
+  grove diff main feat-auth                # Everything that differs
+  grove diff main feat-auth -- .env src/   # Only these paths
+  grove diff main feat-auth --json         # Output as JSON`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			worktrees := cmd.ArgsLenAtDash()
+			if worktrees == -1 {
+				worktrees = len(args)
+			}
+			if worktrees != 2 {
+				return fmt.Errorf("requires two worktrees, with paths after --")
+			}
+			return nil
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if cmd.ArgsLenAtDash() != -1 {
+				return nil, cobra.ShellCompDirectiveDefault
+			}
+			return completeCompareArgs(cmd, args, toComplete)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDiff(args[0], args[1], args[2:], !noRedact, jsonOutput)
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output as JSON")
+	cmd.Flags().BoolVar(&noRedact, "no-redact", false, "Show values of ignored files unredacted")
+	cmd.Flags().BoolP("help", "h", false, "Help for diff")
+
+	return cmd
+}
+
+func runDiff(fromTarget, toTarget string, paths []string, redact, jsonOutput bool) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	bareDir, err := workspace.FindBareDir(cwd)
+	if err != nil {
+		return err
+	}
+
+	infos, err := git.ListWorktreesWithInfo(bareDir, true)
+	if err != nil {
+		return fmt.Errorf("failed to list worktrees: %w", err)
+	}
+	from, to, err := findWorktreePair(infos, fromTarget, toTarget)
+	if err != nil {
+		return err
+	}
+
+	var patterns []string
+	if redact {
+		configWorktree := findConfigWorktree(bareDir)
+		if configWorktree == "" {
+			configWorktree = from.Path
+		}
+		patterns = config.GetMergedDiffRedactPatterns(configWorktree)
+	}
+
+	files, err := listDiffFiles(bareDir, from.Path, to.Path, paths)
+	if err != nil {
+		return err
+	}
+
+	tmpDir, err := os.MkdirTemp("", "grove-diff-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	result := &diffResult{
+		From: diffWorktree{Worktree: git.WorktreeName(infos, from), Path: from.Path},
+		To:   diffWorktree{Worktree: git.WorktreeName(infos, to), Path: to.Path},
+	}
+	fromLabel, toLabel := diffLabels(from.Path, to.Path)
+	if result.Files, err = stageDiffFiles(tmpDir, fromLabel, toLabel, from.Path, to.Path, files, patterns); err != nil {
+		return err
+	}
+
+	if jsonOutput {
+		for i := range result.Files {
+			if err := fillDiffPatch(tmpDir, fromLabel, toLabel, &result.Files[i]); err != nil {
+				return err
+			}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		enc.SetEscapeHTML(false)
+		return enc.Encode(result)
+	}
+
+	if len(result.Files) == 0 {
+		logger.Info("No differences between %s and %s", result.From.Worktree, result.To.Worktree)
+		return nil
+	}
+	flags := []string{"--no-prefix"}
+	if config.IsPlain() {
+		flags = append(flags, "--no-color")
+	}
+	_, err = git.DiffNoIndex(tmpDir, os.Stdout, flags, fromLabel, toLabel)
+	return err
+}
+
+// listDiffFiles returns the files that may differ between two worktrees,
+// mapped to whether they are ignored. Tracked and untracked files count when
+// they differ between the two HEADs or are changed in either worktree, so
+// files the worktrees share unchanged are never read. Ignored files are all
+// listed; without paths, only those matching the preserve patterns.
+func listDiffFiles(bareDir, fromPath, toPath string, paths []string) (map[string]bool, error) {
+	result := make(map[string]bool)
+	add := func(files []string, ignored bool) {
+		for _, file := range files {
+			file = filepath.ToSlash(file)
+			result[file] = result[file] || ignored
+		}
+	}
+
+	fromHead, fromErr := git.RevParse(fromPath, "HEAD")
+	toHead, toErr := git.RevParse(toPath, "HEAD")
+	for _, worktreePath := range []string{fromPath, toPath} {
+		if fromErr != nil || toErr != nil {
+			// Without commits to compare, every file may differ
+			files, err := git.ListFiles(worktreePath, false, paths...)
+			if err != nil {
+				return nil, err
+			}
+			add(files, false)
+		} else {
+			changed, err := git.ListChangedPaths(worktreePath, paths...)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read status of %s: %w", worktreePath, err)
+			}
+			add(changed, false)
+		}
+
+		var ignored []string
+		if len(paths) > 0 {
+			var err error
+			if ignored, err = git.ListFiles(worktreePath, true, paths...); err != nil {
+				return nil, err
+			}
+		} else {
+			ignored = preservedIgnoredFiles(bareDir, worktreePath, nil)
+		}
+		add(ignored, true)
+	}
+
+	if fromErr == nil && toErr == nil && fromHead != toHead {
+		files, err := git.DiffNames(fromPath, fromHead, toHead, paths...)
+		if err != nil {
+			return nil, err
+		}
+		add(files, false)
+	}
+	return result, nil
+}
+
+// diffLabels names the two sides of the diff after their worktree
+// directories, which head the paths in its output
+func diffLabels(fromPath, toPath string) (string, string) {
+	fromLabel, toLabel := filepath.Base(fromPath), filepath.Base(toPath)
+	if fromLabel == toLabel {
+		return "a", "b"
+	}
+	return fromLabel, toLabel
+}
+
+// stageDiffFiles copies the candidates that differ between two worktrees into
+// fromLabel and toLabel under tmpDir, redacting ignored files, and returns
+// them sorted by path
+func stageDiffFiles(tmpDir, fromLabel, toLabel, fromPath, toPath string, candidates map[string]bool, patterns []string) ([]diffFile, error) {
+	for _, label := range []string{fromLabel, toLabel} {
+		if err := os.MkdirAll(filepath.Join(tmpDir, label), fs.DirStrict); err != nil {
+			return nil, fmt.Errorf("failed to create temporary directory: %w", err)
+		}
+	}
+
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("failed to generate redaction key: %w", err)
+	}
+
+	paths := slices.Sorted(maps.Keys(candidates))
+
+	files := []diffFile{}
+	for _, file := range paths {
+		before, err := readDiffFile(fromPath, file)
+		if err != nil {
+			return nil, err
+		}
+		after, err := readDiffFile(toPath, file)
+		if err != nil {
+			return nil, err
+		}
+		if before == nil && after == nil {
+			continue
+		}
+
+		entry := diffFile{Path: file, Ignored: candidates[file]}
+		if len(patterns) > 0 && entry.Ignored {
+			var fromRedacted, toRedacted bool
+			before, fromRedacted = redactSecrets(before, patterns, key)
+			after, toRedacted = redactSecrets(after, patterns, key)
+			entry.Redacted = fromRedacted || toRedacted
+		}
+		if before != nil && after != nil && bytes.Equal(before, after) {
+			continue
+		}
+
+		switch {
+		case before == nil:
+			entry.Status = "added"
+		case after == nil:
+			entry.Status = "deleted"
+		default:
+			entry.Status = "modified"
+		}
+		for _, side := range []struct {
+			label   string
+			content []byte
+		}{{fromLabel, before}, {toLabel, after}} {
+			if side.content == nil {
+				continue
+			}
+			dest := filepath.Join(tmpDir, side.label, filepath.FromSlash(file))
+			if err := os.MkdirAll(filepath.Dir(dest), fs.DirStrict); err != nil {
+				return nil, fmt.Errorf("failed to copy %s: %w", file, err)
+			}
+			if err := os.WriteFile(dest, side.content, fs.FileStrict); err != nil {
+				return nil, fmt.Errorf("failed to copy %s: %w", file, err)
+			}
+		}
+		files = append(files, entry)
+	}
+	return files, nil
+}
+
+// readDiffFile reads a regular file of a worktree, returning nil when it does
+// not exist or is something else, such as a submodule or linked directory
+func readDiffFile(worktreePath, file string) ([]byte, error) {
+	fullPath := filepath.Join(worktreePath, filepath.FromSlash(file))
+	info, err := os.Stat(fullPath)
+	if errors.Is(err, os.ErrNotExist) || (err == nil && !info.Mode().IsRegular()) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", file, err)
+	}
+
+	content, err := os.ReadFile(fullPath) // nolint:gosec // Path listed by git
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", file, err)
+	}
+	if content == nil {
+		content = []byte{}
+	}
+	return content, nil
+}
+
+// redactSecrets replaces the values of assignments whose key matches one of
+// patterns with an HMAC of the value under key. Binary content is returned
+// unchanged. Reports whether anything was redacted.
+func redactSecrets(content []byte, patterns []string, key []byte) ([]byte, bool) {
+	if content == nil || bytes.IndexByte(content, 0) != -1 {
+		return content, false
+	}
+
+	redacted := false
+	lines := strings.Split(string(content), "\n")
+	for i, line := range lines {
+		match := assignmentPattern.FindStringSubmatch(line)
+		if match == nil || !matchesRedactPattern(patterns, match[2]) {
+			continue
+		}
+		mac := hmac.New(sha256.New, key)
+		mac.Write([]byte(match[3]))
+		lines[i] = match[1] + "<redacted:" + hex.EncodeToString(mac.Sum(nil))[:8] + ">" + match[4]
+		redacted = true
+	}
+	if !redacted {
+		return content, false
+	}
+	return []byte(strings.Join(lines, "\n")), true
+}
+
+// matchesRedactPattern matches name against glob patterns, ignoring case
+func matchesRedactPattern(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(strings.ToUpper(pattern), strings.ToUpper(name)); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// fillDiffPatch diffs one staged file and counts its changed lines
+func fillDiffPatch(tmpDir, fromLabel, toLabel string, file *diffFile) error {
+	before, after := "/dev/null", "/dev/null"
+	if file.Status != "added" {
+		before = fromLabel + "/" + file.Path
+	}
+	if file.Status != "deleted" {
+		after = toLabel + "/" + file.Path
+	}
+
+	var patch bytes.Buffer
+	if _, err := git.DiffNoIndex(tmpDir, &patch, []string{"--no-prefix", "--no-color"}, before, after); err != nil {
+		return err
+	}
+	file.Patch = patch.String()
+
+	inHunk := false
+	for _, line := range strings.Split(file.Patch, "\n") {
+		switch {
+		case strings.HasPrefix(line, "@@"):
+			inHunk = true
+		case !inHunk && strings.HasPrefix(line, "Binary files "):
+			file.Binary = true
+		case inHunk && strings.HasPrefix(line, "+"):
+			file.Added++
+		case inHunk && strings.HasPrefix(line, "-"):
+			file.Deleted++
+		}
+	}
+	return nil
+}
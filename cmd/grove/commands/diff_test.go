@@ -0,0 +1,172 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sqve/grove/internal/testutil"
+	testgit "github.com/sqve/grove/internal/testutil/git"
+)
+
+func TestNewDiffCmd(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr bool
+	}{
+		{"two worktrees", []string{"main", "feat-auth"}, false},
+		{"paths after dash", []string{"main", "feat-auth", "--", ".env", "src"}, false},
+		{"one worktree", []string{"main"}, true},
+		{"paths without dash", []string{"main", "feat-auth", ".env"}, true},
+		{"dash too early", []string{"main", "--", "feat-auth"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := NewDiffCmd()
+			if err := cmd.ParseFlags(tt.args); err != nil {
+				t.Fatalf("ParseFlags failed: %v", err)
+			}
+			err := cmd.Args(cmd, cmd.Flags().Args())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestRedactSecrets(t *testing.T) {
+	patterns := []string{"*KEY*", "*PASSWORD*"}
+	key := []byte("test")
+
+	content := []byte("# Local settings\nAPI_KEY=abc123\nexport DB_PASSWORD = \"hunter2\"\r\nPORT=3000\ndatabase:\n  password: hunter2\n")
+	redacted, ok := redactSecrets(content, patterns, key)
+	if !ok {
+		t.Fatal("expected values to be redacted")
+	}
+
+	got := string(redacted)
+	for _, secret := range []string{"abc123", "hunter2"} {
+		if strings.Contains(got, secret) {
+			t.Errorf("expected %q redacted, got %q", secret, got)
+		}
+	}
+	for _, kept := range []string{"# Local settings\n", "API_KEY=<redacted:", "export DB_PASSWORD = <redacted:", ">\r\n", "PORT=3000\n", "database:\n", "  password: <redacted:"} {
+		if !strings.Contains(got, kept) {
+			t.Errorf("expected %q kept, got %q", kept, got)
+		}
+	}
+
+	again, _ := redactSecrets(content, patterns, key)
+	if string(again) != got {
+		t.Error("expected the same value to redact the same way")
+	}
+	other, _ := redactSecrets([]byte("API_KEY=xyz\n"), patterns, key)
+	if strings.Contains(got, string(other)) {
+		t.Error("expected different values to redact differently")
+	}
+
+	if _, ok := redactSecrets([]byte("PORT=3000\n"), patterns, key); ok {
+		t.Error("expected nothing redacted without matching keys")
+	}
+	if _, ok := redactSecrets([]byte("API_KEY=\x00abc"), patterns, key); ok {
+		t.Error("expected binary content left alone")
+	}
+}
+
+func TestStageDiffFiles(t *testing.T) {
+	root := testutil.TempDir(t)
+	fromPath := filepath.Join(root, "main")
+	toPath := filepath.Join(root, "feat-auth")
+	testutil.WriteFile(t, filepath.Join(fromPath, "same.txt"), "same")
+	testutil.WriteFile(t, filepath.Join(toPath, "same.txt"), "same")
+	testutil.WriteFile(t, filepath.Join(fromPath, "changed.txt"), "before")
+	testutil.WriteFile(t, filepath.Join(toPath, "changed.txt"), "after")
+	testutil.WriteFile(t, filepath.Join(fromPath, "gone.txt"), "gone")
+	testutil.WriteFile(t, filepath.Join(toPath, "src", "new.txt"), "new")
+	testutil.WriteFile(t, filepath.Join(fromPath, ".env"), "API_KEY=one\n")
+	testutil.WriteFile(t, filepath.Join(toPath, ".env"), "API_KEY=two\n")
+
+	candidates := map[string]bool{"same.txt": false, "changed.txt": false, "gone.txt": false, "src/new.txt": false, ".env": true}
+
+	tmpDir := testutil.TempDir(t)
+	files, err := stageDiffFiles(tmpDir, "main", "feat-auth", fromPath, toPath, candidates, []string{"*KEY*"})
+	if err != nil {
+		t.Fatalf("stageDiffFiles failed: %v", err)
+	}
+
+	want := []struct{ path, status string }{
+		{".env", "modified"},
+		{"changed.txt", "modified"},
+		{"gone.txt", "deleted"},
+		{"src/new.txt", "added"},
+	}
+	if len(files) != len(want) {
+		t.Fatalf("expected %d files, got %+v", len(want), files)
+	}
+	for i, w := range want {
+		if files[i].Path != w.path || files[i].Status != w.status {
+			t.Errorf("file %d: expected %s %s, got %s %s", i, w.path, w.status, files[i].Path, files[i].Status)
+		}
+	}
+	if !files[0].Ignored || !files[0].Redacted {
+		t.Errorf("expected .env ignored and redacted, got %+v", files[0])
+	}
+
+	staged, err := os.ReadFile(filepath.Join(tmpDir, "feat-auth", ".env"))
+	if err != nil {
+		t.Fatalf("expected .env staged: %v", err)
+	}
+	if strings.Contains(string(staged), "two") {
+		t.Errorf("expected the staged .env redacted, got %q", staged)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "main", "same.txt")); !os.IsNotExist(err) {
+		t.Error("expected identical files not to be staged")
+	}
+
+	if err := fillDiffPatch(tmpDir, "main", "feat-auth", &files[3]); err != nil {
+		t.Fatalf("fillDiffPatch failed: %v", err)
+	}
+	if files[3].Added != 1 || files[3].Deleted != 0 || !strings.Contains(files[3].Patch, "+++ feat-auth/src/new.txt") {
+		t.Errorf("unexpected patch for an added file: %+v", files[3])
+	}
+}
+
+func TestListDiffFiles(t *testing.T) {
+	ws := testgit.NewGroveWorkspace(t, "main")
+	mainPath := ws.WorktreePath("main")
+	testutil.WriteFile(t, filepath.Join(mainPath, "shared.txt"), "shared")
+	testutil.WriteFile(t, filepath.Join(mainPath, "committed.txt"), "before")
+	testutil.MustExec(t, mainPath, "git", "add", ".")
+	testutil.MustExec(t, mainPath, "git", "commit", "-m", "add files")
+
+	featPath := ws.CreateWorktree("feat")
+	testutil.WriteFile(t, filepath.Join(featPath, "committed.txt"), "after")
+	testutil.MustExec(t, featPath, "git", "commit", "-am", "change file")
+	testutil.WriteFile(t, filepath.Join(mainPath, "untracked.txt"), "new")
+	testutil.WriteFile(t, filepath.Join(featPath, ".gitkeep"), "edited")
+
+	candidates, err := listDiffFiles(ws.BareDir, mainPath, featPath, nil)
+	if err != nil {
+		t.Fatalf("listDiffFiles failed: %v", err)
+	}
+	for _, file := range []string{"committed.txt", "untracked.txt", ".gitkeep"} {
+		if _, ok := candidates[file]; !ok {
+			t.Errorf("expected %s listed, got %v", file, candidates)
+		}
+	}
+	// Files both worktrees have unchanged from the same commit are not read
+	if _, ok := candidates["shared.txt"]; ok {
+		t.Errorf("expected shared.txt skipped, got %v", candidates)
+	}
+
+	candidates, err = listDiffFiles(ws.BareDir, mainPath, featPath, []string{"untracked.txt"})
+	if err != nil {
+		t.Fatalf("listDiffFiles failed: %v", err)
+	}
+	if len(candidates) != 1 {
+		t.Errorf("expected only untracked.txt under its path, got %v", candidates)
+	}
+}
@@ -1,7 +1,9 @@
 package commands
 
 import (
+	"cmp"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -748,6 +750,9 @@ func detectConfigIssues(bareDir string, result *DoctorResult) {
 
 	// Check stale lock files
 	detectStaleLockFiles(workspaceRoot, result)
+
+	// Check worktrees are where the path template puts them
+	detectMisplacedWorktrees(bareDir, result)
 }
 
 func detectInvalidToml(workspaceRoot string, result *DoctorResult) {
@@ -794,19 +799,22 @@ func detectInvalidHooks(workspaceRoot string, result *DoctorResult) {
 		return
 	}
 
-	var cfg struct {
-		Hooks struct {
-			Add []string `toml:"add"`
-		} `toml:"hooks"`
-	}
-
+	var cfg config.FileConfig
 	if _, err := toml.Decode(string(content), &cfg); err != nil {
 		// Invalid TOML - already reported by detectInvalidToml
 		return
 	}
 
+	var commands []string
+	commands = append(commands, cfg.Hooks.PreAdd...)
+	commands = append(commands, cfg.Hooks.Add...)
+	commands = append(commands, cfg.Hooks.PostSwitch...)
+	commands = append(commands, cfg.Hooks.PreRemove...)
+	commands = append(commands, cfg.Hooks.PostRemove...)
+	commands = append(commands, cfg.Hooks.PostMove...)
+
 	// Check each hook command
-	for _, cmd := range cfg.Hooks.Add {
+	for _, cmd := range commands {
 		// Extract the executable (first word)
 		parts := strings.Fields(cmd)
 		if len(parts) == 0 {
@@ -845,6 +853,73 @@ func detectStaleLockFiles(workspaceRoot string, result *DoctorResult) {
 	}
 }
 
+func detectMisplacedWorktrees(bareDir string, result *DoctorResult) {
+	infos, err := git.ListWorktreesWithInfo(bareDir, true)
+	if err != nil {
+		logger.Debug("Failed to list worktrees: %v", err)
+		return
+	}
+
+	workspaceRoot := filepath.Dir(bareDir)
+	tmpl := config.GetMergedWorktreePathTemplate(cmp.Or(findConfigWorktree(bareDir), bareDir))
+	if err := workspace.ValidatePathTemplate(tmpl); err != nil {
+		result.Issues = append(result.Issues, Issue{
+			Category:    CategoryConfig,
+			Severity:    SeverityError,
+			Message:     "Invalid worktree path template",
+			Path:        tmpl,
+			Details:     []string{err.Error()},
+			AutoFixable: false,
+		})
+		return
+	}
+
+	for _, info := range infos {
+		// Detached worktrees are named after the ref they were created from,
+		// which is no longer known
+		if info.Detached || info.Prunable {
+			continue
+		}
+
+		expected, err := workspace.ExpectedWorktreePath(workspaceRoot, tmpl, info.Path, info.Branch)
+		if err != nil {
+			logger.Debug("Failed to resolve path template for %s: %v", info.Path, err)
+			continue
+		}
+		if fs.PathsEqual(expected, info.Path) {
+			continue
+		}
+
+		issue := Issue{
+			Category:    CategoryConfig,
+			Severity:    SeverityWarning,
+			Message:     "Worktree does not match path template",
+			Path:        workspaceRelPath(workspaceRoot, info.Path),
+			Details:     []string{"expected at " + workspaceRelPath(workspaceRoot, expected)},
+			FixHint:     "grove doctor --fix",
+			AutoFixable: true,
+		}
+		if info.Locked {
+			issue.FixHint = fmt.Sprintf("grove unlock %s, then grove doctor --fix", git.WorktreeName(infos, info))
+			issue.AutoFixable = false
+		}
+		result.Issues = append(result.Issues, issue)
+	}
+}
+
+// workspaceRelPath returns path relative to workspaceRoot, or path itself
+// when it lies outside the workspace
+func workspaceRelPath(workspaceRoot, path string) string {
+	if !fs.PathHasPrefix(path, workspaceRoot) {
+		return path
+	}
+	rel, err := filepath.Rel(workspaceRoot, path)
+	if err != nil {
+		return path
+	}
+	return filepath.ToSlash(rel)
+}
+
 // Phase 4: Fix capability
 
 func fixIssues(bareDir string, result *DoctorResult) {
@@ -865,6 +940,8 @@ func fixIssues(bareDir string, result *DoctorResult) {
 			err = fixStaleWorktreeEntry(bareDir, issue)
 		case "Broken .git pointer":
 			err = fixBrokenGitPointer(bareDir, workspaceRoot, issue)
+		case "Worktree does not match path template":
+			err = fixMisplacedWorktree(bareDir, issue)
 		}
 
 		if err != nil {
@@ -912,6 +989,44 @@ func fixBrokenGitPointer(bareDir, workspaceRoot string, issue *Issue) error {
 	return os.WriteFile(gitFile, []byte(content), fs.FileGit) //nolint:gosec // Git files need 0644 permissions
 }
 
+// fixMisplacedWorktree moves a worktree to where the path template puts it
+func fixMisplacedWorktree(bareDir string, issue *Issue) error {
+	workspaceRoot := filepath.Dir(bareDir)
+	oldPath := filepath.FromSlash(issue.Path)
+	if !filepath.IsAbs(oldPath) {
+		oldPath = filepath.Join(workspaceRoot, oldPath)
+	}
+
+	branch, err := git.GetCurrentBranch(oldPath)
+	if err != nil {
+		return err
+	}
+	tmpl := config.GetMergedWorktreePathTemplate(cmp.Or(findConfigWorktree(bareDir), bareDir))
+	newPath, err := workspace.ExpectedWorktreePath(workspaceRoot, tmpl, oldPath, branch)
+	if err != nil {
+		return err
+	}
+
+	if cwd, err := os.Getwd(); err == nil && (fs.PathsEqual(cwd, oldPath) || fs.PathHasPrefix(cwd, oldPath)) {
+		return errors.New("cannot move current worktree; switch to a different worktree first")
+	}
+	if fs.PathExists(newPath) {
+		return fmt.Errorf("%s already exists", newPath)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(newPath), fs.DirGit); err != nil {
+		return err
+	}
+	if err := git.MoveWorktree(bareDir, oldPath, newPath); err != nil {
+		workspace.RemoveEmptyParents(newPath, workspaceRoot)
+		return err
+	}
+
+	renameWorktreeEnv(bareDir, oldPath, newPath)
+	workspace.RemoveEmptyParents(oldPath, workspaceRoot)
+	return nil
+}
+
 // Phase 5: JSON output
 
 type jsonIssue struct {
@@ -1029,44 +1144,104 @@ func outputPerfAnalysis(bareDir string) error {
 		return fmt.Errorf("failed to list worktrees: %w", err)
 	}
 
+	usage := newDiskUsage()
+	var total, shared int64
+
 	// Calculate size for each worktree
 	for _, worktreePath := range worktrees {
-		size, err := calculateDirSize(worktreePath)
+		size, sharedSize, err := usage.add(worktreePath)
 		if err != nil {
 			logger.Debug("Failed to calculate size for %s: %v", worktreePath, err)
 
 			continue
 		}
+		total += size
+		shared += sharedSize
 
 		relPath, _ := filepath.Rel(workspaceRoot, worktreePath)
-		fmt.Printf("  %s  %s\n", formatSize(size), relPath)
+		fmt.Printf("  %s  %s%s\n", formatSize(size), relPath, sharedSuffix(sharedSize))
 	}
 
 	// Calculate .bare size
-	bareSize, err := calculateDirSize(bareDir)
+	bareSize, bareShared, err := usage.add(bareDir)
 	if err == nil {
+		total += bareSize
+		shared += bareShared
 		fmt.Printf("  %s  .bare (shared)\n", formatSize(bareSize))
 	}
 
+	if shared > 0 {
+		fmt.Println()
+		fmt.Printf("  %s  total, %s on disk\n", formatSize(total), strings.TrimSpace(formatSize(total-shared)))
+	}
+
 	return nil
 }
 
-func calculateDirSize(path string) (int64, error) {
-	var size int64
+// sharedSuffix describes how much of a worktree shares storage with
+// worktrees listed before it
+func sharedSuffix(shared int64) string {
+	if shared == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (%s in shared extents)", strings.TrimSpace(formatSize(shared)))
+}
+
+// diskUsage sums file sizes across directories. Storage shared through hard
+// links or reflinks is only counted as used by the first file that has it.
+type diskUsage struct {
+	files   map[fs.FileID]bool
+	extents map[fs.Extent]bool
+}
+
+func newDiskUsage() *diskUsage {
+	return &diskUsage{
+		files:   make(map[fs.FileID]bool),
+		extents: make(map[fs.Extent]bool),
+	}
+}
 
-	err := filepath.Walk(path, func(_ string, info os.FileInfo, err error) error {
+// add returns the size of all files below path and how much of it shares
+// storage with files added before
+func (u *diskUsage) add(path string) (size, shared int64, err error) {
+	err = filepath.Walk(path, func(filePath string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
 
-		if !info.IsDir() {
-			size += info.Size()
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		size += info.Size()
+
+		if id, ok := fs.HardLinkID(info); ok {
+			if u.files[id] {
+				shared += info.Size()
+				return nil
+			}
+			u.files[id] = true
+		}
+
+		if info.Size() == 0 {
+			return nil
+		}
+		extents, err := fs.SharedExtents(filePath)
+		if err != nil {
+			logger.Debug("Failed to read extents of %s: %v", filePath, err)
+			return nil
+		}
+		for _, extent := range extents {
+			if u.extents[extent] {
+				shared += int64(min(extent.Length, uint64(info.Size()))) // nolint:gosec // Bounded by file size
+				continue
+			}
+			u.extents[extent] = true
 		}
 
 		return nil
 	})
 
-	return size, err
+	return size, shared, err
 }
 
 func formatSize(bytes int64) string {
@@ -1,10 +1,14 @@
 package commands
 
 import (
+	"os"
+	"path/filepath"
+	"runtime"
 	"strings"
 	"testing"
 
 	"github.com/sqve/grove/internal/config"
+	"github.com/sqve/grove/internal/testutil"
 )
 
 func TestParseVersion(t *testing.T) {
@@ -305,6 +309,42 @@ func TestFormatSize(t *testing.T) {
 	}
 }
 
+func TestDiskUsage(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hard links are not detected on Windows")
+	}
+
+	dir := testutil.TempDir(t)
+	first := filepath.Join(dir, "first")
+	second := filepath.Join(dir, "second")
+	for _, d := range []string{first, second} {
+		if err := os.MkdirAll(d, 0o750); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(first, "model.bin"), make([]byte, 2048), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(second, "own.txt"), make([]byte, 100), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Link(filepath.Join(first, "model.bin"), filepath.Join(second, "model.bin")); err != nil {
+		t.Fatal(err)
+	}
+
+	usage := newDiskUsage()
+
+	size, shared, err := usage.add(first)
+	if err != nil || size != 2048 || shared != 0 {
+		t.Errorf("first: size=%d shared=%d err=%v; want 2048, 0", size, shared, err)
+	}
+
+	size, shared, err = usage.add(second)
+	if err != nil || size != 2148 || shared != 2048 {
+		t.Errorf("second: size=%d shared=%d err=%v; want 2148, 2048", size, shared, err)
+	}
+}
+
 func TestCategoryToString(t *testing.T) {
 	tests := []struct {
 		category Category
@@ -0,0 +1,63 @@
+package commands
+
+import (
+	"strings"
+
+	"github.com/sqve/grove/internal/config"
+	"github.com/sqve/grove/internal/logger"
+	"github.com/sqve/grove/internal/workspace"
+)
+
+// allocateWorktreeEnv assigns the [env] values of .grove.toml to a new worktree
+// and writes them to its .env.grove. Returns nil if nothing is declared or
+// allocation fails, which is reported as a warning.
+func allocateWorktreeEnv(bareDir, worktreePath, branch, configWorktree string) map[string]string {
+	configDir := trustedConfigDir(worktreePath, configWorktree)
+	if configDir == "" {
+		return nil
+	}
+
+	specs, err := config.GetEnvSpecs(configDir)
+	if err != nil {
+		logger.Warning("Failed to allocate env values: %v", err)
+		return nil
+	}
+	if len(specs) == 0 {
+		return nil
+	}
+
+	values, err := workspace.AllocateEnv(bareDir, worktreePath, branch, specs)
+	if err != nil {
+		logger.Warning("Failed to allocate env values: %v", err)
+		return nil
+	}
+	if err := workspace.WriteEnvFile(worktreePath, values); err != nil {
+		logger.Warning("Failed to write %s: %v", workspace.EnvFileName, err)
+	}
+	return values
+}
+
+func logEnvResult(values map[string]string) {
+	if len(values) == 0 {
+		return
+	}
+	logger.ListSubItem("allocated %s", strings.Join(workspace.EnvList(values), " "))
+}
+
+// releaseWorktreeEnv frees the [env] values of a removed worktree. Failures
+// only matter until the next allocation, which frees values of worktrees
+// that no longer exist.
+func releaseWorktreeEnv(bareDir, worktree string) {
+	if err := workspace.ReleaseEnv(bareDir, worktree); err != nil {
+		logger.Debug("Failed to release env values of %s: %v", worktree, err)
+	}
+}
+
+// renameWorktreeEnv keeps the [env] values of a moved worktree. Templates are
+// not expanded again for a renamed branch: resources such as databases were
+// created under the allocated names.
+func renameWorktreeEnv(bareDir, oldWorktree, newWorktree string) {
+	if err := workspace.RenameEnv(bareDir, oldWorktree, newWorktree); err != nil {
+		logger.Warning("Failed to update env values for %s: %v", newWorktree, err)
+	}
+}
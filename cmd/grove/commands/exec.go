@@ -1,41 +1,116 @@
 package commands
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"slices"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/sqve/grove/internal/config"
 	"github.com/sqve/grove/internal/formatter"
 	"github.com/sqve/grove/internal/git"
+	"github.com/sqve/grove/internal/hooks"
 	"github.com/sqve/grove/internal/logger"
+	"github.com/sqve/grove/internal/picker"
+	"github.com/sqve/grove/internal/styles"
 	"github.com/sqve/grove/internal/workspace"
 )
 
 type execTarget struct {
 	label string
+	repo  string // Repository of a multi-repository project
 	name  string
 	path  string
+	env   []string // Values allocated to the worktree by [env], as NAME=value
+}
+
+func newExecTarget(bareDir string, info *git.WorktreeInfo) execTarget {
+	return execTarget{
+		label: formatter.WorktreeLabel(info),
+		name:  filepath.Base(info.Path),
+		path:  info.Path,
+		env:   workspace.EnvList(workspace.LookupEnv(bareDir, info.Path)),
+	}
+}
+
+func newRepoExecTarget(repo workspace.ProjectRepo, info *git.WorktreeInfo) execTarget {
+	target := newExecTarget(repo.BareDir(), info)
+	target.repo = repo.Name
+	target.label = repo.Name + " " + target.label
+	return target
+}
+
+// id names the target in prefixes and messages, including its repository
+func (t execTarget) id() string {
+	if t.repo != "" {
+		return t.repo + "/" + t.name
+	}
+	return t.name
+}
+
+// execStatus describes how a parallel execution ended
+type execStatus string
+
+const (
+	execSucceeded execStatus = "ok"
+	execFailed    execStatus = "failed"
+	execCancelled execStatus = "cancelled"
+	execSkipped   execStatus = "skipped"
+)
+
+// execCancelGracePeriod is how long a cancelled command may take to exit
+// before it is killed and its output pipes are closed
+const execCancelGracePeriod = 5 * time.Second
+
+type execResult struct {
+	target   execTarget
+	status   execStatus
+	exitCode int
+	duration time.Duration
+	err      error
+}
+
+// execOptions holds the flags of grove exec
+type execOptions struct {
+	all      bool
+	allRepos bool
+	failFast bool
+	jobs     int
+	group    bool
 }
 
 // NewExecCmd creates the exec command
 func NewExecCmd() *cobra.Command {
-	var all bool
-	var failFast bool
+	var opts execOptions
 
 	cmd := &cobra.Command{
 		Use:   "exec [--all | <worktree>...] -- <command>",
 		Short: "Execute a command in worktrees",
 		Long: `Run a command in one or more worktrees.
 
+Values allocated to each worktree through [env] in .grove.toml, such as
+PORT, are set in the command's environment.
+
 Examples:
   grove exec --all -- npm install                        # All worktrees
   grove exec main feature -- npm ci                      # Named worktrees
+  grove exec -- npm ci                                   # Pick worktrees interactively
   grove exec --all --fail-fast -- go build               # Stop on first failure
-  grove exec --all -- bash -c "npm install && npm test"  # Multiple commands`,
+  grove exec --all -j 4 -- npm ci                        # Run in 4 worktrees at a time
+  grove exec --all -j 4 --group -- npm test              # Print output per worktree when done
+  grove exec --all -- bash -c "npm install && npm test"  # Multiple commands
+  grove exec --all-repos feat-auth -- git push           # feat-auth of every project repo
+
+From a project directory outside its repos, worktrees are looked up in every
+repo of the grove.workspace.toml project.`,
 		Args:              cobra.ArbitraryArgs,
 		ValidArgsFunction: completeExecArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -49,30 +124,48 @@ Examples:
 				worktrees = args[:dashPos]
 				command = args[dashPos:]
 			}
-			return runExec(all, failFast, worktrees, command)
+			if !opts.all && len(worktrees) == 0 && len(command) > 0 && pickerAvailable() {
+				picked, err := pickWorktrees(picker.Options{Prompt: "Execute in", Multi: true}, nil)
+				if err != nil {
+					return err
+				}
+				worktrees = picked
+			}
+			return runExec(opts, worktrees, command)
 		},
 	}
 
-	cmd.Flags().BoolVarP(&all, "all", "a", false, "Execute in all worktrees")
-	cmd.Flags().BoolVar(&failFast, "fail-fast", false, "Stop on first failure")
+	cmd.Flags().BoolVarP(&opts.all, "all", "a", false, "Execute in all worktrees")
+	cmd.Flags().BoolVar(&opts.allRepos, "all-repos", false, "Execute in worktrees of every repo in the project")
+	cmd.Flags().BoolVar(&opts.failFast, "fail-fast", false, "Stop on first failure")
+	cmd.Flags().IntVarP(&opts.jobs, "parallel", "j", 1, "Number of worktrees to execute in concurrently")
+	cmd.Flags().BoolVar(&opts.group, "group", false, "Buffer output per worktree instead of interleaving (with --parallel)")
 	cmd.Flags().BoolP("help", "h", false, "Help for exec")
 
 	return cmd
 }
 
-func runExec(all, failFast bool, worktrees, command []string) error {
+func runExec(opts execOptions, worktrees, command []string) error {
 	// Validation: must have a command
 	if len(command) == 0 {
 		return errors.New("no command specified after --")
 	}
 
+	if opts.jobs < 1 {
+		return errors.New("--parallel must be at least 1")
+	}
+
+	if opts.group && opts.jobs == 1 {
+		return errors.New("--group requires --parallel greater than 1")
+	}
+
 	// Validation: cannot use both --all and specific worktrees
-	if all && len(worktrees) > 0 {
+	if opts.all && len(worktrees) > 0 {
 		return errors.New("cannot use --all with specific worktrees")
 	}
 
 	// Validation: must specify --all or at least one worktree
-	if !all && len(worktrees) == 0 {
+	if !opts.all && len(worktrees) == 0 {
 		return errors.New("must specify --all or at least one worktree")
 	}
 
@@ -82,36 +175,25 @@ func runExec(all, failFast bool, worktrees, command []string) error {
 		return fmt.Errorf("failed to get current directory: %w", err)
 	}
 
-	bareDir, err := workspace.FindBareDir(cwd)
+	repos, err := projectRepos(cwd, opts.allRepos)
 	if err != nil {
 		return err
 	}
 
-	// Get worktree info
-	infos, err := git.ListWorktreesWithInfo(bareDir, true)
+	var targets []execTarget
+	if repos != nil {
+		targets, err = repoExecTargets(repos, opts.all, worktrees)
+	} else {
+		targets, err = workspaceExecTargets(cwd, opts.all, worktrees)
+	}
 	if err != nil {
-		return fmt.Errorf("failed to list worktrees: %w", err)
+		return err
 	}
 
-	// Determine which worktrees to execute in
-	var targets []execTarget
-	if all {
-		for _, info := range infos {
-			targets = append(targets, execTarget{label: formatter.WorktreeLabel(info), name: filepath.Base(info.Path), path: info.Path})
-		}
-	} else {
-		seen := make(map[string]bool)
-		for _, name := range worktrees {
-			info := git.FindWorktree(infos, name)
-			if info == nil {
-				return fmt.Errorf("worktree not found: %s", name)
-			}
-			if seen[info.Path] {
-				continue
-			}
-			seen[info.Path] = true
-			targets = append(targets, execTarget{label: formatter.WorktreeLabel(info), name: filepath.Base(info.Path), path: info.Path})
-		}
+	if opts.jobs > 1 {
+		results := runExecParallel(targets, command, opts.jobs, opts.failFast, opts.group)
+		printExecSummary(results)
+		return execResultsError(results, opts.failFast)
 	}
 
 	// Execute command in each worktree
@@ -122,18 +204,34 @@ func runExec(all, failFast bool, worktrees, command []string) error {
 
 		cmd := exec.Command(command[0], command[1:]...) //nolint:gosec
 		cmd.Dir = target.path
+		cmd.Env = append(os.Environ(), target.env...)
 		cmd.Stdout = os.Stdout
 		cmd.Stderr = os.Stderr
+		flush := func() {}
+		if logger.EventsEnabled() {
+			stdout, stderr := execEventWriters(target)
+			cmd.Stdout = stdout
+			cmd.Stderr = stderr
+			flush = func() {
+				_ = stdout.Flush()
+				_ = stderr.Flush()
+			}
+		}
 
-		if err := cmd.Run(); err != nil {
-			failed = append(failed, target.name)
-			if failFast {
-				return fmt.Errorf("command failed in %s: %w", target.name, err)
+		err := cmd.Run()
+		flush()
+		emitExecFinished(target, err)
+		if err != nil {
+			failed = append(failed, target.id())
+			if opts.failFast {
+				return fmt.Errorf("command failed in %s: %w", target.id(), err)
 			}
 		} else {
 			succeeded++
 		}
-		fmt.Fprintln(os.Stderr) // Blank line between worktrees
+		if !logger.EventsEnabled() {
+			fmt.Fprintln(os.Stderr) // Blank line between worktrees
+		}
 	}
 
 	// Print summary
@@ -154,6 +252,338 @@ func runExec(all, failFast bool, worktrees, command []string) error {
 	return nil
 }
 
+// workspaceExecTargets returns the worktrees of the current workspace to
+// execute in
+func workspaceExecTargets(cwd string, all bool, worktrees []string) ([]execTarget, error) {
+	bareDir, err := workspace.FindBareDir(cwd)
+	if err != nil {
+		return nil, err
+	}
+
+	infos, err := git.ListWorktreesWithInfo(bareDir, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list worktrees: %w", err)
+	}
+
+	var targets []execTarget
+	if all {
+		for _, info := range infos {
+			targets = append(targets, newExecTarget(bareDir, info))
+		}
+		return targets, nil
+	}
+
+	seen := make(map[string]bool)
+	for _, name := range worktrees {
+		info := git.FindWorktree(infos, name)
+		if info == nil {
+			return nil, fmt.Errorf("worktree not found: %s", name)
+		}
+		if seen[info.Path] {
+			continue
+		}
+		seen[info.Path] = true
+		targets = append(targets, newExecTarget(bareDir, info))
+	}
+	return targets, nil
+}
+
+// repoExecTargets returns the worktrees to execute in across the
+// repositories of a project. Named worktrees are looked up in each repository
+// and must exist in at least one.
+func repoExecTargets(repos []workspace.ProjectRepo, all bool, worktrees []string) ([]execTarget, error) {
+	var targets []execTarget
+	found := make(map[string]bool)
+	for _, repo := range repos {
+		infos, err := git.ListWorktreesWithInfo(repo.BareDir(), true)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list worktrees of %s: %w", repo.Name, err)
+		}
+
+		if all {
+			for _, info := range infos {
+				targets = append(targets, newRepoExecTarget(repo, info))
+			}
+			continue
+		}
+
+		seen := make(map[string]bool)
+		for _, name := range worktrees {
+			info := git.FindWorktree(infos, name)
+			if info == nil {
+				continue
+			}
+			found[name] = true
+			if seen[info.Path] {
+				continue
+			}
+			seen[info.Path] = true
+			targets = append(targets, newRepoExecTarget(repo, info))
+		}
+	}
+
+	for _, name := range worktrees {
+		if !found[name] {
+			return nil, fmt.Errorf("worktree not found in any repo: %s", name)
+		}
+	}
+	return targets, nil
+}
+
+// runExecParallel runs command in up to jobs targets at once. With failFast,
+// the first failure cancels running siblings and skips those not yet started.
+func runExecParallel(targets []execTarget, command []string, jobs int, failFast, group bool) []execResult {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	results := make([]execResult, len(targets))
+	sem := make(chan struct{}, jobs)
+	var outputMu sync.Mutex
+	var wg sync.WaitGroup
+
+	for i, target := range targets {
+		results[i] = execResult{target: target, status: execSkipped}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			defer func() { <-sem }()
+
+			if ctx.Err() != nil {
+				return
+			}
+
+			results[i] = runExecTarget(ctx, target, command, group, &outputMu)
+			if failFast && results[i].status == execFailed {
+				cancel()
+			}
+		}()
+	}
+
+	wg.Wait()
+	return results
+}
+
+// runExecTarget runs command in a single target. Output is either prefixed
+// line by line or buffered and written in one piece when the command ends.
+func runExecTarget(ctx context.Context, target execTarget, command []string, group bool, outputMu *sync.Mutex) execResult {
+	result := execResult{target: target}
+
+	cmd := exec.CommandContext(ctx, command[0], command[1:]...) //nolint:gosec
+	cmd.Dir = target.path
+	cmd.Env = append(os.Environ(), target.env...)
+	setCancelProcessGroup(cmd)
+	cmd.WaitDelay = execCancelGracePeriod
+
+	var flush func()
+	if logger.EventsEnabled() {
+		// Events name their worktree, so output needs no grouping or prefix
+		stdout, stderr := execEventWriters(target)
+		cmd.Stdout = stdout
+		cmd.Stderr = stderr
+		flush = func() {
+			_ = stdout.Flush()
+			_ = stderr.Flush()
+		}
+	} else if group {
+		buf := &streamBuffer{}
+		cmd.Stdout = buf.writer(os.Stdout)
+		cmd.Stderr = buf.writer(os.Stderr)
+		flush = func() {
+			outputMu.Lock()
+			defer outputMu.Unlock()
+			logger.Info("%s", target.label)
+			buf.replay()
+			fmt.Fprintln(os.Stderr) // Blank line between worktrees
+		}
+	} else {
+		prefix := styles.Render(&styles.Dimmed, fmt.Sprintf("[%s]", target.id()))
+		stdout := hooks.NewPrefixWriter(prefix, os.Stdout, outputMu)
+		stderr := hooks.NewPrefixWriter(prefix, os.Stderr, outputMu)
+		cmd.Stdout = stdout
+		cmd.Stderr = stderr
+		flush = func() {
+			_ = stdout.Flush()
+			_ = stderr.Flush()
+		}
+	}
+
+	start := time.Now()
+	err := cmd.Run()
+	result.duration = time.Since(start)
+	flush()
+	if ctx.Err() == nil {
+		emitExecFinished(target, err)
+	}
+
+	switch {
+	case err == nil:
+		result.status = execSucceeded
+	case ctx.Err() != nil:
+		result.status = execCancelled
+		result.exitCode = -1
+	default:
+		result.status = execFailed
+		result.err = err
+		result.exitCode = -1
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			result.exitCode = exitErr.ExitCode()
+		}
+	}
+
+	return result
+}
+
+// execEventWriters returns writers that emit the output of target as events
+func execEventWriters(target execTarget) (stdout, stderr *logger.EventWriter) {
+	return logger.NewEventWriter(logger.Event{Type: logger.EventOutput, Worktree: target.path, Stream: "stdout"}),
+		logger.NewEventWriter(logger.Event{Type: logger.EventOutput, Worktree: target.path, Stream: "stderr"})
+}
+
+// emitExecFinished reports the exit code of a command run in target. Commands
+// that could not be started report -1.
+func emitExecFinished(target execTarget, err error) {
+	exitCode := 0
+	if err != nil {
+		exitCode = -1
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			exitCode = exitErr.ExitCode()
+		}
+	}
+	logger.Emit(logger.Event{Type: logger.EventExecFinished, Worktree: target.path, ExitCode: logger.ExitCode(exitCode)})
+}
+
+// streamBuffer records output chunks with their destination so grouped output
+// keeps the original ordering of stdout and stderr.
+type streamBuffer struct {
+	mu     sync.Mutex
+	chunks []streamChunk
+}
+
+type streamChunk struct {
+	target io.Writer
+	data   []byte
+}
+
+type streamBufferWriter struct {
+	buf    *streamBuffer
+	target io.Writer
+}
+
+func (b *streamBuffer) writer(target io.Writer) io.Writer {
+	return &streamBufferWriter{buf: b, target: target}
+}
+
+func (w *streamBufferWriter) Write(p []byte) (int, error) {
+	w.buf.mu.Lock()
+	defer w.buf.mu.Unlock()
+	w.buf.chunks = append(w.buf.chunks, streamChunk{target: w.target, data: append([]byte(nil), p...)})
+	return len(p), nil
+}
+
+func (b *streamBuffer) replay() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, chunk := range b.chunks {
+		_, _ = chunk.target.Write(chunk.data)
+	}
+}
+
+// printExecSummary prints a table of exit codes and durations to stderr.
+// Targets across the repositories of a project get a repository column.
+func printExecSummary(results []execResult) {
+	nameWidth := len("WORKTREE")
+	repoWidth := 0
+	for _, r := range results {
+		nameWidth = max(nameWidth, len(r.target.name))
+		if r.target.repo != "" {
+			repoWidth = max(repoWidth, len("REPO"), len(r.target.repo))
+		}
+	}
+
+	out := os.Stderr
+	repoColumn := func(repo string) string {
+		if repoWidth == 0 {
+			return ""
+		}
+		return fmt.Sprintf("%-*s  ", repoWidth, repo)
+	}
+	_, _ = fmt.Fprintf(out, "%s%-*s  %-9s  %4s  %s\n", repoColumn("REPO"), nameWidth, "WORKTREE", "STATUS", "EXIT", "DURATION")
+	for _, r := range results {
+		exitCode := "-"
+		if r.status == execSucceeded || r.status == execFailed {
+			exitCode = fmt.Sprintf("%d", r.exitCode)
+		}
+		duration := "-"
+		if r.status != execSkipped {
+			duration = formatExecDuration(r.duration)
+		}
+
+		status := fmt.Sprintf("%-9s", r.status)
+		if !config.IsPlain() {
+			switch r.status {
+			case execSucceeded:
+				status = styles.Render(&styles.Success, status)
+			case execFailed:
+				status = styles.Render(&styles.Error, status)
+			default:
+				status = styles.Render(&styles.Dimmed, status)
+			}
+		}
+
+		_, _ = fmt.Fprintf(out, "%s%-*s  %s  %4s  %s\n", repoColumn(r.target.repo), nameWidth, r.target.name, status, exitCode, duration)
+	}
+	_, _ = fmt.Fprintln(out)
+}
+
+// formatExecDuration formats durations with sub-second precision for short runs
+func formatExecDuration(d time.Duration) string {
+	if d < time.Minute {
+		return fmt.Sprintf("%.1fs", d.Seconds())
+	}
+	return d.Round(time.Second).String()
+}
+
+// execResultsError prints the overall outcome and returns an error if any execution failed
+func execResultsError(results []execResult, failFast bool) error {
+	var failed, notRun []string
+	succeeded := 0
+	for _, r := range results {
+		switch r.status {
+		case execSucceeded:
+			succeeded++
+		case execFailed:
+			failed = append(failed, r.target.id())
+		case execCancelled, execSkipped:
+			notRun = append(notRun, r.target.id())
+		}
+	}
+
+	total := len(results)
+	switch {
+	case len(failed) == 0:
+		logger.Success("Executed in %d worktrees", total)
+		return nil
+	case failFast:
+		logger.Error("Command failed in %s; stopped %d other worktree(s)", strings.Join(failed, ", "), len(notRun))
+		return fmt.Errorf("command failed in %s", strings.Join(failed, ", "))
+	case len(failed) == total:
+		logger.Error("All %d executions failed", total)
+		return errors.New("all executions failed")
+	default:
+		logger.Warning("Executed in %d worktrees (%d succeeded, %d failed)", total, succeeded, len(failed))
+		return errors.New("some executions failed")
+	}
+}
+
 func completeExecArgs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 	if slices.Contains(os.Args, "--") {
 		return nil, cobra.ShellCompDirectiveDefault
@@ -180,10 +610,10 @@ func completeExecArgs(cmd *cobra.Command, args []string, toComplete string) ([]s
 		alreadyUsed[arg] = true
 	}
 
-	// Return worktrees not already specified (by directory name)
+	// Return worktrees not already specified (by worktree name)
 	var completions []string
 	for _, info := range infos {
-		name := filepath.Base(info.Path)
+		name := git.WorktreeName(infos, info)
 		if !alreadyUsed[name] && !alreadyUsed[info.Branch] {
 			completions = append(completions, name)
 		}
@@ -1,12 +1,14 @@
 package commands
 
 import (
+	"bytes"
 	"errors"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/sqve/grove/internal/fs"
 	"github.com/sqve/grove/internal/git"
@@ -41,7 +43,7 @@ func TestRunExec_NotInWorkspace(t *testing.T) {
 	tmpDir := testutil.TempDir(t)
 	testutil.Chdir(t, tmpDir)
 
-	err := runExec(true, false, nil, []string{"echo", "hello"})
+	err := runExec(execOptions{all: true, jobs: 1}, nil, []string{"echo", "hello"})
 	if !errors.Is(err, workspace.ErrNotInWorkspace) {
 		t.Errorf("expected ErrNotInWorkspace, got: %v", err)
 	}
@@ -49,7 +51,7 @@ func TestRunExec_NotInWorkspace(t *testing.T) {
 
 func TestRunExec_NoTargets(t *testing.T) {
 	// No --all and no worktree args
-	err := runExec(false, false, nil, []string{"echo", "hello"})
+	err := runExec(execOptions{jobs: 1}, nil, []string{"echo", "hello"})
 	if err == nil {
 		t.Error("expected error for no targets")
 	}
@@ -60,7 +62,7 @@ func TestRunExec_NoTargets(t *testing.T) {
 
 func TestRunExec_NoCommand(t *testing.T) {
 	// No command after --
-	err := runExec(true, false, nil, nil)
+	err := runExec(execOptions{all: true, jobs: 1}, nil, nil)
 	if err == nil {
 		t.Error("expected error for no command")
 	}
@@ -71,7 +73,7 @@ func TestRunExec_NoCommand(t *testing.T) {
 
 func TestRunExec_AllWithWorktrees(t *testing.T) {
 	// Both --all and worktree args specified
-	err := runExec(true, false, []string{"main"}, []string{"echo", "hello"})
+	err := runExec(execOptions{all: true, jobs: 1}, []string{"main"}, []string{"echo", "hello"})
 	if err == nil {
 		t.Error("expected error when both --all and worktrees specified")
 	}
@@ -148,7 +150,7 @@ func TestRunExec_AllWorktrees(t *testing.T) {
 	testutil.Chdir(t, mainPath)
 
 	// Run command in all worktrees (creates a marker file)
-	err := runExec(true, false, nil, []string{"touch", "exec-marker.txt"})
+	err := runExec(execOptions{all: true, jobs: 1}, nil, []string{"touch", "exec-marker.txt"})
 	if err != nil {
 		t.Fatalf("runExec failed: %v", err)
 	}
@@ -186,7 +188,7 @@ func TestRunExec_SpecificWorktrees(t *testing.T) {
 	testutil.Chdir(t, mainPath)
 
 	// Run command only in main and feature (not bugfix)
-	err := runExec(false, false, []string{"main", "feature"}, []string{"touch", "specific-marker.txt"})
+	err := runExec(execOptions{jobs: 1}, []string{"main", "feature"}, []string{"touch", "specific-marker.txt"})
 	if err != nil {
 		t.Fatalf("runExec failed: %v", err)
 	}
@@ -212,7 +214,7 @@ func TestRunExec_InvalidWorktree(t *testing.T) {
 	testutil.Chdir(t, mainPath)
 
 	// Try to run in non-existent worktree
-	err := runExec(false, false, []string{"nonexistent"}, []string{"echo", "hello"})
+	err := runExec(execOptions{jobs: 1}, []string{"nonexistent"}, []string{"echo", "hello"})
 	if err == nil {
 		t.Error("expected error for non-existent worktree")
 	}
@@ -239,7 +241,7 @@ func TestRunExec_CommandFails_ContinuesByDefault(t *testing.T) {
 
 	// Run a command that creates a marker file then fails (exit 1).
 	// Both worktrees will fail, but execution should continue to all worktrees.
-	err := runExec(true, false, nil, []string{"sh", "-c", "touch marker.txt && exit 1"})
+	err := runExec(execOptions{all: true, jobs: 1}, nil, []string{"sh", "-c", "touch marker.txt && exit 1"})
 
 	// Should return error (all executions failed)
 	if err == nil {
@@ -274,7 +276,7 @@ func TestRunExec_FailFast(t *testing.T) {
 
 	// Run a command that creates a marker then fails, with --fail-fast.
 	// Worktrees are processed in alphabetical order by branch name.
-	err := runExec(true, true, nil, []string{"sh", "-c", "touch failfast-marker.txt && exit 1"})
+	err := runExec(execOptions{all: true, failFast: true, jobs: 1}, nil, []string{"sh", "-c", "touch failfast-marker.txt && exit 1"})
 
 	// Should return error
 	if err == nil {
@@ -307,7 +309,7 @@ func TestRunExec_FindsByDirectoryName(t *testing.T) {
 	testutil.Chdir(t, mainPath)
 
 	// Run command using directory name (not branch name)
-	err := runExec(false, false, []string{"feat-auth"}, []string{"touch", "found-by-dir.txt"})
+	err := runExec(execOptions{jobs: 1}, []string{"feat-auth"}, []string{"touch", "found-by-dir.txt"})
 	if err != nil {
 		t.Fatalf("runExec should find worktree by directory name: %v", err)
 	}
@@ -428,7 +430,7 @@ func TestRunExec_PartialFailure(t *testing.T) {
 		}
 	}
 
-	err := runExec(true, false, nil, []string{"test", "-f", "marker.txt"})
+	err := runExec(execOptions{all: true, jobs: 1}, nil, []string{"test", "-f", "marker.txt"})
 	if err == nil {
 		t.Fatal("expected error for partial failure")
 	}
@@ -454,7 +456,7 @@ func TestRunExec_DuplicateWorktrees(t *testing.T) {
 
 	// Run command with same worktree specified twice. The command appends to a file,
 	// so we can check it ran only once by verifying the file content.
-	err := runExec(false, false, []string{"feature", "feature"}, []string{"sh", "-c", "echo x >> dedup-marker.txt"})
+	err := runExec(execOptions{jobs: 1}, []string{"feature", "feature"}, []string{"sh", "-c", "echo x >> dedup-marker.txt"})
 	if err != nil {
 		t.Fatalf("runExec failed: %v", err)
 	}
@@ -468,3 +470,141 @@ func TestRunExec_DuplicateWorktrees(t *testing.T) {
 		t.Errorf("expected command to run once (deduplication), but ran %d times", strings.Count(string(content), "x"))
 	}
 }
+
+func TestRunExec_ParallelValidation(t *testing.T) {
+	t.Run("rejects parallel below 1", func(t *testing.T) {
+		err := runExec(execOptions{all: true, jobs: 0}, nil, []string{"echo", "hello"})
+		if err == nil || err.Error() != "--parallel must be at least 1" {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("rejects group without parallel", func(t *testing.T) {
+		err := runExec(execOptions{all: true, jobs: 1, group: true}, nil, []string{"echo", "hello"})
+		if err == nil || err.Error() != "--group requires --parallel greater than 1" {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+}
+
+func makeExecTargets(t *testing.T, names ...string) []execTarget {
+	t.Helper()
+
+	root := testutil.TempDir(t)
+	targets := make([]execTarget, 0, len(names))
+	for _, name := range names {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(path, fs.DirStrict); err != nil {
+			t.Fatal(err)
+		}
+		targets = append(targets, execTarget{label: name, name: name, path: path})
+	}
+	return targets
+}
+
+func TestRunExecParallel(t *testing.T) {
+	t.Run("runs in every target and records exit codes", func(t *testing.T) {
+		targets := makeExecTargets(t, "one", "two", "three")
+
+		script := `touch ran; if [ "$(basename "$PWD")" = two ]; then exit 3; fi`
+		results := runExecParallel(targets, []string{"sh", "-c", script}, 2, false, false)
+
+		if len(results) != 3 {
+			t.Fatalf("expected 3 results, got %d", len(results))
+		}
+		for _, r := range results {
+			if _, err := os.Stat(filepath.Join(r.target.path, "ran")); err != nil {
+				t.Errorf("command did not run in %s", r.target.name)
+			}
+		}
+		if results[0].status != execSucceeded || results[2].status != execSucceeded {
+			t.Errorf("expected one and three to succeed, got %s and %s", results[0].status, results[2].status)
+		}
+		if results[1].status != execFailed || results[1].exitCode != 3 {
+			t.Errorf("expected two to fail with exit code 3, got %s (%d)", results[1].status, results[1].exitCode)
+		}
+	})
+
+	t.Run("runs concurrently", func(t *testing.T) {
+		targets := makeExecTargets(t, "a", "b", "c", "d")
+
+		start := time.Now()
+		results := runExecParallel(targets, []string{"sleep", "0.3"}, 4, false, true)
+		elapsed := time.Since(start)
+
+		for _, r := range results {
+			if r.status != execSucceeded {
+				t.Errorf("%s: expected success, got %s", r.target.name, r.status)
+			}
+		}
+		if elapsed > time.Second {
+			t.Errorf("expected concurrent execution, took %v", elapsed)
+		}
+	})
+
+	t.Run("fail-fast cancels running siblings", func(t *testing.T) {
+		targets := makeExecTargets(t, "fail", "slow1", "slow2", "queued")
+
+		script := `if [ "$(basename "$PWD")" = fail ]; then exit 1; fi; sleep 5`
+		start := time.Now()
+		results := runExecParallel(targets, []string{"sh", "-c", script}, 3, true, false)
+		elapsed := time.Since(start)
+
+		if elapsed > 3*time.Second {
+			t.Fatalf("expected siblings to be cancelled, took %v", elapsed)
+		}
+		if results[0].status != execFailed {
+			t.Errorf("expected fail to fail, got %s", results[0].status)
+		}
+		// Siblings are either killed mid-run or never started, depending on scheduling
+		for _, r := range results[1:] {
+			if r.status != execCancelled && r.status != execSkipped {
+				t.Errorf("%s: expected cancelled or skipped, got %s", r.target.name, r.status)
+			}
+		}
+
+		if err := execResultsError(results, true); err == nil {
+			t.Error("expected error for fail-fast failure")
+		}
+	})
+}
+
+func TestStreamBuffer_PreservesOrder(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	buf := &streamBuffer{}
+
+	out := buf.writer(&stdout)
+	errOut := buf.writer(&stderr)
+	_, _ = out.Write([]byte("a\n"))
+	_, _ = errOut.Write([]byte("b\n"))
+	_, _ = out.Write([]byte("c\n"))
+
+	if stdout.Len() != 0 || stderr.Len() != 0 {
+		t.Fatal("expected no output before replay")
+	}
+
+	buf.replay()
+
+	if stdout.String() != "a\nc\n" {
+		t.Errorf("unexpected stdout: %q", stdout.String())
+	}
+	if stderr.String() != "b\n" {
+		t.Errorf("unexpected stderr: %q", stderr.String())
+	}
+}
+
+func TestFormatExecDuration(t *testing.T) {
+	tests := []struct {
+		in   time.Duration
+		want string
+	}{
+		{0, "0.0s"},
+		{1500 * time.Millisecond, "1.5s"},
+		{90 * time.Second, "1m30s"},
+	}
+	for _, tt := range tests {
+		if got := formatExecDuration(tt.in); got != tt.want {
+			t.Errorf("formatExecDuration(%v) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
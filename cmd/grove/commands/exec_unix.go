@@ -0,0 +1,17 @@
+//go:build !windows
+
+package commands
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// setCancelProcessGroup runs cmd in its own process group and terminates the
+// whole group on cancellation, so children of shells don't outlive fail-fast.
+func setCancelProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGTERM)
+	}
+}
@@ -0,0 +1,11 @@
+//go:build windows
+
+package commands
+
+import (
+	"os/exec"
+)
+
+// setCancelProcessGroup is a no-op on Windows; cancellation kills the process
+// itself and WaitDelay releases output pipes held by its children.
+func setCancelProcessGroup(_ *exec.Cmd) {}
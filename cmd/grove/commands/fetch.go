@@ -16,12 +16,15 @@ import (
 )
 
 type remoteResult struct {
+	Repo    string // Repository of a multi-repository project
+	BareDir string
 	Remote  string
 	Changes []git.RefChange
 	Error   error
 }
 
 type fetchChangeJSON struct {
+	Repo        string `json:"repo,omitempty"`
 	Remote      string `json:"remote"`
 	RefName     string `json:"ref"`
 	Type        string `json:"type"`
@@ -31,6 +34,7 @@ type fetchChangeJSON struct {
 }
 
 type fetchErrorJSON struct {
+	Repo    string `json:"repo,omitempty"`
 	Remote  string `json:"remote"`
 	Message string `json:"message"`
 }
@@ -43,57 +47,88 @@ type fetchResultJSON struct {
 func NewFetchCmd() *cobra.Command {
 	var jsonOutput bool
 	var verbose bool
+	var allRepos bool
 
 	cmd := &cobra.Command{
 		Use:   "fetch",
 		Short: "Fetch all remotes and show changes",
-		Args:  cobra.NoArgs,
+		Long: `Fetch all remotes and show which remote branches changed.
+
+With --all-repos, fetches every repo of the grove.workspace.toml project. From a
+project directory outside its repos, this is the default.`,
+		Args: cobra.NoArgs,
 		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 			return nil, cobra.ShellCompDirectiveNoFileComp
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runFetch(jsonOutput, verbose)
+			return runFetch(jsonOutput, verbose, allRepos)
 		},
 	}
 
 	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output as JSON")
 	cmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Show commit hash details")
+	cmd.Flags().BoolVar(&allRepos, "all-repos", false, "Fetch every repo in the project")
 
 	return cmd
 }
 
-func runFetch(jsonOutput, verbose bool) error {
+func runFetch(jsonOutput, verbose, allRepos bool) error {
 	cwd, err := os.Getwd()
 	if err != nil {
 		return fmt.Errorf("failed to get current directory: %w", err)
 	}
 
-	bareDir, err := workspace.FindBareDir(cwd)
+	repos, err := projectRepos(cwd, allRepos)
 	if err != nil {
 		return err
 	}
 
-	remotes, err := git.ListRemotes(bareDir)
-	if err != nil {
-		return fmt.Errorf("failed to list remotes: %w", err)
+	var results []remoteResult
+	if repos != nil {
+		for _, repo := range repos {
+			repoResults, err := fetchRemotes(repo.BareDir(), repo.Name)
+			if err != nil {
+				return fmt.Errorf("%s: %w", repo.Name, err)
+			}
+			results = append(results, repoResults...)
+		}
+	} else {
+		bareDir, err := workspace.FindBareDir(cwd)
+		if err != nil {
+			return err
+		}
+		if results, err = fetchRemotes(bareDir, ""); err != nil {
+			return err
+		}
 	}
 
-	if len(remotes) == 0 {
+	if len(results) == 0 {
 		logger.Info("No remotes configured")
 		return nil
 	}
 
+	return outputFetchResults(results, jsonOutput, verbose)
+}
+
+// fetchRemotes fetches every remote of the workspace. repo names the
+// repository of a project, or is empty for a single workspace.
+func fetchRemotes(bareDir, repo string) ([]remoteResult, error) {
+	remotes, err := git.ListRemotes(bareDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list remotes: %w", err)
+	}
+
 	var results []remoteResult
 	for _, remote := range remotes {
 		result := fetchRemoteWithRetry(bareDir, remote)
+		result.Repo = repo
 		results = append(results, result)
 	}
-
-	return outputFetchResults(bareDir, results, jsonOutput, verbose)
+	return results, nil
 }
 
 func fetchRemoteWithRetry(bareDir, remote string) remoteResult {
-	result := remoteResult{Remote: remote}
+	result := remoteResult{BareDir: bareDir, Remote: remote}
 
 	refsBefore, err := git.GetRemoteRefs(bareDir, remote)
 	if err != nil {
@@ -125,7 +160,15 @@ func fetchRemoteWithRetry(bareDir, remote string) remoteResult {
 	return result
 }
 
-func outputFetchJSON(bareDir string, results []remoteResult) error {
+// label names the remote in output, with its repository in a project
+func (r remoteResult) label() string {
+	if r.Repo != "" {
+		return r.Repo + " " + r.Remote
+	}
+	return r.Remote
+}
+
+func outputFetchJSON(results []remoteResult) error {
 	output := fetchResultJSON{
 		Changes: make([]fetchChangeJSON, 0),
 	}
@@ -133,6 +176,7 @@ func outputFetchJSON(bareDir string, results []remoteResult) error {
 	for _, result := range results {
 		if result.Error != nil {
 			output.Errors = append(output.Errors, fetchErrorJSON{
+				Repo:    result.Repo,
 				Remote:  result.Remote,
 				Message: result.Error.Error(),
 			})
@@ -141,6 +185,7 @@ func outputFetchJSON(bareDir string, results []remoteResult) error {
 
 		for _, change := range result.Changes {
 			jsonChange := fetchChangeJSON{
+				Repo:    result.Repo,
 				Remote:  result.Remote,
 				RefName: stripRefPrefix(change.RefName, result.Remote),
 				Type:    change.Type.String(),
@@ -149,7 +194,7 @@ func outputFetchJSON(bareDir string, results []remoteResult) error {
 			}
 
 			if change.Type == git.Updated {
-				jsonChange.CommitCount = getCommitCount(bareDir, change.OldHash, change.NewHash)
+				jsonChange.CommitCount = getCommitCount(result.BareDir, change.OldHash, change.NewHash)
 			}
 
 			output.Changes = append(output.Changes, jsonChange)
@@ -169,9 +214,9 @@ func outputFetchJSON(bareDir string, results []remoteResult) error {
 	return nil
 }
 
-func outputFetchResults(bareDir string, results []remoteResult, jsonOutput, verbose bool) error {
+func outputFetchResults(results []remoteResult, jsonOutput, verbose bool) error {
 	if jsonOutput {
-		return outputFetchJSON(bareDir, results)
+		return outputFetchJSON(results)
 	}
 
 	var errors []error
@@ -179,7 +224,7 @@ func outputFetchResults(bareDir string, results []remoteResult, jsonOutput, verb
 
 	for _, result := range results {
 		if result.Error != nil {
-			errors = append(errors, fmt.Errorf("%s: %w", result.Remote, result.Error))
+			errors = append(errors, fmt.Errorf("%s: %w", result.label(), result.Error))
 			continue
 		}
 
@@ -188,12 +233,12 @@ func outputFetchResults(bareDir string, results []remoteResult, jsonOutput, verb
 		}
 
 		hasChanges = true
-		fmt.Printf("%s:\n", result.Remote)
+		fmt.Printf("%s:\n", result.label())
 		for _, change := range result.Changes {
 			if verbose {
-				printRefChangeVerbose(bareDir, result.Remote, change)
+				printRefChangeVerbose(result.BareDir, result.Remote, change)
 			} else {
-				printRefChange(bareDir, result.Remote, change)
+				printRefChange(result.BareDir, result.Remote, change)
 			}
 		}
 	}
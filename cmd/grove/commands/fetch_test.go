@@ -65,7 +65,7 @@ func TestRunFetch_NotInWorkspace(t *testing.T) {
 	tmpDir := testutil.TempDir(t)
 	testutil.Chdir(t, tmpDir)
 
-	err := runFetch(false, false)
+	err := runFetch(false, false, false)
 	if err == nil {
 		t.Error("expected error for non-workspace directory")
 	}
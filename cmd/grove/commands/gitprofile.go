@@ -0,0 +1,54 @@
+package commands
+
+import (
+	"strings"
+
+	"github.com/sqve/grove/internal/config"
+	"github.com/sqve/grove/internal/git"
+	"github.com/sqve/grove/internal/logger"
+)
+
+// applyGitProfiles writes the [profiles] of the config worktree's .grove.toml
+// matching branch to the per-worktree config of a new worktree. Returns the
+// profiles applied; failures are reported as warnings.
+func applyGitProfiles(worktreePath, branch, configWorktree string) []config.MatchedGitProfile {
+	configDir := trustedConfigDir(worktreePath, configWorktree)
+	if configDir == "" || branch == "" {
+		return nil
+	}
+
+	profiles, err := config.GetGitProfiles(configDir, branch)
+	if err != nil {
+		logger.Warning("Failed to apply git profiles: %v", err)
+		return nil
+	}
+
+	var applied []config.MatchedGitProfile
+	for _, profile := range profiles {
+		if err := setGitProfile(worktreePath, profile); err != nil {
+			logger.Warning("Failed to apply git profile %s: %v", profile.Name, err)
+			continue
+		}
+		applied = append(applied, profile)
+	}
+	return applied
+}
+
+func setGitProfile(worktreePath string, profile config.MatchedGitProfile) error {
+	for _, v := range profile.Values {
+		if err := git.SetWorktreeConfig(worktreePath, v.Key, v.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func logGitProfiles(applied []config.MatchedGitProfile) {
+	for _, profile := range applied {
+		keys := make([]string, 0, len(profile.Values))
+		for _, v := range profile.Values {
+			keys = append(keys, v.Key)
+		}
+		logger.ListSubItem("applied git profile %s (%s)", profile.Name, strings.Join(keys, ", "))
+	}
+}
@@ -0,0 +1,107 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/sqve/grove/internal/journal"
+	"github.com/sqve/grove/internal/logger"
+	"github.com/sqve/grove/internal/styles"
+	"github.com/sqve/grove/internal/workspace"
+)
+
+// NewHistoryCmd creates the history command
+func NewHistoryCmd() *cobra.Command {
+	var limit int
+
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "List recent operations that can be undone",
+		Long: `List recent remove, prune and move operations, newest first.
+
+Pass an operation's ID to 'grove undo' to reverse it.
+
+Examples:
+  grove history         # Show recent operations
+  grove history -n 5    # Show the last 5 operations`,
+		Args: cobra.NoArgs,
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runHistory(limit)
+		},
+	}
+
+	cmd.Flags().IntVarP(&limit, "limit", "n", 20, "Number of operations to show (0 for all)")
+	cmd.Flags().BoolP("help", "h", false, "Help for history")
+
+	_ = cmd.RegisterFlagCompletionFunc("limit", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	})
+
+	return cmd
+}
+
+func runHistory(limit int) error {
+	if limit < 0 {
+		return fmt.Errorf("--limit must not be negative")
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	bareDir, err := workspace.FindBareDir(cwd)
+	if err != nil {
+		return err
+	}
+
+	entries, err := journal.List(bareDir)
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		logger.Info("No operations recorded yet.")
+		return nil
+	}
+	if limit > 0 && len(entries) > limit {
+		entries = entries[:limit]
+	}
+
+	idWidth := len(fmt.Sprint(entries[0].ID))
+	for _, entry := range entries {
+		line := fmt.Sprintf("%*d  %s  %-6s  %s",
+			idWidth, entry.ID, entry.Time.Local().Format("2006-01-02 15:04"), entry.Command, describeEntry(entry))
+		if entry.Undone() {
+			fmt.Println(styles.Render(&styles.Dimmed, line+" (undone)"))
+		} else {
+			fmt.Println(line)
+		}
+	}
+	return nil
+}
+
+// describeEntry summarizes what an operation changed
+func describeEntry(entry *journal.Entry) string {
+	if entry.Op == journal.OpMove {
+		return fmt.Sprintf("%s → %s", entry.Branch, entry.NewBranch)
+	}
+
+	desc := filepath.Base(entry.Worktree)
+	var notes []string
+	if entry.BranchDeleted {
+		notes = append(notes, "deleted branch "+entry.Branch)
+	}
+	if entry.Snapshot != "" || len(entry.DeletedFiles) > 0 || entry.SnapshotRef != "" {
+		notes = append(notes, "uncommitted files saved")
+	}
+	if len(notes) > 0 {
+		desc += " (" + strings.Join(notes, ", ") + ")"
+	}
+	return desc
+}
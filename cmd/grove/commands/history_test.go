@@ -0,0 +1,71 @@
+package commands
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/sqve/grove/internal/journal"
+	"github.com/sqve/grove/internal/testutil"
+	"github.com/sqve/grove/internal/workspace"
+)
+
+func TestNewHistoryCmd(t *testing.T) {
+	cmd := NewHistoryCmd()
+
+	if cmd.Use != "history" {
+		t.Errorf("expected Use 'history', got %q", cmd.Use)
+	}
+	limitFlag := cmd.Flags().Lookup("limit")
+	if limitFlag == nil {
+		t.Fatal("expected --limit flag")
+	}
+	if limitFlag.Shorthand != "n" {
+		t.Errorf("expected limit shorthand 'n', got %q", limitFlag.Shorthand)
+	}
+}
+
+func TestRunHistory_NotInWorkspace(t *testing.T) {
+	defer testutil.SaveCwd(t)()
+	testutil.Chdir(t, testutil.TempDir(t))
+
+	err := runHistory(20)
+	if !errors.Is(err, workspace.ErrNotInWorkspace) {
+		t.Errorf("expected ErrNotInWorkspace, got %v", err)
+	}
+}
+
+func TestRunHistory_NegativeLimit(t *testing.T) {
+	testutil.AssertErrorContains(t, runHistory(-1), "--limit must not be negative")
+}
+
+func TestDescribeEntry(t *testing.T) {
+	tests := []struct {
+		name  string
+		entry journal.Entry
+		want  string
+	}{
+		{
+			name:  "plain removal",
+			entry: journal.Entry{Op: journal.OpRemove, Worktree: "/ws/feat-auth", Branch: "feat/auth"},
+			want:  "feat-auth",
+		},
+		{
+			name:  "removal with branch and snapshot",
+			entry: journal.Entry{Op: journal.OpRemove, Worktree: "/ws/wip", Branch: "wip", BranchDeleted: true, Snapshot: "snapshot-1.tar.gz"},
+			want:  "wip (deleted branch wip, uncommitted files saved)",
+		},
+		{
+			name:  "move",
+			entry: journal.Entry{Op: journal.OpMove, Branch: "feat/old", NewBranch: "feat/new"},
+			want:  "feat/old → feat/new",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := describeEntry(&tt.entry); got != tt.want {
+				t.Errorf("describeEntry() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/sqve/grove/internal/hooks"
+	"github.com/sqve/grove/internal/logger"
+	"github.com/sqve/grove/internal/workspace"
+)
+
+// loadHooks returns the hooks configured for event in the workspace's trusted
+// .grove.toml, the one in its default branch worktree. Hooks never come from
+// another worktree's file or from target, the worktree the operation acts on,
+// since either may be a contributor's content. Post-clone hooks come from git
+// config and ignore bareDir.
+func loadHooks(bareDir string, event hooks.Event, target string) []string {
+	if event == hooks.EventPostClone {
+		return hooks.GetHooks("", event)
+	}
+
+	configDir := trustedConfigDir(target, findDefaultConfigWorktree(bareDir))
+	if configDir == "" {
+		logger.Debug("No trusted config worktree, skipping %s hooks", event)
+		return nil
+	}
+	return hooks.GetHooks(configDir, event)
+}
+
+// runLifecycleHooks runs commands for hookCtx.Event with workDir as the working
+// directory. Values allocated to the worktree in bareDir are looked up unless
+// hookCtx.Vars is already set. Returns nil when there is nothing to run.
+func runLifecycleHooks(bareDir string, commands []string, workDir string, hookCtx *hooks.Context) *hooks.RunResult {
+	if len(commands) == 0 {
+		logger.Debug("No %s hooks configured", hookCtx.Event)
+		return nil
+	}
+
+	if hookCtx.Vars == nil && bareDir != "" && hookCtx.Worktree != "" {
+		hookCtx.Vars = workspace.LookupEnv(bareDir, hookCtx.Worktree)
+	}
+
+	logger.Info("Running %d %s hook(s)...", len(commands), hookCtx.Event)
+	return hooks.RunStreaming(workDir, commands, hookCtx.Env(), os.Stderr)
+}
+
+// runPreHooks runs pre-event hooks and returns an error if any of them fail,
+// which callers use to abort the operation.
+func runPreHooks(bareDir string, commands []string, workDir string, hookCtx *hooks.Context) error {
+	result := runLifecycleHooks(bareDir, commands, workDir, hookCtx)
+	if result == nil || result.Failed == nil {
+		return nil
+	}
+	return fmt.Errorf("%s hook failed: %s (exit code %d)", hookCtx.Event, result.Failed.Command, result.Failed.ExitCode)
+}
+
+// runPostHooks runs post-event hooks. Failures are reported as warnings since
+// the operation has already completed.
+func runPostHooks(bareDir string, commands []string, workDir string, hookCtx *hooks.Context) {
+	result := runLifecycleHooks(bareDir, commands, workDir, hookCtx)
+	if result == nil || result.Failed == nil {
+		return
+	}
+	logger.Warning("Hook failed (%s): %s (exit code %d)", hookCtx.Event, result.Failed.Command, result.Failed.ExitCode)
+}
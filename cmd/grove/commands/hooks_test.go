@@ -0,0 +1,98 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sqve/grove/internal/fs"
+	"github.com/sqve/grove/internal/hooks"
+	"github.com/sqve/grove/internal/testutil"
+	testgit "github.com/sqve/grove/internal/testutil/git"
+)
+
+func TestLoadHooks(t *testing.T) {
+	writeHooks := func(t *testing.T, dir string) {
+		t.Helper()
+		content := "[hooks]\npre_remove = [\"docker compose down\"]\n"
+		if err := os.WriteFile(filepath.Join(dir, ".grove.toml"), []byte(content), fs.FileStrict); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("returns nil outside a workspace", func(t *testing.T) {
+		if got := loadHooks(testutil.TempDir(t), hooks.EventPreRemove, ""); got != nil {
+			t.Errorf("expected nil, got %v", got)
+		}
+	})
+
+	t.Run("reads hooks from default branch worktree", func(t *testing.T) {
+		ws := testgit.NewGroveWorkspace(t, "main", "feat")
+		writeHooks(t, ws.WorktreePath("main"))
+
+		got := loadHooks(ws.BareDir, hooks.EventPreRemove, ws.WorktreePath("feat"))
+		if len(got) != 1 || got[0] != "docker compose down" {
+			t.Errorf("unexpected hooks: %v", got)
+		}
+	})
+
+	t.Run("ignores other worktrees", func(t *testing.T) {
+		ws := testgit.NewGroveWorkspace(t, "main", "feat")
+		writeHooks(t, ws.WorktreePath("feat"))
+
+		if got := loadHooks(ws.BareDir, hooks.EventPreRemove, ""); got != nil {
+			t.Errorf("expected nil, got %v", got)
+		}
+	})
+
+	t.Run("ignores target worktree", func(t *testing.T) {
+		ws := testgit.NewGroveWorkspace(t, "main")
+		writeHooks(t, ws.WorktreePath("main"))
+
+		if got := loadHooks(ws.BareDir, hooks.EventPreRemove, ws.WorktreePath("main")); got != nil {
+			t.Errorf("expected nil, got %v", got)
+		}
+	})
+}
+
+func TestRunPreHooks(t *testing.T) {
+	t.Run("no hooks succeeds", func(t *testing.T) {
+		ctx := &hooks.Context{Event: hooks.EventPreRemove}
+		if err := runPreHooks("", nil, testutil.TempDir(t), ctx); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("failing hook returns error", func(t *testing.T) {
+		ctx := &hooks.Context{Event: hooks.EventPreRemove}
+		err := runPreHooks("", []string{"exit 3"}, testutil.TempDir(t), ctx)
+		if err == nil {
+			t.Fatal("expected error from failing pre-hook")
+		}
+		if !strings.Contains(err.Error(), "pre-remove hook failed") || !strings.Contains(err.Error(), "exit code 3") {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("hook runs in work dir with context env", func(t *testing.T) {
+		workDir := testutil.TempDir(t)
+		ctx := &hooks.Context{Event: hooks.EventPreAdd, Branch: "feat/x"}
+		if err := runPreHooks("", []string{`echo "$GROVE_EVENT:$GROVE_BRANCH" > out`}, workDir, ctx); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		data, err := os.ReadFile(filepath.Join(workDir, "out"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.TrimSpace(string(data)) != "pre-add:feat/x" {
+			t.Errorf("unexpected hook output: %q", data)
+		}
+	})
+}
+
+func TestRunPostHooks_FailureDoesNotPanic(t *testing.T) {
+	ctx := &hooks.Context{Event: hooks.EventPostRemove}
+	runPostHooks("", []string{"false"}, testutil.TempDir(t), ctx)
+}
@@ -5,23 +5,32 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/spf13/cobra"
+	"github.com/sqve/grove/internal/branchmatch"
 	"github.com/sqve/grove/internal/formatter"
 	"github.com/sqve/grove/internal/fs"
 	"github.com/sqve/grove/internal/git"
 	"github.com/sqve/grove/internal/logger"
+	"github.com/sqve/grove/internal/stack"
 	"github.com/sqve/grove/internal/workspace"
 )
 
+// branchFilterPrefix starts a --filter value that matches branch names
+const branchFilterPrefix = "branch:"
+
 // NewListCmd creates the list command
 func NewListCmd() *cobra.Command {
 	var fast bool
 	var jsonOutput bool
 	var verbose bool
 	var filter string
+	var stacked bool
+	var allRepos bool
 
 	cmd := &cobra.Command{
 		Use:   "list",
@@ -32,20 +41,28 @@ Examples:
   grove list                  # Show all worktrees
   grove list --fast           # Skip remote sync checks
   grove list --filter dirty   # Show only dirty worktrees
-  grove list --verbose        # Include paths and upstreams`,
+  grove list --filter 'branch:feat/**'  # Show only branches under feat/
+  grove list --verbose        # Include paths and upstreams
+  grove list --stack          # Indent stacked branches under their parents
+  grove list --all-repos      # Every repo of the grove.workspace.toml project
+
+From a project directory outside its repos, worktrees of every repo are
+listed with the repo as the first column.`,
 		Args: cobra.NoArgs,
 		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 			return nil, cobra.ShellCompDirectiveNoFileComp
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runList(fast, jsonOutput, verbose, filter)
+			return runList(fast, jsonOutput, verbose, stacked, allRepos, filter)
 		},
 	}
 
 	cmd.Flags().BoolVar(&fast, "fast", false, "Skip sync status checks")
 	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output as JSON")
 	cmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Show paths and upstream names")
-	cmd.Flags().StringVar(&filter, "filter", "", "Filter by status: dirty,ahead,behind,gone,locked (comma-separated)")
+	cmd.Flags().BoolVar(&stacked, "stack", false, "Order by branch stack and indent stacked worktrees")
+	cmd.Flags().BoolVar(&allRepos, "all-repos", false, "List worktrees of every repo in the project")
+	cmd.Flags().StringVar(&filter, "filter", "", "Filter by status: dirty,ahead,behind,gone,locked, or branch:<pattern> (comma-separated, \\, for a literal comma)")
 	cmd.Flags().BoolP("help", "h", false, "Help for list")
 
 	_ = cmd.RegisterFlagCompletionFunc("filter", completeFilterValues)
@@ -53,48 +70,161 @@ Examples:
 	return cmd
 }
 
-func runList(fast, jsonOutput, verbose bool, filter string) error {
+func runList(fast, jsonOutput, verbose, stacked, allRepos bool, filter string) error {
 	cwd, err := os.Getwd()
 	if err != nil {
 		return fmt.Errorf("failed to get current directory: %w", err)
 	}
 
+	repos, err := projectRepos(cwd, allRepos)
+	if err != nil {
+		return err
+	}
+	if repos != nil {
+		if stacked {
+			return fmt.Errorf("--stack cannot be used across repos")
+		}
+		return runListRepos(repos, cwd, fast, jsonOutput, verbose, filter)
+	}
+
 	bareDir, err := workspace.FindBareDir(cwd)
 	if err != nil {
 		return err
 	}
 
-	// Get worktree info
-	spin := logger.StartSpinner("Gathering worktree status...")
-	infos, err := git.ListWorktreesWithInfo(bareDir, fast)
+	infos, err := gatherWorktrees(bareDir, fast)
 	if err != nil {
-		spin.StopWithError("Failed to gather worktree status")
-		return fmt.Errorf("failed to list worktrees: %w", err)
+		return err
 	}
-	spin.Stop()
 
 	// Apply filter if specified
-	infos = filterWorktrees(infos, filter)
+	if infos, err = filterWorktrees(infos, filter); err != nil {
+		return err
+	}
 
 	// Determine current worktree path (also works from subdirectories)
 	currentPath := ""
 	for _, info := range infos {
-		if fs.PathsEqual(cwd, info.Path) || fs.PathHasPrefix(cwd, info.Path) {
+		if cwdInWorktree(cwd, info.Path) {
 			currentPath = info.Path
 			break
 		}
 	}
 
+	var parents map[string]string
+	if jsonOutput || stacked {
+		if parents, err = git.ListBranchParents(bareDir); err != nil {
+			logger.Debug("Failed to read stacked branches: %v", err)
+		}
+	}
+
 	if jsonOutput {
-		return outputJSON(infos, currentPath)
+		return outputJSON(infos, currentPath, parents)
+	}
+
+	if verbose {
+		for _, info := range infos {
+			info.Sparse = sparseLabel(info.Path)
+		}
+	}
+
+	return outputTable(infos, currentPath, fast, verbose, parents)
+}
+
+// gatherWorktrees returns the worktrees of a workspace with their status,
+// from the daemon when it is running
+func gatherWorktrees(bareDir string, fast bool) ([]*git.WorktreeInfo, error) {
+	if infos, ok := daemonWorktrees(bareDir); ok {
+		return infos, nil
 	}
 
-	return outputTable(infos, currentPath, fast, verbose)
+	spin := logger.StartSpinner("Gathering worktree status...")
+	infos, err := git.ListWorktreesWithInfo(bareDir, fast)
+	if err != nil {
+		spin.StopWithError("Failed to gather worktree status")
+		return nil, fmt.Errorf("failed to list worktrees: %w", err)
+	}
+	spin.Stop()
+	return infos, nil
+}
+
+// repoWorktree is a worktree of one of the repositories of a project
+type repoWorktree struct {
+	repo string
+	info *git.WorktreeInfo
+}
+
+// runListRepos lists the worktrees of every repository of a project, in
+// manifest order
+func runListRepos(repos []workspace.ProjectRepo, cwd string, fast, jsonOutput, verbose bool, filter string) error {
+	var rows []repoWorktree
+	for _, repo := range repos {
+		infos, err := gatherWorktrees(repo.BareDir(), fast)
+		if err != nil {
+			return fmt.Errorf("%s: %w", repo.Name, err)
+		}
+		if infos, err = filterWorktrees(infos, filter); err != nil {
+			return err
+		}
+		sort.SliceStable(infos, func(i, j int) bool {
+			return filepath.Base(infos[i].Path) < filepath.Base(infos[j].Path)
+		})
+		for _, info := range infos {
+			rows = append(rows, repoWorktree{repo: repo.Name, info: info})
+		}
+	}
+
+	currentPath := ""
+	for _, row := range rows {
+		if cwdInWorktree(cwd, row.info.Path) {
+			currentPath = row.info.Path
+			break
+		}
+	}
+
+	if jsonOutput {
+		output := []worktreeJSON{}
+		for _, row := range rows {
+			entry := newWorktreeJSON(row.info, currentPath, nil)
+			entry.Repo = row.repo
+			output = append(output, entry)
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(output)
+	}
+
+	infos := make([]*git.WorktreeInfo, 0, len(rows))
+	for _, row := range rows {
+		infos = append(infos, row.info)
+	}
+	maxNameLen, maxBranchLen := worktreeColumnWidths(infos)
+	repoWidth := repoNameWidth(repos)
+
+	for _, row := range rows {
+		displayInfo := row.info
+		if verbose {
+			displayInfo.Sparse = sparseLabel(displayInfo.Path)
+		}
+		if fast {
+			displayInfo = fastWorktreeInfo(displayInfo)
+		}
+
+		fmt.Println(formatter.RepoWorktreeRow(row.repo, repoWidth, displayInfo, fs.PathsEqual(row.info.Path, currentPath), maxNameLen, maxBranchLen))
+		if verbose {
+			for _, item := range formatter.VerboseSubItems(displayInfo) {
+				fmt.Println(item)
+			}
+		}
+	}
+	return nil
 }
 
 type worktreeJSON struct {
+	Repo       string `json:"repo,omitempty"` // Repository of a multi-repository project
 	Name       string `json:"name"`
 	Branch     string `json:"branch,omitempty"`
+	Parent     string `json:"parent,omitempty"` // Stack parent of the branch
 	Path       string `json:"path"`
 	Current    bool   `json:"current"`
 	Detached   bool   `json:"detached,omitempty"`
@@ -108,27 +238,10 @@ type worktreeJSON struct {
 	LockReason string `json:"lock_reason,omitempty"`
 }
 
-func outputJSON(infos []*git.WorktreeInfo, currentPath string) error {
+func outputJSON(infos []*git.WorktreeInfo, currentPath string, parents map[string]string) error {
 	output := []worktreeJSON{}
 	for _, info := range infos {
-		entry := worktreeJSON{
-			Name:       filepath.Base(info.Path),
-			Path:       info.Path,
-			Current:    fs.PathsEqual(info.Path, currentPath),
-			Detached:   info.Detached,
-			Upstream:   info.Upstream,
-			Dirty:      info.Dirty,
-			Ahead:      info.Ahead,
-			Behind:     info.Behind,
-			Gone:       info.Gone,
-			NoUpstream: info.NoUpstream,
-			Locked:     info.Locked,
-			LockReason: info.LockReason,
-		}
-		if !info.Detached {
-			entry.Branch = info.Branch
-		}
-		output = append(output, entry)
+		output = append(output, newWorktreeJSON(info, currentPath, parents))
 	}
 
 	enc := json.NewEncoder(os.Stdout)
@@ -136,55 +249,70 @@ func outputJSON(infos []*git.WorktreeInfo, currentPath string) error {
 	return enc.Encode(output)
 }
 
-func outputTable(infos []*git.WorktreeInfo, currentPath string, fast, verbose bool) error {
-	// Sort: current worktree first, then alphabetically by worktree name
-	sort.SliceStable(infos, func(i, j int) bool {
-		iCurrent := fs.PathsEqual(infos[i].Path, currentPath)
-		jCurrent := fs.PathsEqual(infos[j].Path, currentPath)
-		if iCurrent != jCurrent {
-			return iCurrent // Current worktree comes first
-		}
-		// Sort by worktree name (directory basename)
-		return filepath.Base(infos[i].Path) < filepath.Base(infos[j].Path)
-	})
+func newWorktreeJSON(info *git.WorktreeInfo, currentPath string, parents map[string]string) worktreeJSON {
+	entry := worktreeJSON{
+		Name:       filepath.Base(info.Path),
+		Path:       info.Path,
+		Current:    fs.PathsEqual(info.Path, currentPath),
+		Detached:   info.Detached,
+		Upstream:   info.Upstream,
+		Dirty:      info.Dirty,
+		Ahead:      info.Ahead,
+		Behind:     info.Behind,
+		Gone:       info.Gone,
+		NoUpstream: info.NoUpstream,
+		Locked:     info.Locked,
+		LockReason: info.LockReason,
+	}
+	if !info.Detached {
+		entry.Branch = info.Branch
+		entry.Parent = parents[info.Branch]
+	}
+	return entry
+}
 
-	// Calculate max widths for padding
-	maxNameLen := 0
-	maxBranchLen := 0
-	for _, info := range infos {
-		nameLen := len(filepath.Base(info.Path))
-		if nameLen > maxNameLen {
-			maxNameLen = nameLen
-		}
+// outputTable prints a row per worktree. With parents, worktrees are ordered
+// by branch stack and indented by their depth in it.
+func outputTable(infos []*git.WorktreeInfo, currentPath string, fast, verbose bool, parents map[string]string) error {
+	if parents != nil {
+		sortByStack(infos, parents)
+	} else {
+		// Sort: current worktree first, then alphabetically by worktree name
+		sort.SliceStable(infos, func(i, j int) bool {
+			iCurrent := fs.PathsEqual(infos[i].Path, currentPath)
+			jCurrent := fs.PathsEqual(infos[j].Path, currentPath)
+			if iCurrent != jCurrent {
+				return iCurrent // Current worktree comes first
+			}
+			// Sort by worktree name (directory basename)
+			return filepath.Base(infos[i].Path) < filepath.Base(infos[j].Path)
+		})
+	}
 
-		branchLen := len(info.Branch) + 2 // brackets add 2 chars
-		if info.Detached {
-			branchLen = 10 // "(detached)" is 10 chars
-		}
-		if branchLen > maxBranchLen {
-			maxBranchLen = branchLen
+	depths := make(map[*git.WorktreeInfo]int, len(infos))
+	if parents != nil {
+		for _, info := range infos {
+			if !info.Detached {
+				depths[info] = stack.Depth(parents, info.Branch)
+			}
 		}
 	}
 
+	maxNameLen, maxBranchLen := worktreeColumnWidths(infos)
+	for info, depth := range depths {
+		maxNameLen = max(maxNameLen, utf8.RuneCountInString(formatter.StackIndent(depth)+filepath.Base(info.Path)))
+	}
+
 	for _, info := range infos {
 		isCurrent := fs.PathsEqual(info.Path, currentPath)
 
-		// In fast mode, we don't have sync status - create a copy with zeroed sync info
 		displayInfo := info
 		if fast {
-			displayInfo = &git.WorktreeInfo{
-				Branch:     info.Branch,
-				Path:       info.Path,
-				Upstream:   info.Upstream,
-				Locked:     info.Locked,
-				LockReason: info.LockReason,
-				Detached:   info.Detached,
-				NoUpstream: true, // This prevents showing sync status
-			}
+			displayInfo = fastWorktreeInfo(info)
 		}
 
 		// Print the worktree row using the formatter
-		fmt.Println(formatter.WorktreeRow(displayInfo, isCurrent, maxNameLen, maxBranchLen))
+		fmt.Println(formatter.StackedWorktreeRow(displayInfo, isCurrent, depths[info], maxNameLen, maxBranchLen))
 
 		// Print verbose sub-items
 		if verbose {
@@ -197,19 +325,98 @@ func outputTable(infos []*git.WorktreeInfo, currentPath string, fast, verbose bo
 	return nil
 }
 
-func filterWorktrees(infos []*git.WorktreeInfo, filter string) []*git.WorktreeInfo {
+// fastWorktreeInfo returns a copy of info without sync status, which fast
+// mode does not gather
+func fastWorktreeInfo(info *git.WorktreeInfo) *git.WorktreeInfo {
+	return &git.WorktreeInfo{
+		Branch:     info.Branch,
+		Path:       info.Path,
+		Upstream:   info.Upstream,
+		Locked:     info.Locked,
+		LockReason: info.LockReason,
+		Detached:   info.Detached,
+		Sparse:     info.Sparse,
+		NoUpstream: true, // This prevents showing sync status
+	}
+}
+
+// sortByStack orders infos so stacked worktrees follow the worktree of their
+// parent, with stacks and siblings sorted by branch name
+func sortByStack(infos []*git.WorktreeInfo, parents map[string]string) {
+	// The chain of branches from the root of its stack sorts each worktree
+	// after its ancestors and before the next sibling
+	chains := make(map[*git.WorktreeInfo][]string, len(infos))
+	for _, info := range infos {
+		chain := []string{filepath.Base(info.Path)}
+		if !info.Detached {
+			chain = []string{info.Branch}
+			for range stack.Depth(parents, info.Branch) {
+				chain = append([]string{parents[chain[0]]}, chain...)
+			}
+		}
+		chains[info] = chain
+	}
+
+	sort.SliceStable(infos, func(i, j int) bool {
+		return slices.Compare(chains[infos[i]], chains[infos[j]]) < 0
+	})
+}
+
+// worktreeColumnWidths returns the padding widths that align worktree names
+// and branches in formatter.WorktreeRow output.
+func worktreeColumnWidths(infos []*git.WorktreeInfo) (maxNameLen, maxBranchLen int) {
+	for _, info := range infos {
+		nameLen := len(filepath.Base(info.Path))
+		if nameLen > maxNameLen {
+			maxNameLen = nameLen
+		}
+
+		branchLen := len(info.Branch) + 2 // brackets add 2 chars
+		if info.Detached {
+			branchLen = 10 // "(detached)" is 10 chars
+		}
+		if branchLen > maxBranchLen {
+			maxBranchLen = branchLen
+		}
+	}
+	return maxNameLen, maxBranchLen
+}
+
+// filterWorktrees keeps the worktrees matching any status filter and, when
+// branch:<pattern> filters are given, whose branch matches those patterns
+func filterWorktrees(infos []*git.WorktreeInfo, filter string) ([]*git.WorktreeInfo, error) {
 	filters := parseFilters(filter)
 	if len(filters) == 0 {
-		return infos
+		return infos, nil
+	}
+
+	var statuses, patterns []string
+	for _, f := range filters {
+		if pattern, ok := strings.CutPrefix(f, branchFilterPrefix); ok {
+			patterns = append(patterns, pattern)
+		} else {
+			statuses = append(statuses, f)
+		}
+	}
+	var branches *branchmatch.Matcher
+	if len(patterns) > 0 {
+		var err error
+		if branches, err = branchmatch.New(patterns); err != nil {
+			return nil, fmt.Errorf("invalid --filter: %w", err)
+		}
 	}
 
 	var filtered []*git.WorktreeInfo
 	for _, info := range infos {
-		if matchesAnyFilter(info, filters) {
-			filtered = append(filtered, info)
+		if branches != nil && !branches.Match(info.Branch) {
+			continue
 		}
+		if len(statuses) > 0 && !matchesAnyFilter(info, statuses) {
+			continue
+		}
+		filtered = append(filtered, info)
 	}
-	return filtered
+	return filtered, nil
 }
 
 func parseFilters(filter string) []string {
@@ -217,10 +424,15 @@ func parseFilters(filter string) []string {
 		return nil
 	}
 
-	parts := strings.Split(filter, ",")
 	var filters []string
-	for _, p := range parts {
-		p = strings.TrimSpace(strings.ToLower(p))
+	for _, p := range splitFilters(filter) {
+		p = strings.TrimSpace(p)
+		// Branch patterns keep their case, like branch names
+		if len(p) >= len(branchFilterPrefix) && strings.EqualFold(p[:len(branchFilterPrefix)], branchFilterPrefix) {
+			p = branchFilterPrefix + p[len(branchFilterPrefix):]
+		} else {
+			p = strings.ToLower(p)
+		}
 		if p != "" {
 			filters = append(filters, p)
 		}
@@ -228,6 +440,26 @@ func parseFilters(filter string) []string {
 	return filters
 }
 
+// splitFilters splits a --filter value on commas. \, is a literal comma, so
+// that branch patterns such as branch:re:a{1\,3} can contain one.
+func splitFilters(filter string) []string {
+	var parts []string
+	var b strings.Builder
+	for i := 0; i < len(filter); i++ {
+		switch {
+		case filter[i] == '\\' && i+1 < len(filter) && filter[i+1] == ',':
+			b.WriteByte(',')
+			i++
+		case filter[i] == ',':
+			parts = append(parts, b.String())
+			b.Reset()
+		default:
+			b.WriteByte(filter[i])
+		}
+	}
+	return append(parts, b.String())
+}
+
 func matchesAnyFilter(info *git.WorktreeInfo, filters []string) bool {
 	for _, f := range filters {
 		switch f {
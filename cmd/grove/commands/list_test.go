@@ -35,6 +35,9 @@ func TestNewListCmd(t *testing.T) {
 	if cmd.Flags().Lookup("filter") == nil {
 		t.Error("expected --filter flag")
 	}
+	if cmd.Flags().Lookup("stack") == nil {
+		t.Error("expected --stack flag")
+	}
 }
 
 func TestRunList(t *testing.T) {
@@ -45,7 +48,7 @@ func TestRunList(t *testing.T) {
 		tmpDir := testutil.TempDir(t)
 		testutil.Chdir(t, tmpDir)
 
-		err := runList(false, false, false, "")
+		err := runList(false, false, false, false, false, "")
 		if err == nil {
 			t.Error("expected error for non-workspace directory")
 		}
@@ -69,6 +72,9 @@ func TestParseFilters(t *testing.T) {
 		{"mixed case", "Dirty,AHEAD,behind", []string{"dirty", "ahead", "behind"}},
 		{"trailing comma", "dirty,", []string{"dirty"}},
 		{"leading comma", ",dirty", []string{"dirty"}},
+		{"branch pattern keeps case", "DIRTY,Branch:Feat/*", []string{"dirty", "branch:Feat/*"}},
+		{"escaped comma", `dirty,branch:re:^a{1\,3}$,branch:[a\,b]`, []string{"dirty", "branch:re:^a{1,3}$", "branch:[a,b]"}},
+		{"other backslashes kept", `branch:\!wip`, []string{`branch:\!wip`}},
 	}
 
 	for _, tt := range tests {
@@ -125,35 +131,50 @@ func TestFilterWorktrees(t *testing.T) {
 	}
 
 	t.Run("empty filter returns all", func(t *testing.T) {
-		got := filterWorktrees(infos, "")
+		got, err := filterWorktrees(infos, "")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
 		if len(got) != 4 {
 			t.Errorf("expected 4 worktrees, got %d", len(got))
 		}
 	})
 
 	t.Run("filter dirty", func(t *testing.T) {
-		got := filterWorktrees(infos, "dirty")
+		got, err := filterWorktrees(infos, "dirty")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
 		if len(got) != 1 || got[0].Branch != "feature" {
 			t.Errorf("expected [feature], got %v", got)
 		}
 	})
 
 	t.Run("filter locked", func(t *testing.T) {
-		got := filterWorktrees(infos, "locked")
+		got, err := filterWorktrees(infos, "locked")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
 		if len(got) != 1 || got[0].Branch != "main" {
 			t.Errorf("expected [main], got %v", got)
 		}
 	})
 
 	t.Run("filter gone", func(t *testing.T) {
-		got := filterWorktrees(infos, "gone")
+		got, err := filterWorktrees(infos, "gone")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
 		if len(got) != 1 || got[0].Branch != "old" {
 			t.Errorf("expected [old], got %v", got)
 		}
 	})
 
 	t.Run("filter dirty,locked OR logic", func(t *testing.T) {
-		got := filterWorktrees(infos, "dirty,locked")
+		got, err := filterWorktrees(infos, "dirty,locked")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
 		if len(got) != 2 {
 			t.Errorf("expected 2 worktrees, got %d", len(got))
 		}
@@ -165,6 +186,53 @@ func TestFilterWorktrees(t *testing.T) {
 			t.Errorf("expected main and feature, got %v", got)
 		}
 	})
+
+	branchInfos := []*git.WorktreeInfo{
+		{Branch: "main"},
+		{Branch: "feat/login", Dirty: true},
+		{Branch: "feat/ui/menu"},
+		{Branch: "feat/wip"},
+		{Branch: ""},
+	}
+
+	branchTests := []struct {
+		name   string
+		filter string
+		want   []string
+	}{
+		{"single segment glob", "branch:feat/*", []string{"feat/login", "feat/wip"}},
+		{"recursive glob", "branch:feat/**", []string{"feat/login", "feat/ui/menu", "feat/wip"}},
+		{"negation excludes", "branch:feat/**,branch:!feat/wip", []string{"feat/login", "feat/ui/menu"}},
+		{"regular expression", "branch:re:^feat/(login|wip)$", []string{"feat/login", "feat/wip"}},
+		{"combined with status", "dirty,branch:feat/**", []string{"feat/login"}},
+		{"prefix case ignored", "BRANCH:main", []string{"main"}},
+		{"pattern case kept", "branch:MAIN", nil},
+	}
+
+	for _, tt := range branchTests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := filterWorktrees(branchInfos, tt.filter)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var branches []string
+			for _, info := range got {
+				branches = append(branches, info.Branch)
+			}
+			if !reflect.DeepEqual(branches, tt.want) {
+				t.Errorf("filterWorktrees(%q) = %v, want %v", tt.filter, branches, tt.want)
+			}
+		})
+	}
+
+	t.Run("invalid branch pattern", func(t *testing.T) {
+		if _, err := filterWorktrees(branchInfos, "branch:feat/[a"); err == nil {
+			t.Error("expected error for invalid branch pattern")
+		}
+		if _, err := filterWorktrees(branchInfos, "branch:"); err == nil {
+			t.Error("expected error for empty branch pattern")
+		}
+	})
 }
 
 func TestCompleteFilterValues(t *testing.T) {
@@ -229,3 +297,25 @@ func TestCompleteFilterValues(t *testing.T) {
 		})
 	}
 }
+
+func TestSortByStack(t *testing.T) {
+	infos := []*git.WorktreeInfo{
+		{Path: "/ws/zeta", Branch: "zeta"},
+		{Path: "/ws/feat-b", Branch: "feat/b"},
+		{Path: "/ws/main", Branch: "main"},
+		{Path: "/ws/fix", Branch: "fix"},
+		{Path: "/ws/feat-a", Branch: "feat/a"},
+	}
+	parents := map[string]string{"feat/b": "feat/a", "feat/a": "main", "fix": "main"}
+
+	sortByStack(infos, parents)
+
+	var got []string
+	for _, info := range infos {
+		got = append(got, info.Branch)
+	}
+	want := []string{"main", "feat/a", "feat/b", "fix", "zeta"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("sortByStack() = %v, want %v", got, want)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/sqve/grove/internal/formatter"
 	"github.com/sqve/grove/internal/git"
 	"github.com/sqve/grove/internal/logger"
+	"github.com/sqve/grove/internal/picker"
 	"github.com/sqve/grove/internal/workspace"
 )
 
@@ -23,15 +24,27 @@ func NewLockCmd() *cobra.Command {
 		Long: `Lock one or more worktrees to prevent removal.
 
 Locked worktrees resist prune and remove. Use unlock to clear.
-Accepts worktree names (directories) or branch names.
+Accepts worktree names (directories) or branch names. Without arguments in
+an interactive terminal, opens a picker to choose worktrees (tab to select
+several).
 
 Examples:
+  grove lock                                # Pick worktrees interactively
   grove lock feat-auth                      # Lock worktree
   grove lock feat-auth --reason "WIP"       # Lock with reason
   grove lock feat-auth bugfix-123           # Lock multiple`,
 		Args:              cobra.ArbitraryArgs,
 		ValidArgsFunction: completeLockArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 && pickerAvailable() {
+				picked, err := pickWorktrees(picker.Options{Prompt: "Lock", Multi: true}, func(info *git.WorktreeInfo, _ bool) bool {
+					return info.Locked
+				})
+				if err != nil {
+					return err
+				}
+				args = picked
+			}
 			return runLock(args, reason)
 		},
 	}
@@ -149,7 +162,7 @@ func completeLockArgs(cmd *cobra.Command, args []string, toComplete string) ([]s
 
 	var completions []string
 	for _, info := range infos {
-		name := filepath.Base(info.Path)
+		name := git.WorktreeName(infos, info)
 
 		// Skip already-used (check both path basename and branch name)
 		if alreadyUsed[name] || alreadyUsed[info.Branch] {
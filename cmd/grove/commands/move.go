@@ -9,6 +9,8 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/sqve/grove/internal/fs"
 	"github.com/sqve/grove/internal/git"
+	"github.com/sqve/grove/internal/hooks"
+	"github.com/sqve/grove/internal/journal"
 	"github.com/sqve/grove/internal/logger"
 	"github.com/sqve/grove/internal/workspace"
 )
@@ -97,8 +99,10 @@ func runMove(target, newBranch string) error {
 	}
 
 	// Calculate new worktree path
-	newDirName := workspace.SanitizeBranchName(newBranch)
-	newWorktreePath := filepath.Join(workspaceRoot, newDirName)
+	newWorktreePath, err := newWorktreePath(bareDir, "", newBranch)
+	if err != nil {
+		return err
+	}
 
 	// Check if new directory already exists
 	if _, err := os.Stat(newWorktreePath); err == nil {
@@ -116,6 +120,10 @@ func runMove(target, newBranch string) error {
 		_ = os.Remove(lockFile)
 	}()
 
+	// Capture what grove undo needs before the branch is renamed
+	head, headErr := git.RevParse(worktreeInfo.Path, "HEAD")
+	upstreamRemote, upstreamMerge := git.GetBranchUpstream(bareDir, worktreeInfo.Branch)
+
 	// Track steps for rollback
 	var branchRenamed, dirMoved bool
 	oldWorktreePath := worktreeInfo.Path
@@ -133,6 +141,7 @@ func runMove(target, newBranch string) error {
 			if err := os.Rename(newWorktreePath, oldWorktreePath); err != nil {
 				logger.Error("Failed to restore directory: %v", err)
 			}
+			workspace.RemoveEmptyParents(newWorktreePath, workspaceRoot)
 		}
 
 		if branchRenamed {
@@ -153,6 +162,10 @@ func runMove(target, newBranch string) error {
 	branchRenamed = true
 
 	// Step 2: Move the worktree directory
+	if err := os.MkdirAll(filepath.Dir(newWorktreePath), fs.DirGit); err != nil {
+		spin.StopWithError("Failed to move directory")
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(newWorktreePath), err)
+	}
 	if err := os.Rename(oldWorktreePath, newWorktreePath); err != nil {
 		spin.StopWithError("Failed to move directory")
 		return fmt.Errorf("failed to move worktree directory: %w", err)
@@ -188,13 +201,48 @@ func runMove(target, newBranch string) error {
 	// Success - clear rollback flags
 	branchRenamed = false
 	dirMoved = false
+	renameWorktreeEnv(bareDir, oldWorktreePath, newWorktreePath)
+	workspace.RemoveEmptyParents(oldWorktreePath, workspaceRoot)
+	if err := git.RenameBranchParent(bareDir, worktreeInfo.Branch, newBranch); err != nil {
+		logger.Warning("Failed to update branches stacked on %s: %v", worktreeInfo.Branch, err)
+	}
+
+	if headErr != nil {
+		logger.Warning("Move cannot be undone: %v", headErr)
+	} else {
+		recordJournal(bareDir, &journal.Entry{
+			Command:        "move",
+			Op:             journal.OpMove,
+			Worktree:       oldWorktreePath,
+			Branch:         worktreeInfo.Branch,
+			Head:           head,
+			UpstreamRemote: upstreamRemote,
+			UpstreamMerge:  upstreamMerge,
+			NewWorktree:    newWorktreePath,
+			NewBranch:      newBranch,
+		})
+	}
 
+	newDirName := newWorktreePath
+	if rel, err := filepath.Rel(workspaceRoot, newWorktreePath); err == nil && fs.PathHasPrefix(newWorktreePath, workspaceRoot) {
+		newDirName = filepath.ToSlash(rel)
+	}
 	if newDirName != newBranch {
 		logger.Success("Renamed %s to %s (dir: %s)", target, newBranch, newDirName)
 	} else {
 		logger.Success("Renamed %s to %s", target, newBranch)
 	}
 
+	runPostHooks(bareDir, loadHooks(bareDir, hooks.EventPostMove, newWorktreePath), newWorktreePath, &hooks.Context{
+		Event:       hooks.EventPostMove,
+		Command:     "move",
+		Workspace:   workspaceRoot,
+		Worktree:    newWorktreePath,
+		Branch:      newBranch,
+		OldWorktree: oldWorktreePath,
+		OldBranch:   worktreeInfo.Branch,
+	})
+
 	return nil
 }
 
@@ -223,7 +271,7 @@ func completeMoveArgs(cmd *cobra.Command, args []string, toComplete string) ([]s
 	for _, info := range infos {
 		// Exclude current worktree
 		if !fs.PathsEqual(cwd, info.Path) && !fs.PathHasPrefix(cwd, info.Path) {
-			completions = append(completions, filepath.Base(info.Path))
+			completions = append(completions, git.WorktreeName(infos, info))
 		}
 	}
 
@@ -0,0 +1,87 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/sqve/grove/internal/formatter"
+	"github.com/sqve/grove/internal/fs"
+	"github.com/sqve/grove/internal/git"
+	"github.com/sqve/grove/internal/logger"
+	"github.com/sqve/grove/internal/picker"
+	"github.com/sqve/grove/internal/styles"
+	"github.com/sqve/grove/internal/workspace"
+)
+
+// pickerAvailable reports whether commands given no worktree should fall back
+// to the interactive picker. Overridden in tests.
+var pickerAvailable = picker.Available
+
+// pickWorktrees shows the workspace's worktrees in the interactive picker and
+// returns the names of those chosen. Worktrees for which skip returns true
+// are left out.
+func pickWorktrees(opts picker.Options, skip func(info *git.WorktreeInfo, current bool) bool) ([]string, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	bareDir, err := workspace.FindBareDir(cwd)
+	if err != nil {
+		return nil, err
+	}
+
+	spin := logger.StartSpinner("Gathering worktree status...")
+	infos, err := git.ListWorktreesWithInfo(bareDir, false)
+	spin.Stop()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list worktrees: %w", err)
+	}
+
+	items := worktreePickerItems(infos, cwd, skip)
+	if len(items) == 0 {
+		return nil, errors.New("no worktrees to choose from")
+	}
+
+	return picker.Run(items, opts)
+}
+
+// worktreePickerItems builds picker rows in the same format as grove list,
+// followed by the age of the last commit.
+func worktreePickerItems(infos []*git.WorktreeInfo, cwd string, skip func(info *git.WorktreeInfo, current bool) bool) []picker.Item {
+	var candidates []*git.WorktreeInfo
+	for _, info := range infos {
+		if skip == nil || !skip(info, isCurrentWorktree(cwd, info.Path)) {
+			candidates = append(candidates, info)
+		}
+	}
+
+	maxNameLen, maxBranchLen := worktreeColumnWidths(candidates)
+
+	items := make([]picker.Item, 0, len(candidates))
+	for _, info := range candidates {
+		label := formatter.WorktreeRow(info, isCurrentWorktree(cwd, info.Path), maxNameLen, maxBranchLen)
+		if age := formatAge(info.LastCommitTime); age != "" {
+			label += " " + styles.Render(&styles.Dimmed, age)
+		}
+
+		name := git.WorktreeName(infos, info)
+		items = append(items, picker.Item{
+			Label: label,
+			Text:  name + " " + info.Branch,
+			Value: name,
+		})
+	}
+	return items
+}
+
+// isCurrentWorktree reports whether cwd is at or inside worktreePath
+func isCurrentWorktree(cwd, worktreePath string) bool {
+	return fs.PathsEqual(cwd, worktreePath) || fs.PathHasPrefix(cwd, worktreePath)
+}
+
+// skipCurrentWorktree excludes the worktree the user is in
+func skipCurrentWorktree(_ *git.WorktreeInfo, current bool) bool {
+	return current
+}
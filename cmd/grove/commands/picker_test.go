@@ -0,0 +1,59 @@
+package commands
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sqve/grove/internal/config"
+	"github.com/sqve/grove/internal/git"
+)
+
+// stubPickerAvailable overrides picker detection and returns a restore func
+func stubPickerAvailable(available bool) func() {
+	orig := pickerAvailable
+	pickerAvailable = func() bool { return available }
+	return func() { pickerAvailable = orig }
+}
+
+func TestWorktreePickerItems(t *testing.T) {
+	config.SetPlain(true)
+	defer config.SetPlain(false)
+
+	root := filepath.Join(string(filepath.Separator), "ws")
+	infos := []*git.WorktreeInfo{
+		{Path: filepath.Join(root, "main"), Branch: "main", Locked: true},
+		{Path: filepath.Join(root, "feat-auth"), Branch: "feat/auth", Dirty: true, LastCommitTime: time.Now().Unix()},
+	}
+
+	t.Run("renders rows with status and age", func(t *testing.T) {
+		items := worktreePickerItems(infos, filepath.Join(root, "main", "src"), nil)
+		if len(items) != 2 {
+			t.Fatalf("expected 2 items, got %d", len(items))
+		}
+
+		if items[0].Value != "main" || items[1].Value != "feat-auth" {
+			t.Errorf("expected values [main feat-auth], got [%s %s]", items[0].Value, items[1].Value)
+		}
+		if items[1].Text != "feat-auth feat/auth" {
+			t.Errorf("expected match text to include name and branch, got %q", items[1].Text)
+		}
+		if !strings.HasPrefix(items[0].Label, "*") {
+			t.Errorf("expected current marker on main, got %q", items[0].Label)
+		}
+		if !strings.Contains(items[0].Label, "[locked]") {
+			t.Errorf("expected lock indicator, got %q", items[0].Label)
+		}
+		if !strings.Contains(items[1].Label, "[dirty]") || !strings.HasSuffix(items[1].Label, "today") {
+			t.Errorf("expected dirty indicator and age, got %q", items[1].Label)
+		}
+	})
+
+	t.Run("skips excluded worktrees", func(t *testing.T) {
+		items := worktreePickerItems(infos, filepath.Join(root, "main"), skipCurrentWorktree)
+		if len(items) != 1 || items[0].Value != "feat-auth" {
+			t.Errorf("expected only feat-auth, got %+v", items)
+		}
+	})
+}
@@ -0,0 +1,175 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/sqve/grove/internal/fs"
+	"github.com/sqve/grove/internal/git"
+	"github.com/sqve/grove/internal/logger"
+	"github.com/sqve/grove/internal/styles"
+	"github.com/sqve/grove/internal/workspace"
+)
+
+// projectRepos returns the cloned repositories of the project around cwd
+// when a command should work across them: with --all-repos, or when run from
+// the project outside any of its workspaces. Returns nil to work on the
+// current workspace.
+func projectRepos(cwd string, allRepos bool) ([]workspace.ProjectRepo, error) {
+	if !allRepos {
+		if _, err := workspace.FindBareDir(cwd); err == nil {
+			return nil, nil
+		}
+	}
+
+	project, err := workspace.FindProject(cwd)
+	if err != nil {
+		if errors.Is(err, workspace.ErrNotInProject) && !allRepos {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return clonedProjectRepos(project)
+}
+
+// clonedProjectRepos returns the repositories of project that have been set
+// up, warning about the others
+func clonedProjectRepos(project *workspace.Project) ([]workspace.ProjectRepo, error) {
+	var repos []workspace.ProjectRepo
+	for _, repo := range project.Repos {
+		if !repo.IsCloned() {
+			logger.Warning("Skipping %s: not cloned yet, run 'grove clone --manifest %s'", repo.Name, styles.RenderPath(project.Root))
+			continue
+		}
+		repos = append(repos, repo)
+	}
+	if len(repos) == 0 {
+		return nil, fmt.Errorf("no repos of %s are cloned", filepath.Join(project.Root, workspace.ManifestFileName))
+	}
+	return repos, nil
+}
+
+// repoNameWidth returns the width that aligns a column of repository names
+func repoNameWidth(repos []workspace.ProjectRepo) int {
+	width := 0
+	for _, repo := range repos {
+		width = max(width, len(repo.Name))
+	}
+	return width
+}
+
+// groupMember is the worktree of a repository in a branch group
+type groupMember struct {
+	repo workspace.ProjectRepo
+	info *git.WorktreeInfo
+}
+
+// groupWorktrees finds the worktree matching target, by name or branch, in
+// each repository
+func groupWorktrees(repos []workspace.ProjectRepo, target string) ([]groupMember, error) {
+	var members []groupMember
+	for _, repo := range repos {
+		infos, err := git.ListWorktreesWithInfo(repo.BareDir(), true)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list worktrees of %s: %w", repo.Name, err)
+		}
+		if info := git.FindWorktree(infos, target); info != nil {
+			members = append(members, groupMember{repo: repo, info: info})
+		}
+	}
+	return members, nil
+}
+
+// linkProjectGroup links the worktrees of branch into its group directory
+// and returns the directory
+func linkProjectGroup(project *workspace.Project, branch string, worktrees map[string]string) (string, error) {
+	groupDir, err := project.GroupPath(branch)
+	if err != nil {
+		return "", err
+	}
+
+	result, err := workspace.LinkGroup(groupDir, worktrees)
+	if err != nil {
+		return "", fmt.Errorf("failed to link %s: %w", styles.RenderPath(groupDir), err)
+	}
+	for _, name := range result.Conflicts {
+		logger.Warning("Not linking %s: %s exists and is not a symlink", name, styles.RenderPath(filepath.Join(groupDir, name)))
+	}
+	return groupDir, nil
+}
+
+// findProjectGroup returns the group directory of target when cwd is in a
+// project and at least one repository has a matching worktree, linking it
+// first. Returns an empty path otherwise.
+func findProjectGroup(cwd, target string) (string, []groupMember, error) {
+	project, err := workspace.FindProject(cwd)
+	if errors.Is(err, workspace.ErrNotInProject) {
+		return "", nil, nil
+	}
+	if err != nil {
+		return "", nil, err
+	}
+
+	var repos []workspace.ProjectRepo
+	for _, repo := range project.Repos {
+		if repo.IsCloned() {
+			repos = append(repos, repo)
+		}
+	}
+	members, err := groupWorktrees(repos, target)
+	if err != nil || len(members) == 0 {
+		return "", nil, err
+	}
+
+	worktrees := make(map[string]string, len(members))
+	for _, member := range members {
+		worktrees[member.repo.Name] = member.info.Path
+	}
+	groupDir, err := linkProjectGroup(project, target, worktrees)
+	if err != nil {
+		return "", nil, err
+	}
+	logger.Debug("Grouped %s from %s", target, strings.Join(slices.Sorted(maps.Keys(worktrees)), ", "))
+	return groupDir, members, nil
+}
+
+// unlinkProjectGroups removes the links to a removed worktree from the group
+// directories of its project, and the group directories left empty
+func unlinkProjectGroups(bareDir, worktree string) {
+	project, err := workspace.FindProject(filepath.Dir(bareDir))
+	if err != nil {
+		if !errors.Is(err, workspace.ErrNotInProject) {
+			logger.Debug("Failed to find project: %v", err)
+		}
+		return
+	}
+	repo := project.RepoContaining(bareDir)
+	if repo == nil {
+		return
+	}
+	if err := project.UnlinkGroups(repo.Name, worktree); err != nil {
+		logger.Warning("Failed to unlink %s from its group: %v", styles.RenderPath(worktree), err)
+	}
+}
+
+// cwdInWorktree reports whether cwd is inside worktree, also when cwd reaches
+// it through the symlinks of a group directory
+func cwdInWorktree(cwd, worktree string) bool {
+	if fs.PathsEqual(cwd, worktree) || fs.PathHasPrefix(cwd, worktree) {
+		return true
+	}
+
+	resolvedCwd, err := filepath.EvalSymlinks(cwd)
+	if err != nil {
+		return false
+	}
+	resolvedWorktree, err := filepath.EvalSymlinks(worktree)
+	if err != nil {
+		return false
+	}
+	return fs.PathsEqual(resolvedCwd, resolvedWorktree) || fs.PathHasPrefix(resolvedCwd, resolvedWorktree)
+}
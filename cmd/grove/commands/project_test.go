@@ -0,0 +1,107 @@
+package commands
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sqve/grove/internal/fs"
+	"github.com/sqve/grove/internal/testutil"
+	"github.com/sqve/grove/internal/workspace"
+)
+
+// setupProject creates a project with repos api and web, where only api has
+// a workspace
+func setupProject(t *testing.T) string {
+	t.Helper()
+
+	root := testutil.TempDir(t)
+	testutil.WriteFile(t, filepath.Join(root, workspace.ManifestFileName), "[[repos]]\nname = \"api\"\n\n[[repos]]\nname = \"web\"\n")
+	if err := os.MkdirAll(filepath.Join(root, "api", ".bare"), fs.DirGit); err != nil {
+		t.Fatal(err)
+	}
+	return root
+}
+
+func TestProjectRepos(t *testing.T) {
+	root := setupProject(t)
+
+	t.Run("works across cloned repos from the project root", func(t *testing.T) {
+		repos, err := projectRepos(root, false)
+		if err != nil {
+			t.Fatalf("projectRepos failed: %v", err)
+		}
+		if len(repos) != 1 || repos[0].Name != "api" {
+			t.Errorf("expected only api, got %+v", repos)
+		}
+	})
+
+	t.Run("stays in the current workspace inside a repo", func(t *testing.T) {
+		repos, err := projectRepos(filepath.Join(root, "api"), false)
+		if err != nil {
+			t.Fatalf("projectRepos failed: %v", err)
+		}
+		if repos != nil {
+			t.Errorf("expected nil, got %+v", repos)
+		}
+	})
+
+	t.Run("all repos from inside a repo", func(t *testing.T) {
+		repos, err := projectRepos(filepath.Join(root, "api"), true)
+		if err != nil {
+			t.Fatalf("projectRepos failed: %v", err)
+		}
+		if len(repos) != 1 {
+			t.Errorf("expected 1 repo, got %+v", repos)
+		}
+	})
+
+	t.Run("outside a project", func(t *testing.T) {
+		dir := testutil.TempDir(t)
+
+		repos, err := projectRepos(dir, false)
+		if err != nil || repos != nil {
+			t.Errorf("expected nil without error, got %+v, %v", repos, err)
+		}
+
+		if _, err := projectRepos(dir, true); !errors.Is(err, workspace.ErrNotInProject) {
+			t.Errorf("expected ErrNotInProject with --all-repos, got %v", err)
+		}
+	})
+}
+
+func TestCwdInWorktree(t *testing.T) {
+	root := testutil.TempDir(t)
+	worktree := filepath.Join(root, "api", "feat-x")
+	if err := os.MkdirAll(filepath.Join(worktree, "src"), fs.DirGit); err != nil {
+		t.Fatal(err)
+	}
+	groupDir := filepath.Join(root, "feat-x")
+	if err := os.MkdirAll(groupDir, fs.DirGit); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join("..", "api", "feat-x"), filepath.Join(groupDir, "api")); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		cwd  string
+		want bool
+	}{
+		{"worktree", worktree, true},
+		{"subdirectory", filepath.Join(worktree, "src"), true},
+		{"through group link", filepath.Join(groupDir, "api", "src"), true},
+		{"group directory", groupDir, false},
+		{"project root", root, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cwdInWorktree(tt.cwd, worktree); got != tt.want {
+				t.Errorf("cwdInWorktree(%s) = %v, want %v", tt.cwd, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,396 @@
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/sqve/grove/internal/formatter"
+	"github.com/sqve/grove/internal/fs"
+	"github.com/sqve/grove/internal/git"
+	"github.com/sqve/grove/internal/logger"
+	"github.com/sqve/grove/internal/workspace"
+)
+
+const (
+	defaultPromptFormat = "{name}{dirty:*}{sync: %s}"
+
+	// promptCacheMaxAge bounds how long edits that leave the index and HEAD
+	// untouched take to show up in the prompt
+	promptCacheMaxAge = 10 * time.Second
+
+	// promptRefreshTimeout is how long a refresh may hold its lock before
+	// other prompts assume it died
+	promptRefreshTimeout = time.Minute
+)
+
+// promptField renders a status field, returning an empty string when it is
+// unset. Flags have no value of their own and need text to show.
+type promptField struct {
+	flag  bool
+	value func(info *StatusInfo) string
+}
+
+var promptFields = map[string]promptField{
+	"name":      {value: func(info *StatusInfo) string { return filepath.Base(info.Path) }},
+	"branch":    {value: func(info *StatusInfo) string { return info.Branch }},
+	"upstream":  {value: func(info *StatusInfo) string { return info.Upstream }},
+	"operation": {value: func(info *StatusInfo) string { return info.Operation }},
+	"ahead":     {value: func(info *StatusInfo) string { return promptCount(info.Ahead) }},
+	"behind":    {value: func(info *StatusInfo) string { return promptCount(info.Behind) }},
+	"staged":    {value: func(info *StatusInfo) string { return promptCount(info.Staged) }},
+	"unstaged":  {value: func(info *StatusInfo) string { return promptCount(info.Unstaged) }},
+	"stashes":   {value: func(info *StatusInfo) string { return promptCount(info.Stashes) }},
+	"conflicts": {value: func(info *StatusInfo) string { return promptCount(info.Conflicts) }},
+	"sync": {value: func(info *StatusInfo) string {
+		return formatter.Sync(info.Ahead, info.Behind, !info.NoUpstream)
+	}},
+	"dirty":    {flag: true, value: func(info *StatusInfo) string { return promptFlag(info.Dirty) }},
+	"locked":   {flag: true, value: func(info *StatusInfo) string { return promptFlag(info.Locked) }},
+	"detached": {flag: true, value: func(info *StatusInfo) string { return promptFlag(info.Detached) }},
+	"gone":     {flag: true, value: func(info *StatusInfo) string { return promptFlag(info.Gone) }},
+}
+
+func promptCount(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return strconv.Itoa(n)
+}
+
+func promptFlag(set bool) string {
+	if !set {
+		return ""
+	}
+	return "true"
+}
+
+// promptSegment is literal text, or a field with optional text to show in
+// its place
+type promptSegment struct {
+	literal string
+	field   string
+	text    string
+	hasText bool
+}
+
+// promptCache is the status of a worktree as last read by grove prompt
+type promptCache struct {
+	Stamp  string     `json:"stamp"`
+	Time   time.Time  `json:"time"`
+	Status StatusInfo `json:"status"`
+}
+
+// NewPromptCmd creates the prompt command
+func NewPromptCmd() *cobra.Command {
+	var format string
+	var refresh bool
+
+	cmd := &cobra.Command{
+		Use:   "prompt",
+		Short: "Print worktree status for a shell prompt",
+		Long: `Print a short worktree status for shell prompts such as zsh or starship.
+
+Status is read from a cache in .bare/grove/cache, which is refreshed in the
+background when the worktree's index or HEAD changed or the cache is more
+than 10 seconds old. Only the first prompt in a worktree waits for git.
+Outside a worktree nothing is printed.
+
+Placeholders:
+  {field}        The value of field, or nothing when it is unset
+  {field:text}   text when field is set, with %d or %s replaced by its value
+  {{ and }}      Literal braces
+
+Fields: name, branch, upstream, operation, ahead, behind, staged, unstaged,
+stashes, conflicts and sync (↑N↓N, or = when up to date). The flags dirty,
+locked, detached and gone need text, like {dirty:*}.
+
+Examples:
+  grove prompt                                   # main* ↑1
+  grove prompt --format '{branch}{dirty:*}{ahead: ↑%d}{behind: ↓%d}'
+  grove prompt --format '{name}{operation: (%s)}{stashes: ≡%d}'`,
+		Args: cobra.NoArgs,
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// git status refreshes the index when it can, which would
+			// invalidate the cache it was read for
+			if err := os.Setenv("GIT_OPTIONAL_LOCKS", "0"); err != nil {
+				return err
+			}
+			if refresh {
+				return runPromptRefresh()
+			}
+			return runPrompt(format)
+		},
+	}
+
+	cmd.Flags().StringVarP(&format, "format", "f", defaultPromptFormat, "Format with {field} placeholders")
+	cmd.Flags().BoolVar(&refresh, "refresh", false, "Refresh the status cache and exit")
+	_ = cmd.Flags().MarkHidden("refresh")
+	cmd.Flags().BoolP("help", "h", false, "Help for prompt")
+
+	return cmd
+}
+
+// promptWorktree finds the workspace and worktree of the current directory
+// without running git. It returns false outside a worktree.
+func promptWorktree() (bareDir, worktreeRoot, gitDir string, ok bool) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", "", "", false
+	}
+	bareDir, err = workspace.FindBareDir(cwd)
+	if err != nil {
+		return "", "", "", false
+	}
+	worktreeRoot, err = git.FindWorktreeRoot(cwd)
+	if err != nil {
+		return "", "", "", false
+	}
+	gitDir, err = git.GetWorktreeGitDir(worktreeRoot)
+	if err != nil || !fs.PathsEqual(filepath.Dir(gitDir), filepath.Join(bareDir, "worktrees")) {
+		return "", "", "", false
+	}
+	return bareDir, worktreeRoot, gitDir, true
+}
+
+func runPrompt(format string) error {
+	segments, err := parsePromptFormat(format)
+	if err != nil {
+		return err
+	}
+
+	// Prompts run in every directory, so there is nothing to report outside
+	// a worktree
+	bareDir, worktreeRoot, gitDir, ok := promptWorktree()
+	if !ok {
+		return nil
+	}
+
+	cachePath := promptCachePath(bareDir, gitDir)
+	cache, err := readPromptCache(cachePath, worktreeRoot)
+	if err != nil {
+		logger.Debug("No prompt cache: %v", err)
+		if cache, err = refreshPromptCache(cachePath, worktreeRoot, gitDir); err != nil {
+			return err
+		}
+	} else if cache.isStale(promptStamp(gitDir), time.Now()) {
+		startPromptRefresh(cachePath, worktreeRoot)
+	}
+
+	if out := renderPrompt(segments, &cache.Status); out != "" {
+		fmt.Println(out)
+	}
+	return nil
+}
+
+// runPromptRefresh rewrites the cache of the current worktree. It runs
+// detached from the prompt that found the cache stale.
+func runPromptRefresh() error {
+	bareDir, worktreeRoot, gitDir, ok := promptWorktree()
+	if !ok {
+		return errors.New("not inside a worktree (run from a worktree directory)")
+	}
+
+	cachePath := promptCachePath(bareDir, gitDir)
+	defer func() { _ = os.Remove(cachePath + ".lock") }()
+
+	_, err := refreshPromptCache(cachePath, worktreeRoot, gitDir)
+	return err
+}
+
+// parsePromptFormat splits format into literals and fields
+func parsePromptFormat(format string) ([]promptSegment, error) {
+	var segments []promptSegment
+	var literal strings.Builder
+	flush := func() {
+		if literal.Len() > 0 {
+			segments = append(segments, promptSegment{literal: literal.String()})
+			literal.Reset()
+		}
+	}
+
+	for i := 0; i < len(format); i++ {
+		c := format[i]
+		switch {
+		case (c == '{' || c == '}') && i+1 < len(format) && format[i+1] == c:
+			literal.WriteByte(c)
+			i++
+		case c == '}':
+			return nil, fmt.Errorf("unexpected '}' at position %d in prompt format (use '}}' for a literal brace)", i)
+		case c == '{':
+			end := strings.IndexByte(format[i:], '}')
+			if end < 0 {
+				return nil, fmt.Errorf("unclosed '{' at position %d in prompt format", i)
+			}
+			placeholder := format[i+1 : i+end]
+			name, text, hasText := strings.Cut(placeholder, ":")
+			field, ok := promptFields[name]
+			if !ok {
+				return nil, fmt.Errorf("unknown prompt field %q", name)
+			}
+			if field.flag && !hasText {
+				return nil, fmt.Errorf("prompt field %q needs text to show, like {%s:*}", name, name)
+			}
+			flush()
+			segments = append(segments, promptSegment{field: name, text: text, hasText: hasText})
+			i += end
+		default:
+			literal.WriteByte(c)
+		}
+	}
+	flush()
+
+	return segments, nil
+}
+
+// renderPrompt fills segments with the fields of info, leaving out unset
+// fields along with their text
+func renderPrompt(segments []promptSegment, info *StatusInfo) string {
+	var b strings.Builder
+	for _, segment := range segments {
+		if segment.field == "" {
+			b.WriteString(segment.literal)
+			continue
+		}
+		value := promptFields[segment.field].value(info)
+		if value == "" {
+			continue
+		}
+		if segment.hasText {
+			value = strings.NewReplacer("%d", value, "%s", value).Replace(segment.text)
+		}
+		b.WriteString(value)
+	}
+	return b.String()
+}
+
+// promptCachePath returns the cache file of the worktree with gitDir, named
+// after its entry in .bare/worktrees
+func promptCachePath(bareDir, gitDir string) string {
+	return filepath.Join(bareDir, "grove", "cache", filepath.Base(gitDir)+".json")
+}
+
+// promptStamp identifies the state of the index and HEAD of a worktree. The
+// HEAD reflog is included, since commits move the branch without touching
+// HEAD itself.
+func promptStamp(gitDir string) string {
+	var parts []string
+	for _, name := range []string{"index", "HEAD", filepath.Join("logs", "HEAD")} {
+		info, err := os.Stat(filepath.Join(gitDir, name))
+		if err != nil {
+			parts = append(parts, "-")
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%d:%d", info.ModTime().UnixNano(), info.Size()))
+	}
+	return strings.Join(parts, " ")
+}
+
+func (c *promptCache) isStale(stamp string, now time.Time) bool {
+	return c.Stamp != stamp || now.Sub(c.Time) > promptCacheMaxAge
+}
+
+// readPromptCache reads the cache at path, which must describe worktreeRoot.
+// A moved worktree keeps its entry in .bare/worktrees, so its cache would
+// otherwise show the old name.
+func readPromptCache(path, worktreeRoot string) (*promptCache, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // Path derived from the workspace
+	if err != nil {
+		return nil, err
+	}
+	var cache promptCache
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return nil, fmt.Errorf("invalid prompt cache: %w", err)
+	}
+	if !fs.PathsEqual(cache.Status.Path, worktreeRoot) {
+		return nil, fmt.Errorf("prompt cache is for %s", cache.Status.Path)
+	}
+	return &cache, nil
+}
+
+// refreshPromptCache reads the status of worktreeRoot and writes it to the
+// cache at path. A cache that cannot be written is not an error, since the
+// status is still shown.
+func refreshPromptCache(path, worktreeRoot, gitDir string) (*promptCache, error) {
+	// Stamp before reading, so changes made meanwhile trigger another refresh
+	stamp := promptStamp(gitDir)
+	info, err := gatherStatusInfo(worktreeRoot)
+	if err != nil {
+		return nil, err
+	}
+	cache := &promptCache{Stamp: stamp, Time: time.Now(), Status: *info}
+
+	data, err := json.Marshal(cache)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), fs.DirGit); err != nil {
+		logger.Debug("Failed to create prompt cache directory: %v", err)
+		return cache, nil
+	}
+	if err := fs.WriteFileAtomic(path, data, fs.FileGit); err != nil {
+		logger.Debug("Failed to write prompt cache: %v", err)
+	}
+	return cache, nil
+}
+
+// startPromptRefresh refreshes the cache at path in a detached grove
+// process, unless another refresh is already running
+func startPromptRefresh(path, worktreeRoot string) {
+	lockPath := path + ".lock"
+	if !acquirePromptLock(lockPath) {
+		logger.Debug("Prompt cache refresh already running")
+		return
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		logger.Debug("Failed to find grove executable: %v", err)
+		_ = os.Remove(lockPath)
+		return
+	}
+
+	cmd := exec.Command(exe, "prompt", "--refresh") //nolint:gosec // Runs grove itself
+	cmd.Dir = worktreeRoot
+	detachProcess(cmd)
+	if err := cmd.Start(); err != nil {
+		logger.Debug("Failed to start prompt cache refresh: %v", err)
+		_ = os.Remove(lockPath)
+		return
+	}
+	_ = cmd.Process.Release()
+	logger.Debug("Refreshing prompt cache in the background")
+}
+
+// acquirePromptLock creates the lock at path, taking over locks left behind
+// by refreshes that died
+func acquirePromptLock(path string) bool {
+	create := func() bool {
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, fs.FileGit) //nolint:gosec // Path derived from the workspace
+		if err != nil {
+			return false
+		}
+		_ = file.Close()
+		return true
+	}
+
+	if create() {
+		return true
+	}
+	info, err := os.Stat(path)
+	if err != nil || time.Since(info.ModTime()) < promptRefreshTimeout {
+		return false
+	}
+	_ = os.Remove(path)
+	return create()
+}
@@ -0,0 +1,165 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sqve/grove/internal/config"
+	"github.com/sqve/grove/internal/fs"
+	"github.com/sqve/grove/internal/testutil"
+)
+
+func TestNewPromptCmd(t *testing.T) {
+	cmd := NewPromptCmd()
+
+	if cmd.Use != "prompt" {
+		t.Errorf("expected Use 'prompt', got %q", cmd.Use)
+	}
+
+	format, err := cmd.Flags().GetString("format")
+	if err != nil {
+		t.Fatalf("failed to get format flag: %v", err)
+	}
+	if format != defaultPromptFormat {
+		t.Errorf("expected format to default to %q, got %q", defaultPromptFormat, format)
+	}
+
+	refresh := cmd.Flags().Lookup("refresh")
+	if refresh == nil || !refresh.Hidden {
+		t.Error("expected hidden --refresh flag")
+	}
+}
+
+func TestRenderPrompt(t *testing.T) {
+	config.SetPlain(true)
+	defer config.SetPlain(false)
+
+	info := &StatusInfo{
+		Path:      filepath.Join("workspace", "feat-login"),
+		Branch:    "feat/login",
+		Ahead:     2,
+		Dirty:     true,
+		Operation: "rebase",
+	}
+
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{"{name}", "feat-login"},
+		{"{branch}{dirty:*}", "feat/login*"},
+		{"{name}{dirty:*}{ahead:↑%d}{behind:↓%d}", "feat-login*↑2"},
+		{"{sync}", "+2"},
+		{"{locked:🔒}{stashes: ≡%d}", ""},
+		{"[{operation:%s}]", "[rebase]"},
+		{"{{{branch}}}", "{feat/login}"},
+		{"on {branch:%s} ", "on feat/login "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			segments, err := parsePromptFormat(tt.format)
+			if err != nil {
+				t.Fatalf("parsePromptFormat(%q) failed: %v", tt.format, err)
+			}
+			if got := renderPrompt(segments, info); got != tt.want {
+				t.Errorf("renderPrompt(%q) = %q, want %q", tt.format, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePromptFormat_Errors(t *testing.T) {
+	tests := []struct {
+		format  string
+		wantErr string
+	}{
+		{"{nope}", `unknown prompt field "nope"`},
+		{"{dirty}", `prompt field "dirty" needs text`},
+		{"{branch", "unclosed '{'"},
+		{"branch}", "unexpected '}'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			_, err := parsePromptFormat(tt.format)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("parsePromptFormat(%q) error = %v, want %q", tt.format, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPromptCacheStale(t *testing.T) {
+	gitDir := testutil.TempDir(t)
+	for _, name := range []string{"index", "HEAD"} {
+		if err := os.WriteFile(filepath.Join(gitDir, name), []byte(name), fs.FileGit); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	now := time.Now()
+	cache := &promptCache{Stamp: promptStamp(gitDir), Time: now}
+	if cache.isStale(promptStamp(gitDir), now) {
+		t.Error("expected fresh cache")
+	}
+	if !cache.isStale(promptStamp(gitDir), now.Add(promptCacheMaxAge+time.Second)) {
+		t.Error("expected cache to go stale with age")
+	}
+
+	if err := os.WriteFile(filepath.Join(gitDir, "index"), []byte("changed index"), fs.FileGit); err != nil {
+		t.Fatal(err)
+	}
+	if !cache.isStale(promptStamp(gitDir), now) {
+		t.Error("expected cache to go stale when the index changes")
+	}
+}
+
+func TestReadPromptCache_OtherWorktree(t *testing.T) {
+	path := filepath.Join(testutil.TempDir(t), "main.json")
+	data := `{"stamp":"-","time":"2026-01-01T00:00:00Z","status":{"path":"/old/main"}}`
+	if err := os.WriteFile(path, []byte(data), fs.FileGit); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := readPromptCache(path, "/old/main"); err != nil {
+		t.Errorf("expected cache of the same worktree, got %v", err)
+	}
+	if _, err := readPromptCache(path, "/new/main"); err == nil {
+		t.Error("expected cache of a moved worktree to be ignored")
+	}
+}
+
+func TestAcquirePromptLock(t *testing.T) {
+	path := filepath.Join(testutil.TempDir(t), "main.json.lock")
+
+	if !acquirePromptLock(path) {
+		t.Fatal("expected to acquire a free lock")
+	}
+	if acquirePromptLock(path) {
+		t.Error("expected a held lock to stay held")
+	}
+
+	old := time.Now().Add(-2 * promptRefreshTimeout)
+	if err := os.Chtimes(path, old, old); err != nil {
+		t.Fatal(err)
+	}
+	if !acquirePromptLock(path) {
+		t.Error("expected to take over an abandoned lock")
+	}
+}
+
+func TestRunPrompt_NotInWorkspace(t *testing.T) {
+	defer testutil.SaveCwd(t)()
+	testutil.Chdir(t, testutil.TempDir(t))
+
+	if err := runPrompt(defaultPromptFormat); err != nil {
+		t.Errorf("expected no error outside a workspace, got %v", err)
+	}
+	if err := runPrompt("{nope}"); err == nil {
+		t.Error("expected invalid format to fail outside a workspace")
+	}
+}
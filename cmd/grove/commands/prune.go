@@ -3,16 +3,18 @@ package commands
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/sqve/grove/internal/config"
+	"github.com/sqve/grove/internal/forge"
 	"github.com/sqve/grove/internal/formatter"
 	"github.com/sqve/grove/internal/fs"
 	"github.com/sqve/grove/internal/git"
-	"github.com/sqve/grove/internal/github"
+	"github.com/sqve/grove/internal/hooks"
 	"github.com/sqve/grove/internal/logger"
 	"github.com/sqve/grove/internal/workspace"
 )
@@ -41,12 +43,21 @@ const (
 
 // pruneCandidate represents a worktree that could be pruned
 type pruneCandidate struct {
+	repo      string // Repository of a multi-repository project
 	info      *git.WorktreeInfo
 	reason    skipReason
 	pruneType pruneType
 	staleAge  string // Human-readable age for stale worktrees
 }
 
+// label names the candidate in output, with its repository in a project
+func (c pruneCandidate) label() string {
+	if c.repo != "" {
+		return c.repo + " " + formatter.WorktreeLabel(c.info)
+	}
+	return formatter.WorktreeLabel(c.info)
+}
+
 // NewPruneCmd creates the prune command
 func NewPruneCmd() *cobra.Command {
 	var commit bool
@@ -54,6 +65,7 @@ func NewPruneCmd() *cobra.Command {
 	var stale string
 	var merged bool
 	var detached bool
+	var allRepos bool
 
 	cmd := &cobra.Command{
 		Use:   "prune",
@@ -68,7 +80,10 @@ Examples:
   grove prune --stale 30d     # Include inactive worktrees
   grove prune --merged        # Include merged branches
   grove prune --detached      # Include detached worktrees
-  grove prune --force         # Remove even if dirty or locked`,
+  grove prune --force         # Remove even if dirty or locked
+  grove prune --all-repos     # Every repo of the grove.workspace.toml project
+
+From a project directory outside its repos, every repo is pruned.`,
 		Args: cobra.NoArgs,
 		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 			return nil, cobra.ShellCompDirectiveNoFileComp
@@ -78,7 +93,7 @@ Examples:
 			if cmd.Flags().Changed("stale") && stale == "" {
 				stale = config.GetStaleThreshold()
 			}
-			return runPrune(commit, force, stale, merged, detached)
+			return runPrune(commit, force, allRepos, stale, merged, detached)
 		},
 	}
 
@@ -87,6 +102,7 @@ Examples:
 	cmd.Flags().StringVar(&stale, "stale", "", fmt.Sprintf("Include inactive worktrees (e.g., 30d, 2w; default: %s)", config.GetStaleThreshold()))
 	cmd.Flags().BoolVar(&merged, "merged", false, "Include worktrees merged into default branch")
 	cmd.Flags().BoolVar(&detached, "detached", false, "Include detached worktrees")
+	cmd.Flags().BoolVar(&allRepos, "all-repos", false, "Prune every repo in the project")
 	cmd.Flags().BoolP("help", "h", false, "Help for prune")
 
 	_ = cmd.RegisterFlagCompletionFunc("stale", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -96,7 +112,7 @@ Examples:
 	return cmd
 }
 
-func runPrune(commit, force bool, stale string, merged, detached bool) error {
+func runPrune(commit, force, allRepos bool, stale string, merged, detached bool) error {
 	// Parse stale threshold if provided
 	var staleCutoff int64
 	if stale != "" {
@@ -112,13 +128,76 @@ func runPrune(commit, force bool, stale string, merged, detached bool) error {
 		return fmt.Errorf("failed to get current directory: %w", err)
 	}
 
+	repos, err := projectRepos(cwd, allRepos)
+	if err != nil {
+		return err
+	}
+	if repos != nil {
+		return pruneRepos(repos, cwd, commit, force, staleCutoff, merged, detached)
+	}
+
 	bareDir, err := workspace.FindBareDir(cwd)
 	if err != nil {
 		return err
 	}
 
+	candidates, defaultBranch, err := findPruneCandidates(bareDir, "", cwd, force, staleCutoff, merged, detached)
+	if err != nil {
+		return err
+	}
+
+	// Output results
+	if commit {
+		return executePrune(bareDir, candidates, force, defaultBranch)
+	}
+	return displayDryRun(candidates)
+}
+
+// pruneRepos prunes the repositories of a project. The dry-run shows the
+// candidates of all of them in one list.
+func pruneRepos(repos []workspace.ProjectRepo, cwd string, commit, force bool, staleCutoff int64, merged, detached bool) error {
+	var all []pruneCandidate
+	var failed []string
+	for _, repo := range repos {
+		candidates, defaultBranch, err := findPruneCandidates(repo.BareDir(), repo.Name, cwd, force, staleCutoff, merged, detached)
+		if err != nil {
+			logger.Error("%s: %v", repo.Name, err)
+			failed = append(failed, repo.Name)
+			continue
+		}
+		if commit && len(candidates) > 0 {
+			if err := executePrune(repo.BareDir(), candidates, force, defaultBranch); err != nil {
+				logger.Error("%s: %v", repo.Name, err)
+				failed = append(failed, repo.Name)
+			}
+		}
+		all = append(all, candidates...)
+	}
+
+	if !commit {
+		if err := displayDryRun(all); err != nil {
+			return err
+		}
+	} else if len(all) == 0 && len(failed) == 0 {
+		logger.Info("No worktrees to remove.")
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("failed: %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// findPruneCandidates fetches the workspace and returns its worktrees that
+// could be pruned, with the default branch. repo names the repository of a
+// project, or is empty for a single workspace.
+func findPruneCandidates(bareDir, repo, cwd string, force bool, staleCutoff int64, merged, detached bool) ([]pruneCandidate, string, error) {
 	// Fetch and prune remote refs
-	spin := logger.StartSpinner("Fetching remote changes...")
+	fetchMessage := "Fetching remote changes..."
+	if repo != "" {
+		fetchMessage = fmt.Sprintf("Fetching remote changes of %s...", repo)
+	}
+	spin := logger.StartSpinner(fetchMessage)
 	if err := git.FetchPrune(bareDir); err != nil {
 		spin.Stop()
 		// Non-fatal: network issues shouldn't block prune of already-known gone branches
@@ -140,7 +219,7 @@ func runPrune(commit, force bool, stale string, merged, detached bool) error {
 	// Get all worktrees with info
 	infos, err := git.ListWorktreesWithInfo(bareDir, false)
 	if err != nil {
-		return fmt.Errorf("failed to list worktrees: %w", err)
+		return nil, "", fmt.Errorf("failed to list worktrees: %w", err)
 	}
 
 	// Find prune candidates. git-prunable (path-gone) worktrees are listed
@@ -150,10 +229,11 @@ func runPrune(commit, force bool, stale string, merged, detached bool) error {
 
 	prunables, err := git.ListPrunableWorktrees(bareDir)
 	if err != nil {
-		return fmt.Errorf("failed to list prunable worktrees: %w", err)
+		return nil, "", fmt.Errorf("failed to list prunable worktrees: %w", err)
 	}
 	for _, info := range prunables {
 		candidates = append(candidates, pruneCandidate{
+			repo:      repo,
 			info:      info,
 			reason:    determineSkipReason(info, cwd, force),
 			pruneType: prunePrunable,
@@ -165,6 +245,7 @@ func runPrune(commit, force bool, stale string, merged, detached bool) error {
 		if info.Gone {
 			reason := determineSkipReason(info, cwd, force)
 			candidates = append(candidates, pruneCandidate{
+				repo:      repo,
 				info:      info,
 				reason:    reason,
 				pruneType: pruneGone,
@@ -176,6 +257,7 @@ func runPrune(commit, force bool, stale string, merged, detached bool) error {
 		if detached && info.Detached {
 			reason := determineSkipReason(info, cwd, force)
 			candidates = append(candidates, pruneCandidate{
+				repo:      repo,
 				info:      info,
 				reason:    reason,
 				pruneType: pruneDetached,
@@ -189,6 +271,7 @@ func runPrune(commit, force bool, stale string, merged, detached bool) error {
 			if mergeErr == nil && isMerged {
 				reason := determineSkipReason(info, cwd, force)
 				candidates = append(candidates, pruneCandidate{
+					repo:      repo,
 					info:      info,
 					reason:    reason,
 					pruneType: pruneMerged,
@@ -201,6 +284,7 @@ func runPrune(commit, force bool, stale string, merged, detached bool) error {
 		if staleCutoff > 0 && info.LastCommitTime > 0 && info.LastCommitTime < staleCutoff {
 			reason := determineSkipReason(info, cwd, force)
 			candidates = append(candidates, pruneCandidate{
+				repo:      repo,
 				info:      info,
 				reason:    reason,
 				pruneType: pruneStale,
@@ -209,16 +293,12 @@ func runPrune(commit, force bool, stale string, merged, detached bool) error {
 		}
 	}
 
-	// Output results
-	if commit {
-		return executePrune(bareDir, candidates, force, defaultBranch)
-	}
-	return displayDryRun(candidates)
+	return candidates, defaultBranch, nil
 }
 
 func determineSkipReason(info *git.WorktreeInfo, cwd string, force bool) skipReason {
 	// Current worktree is always protected (also from subdirectories)
-	if fs.PathsEqual(cwd, info.Path) || fs.PathHasPrefix(cwd, info.Path) {
+	if cwdInWorktree(cwd, info.Path) {
 		return skipCurrent
 	}
 
@@ -252,7 +332,7 @@ func displayDryRun(candidates []pruneCandidate) error {
 	var toSkip []string
 
 	for _, candidate := range candidates {
-		label := formatter.WorktreeLabel(candidate.info)
+		label := candidate.label()
 		if candidate.pruneType == prunePrunable {
 			label = fmt.Sprintf("%s (%s)", label, candidate.pruneType)
 		}
@@ -308,10 +388,11 @@ func executePrune(bareDir string, candidates []pruneCandidate, force bool, defau
 		return nil
 	}
 
-	// Fetch merged PR branches from GitHub (for multi-commit squash detection)
+	// Fetch branches of merged PRs/MRs from the forge (for multi-commit squash detection)
 	var mergedViaPR map[string]bool
-	if mergedPRs, err := github.GetMergedPRBranches(bareDir); err != nil {
-		logger.Debug("GitHub PR check unavailable: %v", err)
+	originURL, _ := git.GetRemoteURL(bareDir, "origin")
+	if mergedPRs, err := forge.MergedBranches(bareDir, originURL); err != nil {
+		logger.Debug("Merged PR/MR check unavailable: %v", err)
 	} else {
 		mergedViaPR = mergedPRs
 	}
@@ -352,8 +433,10 @@ func executePrune(bareDir string, candidates []pruneCandidate, force bool, defau
 		return false
 	}
 
+	workspaceRoot := filepath.Dir(bareDir)
+
 	for _, candidate := range candidates {
-		label := formatter.WorktreeLabel(candidate.info)
+		label := candidate.label()
 		if candidate.pruneType == prunePrunable {
 			label = fmt.Sprintf("%s (%s)", label, candidate.pruneType)
 		}
@@ -363,6 +446,16 @@ func executePrune(bareDir string, candidates []pruneCandidate, force bool, defau
 			continue
 		}
 
+		preRemoveHooks := loadHooks(bareDir, hooks.EventPreRemove, candidate.info.Path)
+		postRemoveHooks := loadHooks(bareDir, hooks.EventPostRemove, candidate.info.Path)
+		hookCtx := &hooks.Context{
+			Event:     hooks.EventPostRemove,
+			Command:   "prune",
+			Workspace: workspaceRoot,
+			Worktree:  candidate.info.Path,
+			Branch:    candidate.info.Branch,
+		}
+
 		// git-prunable entries are reaped by the shared prune call; confirm the
 		// entry is actually gone before reporting it pruned.
 		if candidate.pruneType == prunePrunable {
@@ -375,16 +468,47 @@ func executePrune(bareDir string, candidates []pruneCandidate, force bool, defau
 				continue
 			}
 			pruned = append(pruned, label)
+			logger.Emit(logger.Event{Type: logger.EventWorktreeRemoved, Worktree: candidate.info.Path, Branch: candidate.info.Branch})
+			closeTmuxWindow(candidate.info.Path)
+			workspace.RemoveEmptyParents(candidate.info.Path, workspaceRoot)
+			unlinkProjectGroups(bareDir, candidate.info.Path)
+			runPostHooks(bareDir, postRemoveHooks, workspaceRoot, hookCtx)
+			releaseWorktreeEnv(bareDir, candidate.info.Path)
+			continue
+		}
+
+		// Pre-remove hooks need the worktree directory, so they only run for
+		// worktrees that still exist on disk
+		hookCtx.Event = hooks.EventPreRemove
+		if err := runPreHooks(bareDir, preRemoveHooks, candidate.info.Path, hookCtx); err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", label, err))
+			continue
+		}
+
+		// Capture the worktree for grove undo before it is removed
+		entry, snap, err := saveRemoval(bareDir, "prune", candidate.info, force)
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", label, err))
 			continue
 		}
 
 		// Actually remove the worktree
 		if err := git.RemoveWorktree(bareDir, candidate.info.Path, force); err != nil {
+			discardRemoval(bareDir, entry, snap)
 			failed = append(failed, fmt.Sprintf("%s: %v", label, err))
 			continue
 		}
+		logSavedSnapshot(candidate.info, snap)
 
 		pruned = append(pruned, label)
+		logger.Emit(logger.Event{Type: logger.EventWorktreeRemoved, Worktree: candidate.info.Path, Branch: candidate.info.Branch})
+		closeTmuxWindow(candidate.info.Path)
+		workspace.RemoveEmptyParents(candidate.info.Path, workspaceRoot)
+		unlinkProjectGroups(bareDir, candidate.info.Path)
+
+		hookCtx.Event = hooks.EventPostRemove
+		runPostHooks(bareDir, postRemoveHooks, workspaceRoot, hookCtx)
+		releaseWorktreeEnv(bareDir, candidate.info.Path)
 
 		// Delete local branch for gone worktrees (not detached)
 		if candidate.pruneType == pruneGone && !candidate.info.Detached {
@@ -408,6 +532,7 @@ func executePrune(bareDir string, candidates []pruneCandidate, force bool, defau
 				forceDelete = true
 			}
 
+			stackParent, _ := git.GetBranchParent(bareDir, candidate.info.Branch)
 			if err := git.DeleteBranch(bareDir, candidate.info.Branch, forceDelete); err != nil {
 				if strings.Contains(err.Error(), "not fully merged") {
 					keptBranches = append(keptBranches, fmt.Sprintf("%s (unmerged commits)", candidate.info.Branch))
@@ -416,8 +541,13 @@ func executePrune(bareDir string, candidates []pruneCandidate, force bool, defau
 				}
 			} else {
 				deletedBranches++
+				reparentStackedBranches(bareDir, candidate.info.Branch, stackParent)
+				if entry != nil {
+					entry.BranchDeleted = true
+				}
 			}
 		}
+		recordJournal(bareDir, entry)
 	}
 
 	// Branch deletion can leave the bare repo's HEAD pointing at a deleted
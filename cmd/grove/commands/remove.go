@@ -10,7 +10,10 @@ import (
 	"github.com/sqve/grove/internal/formatter"
 	"github.com/sqve/grove/internal/fs"
 	"github.com/sqve/grove/internal/git"
+	"github.com/sqve/grove/internal/hooks"
+	"github.com/sqve/grove/internal/journal"
 	"github.com/sqve/grove/internal/logger"
+	"github.com/sqve/grove/internal/picker"
 	"github.com/sqve/grove/internal/styles"
 	"github.com/sqve/grove/internal/workspace"
 )
@@ -25,9 +28,15 @@ func NewRemoveCmd() *cobra.Command {
 		Short: "Remove worktrees",
 		Long: `Remove one or more worktrees, optionally deleting their branches.
 
-Accepts worktree names (directories) or branch names.
+Accepts worktree names (directories) or branch names. Without arguments in
+an interactive terminal, opens a picker to choose worktrees (tab to select
+several).
+
+With --force, uncommitted changes are saved as a snapshot first; bring them
+back with 'grove restore <worktree> --new <branch>'.
 
 Examples:
+  grove remove                      # Pick worktrees interactively
   grove remove feat-auth            # Remove worktree
   grove remove --branch feat        # Remove worktree and branch
   grove remove --force wip          # Force remove if dirty or locked
@@ -35,6 +44,13 @@ Examples:
 		Args:              cobra.ArbitraryArgs,
 		ValidArgsFunction: completeRemoveArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 && pickerAvailable() {
+				picked, err := pickWorktrees(picker.Options{Prompt: "Remove", Multi: true}, skipCurrentWorktree)
+				if err != nil {
+					return err
+				}
+				args = picked
+			}
 			return runRemove(args, force, deleteBranch)
 		},
 	}
@@ -96,8 +112,11 @@ func runRemove(targets []string, force, deleteBranch bool) error {
 	var removed []removedWorktree
 	var failed []string
 
+	workspaceRoot := filepath.Dir(bareDir)
+
+	// Hook output streams to stderr, so skip the spinner when hooks will run
 	var spin *logger.Spinner
-	if len(unique) > 1 {
+	if len(unique) > 1 && len(loadHooks(bareDir, hooks.EventPreRemove, "")) == 0 && len(loadHooks(bareDir, hooks.EventPostRemove, "")) == 0 {
 		spin = logger.StartSpinner(fmt.Sprintf("Removing worktrees (0/%d)...", len(unique)))
 	}
 
@@ -135,7 +154,32 @@ func runRemove(targets []string, force, deleteBranch bool) error {
 				failed = append(failed, dirName)
 				continue
 			}
-		} else if git.IsWorktreeLocked(info.Path) {
+		}
+
+		preRemoveHooks := loadHooks(bareDir, hooks.EventPreRemove, info.Path)
+		postRemoveHooks := loadHooks(bareDir, hooks.EventPostRemove, info.Path)
+		hookCtx := &hooks.Context{
+			Event:     hooks.EventPreRemove,
+			Command:   "remove",
+			Workspace: workspaceRoot,
+			Worktree:  info.Path,
+			Branch:    info.Branch,
+		}
+		if err := runPreHooks(bareDir, preRemoveHooks, info.Path, hookCtx); err != nil {
+			logger.Error("%s: %v", displayName, err)
+			failed = append(failed, dirName)
+			continue
+		}
+
+		// Capture the worktree before it is unlocked or removed, for grove undo
+		entry, snap, err := saveRemoval(bareDir, "remove", info, force)
+		if err != nil {
+			logger.Error("%s: %v", displayName, err)
+			failed = append(failed, dirName)
+			continue
+		}
+
+		if force && git.IsWorktreeLocked(info.Path) {
 			// Unlock worktree first if locked (git requires double force otherwise)
 			if err := git.UnlockWorktree(bareDir, info.Path); err != nil {
 				logger.Debug("Failed to unlock worktree: %v", err)
@@ -151,10 +195,20 @@ func runRemove(targets []string, force, deleteBranch bool) error {
 
 		// Remove the worktree
 		if err := git.RemoveWorktree(bareDir, info.Path, force); err != nil {
+			discardRemoval(bareDir, entry, snap)
 			logger.Error("%s: failed to remove worktree: %v", displayName, err)
 			failed = append(failed, dirName)
 			continue
 		}
+		logSavedSnapshot(info, snap)
+
+		closeTmuxWindow(info.Path)
+		workspace.RemoveEmptyParents(info.Path, workspaceRoot)
+		unlinkProjectGroups(bareDir, info.Path)
+
+		hookCtx.Event = hooks.EventPostRemove
+		runPostHooks(bareDir, postRemoveHooks, workspaceRoot, hookCtx)
+		releaseWorktreeEnv(bareDir, info.Path)
 
 		// Optionally delete the branch
 		if deleteBranch {
@@ -162,13 +216,21 @@ func runRemove(targets []string, force, deleteBranch bool) error {
 				logger.Warning("%s: branch has %d unpushed commit(s)", info.Branch, aheadCount)
 			}
 
+			stackParent, _ := git.GetBranchParent(bareDir, info.Branch)
 			if err := git.DeleteBranch(bareDir, info.Branch, force); err != nil {
+				recordJournal(bareDir, entry)
 				logger.Error("%s: worktree removed but failed to delete branch: %v", displayName, err)
 				failed = append(failed, dirName)
 				continue
 			}
+			reparentStackedBranches(bareDir, info.Branch, stackParent)
+			if entry != nil {
+				entry.BranchDeleted = true
+			}
 		}
+		recordJournal(bareDir, entry)
 		removed = append(removed, removedWorktree{path: info.Path, branch: info.Branch})
+		logger.Emit(logger.Event{Type: logger.EventWorktreeRemoved, Worktree: info.Path, Branch: info.Branch})
 	}
 
 	if spin != nil {
@@ -205,6 +267,65 @@ func runRemove(targets []string, force, deleteBranch bool) error {
 	return nil
 }
 
+// saveRemoval captures a worktree for grove undo before it is removed. With
+// force, uncommitted changes are saved as a snapshot, which undo restores;
+// the journal only keeps its own copy when no snapshot can be taken.
+func saveRemoval(bareDir, command string, info *git.WorktreeInfo, force bool) (*journal.Entry, *snapshot, error) {
+	var snap *snapshot
+	if force {
+		var err error
+		if snap, err = snapshotBeforeRemoval(bareDir, info); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	entry, err := journalRemoval(bareDir, command, info, force && snap == nil)
+	if err != nil {
+		discardRemoval(bareDir, nil, snap)
+		return nil, nil, err
+	}
+	if entry != nil && snap != nil {
+		entry.SnapshotRef = snap.Ref
+	}
+	return entry, snap, nil
+}
+
+// discardRemoval drops what saveRemoval captured for a removal that did not
+// happen, so no "(removed)" snapshot is left of a worktree that still exists
+func discardRemoval(bareDir string, entry *journal.Entry, snap *snapshot) {
+	discardJournal(bareDir, entry)
+	if snap != nil {
+		if err := git.DeleteRef(bareDir, snap.Ref); err != nil {
+			logger.Debug("Failed to delete snapshot %s: %v", snap.Ref, err)
+		}
+	}
+}
+
+// snapshotBeforeRemoval saves the uncommitted changes of a worktree removed
+// with --force as a snapshot, which outlives the worktree
+func snapshotBeforeRemoval(bareDir string, info *git.WorktreeInfo) (*snapshot, error) {
+	hasChanges, _, err := git.CheckGitChanges(info.Path)
+	if err != nil || !hasChanges {
+		return nil, nil // Missing or broken worktrees have nothing to save
+	}
+	if unborn, _ := git.IsUnbornHead(info.Path); unborn {
+		return nil, nil
+	}
+
+	snap, err := createSnapshot(bareDir, info, defaultSnapshotMessage(info)+" (removed)", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to snapshot uncommitted changes: %w", err)
+	}
+	return snap, nil
+}
+
+// logSavedSnapshot reports the snapshot a removed worktree left behind
+func logSavedSnapshot(info *git.WorktreeInfo, snap *snapshot) {
+	if snap != nil {
+		logger.Info("Saved uncommitted changes of %s as snapshot %s", formatter.WorktreeLabel(info), snap.id())
+	}
+}
+
 func completeRemoveArgs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 	cwd, err := os.Getwd()
 	if err != nil {
@@ -229,7 +350,7 @@ func completeRemoveArgs(cmd *cobra.Command, args []string, toComplete string) ([
 
 	var completions []string
 	for _, info := range infos {
-		name := filepath.Base(info.Path)
+		name := git.WorktreeName(infos, info)
 
 		// Skip already-used (check both path basename and branch name)
 		if alreadyUsed[name] || alreadyUsed[info.Branch] {
@@ -12,8 +12,10 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/sqve/grove/internal/fs"
 	"github.com/sqve/grove/internal/git"
+	"github.com/sqve/grove/internal/journal"
 	"github.com/sqve/grove/internal/logger"
 	"github.com/sqve/grove/internal/testutil"
+	testgit "github.com/sqve/grove/internal/testutil/git"
 	"github.com/sqve/grove/internal/workspace"
 )
 
@@ -1160,3 +1162,28 @@ func TestRunRemove_CurrentWorktreeSubdirectory(t *testing.T) {
 		t.Error("feature worktree should still exist (protected from subdirectory)")
 	}
 }
+
+func TestSaveRemoval_DiscardDropsSnapshot(t *testing.T) {
+	ws := testgit.NewGroveWorkspace(t, "main", "feat")
+	info := &git.WorktreeInfo{Path: ws.WorktreePath("feat"), Branch: "feat"}
+	if err := os.WriteFile(filepath.Join(info.Path, "dirty.txt"), []byte("dirty"), fs.FileStrict); err != nil {
+		t.Fatal(err)
+	}
+
+	entry, snap, err := saveRemoval(ws.BareDir, "remove", info, true)
+	if err != nil {
+		t.Fatalf("saveRemoval failed: %v", err)
+	}
+	if snap == nil || entry == nil || entry.SnapshotRef != snap.Ref || entry.Snapshot != "" {
+		t.Fatalf("expected the changes in a snapshot only, got entry %+v and snapshot %+v", entry, snap)
+	}
+
+	// A removal that fails leaves neither the snapshot nor the journal entry
+	discardRemoval(ws.BareDir, entry, snap)
+	if refs, err := git.ListSnapshotRefs(ws.BareDir); err != nil || len(refs) != 0 {
+		t.Errorf("expected no snapshots, got %v (%v)", refs, err)
+	}
+	if entries, err := journal.List(ws.BareDir); err != nil || len(entries) != 0 {
+		t.Errorf("expected no journal entries, got %v (%v)", entries, err)
+	}
+}
@@ -0,0 +1,211 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"github.com/sqve/grove/internal/fs"
+	"github.com/sqve/grove/internal/git"
+	"github.com/sqve/grove/internal/logger"
+	"github.com/sqve/grove/internal/styles"
+	"github.com/sqve/grove/internal/workspace"
+)
+
+// NewRestoreCmd creates the restore command
+func NewRestoreCmd() *cobra.Command {
+	var into string
+	var newBranch string
+
+	cmd := &cobra.Command{
+		Use:   "restore [snapshot]",
+		Short: "Reapply a worktree snapshot",
+		Long: `Reapply a snapshot made with 'grove snapshot'.
+
+The snapshot is named <worktree>/<n>, by its number alone for the current
+worktree, or by a worktree for its latest snapshot. Without an argument,
+restores the latest snapshot of the current worktree.
+
+Changes are merged into the worktree the snapshot was taken from, restoring
+which were staged, unless --into picks another worktree. With --new, a
+worktree is created for a new branch at the commit the snapshot was taken
+on. The snapshot is kept; drop it with 'grove snapshot drop'.
+
+Examples:
+  grove restore                        # Latest snapshot of this worktree
+  grove restore 2                      # Snapshot 2 of this worktree
+  grove restore feat-auth/2            # Into the worktree it came from
+  grove restore feat-auth --into main  # Latest of feat-auth into main
+  grove restore feat-auth/2 --new feat/auth-retry`,
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeSnapshotIDs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if into != "" && newBranch != "" {
+				return fmt.Errorf("--into and --new cannot be used together")
+			}
+			id := ""
+			if len(args) > 0 {
+				id = args[0]
+			}
+			return runRestore(id, into, newBranch)
+		},
+	}
+
+	cmd.Flags().StringVar(&into, "into", "", "Restore into this worktree")
+	cmd.Flags().StringVar(&newBranch, "new", "", "Restore into a new worktree for this branch")
+	cmd.Flags().BoolP("help", "h", false, "Help for restore")
+
+	_ = cmd.RegisterFlagCompletionFunc("into", completeSnapshotArgs)
+	_ = cmd.RegisterFlagCompletionFunc("new", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	})
+
+	return cmd
+}
+
+func runRestore(id, into, newBranch string) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	bareDir, err := workspace.FindBareDir(cwd)
+	if err != nil {
+		return err
+	}
+
+	infos, err := git.ListWorktreesWithInfo(bareDir, true)
+	if err != nil {
+		return fmt.Errorf("failed to list worktrees: %w", err)
+	}
+
+	snapshots, err := listSnapshots(bareDir)
+	if err != nil {
+		return err
+	}
+	snap, err := resolveSnapshot(bareDir, cwd, infos, snapshots, id)
+	if err != nil {
+		return err
+	}
+
+	var target string
+	switch {
+	case newBranch != "":
+		if target, err = createRestoreWorktree(bareDir, snap, newBranch); err != nil {
+			return err
+		}
+	case into != "":
+		info := git.FindWorktree(infos, into)
+		if info == nil {
+			return fmt.Errorf("worktree not found: %s", into)
+		}
+		target = info.Path
+	default:
+		for _, info := range infos {
+			if snapshotWorktree(bareDir, info.Path) == snap.worktree {
+				target = info.Path
+				break
+			}
+		}
+		if target == "" {
+			return fmt.Errorf("worktree %s no longer exists; restore with --new <branch> or --into <worktree>", snap.worktree)
+		}
+	}
+
+	if err := git.ApplyStash(target, snap.Commit); err != nil {
+		return fmt.Errorf("failed to restore snapshot %s: %w", snap.id(), err)
+	}
+
+	logger.Success("Restored snapshot %s into %s", snap.id(), styles.RenderPath(target))
+	return nil
+}
+
+// resolveSnapshot finds the snapshot id refers to: a <worktree>/<n> name, a
+// number of the current worktree, or a worktree for its latest snapshot. An
+// empty id is the latest snapshot of the current worktree.
+func resolveSnapshot(bareDir, cwd string, infos []*git.WorktreeInfo, snapshots []snapshot, id string) (*snapshot, error) {
+	for i := range snapshots {
+		if snapshots[i].id() == id {
+			return &snapshots[i], nil
+		}
+	}
+
+	worktree := id
+	number := 0
+	if n, err := strconv.Atoi(id); err == nil || id == "" {
+		number = n
+		worktree = ""
+		for _, info := range infos {
+			if cwdInWorktree(cwd, info.Path) {
+				worktree = snapshotWorktree(bareDir, info.Path)
+				break
+			}
+		}
+		if worktree == "" {
+			return nil, fmt.Errorf("not inside a worktree; name the snapshot as <worktree>/<n>")
+		}
+	} else if info := git.FindWorktree(infos, id); info != nil {
+		worktree = snapshotWorktree(bareDir, info.Path)
+	}
+
+	var found *snapshot
+	for i := range snapshots {
+		s := &snapshots[i]
+		if s.worktree != worktree || (number != 0 && s.number != number) {
+			continue
+		}
+		found = s // Sorted oldest first, so the last match is the latest
+	}
+	if found == nil {
+		if id == "" {
+			return nil, fmt.Errorf("no snapshots of %s", worktree)
+		}
+		return nil, fmt.Errorf("snapshot not found: %s", id)
+	}
+	return found, nil
+}
+
+// createRestoreWorktree adds a worktree for a new branch at the commit snap
+// was taken on
+func createRestoreWorktree(bareDir string, snap *snapshot, branch string) (string, error) {
+	workspaceRoot := filepath.Dir(bareDir)
+	lockFile := filepath.Join(workspaceRoot, ".grove-worktree.lock")
+	lockHandle, err := workspace.AcquireWorkspaceLock(lockFile)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = lockHandle.Close()
+		_ = os.Remove(lockFile)
+	}()
+
+	exists, err := git.BranchExists(bareDir, branch)
+	if err != nil {
+		return "", fmt.Errorf("failed to check branch: %w", err)
+	}
+	if exists {
+		return "", fmt.Errorf("branch %q already exists", branch)
+	}
+
+	path, err := newWorktreePath(bareDir, "", branch)
+	if err != nil {
+		return "", err
+	}
+	if fs.PathExists(path) {
+		return "", fmt.Errorf("directory already exists: %s", path)
+	}
+
+	base, err := git.RevParse(bareDir, snap.Commit+"^1")
+	if err != nil {
+		return "", err
+	}
+	if err := git.CreateWorktreeWithNewBranchFrom(bareDir, path, branch, base, true); err != nil {
+		return "", git.HintGitTooOld(fmt.Errorf("failed to create worktree: %w", err))
+	}
+
+	logger.Emit(logger.Event{Type: logger.EventWorktreeCreated, Worktree: path, Branch: branch})
+	logger.Success("Created worktree at %s", styles.RenderPath(path))
+	return path, nil
+}
@@ -0,0 +1,398 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/sqve/grove/internal/config"
+	"github.com/sqve/grove/internal/formatter"
+	"github.com/sqve/grove/internal/git"
+	"github.com/sqve/grove/internal/logger"
+	"github.com/sqve/grove/internal/styles"
+	"github.com/sqve/grove/internal/workspace"
+)
+
+// snapshot is a saved worktree state, named <worktree>/<n>
+type snapshot struct {
+	git.SnapshotRef
+	worktree string // Worktree directory relative to the workspace
+	number   int
+}
+
+func (s snapshot) id() string {
+	return fmt.Sprintf("%s/%d", s.worktree, s.number)
+}
+
+// NewSnapshotCmd creates the snapshot command
+func NewSnapshotCmd() *cobra.Command {
+	var message string
+	var include []string
+
+	cmd := &cobra.Command{
+		Use:   "snapshot [worktree]",
+		Short: "Save the uncommitted state of a worktree",
+		Long: `Save the index, working tree and untracked files of a worktree, without
+touching it or the stash list that all worktrees share.
+
+Ignored files matching the preserve patterns, such as .env, are saved too;
+--include adds more patterns. Snapshots are commits under
+refs/grove/snapshots/<worktree>/<n> in the workspace repository. Bring one
+back with 'grove restore'.
+
+Examples:
+  grove snapshot                         # Snapshot the current worktree
+  grove snapshot -m "before rebase"      # With a message
+  grove snapshot feat-auth               # Snapshot another worktree
+  grove snapshot --include "*.sqlite"    # Also save matching ignored files
+  grove snapshot list                    # List snapshots
+  grove snapshot drop feat-auth/1        # Delete a snapshot`,
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeSnapshotArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			target := ""
+			if len(args) > 0 {
+				target = args[0]
+			}
+			return runSnapshot(target, message, include)
+		},
+	}
+
+	cmd.Flags().StringVarP(&message, "message", "m", "", "Describe the snapshot")
+	cmd.Flags().StringSliceVar(&include, "include", nil, "Also save ignored files matching these patterns")
+	cmd.Flags().BoolP("help", "h", false, "Help for snapshot")
+
+	_ = cmd.RegisterFlagCompletionFunc("include", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	})
+
+	cmd.AddCommand(newSnapshotListCmd())
+	cmd.AddCommand(newSnapshotDropCmd())
+
+	return cmd
+}
+
+func newSnapshotListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list [worktree]",
+		Short: "List snapshots",
+		Long: `List the snapshots of all worktrees, or of one worktree, oldest first.
+
+Examples:
+  grove snapshot list             # All snapshots
+  grove snapshot list feat-auth   # Snapshots of one worktree`,
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeSnapshotArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			target := ""
+			if len(args) > 0 {
+				target = args[0]
+			}
+			return runSnapshotList(target)
+		},
+	}
+}
+
+func newSnapshotDropCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "drop <snapshot>...",
+		Short: "Delete snapshots",
+		Long: `Delete snapshots by their <worktree>/<n> name, as shown by 'grove snapshot list'.
+
+Examples:
+  grove snapshot drop feat-auth/1
+  grove snapshot drop feat-auth/1 feat-auth/2`,
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: completeSnapshotIDs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSnapshotDrop(args)
+		},
+	}
+}
+
+func runSnapshot(target, message string, include []string) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	bareDir, err := workspace.FindBareDir(cwd)
+	if err != nil {
+		return err
+	}
+
+	info, err := findTargetWorktree(bareDir, cwd, target)
+	if err != nil {
+		return err
+	}
+
+	snap, err := createSnapshot(bareDir, info, message, include)
+	if err != nil {
+		return err
+	}
+	if snap == nil {
+		logger.Info("No changes to snapshot in %s", formatter.WorktreeLabel(info))
+		return nil
+	}
+
+	logger.Success("Saved snapshot %s", snap.id())
+	logger.ListSubItem("%s", snap.Message)
+	return nil
+}
+
+// findTargetWorktree returns the worktree named by target, or the current
+// worktree when target is empty
+func findTargetWorktree(bareDir, cwd, target string) (*git.WorktreeInfo, error) {
+	infos, err := git.ListWorktreesWithInfo(bareDir, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list worktrees: %w", err)
+	}
+
+	if target != "" {
+		info := git.FindWorktree(infos, target)
+		if info == nil {
+			return nil, fmt.Errorf("worktree not found: %s", target)
+		}
+		return info, nil
+	}
+
+	for _, info := range infos {
+		if cwdInWorktree(cwd, info.Path) {
+			return info, nil
+		}
+	}
+	return nil, fmt.Errorf("not inside a worktree; name one instead")
+}
+
+// createSnapshot saves the state of a worktree and returns the new snapshot,
+// or nil when the worktree has nothing to save
+func createSnapshot(bareDir string, info *git.WorktreeInfo, message string, include []string) (*snapshot, error) {
+	if message == "" {
+		message = defaultSnapshotMessage(info)
+	}
+
+	commit, err := git.CreateSnapshot(info.Path, message, preservedIgnoredFiles(bareDir, info.Path, include))
+	if err != nil {
+		return nil, err
+	}
+	if commit == "" {
+		return nil, nil
+	}
+
+	snapshots, err := listSnapshots(bareDir)
+	if err != nil {
+		return nil, err
+	}
+	snap := &snapshot{worktree: snapshotWorktree(bareDir, info.Path), number: 1}
+	for _, existing := range snapshots {
+		if existing.worktree == snap.worktree && existing.number >= snap.number {
+			snap.number = existing.number + 1
+		}
+	}
+	snap.Ref = git.SnapshotRefPrefix + snap.id()
+	snap.Commit = commit
+	snap.Message = message
+
+	if err := git.CreateRef(bareDir, snap.Ref, commit); err != nil {
+		return nil, fmt.Errorf("failed to save snapshot %s: %w", snap.id(), err)
+	}
+	return snap, nil
+}
+
+func defaultSnapshotMessage(info *git.WorktreeInfo) string {
+	if info.Branch == "" {
+		return "WIP on detached HEAD"
+	}
+	return "WIP on " + info.Branch
+}
+
+// preservedIgnoredFiles returns the ignored files of a worktree matching the
+// preserve patterns or include, which snapshots keep and diff compares
+func preservedIgnoredFiles(bareDir, worktreePath string, include []string) []string {
+	configWorktree := findConfigWorktree(bareDir)
+	if configWorktree == "" {
+		configWorktree = worktreePath
+	}
+
+	ignored, err := workspace.FindIgnoredFilesInWorktree(worktreePath)
+	if err != nil {
+		logger.Debug("Failed to find ignored files: %v", err)
+		return nil
+	}
+	patterns := slices.Concat(config.GetMergedPreservePatterns(configWorktree), include)
+	return workspace.MatchPreservedFiles(ignored, patterns, config.GetMergedPreserveExcludePatterns(configWorktree))
+}
+
+// snapshotWorktree names a worktree in snapshot refs by its directory
+// relative to the workspace, or its base name when it lives elsewhere
+func snapshotWorktree(bareDir, worktreePath string) string {
+	rel, err := filepath.Rel(filepath.Dir(bareDir), worktreePath)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return filepath.Base(worktreePath)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// listSnapshots returns the snapshots of the workspace by worktree, oldest
+// first
+func listSnapshots(bareDir string) ([]snapshot, error) {
+	refs, err := git.ListSnapshotRefs(bareDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshots []snapshot
+	for _, ref := range refs {
+		name := strings.TrimPrefix(ref.Ref, git.SnapshotRefPrefix)
+		i := strings.LastIndex(name, "/")
+		if i <= 0 {
+			continue
+		}
+		number, err := strconv.Atoi(name[i+1:])
+		if err != nil {
+			continue
+		}
+		snapshots = append(snapshots, snapshot{SnapshotRef: ref, worktree: name[:i], number: number})
+	}
+
+	slices.SortFunc(snapshots, func(a, b snapshot) int {
+		if a.worktree != b.worktree {
+			return strings.Compare(a.worktree, b.worktree)
+		}
+		return a.number - b.number
+	})
+	return snapshots, nil
+}
+
+func runSnapshotList(target string) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	bareDir, err := workspace.FindBareDir(cwd)
+	if err != nil {
+		return err
+	}
+
+	snapshots, err := listSnapshots(bareDir)
+	if err != nil {
+		return err
+	}
+
+	if target != "" {
+		worktree := target
+		if info, err := findTargetWorktree(bareDir, cwd, target); err == nil {
+			worktree = snapshotWorktree(bareDir, info.Path)
+		}
+		snapshots = slices.DeleteFunc(snapshots, func(s snapshot) bool { return s.worktree != worktree })
+	}
+
+	if len(snapshots) == 0 {
+		logger.Info("No snapshots.")
+		return nil
+	}
+
+	idWidth := 0
+	for _, snap := range snapshots {
+		idWidth = max(idWidth, len(snap.id()))
+	}
+	for _, snap := range snapshots {
+		fmt.Printf("%-*s  %s  %s\n", idWidth, snap.id(), snap.Time.Local().Format("2006-01-02 15:04"), snap.Message)
+	}
+	return nil
+}
+
+func runSnapshotDrop(ids []string) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	bareDir, err := workspace.FindBareDir(cwd)
+	if err != nil {
+		return err
+	}
+
+	snapshots, err := listSnapshots(bareDir)
+	if err != nil {
+		return err
+	}
+
+	var failed []string
+	for _, id := range ids {
+		i := slices.IndexFunc(snapshots, func(s snapshot) bool { return s.id() == id })
+		if i < 0 {
+			logger.Error("snapshot not found: %s", id)
+			failed = append(failed, id)
+			continue
+		}
+		if err := git.DeleteRef(bareDir, snapshots[i].Ref); err != nil {
+			logger.Error("%s: failed to drop snapshot: %v", id, err)
+			failed = append(failed, id)
+			continue
+		}
+		logger.Success("Dropped snapshot %s", styles.Render(&styles.Worktree, id))
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("failed: %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+func completeSnapshotArgs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) != 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	bareDir, err := workspace.FindBareDir(cwd)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	infos, err := git.ListWorktreesWithInfo(bareDir, true)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	var completions []string
+	for _, info := range infos {
+		completions = append(completions, git.WorktreeName(infos, info))
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+func completeSnapshotIDs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	bareDir, err := workspace.FindBareDir(cwd)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	snapshots, err := listSnapshots(bareDir)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	var completions []string
+	for _, snap := range snapshots {
+		if !slices.Contains(args, snap.id()) {
+			completions = append(completions, fmt.Sprintf("%s\t%s", snap.id(), snap.Message))
+		}
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveKeepOrder
+}
@@ -0,0 +1,128 @@
+package commands
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/sqve/grove/internal/git"
+	"github.com/sqve/grove/internal/testutil"
+	"github.com/sqve/grove/internal/workspace"
+)
+
+func TestNewSnapshotCmd(t *testing.T) {
+	cmd := NewSnapshotCmd()
+
+	if cmd.Use != "snapshot [worktree]" {
+		t.Errorf("expected Use 'snapshot [worktree]', got %q", cmd.Use)
+	}
+	if cmd.Flags().ShorthandLookup("m") == nil {
+		t.Error("expected -m flag")
+	}
+	if cmd.Flags().Lookup("include") == nil {
+		t.Error("expected --include flag")
+	}
+
+	subcommands := make(map[string]bool)
+	for _, sub := range cmd.Commands() {
+		subcommands[sub.Name()] = true
+	}
+	for _, name := range []string{"list", "drop"} {
+		if !subcommands[name] {
+			t.Errorf("expected %s subcommand", name)
+		}
+	}
+}
+
+func TestNewRestoreCmd(t *testing.T) {
+	cmd := NewRestoreCmd()
+
+	if err := cmd.Args(cmd, []string{"a", "b"}); err == nil {
+		t.Error("expected error for two snapshots")
+	}
+
+	_ = cmd.Flags().Set("into", "main")
+	_ = cmd.Flags().Set("new", "feat/retry")
+	testutil.AssertErrorContains(t, cmd.RunE(cmd, []string{"main/1"}), "--into and --new cannot be used together")
+}
+
+func TestRunSnapshot_NotInWorkspace(t *testing.T) {
+	defer testutil.SaveCwd(t)()
+	testutil.Chdir(t, testutil.TempDir(t))
+
+	if err := runSnapshot("", "", nil); !errors.Is(err, workspace.ErrNotInWorkspace) {
+		t.Errorf("expected ErrNotInWorkspace, got %v", err)
+	}
+}
+
+func TestSnapshotWorktree(t *testing.T) {
+	root := filepath.FromSlash("/work/repo")
+	bareDir := filepath.Join(root, ".bare")
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{filepath.Join(root, "main"), "main"},
+		{filepath.Join(root, "feat", "auth"), "feat/auth"},
+		{filepath.FromSlash("/elsewhere/hotfix"), "hotfix"},
+	}
+
+	for _, tt := range tests {
+		if got := snapshotWorktree(bareDir, tt.path); got != tt.want {
+			t.Errorf("snapshotWorktree(%s) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestResolveSnapshot(t *testing.T) {
+	root := testutil.TempDir(t)
+	bareDir := filepath.Join(root, ".bare")
+	mainPath := filepath.Join(root, "main")
+	featPath := filepath.Join(root, "feat-auth")
+	infos := []*git.WorktreeInfo{
+		{Path: mainPath, Branch: "main"},
+		{Path: featPath, Branch: "feat/auth"},
+	}
+	snapshots := []snapshot{
+		{worktree: "feat-auth", number: 1},
+		{worktree: "feat-auth", number: 3},
+		{worktree: "gone", number: 1},
+		{worktree: "main", number: 2},
+	}
+
+	tests := []struct {
+		name    string
+		cwd     string
+		id      string
+		want    string
+		wantErr string
+	}{
+		{"full name", root, "feat-auth/1", "feat-auth/1", ""},
+		{"removed worktree", root, "gone/1", "gone/1", ""},
+		{"latest of current worktree", filepath.Join(featPath, "src"), "", "feat-auth/3", ""},
+		{"number in current worktree", mainPath, "2", "main/2", ""},
+		{"latest by directory", mainPath, "feat-auth", "feat-auth/3", ""},
+		{"latest by branch", mainPath, "feat/auth", "feat-auth/3", ""},
+		{"latest of removed worktree", mainPath, "gone", "gone/1", ""},
+		{"unknown number", mainPath, "7", "", "snapshot not found: 7"},
+		{"outside worktrees", root, "", "", "not inside a worktree"},
+		{"unknown", mainPath, "nope", "", "snapshot not found: nope"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveSnapshot(bareDir, tt.cwd, infos, snapshots, tt.id)
+			if tt.wantErr != "" {
+				testutil.AssertErrorContains(t, err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolveSnapshot failed: %v", err)
+			}
+			if got.id() != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, got.id())
+			}
+		})
+	}
+}
@@ -0,0 +1,371 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/sqve/grove/internal/config"
+	"github.com/sqve/grove/internal/formatter"
+	"github.com/sqve/grove/internal/git"
+	"github.com/sqve/grove/internal/logger"
+	"github.com/sqve/grove/internal/workspace"
+)
+
+// sparseProfile is a resolved [sparse.profiles.<name>] entry from .grove.toml
+type sparseProfile struct {
+	name string
+	dirs []string
+}
+
+// NewSparseCmd creates the sparse command with all subcommands
+func NewSparseCmd() *cobra.Command {
+	sparseCmd := &cobra.Command{
+		Use:   "sparse",
+		Short: "Manage sparse checkout profiles of the current worktree",
+		Long: `Check out only some directories of the current worktree.
+
+Profiles are named sets of directories in .grove.toml:
+
+  [sparse.profiles.web]
+  paths = ["apps/web", "packages/ui"]
+
+Files at the repository root are always checked out. Use 'grove add --sparse'
+to create a worktree with a profile from the start.`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			_ = cmd.Help()
+		},
+	}
+
+	setCmd := &cobra.Command{
+		Use:   "set <profile>...",
+		Short: "Check out only the directories of profiles",
+		Long: `Replace the sparse checkout of the current worktree with the directories
+of one or more profiles.
+
+Examples:
+  grove sparse set web       # Check out only the web profile
+  grove sparse set web api   # Check out web and api`,
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: completeSparseProfiles,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSparseSet(args)
+		},
+	}
+
+	addCmd := &cobra.Command{
+		Use:   "add <profile>...",
+		Short: "Add the directories of profiles",
+		Long: `Add the directories of one or more profiles to the sparse checkout of the
+current worktree.
+
+Examples:
+  grove sparse add docs      # Also check out the docs profile`,
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: completeSparseProfiles,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSparseAdd(args)
+		},
+	}
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List sparse profiles",
+		Long: `List the sparse profiles in .grove.toml. Profiles checked out in the
+current worktree are marked.
+
+Examples:
+  grove sparse list`,
+		Args: cobra.NoArgs,
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSparseList()
+		},
+	}
+
+	disableCmd := &cobra.Command{
+		Use:   "disable",
+		Short: "Check out all files again",
+		Long: `Disable sparse checkout and restore a full checkout of the current worktree.
+
+Examples:
+  grove sparse disable`,
+		Args: cobra.NoArgs,
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSparseDisable()
+		},
+	}
+
+	sparseCmd.AddCommand(setCmd, addCmd, listCmd, disableCmd)
+	return sparseCmd
+}
+
+// sparseContext returns the bare directory and the root of the worktree
+// containing the current directory
+func sparseContext() (bareDir, worktreeRoot string, err error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	bareDir, err = workspace.FindBareDir(cwd)
+	if err != nil {
+		return "", "", err
+	}
+
+	worktreeRoot, err = git.FindWorktreeRoot(cwd)
+	if err != nil {
+		return "", "", fmt.Errorf("not inside a worktree (run from a worktree directory)")
+	}
+	return bareDir, worktreeRoot, nil
+}
+
+func runSparseSet(names []string) error {
+	bareDir, worktreeRoot, err := sparseContext()
+	if err != nil {
+		return err
+	}
+
+	profiles, err := resolveSparseProfiles(bareDir, worktreeRoot, names)
+	if err != nil {
+		return err
+	}
+
+	if err := git.SparseCheckoutSet(worktreeRoot, sparseDirs(profiles)); err != nil {
+		return fmt.Errorf("failed to set sparse checkout: %w", err)
+	}
+	recordSparseProfiles(worktreeRoot, sparseNames(profiles))
+
+	logger.Success("Set sparse checkout of %s to %s", filepath.Base(worktreeRoot), strings.Join(sparseNames(profiles), ", "))
+	logSparseDirs(profiles)
+	return nil
+}
+
+func runSparseAdd(names []string) error {
+	bareDir, worktreeRoot, err := sparseContext()
+	if err != nil {
+		return err
+	}
+
+	profiles, err := resolveSparseProfiles(bareDir, worktreeRoot, names)
+	if err != nil {
+		return err
+	}
+
+	// Adding to a full checkout would check out everything anyway, so start
+	// a sparse checkout with just these profiles
+	if !git.IsSparseCheckout(worktreeRoot) {
+		return runSparseSet(names)
+	}
+
+	if err := git.SparseCheckoutAdd(worktreeRoot, sparseDirs(profiles)); err != nil {
+		return fmt.Errorf("failed to add to sparse checkout: %w", err)
+	}
+
+	active := git.GetSparseProfiles(worktreeRoot)
+	for _, name := range sparseNames(profiles) {
+		if !slices.Contains(active, name) {
+			active = append(active, name)
+		}
+	}
+	recordSparseProfiles(worktreeRoot, active)
+
+	logger.Success("Added %s to sparse checkout of %s", strings.Join(sparseNames(profiles), ", "), filepath.Base(worktreeRoot))
+	logSparseDirs(profiles)
+	return nil
+}
+
+func runSparseList() error {
+	bareDir, worktreeRoot, err := sparseContext()
+	if err != nil {
+		return err
+	}
+
+	var cfg config.FileConfig
+	if configDir := sparseConfigDir(bareDir, worktreeRoot); configDir != "" {
+		if cfg, err = config.LoadFromFile(configDir); err != nil {
+			return fmt.Errorf("failed to parse %s: %w", config.FileName, err)
+		}
+	}
+
+	names := config.SparseProfileNames(cfg)
+	if len(names) == 0 {
+		logger.Info("No sparse profiles configured in %s.", config.FileName)
+		return nil
+	}
+
+	var active []string
+	if git.IsSparseCheckout(worktreeRoot) {
+		active = git.GetSparseProfiles(worktreeRoot)
+	}
+
+	width := 0
+	for _, name := range names {
+		width = max(width, len(name))
+	}
+	for _, name := range names {
+		marker := formatter.CurrentMarker(slices.Contains(active, name))
+		fmt.Printf("%s %-*s  %s\n", marker, width, name, strings.Join(cfg.Sparse.Profiles[name].Paths, " "))
+	}
+	return nil
+}
+
+func runSparseDisable() error {
+	_, worktreeRoot, err := sparseContext()
+	if err != nil {
+		return err
+	}
+
+	if !git.IsSparseCheckout(worktreeRoot) {
+		logger.Info("%s already has a full checkout.", filepath.Base(worktreeRoot))
+		return nil
+	}
+
+	if err := git.SparseCheckoutDisable(worktreeRoot); err != nil {
+		return fmt.Errorf("failed to disable sparse checkout: %w", err)
+	}
+	recordSparseProfiles(worktreeRoot, nil)
+
+	logger.Success("Restored full checkout of %s", filepath.Base(worktreeRoot))
+	return nil
+}
+
+// sparseConfigDir returns the worktree whose .grove.toml defines sparse
+// profiles: dir itself if it has one, otherwise the workspace's config worktree
+func sparseConfigDir(bareDir, dir string) string {
+	if dir != "" && config.FileConfigExists(dir) {
+		return dir
+	}
+	return findConfigWorktree(bareDir)
+}
+
+// resolveSparseProfiles loads the named profiles from .grove.toml
+func resolveSparseProfiles(bareDir, dir string, names []string) ([]*sparseProfile, error) {
+	configDir := sparseConfigDir(bareDir, dir)
+	if configDir == "" {
+		return nil, fmt.Errorf("unknown sparse profile %q: no %s found", names[0], config.FileName)
+	}
+
+	var profiles []*sparseProfile
+	for _, name := range names {
+		dirs, err := config.GetSparseProfile(configDir, name)
+		if err != nil {
+			return nil, err
+		}
+		profiles = append(profiles, &sparseProfile{name: name, dirs: dirs})
+	}
+	return profiles, nil
+}
+
+func sparseNames(profiles []*sparseProfile) []string {
+	names := make([]string, 0, len(profiles))
+	for _, profile := range profiles {
+		names = append(names, profile.name)
+	}
+	return names
+}
+
+func sparseDirs(profiles []*sparseProfile) []string {
+	var dirs []string
+	for _, profile := range profiles {
+		for _, dir := range profile.dirs {
+			if !slices.Contains(dirs, dir) {
+				dirs = append(dirs, dir)
+			}
+		}
+	}
+	return dirs
+}
+
+func logSparseDirs(profiles []*sparseProfile) {
+	for _, dir := range sparseDirs(profiles) {
+		logger.ListSubItem("%s", dir)
+	}
+}
+
+func logSparseProfile(profile *sparseProfile) {
+	if profile != nil {
+		logger.ListSubItem("sparse checkout: %s", profile.name)
+	}
+}
+
+// recordSparseProfiles remembers which profiles a worktree checks out, for
+// display in list and status. Failing to record them is only a warning.
+func recordSparseProfiles(worktreePath string, names []string) {
+	if err := git.SetSparseProfiles(worktreePath, names); err != nil {
+		logger.Warning("Failed to record sparse profile: %v", err)
+	}
+}
+
+// createSparseWorktree creates a worktree that checks out only the
+// directories of profile. With newBranch, branch is first created from base
+// (HEAD if empty) and deleted again if the worktree cannot be created.
+func createSparseWorktree(bareDir, worktreePath, branch, base string, newBranch bool, profile *sparseProfile) error {
+	if newBranch {
+		if err := git.CreateBranch(bareDir, branch, base); err != nil {
+			return fmt.Errorf("failed to create branch: %w", err)
+		}
+	}
+
+	if err := git.CreateSparseWorktree(bareDir, worktreePath, branch, false, profile.dirs); err != nil {
+		if newBranch {
+			_ = git.DeleteBranch(bareDir, branch, true)
+		}
+		return git.HintGitTooOld(fmt.Errorf("failed to create worktree: %w", err))
+	}
+
+	recordSparseProfiles(worktreePath, []string{profile.name})
+	return nil
+}
+
+// sparseLabel describes the sparse checkout of a worktree for list and
+// status: its profiles, "custom" if it was not set up from a profile, or
+// empty for a full checkout
+func sparseLabel(worktreePath string) string {
+	if !git.IsSparseCheckout(worktreePath) {
+		return ""
+	}
+	if profiles := git.GetSparseProfiles(worktreePath); len(profiles) > 0 {
+		return strings.Join(profiles, ", ")
+	}
+	return "custom"
+}
+
+func completeSparseProfiles(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	bareDir, err := workspace.FindBareDir(cwd)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	dir, _ := git.FindWorktreeRoot(cwd)
+	configDir := sparseConfigDir(bareDir, dir)
+	if configDir == "" {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	cfg, err := config.LoadFromFile(configDir)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	var completions []string
+	for _, name := range config.SparseProfileNames(cfg) {
+		if !slices.Contains(args, name) {
+			completions = append(completions, name+"\t"+strings.Join(cfg.Sparse.Profiles[name].Paths, " "))
+		}
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
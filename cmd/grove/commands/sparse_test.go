@@ -0,0 +1,84 @@
+package commands
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/sqve/grove/internal/config"
+	"github.com/sqve/grove/internal/testutil"
+	"github.com/sqve/grove/internal/workspace"
+)
+
+func TestNewSparseCmd(t *testing.T) {
+	cmd := NewSparseCmd()
+
+	if cmd.Use != "sparse" {
+		t.Errorf("expected Use 'sparse', got %q", cmd.Use)
+	}
+
+	var names []string
+	for _, sub := range cmd.Commands() {
+		names = append(names, sub.Name())
+	}
+	for _, want := range []string{"set", "add", "list", "disable"} {
+		if !slices.Contains(names, want) {
+			t.Errorf("expected subcommand %q, got %v", want, names)
+		}
+	}
+}
+
+func TestRunSparseSet_NotInWorkspace(t *testing.T) {
+	defer testutil.SaveCwd(t)()
+	testutil.Chdir(t, testutil.TempDir(t))
+
+	err := runSparseSet([]string{"web"})
+	if !errors.Is(err, workspace.ErrNotInWorkspace) {
+		t.Errorf("expected ErrNotInWorkspace, got %v", err)
+	}
+}
+
+func TestResolveSparseProfiles(t *testing.T) {
+	dir := testutil.TempDir(t)
+	tomlContent := `[sparse.profiles.web]
+paths = ["apps/web", "packages/ui"]
+
+[sparse.profiles.admin]
+paths = ["apps/admin", "packages/ui"]
+`
+	if err := os.WriteFile(filepath.Join(dir, config.FileName), []byte(tomlContent), 0o644); err != nil { //nolint:gosec
+		t.Fatal(err)
+	}
+
+	t.Run("merges directories of all profiles", func(t *testing.T) {
+		profiles, err := resolveSparseProfiles("", dir, []string{"web", "admin"})
+		if err != nil {
+			t.Fatalf("resolveSparseProfiles failed: %v", err)
+		}
+		if names := sparseNames(profiles); !slices.Equal(names, []string{"web", "admin"}) {
+			t.Errorf("sparseNames = %v", names)
+		}
+		want := []string{"apps/web", "packages/ui", "apps/admin"}
+		if dirs := sparseDirs(profiles); !slices.Equal(dirs, want) {
+			t.Errorf("sparseDirs = %v, want %v", dirs, want)
+		}
+	})
+
+	t.Run("fails for unknown profile", func(t *testing.T) {
+		_, err := resolveSparseProfiles("", dir, []string{"web", "docs"})
+		testutil.AssertErrorContains(t, err, `unknown sparse profile "docs"`)
+	})
+
+	t.Run("fails without config", func(t *testing.T) {
+		_, err := resolveSparseProfiles(filepath.Join(testutil.TempDir(t), ".bare"), testutil.TempDir(t), []string{"web"})
+		testutil.AssertErrorContains(t, err, "no .grove.toml found")
+	})
+}
+
+func TestSparseLabel_FullCheckout(t *testing.T) {
+	if label := sparseLabel(testutil.TempDir(t)); label != "" {
+		t.Errorf("expected empty label outside sparse checkout, got %q", label)
+	}
+}
@@ -0,0 +1,391 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/sqve/grove/internal/formatter"
+	"github.com/sqve/grove/internal/fs"
+	"github.com/sqve/grove/internal/git"
+	"github.com/sqve/grove/internal/logger"
+	"github.com/sqve/grove/internal/stack"
+	"github.com/sqve/grove/internal/styles"
+	"github.com/sqve/grove/internal/workspace"
+)
+
+// restackResult is the outcome of restacking a single branch
+type restackResult struct {
+	branch   string
+	parent   string
+	info     *git.WorktreeInfo // Nil if the branch has no worktree
+	upToDate bool              // Already based on the current parent
+	reason   string            // Why the branch was skipped
+	err      error             // Set when the rebase was attempted and failed
+}
+
+// NewStackCmd creates the stack command with all subcommands
+func NewStackCmd() *cobra.Command {
+	stackCmd := &cobra.Command{
+		Use:   "stack",
+		Short: "Manage stacked branches",
+		Long: `Work with branches stacked on top of each other, such as a series of
+dependent pull requests.
+
+Create a stacked branch with 'grove add --stack', which records the branch it
+was created from as its parent in the branch config.`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			_ = cmd.Help()
+		},
+	}
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "Show stacked branches as a tree",
+		Long: `Show stacked branches as a tree under the branches they are based on, with
+the worktree of each branch.
+
+Examples:
+  grove stack list`,
+		Args: cobra.NoArgs,
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runStackList()
+		},
+	}
+
+	var continueRestack, abortRestack bool
+	restackCmd := &cobra.Command{
+		Use:   "restack",
+		Short: "Rebase stacked worktrees onto their updated parents",
+		Long: `Rebase each stacked branch onto its parent, parents first, so the whole stack
+picks up changes to the branches below it. Only the commits of each branch
+are replayed, so rewritten parents do not duplicate commits.
+
+Branches without a worktree, and dirty, locked and detached worktrees, are
+skipped along with the branches stacked on them. When a rebase stops on
+conflicts, restack stops and leaves the rebase in progress. Resolve the
+conflicts, run 'git rebase --continue' in that worktree, then run
+'grove stack restack --continue'.
+
+Examples:
+  grove stack restack             # Restack all stacks
+  grove stack restack --continue  # Resume after resolving conflicts
+  grove stack restack --abort     # Abort the stopped rebase`,
+		Args: cobra.NoArgs,
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runStackRestack(continueRestack, abortRestack)
+		},
+	}
+	restackCmd.Flags().BoolVar(&continueRestack, "continue", false, "Resume a restack that stopped on conflicts")
+	restackCmd.Flags().BoolVar(&abortRestack, "abort", false, "Abort the rebase a restack stopped on")
+	restackCmd.MarkFlagsMutuallyExclusive("continue", "abort")
+
+	stackCmd.AddCommand(listCmd, restackCmd)
+	return stackCmd
+}
+
+func runStackList() error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	bareDir, err := workspace.FindBareDir(cwd)
+	if err != nil {
+		return err
+	}
+
+	parents, err := git.ListBranchParents(bareDir)
+	if err != nil {
+		return fmt.Errorf("failed to read stacked branches: %w", err)
+	}
+	if len(parents) == 0 {
+		logger.Info("No stacked branches. Create one with 'grove add --stack <branch>'.")
+		return nil
+	}
+
+	infos, err := git.ListWorktreesWithInfo(bareDir, true)
+	if err != nil {
+		return fmt.Errorf("failed to list worktrees: %w", err)
+	}
+	worktrees := make(map[string]*git.WorktreeInfo)
+	for _, info := range infos {
+		if !info.Detached {
+			worktrees[info.Branch] = info
+		}
+	}
+
+	children := stack.Children(parents)
+	var printNode func(branch string, ancestorsLast []bool, isRoot, last bool)
+	printNode = func(branch string, ancestorsLast []bool, isRoot, last bool) {
+		prefix := ""
+		if !isRoot {
+			prefix = formatter.StackTreePrefix(ancestorsLast, last)
+		}
+
+		info := worktrees[branch]
+		isCurrent := info != nil && (fs.PathsEqual(cwd, info.Path) || fs.PathHasPrefix(cwd, info.Path))
+		note := "no worktree"
+		if info != nil {
+			note = git.WorktreeName(infos, info)
+		}
+		fmt.Printf("%s %s%s %s\n", formatter.CurrentMarker(isCurrent), prefix,
+			styles.Render(&styles.Worktree, branch), styles.Render(&styles.Dimmed, "("+note+")"))
+
+		if !isRoot {
+			ancestorsLast = append(ancestorsLast, last)
+		}
+		for i, child := range children[branch] {
+			printNode(child, ancestorsLast, false, i == len(children[branch])-1)
+		}
+	}
+	for _, root := range stack.Roots(parents) {
+		printNode(root, nil, true, true)
+	}
+	return nil
+}
+
+func runStackRestack(continueRestack, abortRestack bool) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	bareDir, err := workspace.FindBareDir(cwd)
+	if err != nil {
+		return err
+	}
+
+	lockFile := filepath.Join(filepath.Dir(bareDir), ".grove-worktree.lock")
+	lockHandle, err := workspace.AcquireWorkspaceLock(lockFile)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = lockHandle.Close()
+		_ = os.Remove(lockFile)
+	}()
+
+	state, err := stack.LoadState(bareDir)
+	if err != nil {
+		return err
+	}
+
+	if abortRestack {
+		if state == nil {
+			return errors.New("no restack in progress")
+		}
+		if ongoingOperation(state.Worktree) == "rebasing" {
+			if err := git.AbortRebase(state.Worktree); err != nil {
+				return fmt.Errorf("failed to abort rebase in %s: %w", state.Worktree, err)
+			}
+		}
+		if err := stack.ClearState(bareDir); err != nil {
+			return err
+		}
+		logger.Success("Aborted restack of %s", state.Branch)
+		return nil
+	}
+
+	parents, err := git.ListBranchParents(bareDir)
+	if err != nil {
+		return fmt.Errorf("failed to read stacked branches: %w", err)
+	}
+
+	var results []restackResult
+	var queue []string
+	skipped := make(map[string]bool)
+
+	switch {
+	case continueRestack:
+		if state == nil {
+			return errors.New("no restack in progress")
+		}
+		if ongoingOperation(state.Worktree) != "" {
+			return fmt.Errorf("%s is still rebasing\n\nHint: Resolve the conflicts and run 'git rebase --continue' in %s first", state.Branch, state.Worktree)
+		}
+
+		result := restackResult{branch: state.Branch, parent: state.Parent}
+		if info, err := git.GetWorktreeInfo(state.Worktree); err == nil {
+			result.info = info
+		}
+		if base, err := git.MergeBase(bareDir, state.Onto, state.Branch); err != nil || base != state.Onto {
+			// The rebase was aborted by hand, so the branches above it are
+			// still based on the old commits
+			result.reason = "rebase aborted"
+			skipped[state.Branch] = true
+		} else if err := git.SetBranchParent(bareDir, state.Branch, state.Parent, state.Onto); err != nil {
+			logger.Warning("Failed to record stack base of %s: %v", state.Branch, err)
+		}
+		results = append(results, result)
+		queue = state.Pending
+
+		if err := stack.ClearState(bareDir); err != nil {
+			return err
+		}
+	case state != nil:
+		return fmt.Errorf("restack stopped at %s\n\nHint: Resolve the conflicts in %s, run 'git rebase --continue', then 'grove stack restack --continue', or use 'grove stack restack --abort'", state.Branch, state.Worktree)
+	default:
+		if len(parents) == 0 {
+			logger.Info("No stacked branches. Create one with 'grove add --stack <branch>'.")
+			return nil
+		}
+		queue = stack.Order(parents)
+	}
+
+	infos, err := git.ListWorktreesWithInfo(bareDir, true)
+	if err != nil {
+		return fmt.Errorf("failed to list worktrees: %w", err)
+	}
+	worktrees := make(map[string]*git.WorktreeInfo)
+	for _, info := range infos {
+		if !info.Detached {
+			worktrees[info.Branch] = info
+		}
+	}
+
+	spin := logger.StartSpinner("Restacking branches...")
+	for i, branch := range queue {
+		parent, ok := parents[branch]
+		if !ok {
+			continue // No longer stacked
+		}
+
+		result := restackBranch(bareDir, branch, parent, worktrees[branch], skipped[parent])
+		if result.reason != "" || result.err != nil {
+			skipped[branch] = true
+		}
+
+		if result.err != nil && result.info != nil && ongoingOperation(result.info.Path) == "rebasing" {
+			spin.Stop()
+			outputRestackResults(results)
+
+			onto, _ := git.RevParse(bareDir, parent)
+			if err := stack.SaveState(bareDir, &stack.State{
+				Branch:   branch,
+				Worktree: result.info.Path,
+				Parent:   parent,
+				Onto:     onto,
+				Pending:  queue[i+1:],
+			}); err != nil {
+				logger.Warning("Failed to save restack state: %v", err)
+			}
+			return fmt.Errorf("rebase of %s onto %s stopped on conflicts\n\nHint: Resolve them in %s and run 'git rebase --continue', then 'grove stack restack --continue'", branch, parent, result.info.Path)
+		}
+
+		results = append(results, result)
+	}
+	spin.Stop()
+
+	return outputRestackResults(results)
+}
+
+// restackBranch rebases the worktree of branch onto the current commit of
+// parent. parentSkipped tells whether parent itself could not be restacked.
+func restackBranch(bareDir, branch, parent string, info *git.WorktreeInfo, parentSkipped bool) restackResult {
+	result := restackResult{branch: branch, parent: parent, info: info}
+
+	switch {
+	case parentSkipped:
+		result.reason = "parent skipped"
+		return result
+	case info == nil:
+		result.reason = "no worktree"
+		return result
+	case info.Locked:
+		result.reason = "locked"
+		return result
+	}
+	if operation := ongoingOperation(info.Path); operation != "" {
+		result.reason = operation + " in progress"
+		return result
+	}
+	if dirty, _, err := git.CheckGitChanges(info.Path); err != nil || dirty {
+		result.reason = "dirty"
+		return result
+	}
+
+	onto, err := git.RevParse(bareDir, parent)
+	if err != nil {
+		result.reason = fmt.Sprintf("parent %s not found", parent)
+		return result
+	}
+
+	// The recorded base is where the branch was created or last restacked.
+	// Fall back to the merge base when it is missing or no longer applies,
+	// for example after the branch was rebased by hand.
+	_, base := git.GetBranchParent(bareDir, branch)
+	if mergeBase, err := git.MergeBase(bareDir, base, branch); base == "" || err != nil || mergeBase != base {
+		if base, err = git.MergeBase(bareDir, parent, branch); err != nil {
+			result.err = err
+			return result
+		}
+	}
+
+	result.upToDate = base == onto
+	if !result.upToDate {
+		if result.err = git.RebaseOnto(info.Path, onto, base); result.err != nil {
+			return result
+		}
+	}
+	if err := git.SetBranchParent(bareDir, branch, parent, onto); err != nil {
+		logger.Warning("Failed to record stack base of %s: %v", branch, err)
+	}
+	return result
+}
+
+// reparentStackedBranches stacks the branches that were stacked on a deleted
+// branch on its parent instead, so the next restack moves them down, for
+// example after the bottom of a stack was merged. Does nothing if the deleted
+// branch was not stacked itself.
+func reparentStackedBranches(bareDir, deleted, parent string) {
+	if parent == "" {
+		return
+	}
+	if err := git.RenameBranchParent(bareDir, deleted, parent); err != nil {
+		logger.Warning("Failed to update branches stacked on %s: %v", deleted, err)
+	}
+}
+
+func outputRestackResults(results []restackResult) error {
+	var restacked, skipped, failed []string
+
+	for _, result := range results {
+		label := result.branch
+		if result.info != nil {
+			label = formatter.WorktreeLabel(result.info)
+		}
+		switch {
+		case result.err != nil:
+			failed = append(failed, fmt.Sprintf("%s (%v)", label, result.err))
+		case result.upToDate:
+		case result.reason != "":
+			skipped = append(skipped, fmt.Sprintf("%s (%s)", label, result.reason))
+		default:
+			restacked = append(restacked, fmt.Sprintf("%s (onto %s)", label, result.parent))
+		}
+	}
+
+	if len(restacked) == 0 && len(skipped) == 0 && len(failed) == 0 {
+		logger.Success("All stacks up to date")
+		return nil
+	}
+
+	printSyncGroup(logger.Success, "Restacked", restacked)
+	printSyncGroup(logger.Warning, "Skipped", skipped)
+	printSyncGroup(logger.Error, "Failed to restack", failed)
+
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to restack %d worktree(s)", len(failed))
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package commands
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/sqve/grove/internal/git"
+	"github.com/sqve/grove/internal/testutil"
+	"github.com/sqve/grove/internal/workspace"
+)
+
+func TestNewStackCmd(t *testing.T) {
+	cmd := NewStackCmd()
+
+	if cmd.Use != "stack" {
+		t.Errorf("expected Use 'stack', got %q", cmd.Use)
+	}
+
+	var names []string
+	for _, sub := range cmd.Commands() {
+		names = append(names, sub.Name())
+	}
+	for _, want := range []string{"list", "restack"} {
+		if !slices.Contains(names, want) {
+			t.Errorf("expected subcommand %q, got %v", want, names)
+		}
+	}
+}
+
+func TestRunStackRestack_NotInWorkspace(t *testing.T) {
+	defer testutil.SaveCwd(t)()
+	testutil.Chdir(t, testutil.TempDir(t))
+
+	err := runStackRestack(false, false)
+	if !errors.Is(err, workspace.ErrNotInWorkspace) {
+		t.Errorf("expected ErrNotInWorkspace, got %v", err)
+	}
+}
+
+func TestRestackBranch_Skips(t *testing.T) {
+	tests := []struct {
+		name          string
+		info          *git.WorktreeInfo
+		parentSkipped bool
+		want          string
+	}{
+		{"parent skipped", &git.WorktreeInfo{Path: "/ws/feat-b", Branch: "feat/b"}, true, "parent skipped"},
+		{"no worktree", nil, false, "no worktree"},
+		{"locked", &git.WorktreeInfo{Path: "/ws/feat-b", Branch: "feat/b", Locked: true}, false, "locked"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := restackBranch("/ws/.bare", "feat/b", "feat/a", tt.info, tt.parentSkipped)
+			if result.reason != tt.want {
+				t.Errorf("restackBranch() reason = %q, want %q", result.reason, tt.want)
+			}
+			if result.err != nil {
+				t.Errorf("expected no error, got %v", result.err)
+			}
+		})
+	}
+}
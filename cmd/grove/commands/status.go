@@ -33,6 +33,7 @@ type StatusInfo struct {
 	Detached   bool   `json:"detached"`
 	Gone       bool   `json:"gone"`
 	NoUpstream bool   `json:"no_upstream"`
+	Sparse     string `json:"sparse,omitempty"`
 }
 
 // NewStatusCmd creates the status command
@@ -72,7 +73,8 @@ func runStatus(verbose, jsonOutput bool) error {
 	}
 
 	// Verify we're in a grove workspace
-	if _, err := workspace.FindBareDir(cwd); err != nil {
+	bareDir, err := workspace.FindBareDir(cwd)
+	if err != nil {
 		return err
 	}
 
@@ -82,9 +84,11 @@ func runStatus(verbose, jsonOutput bool) error {
 		return fmt.Errorf("not inside a worktree (run from a worktree directory)")
 	}
 
-	info, err := gatherStatusInfo(worktreeRoot)
-	if err != nil {
-		return err
+	info, ok := daemonStatus(bareDir, worktreeRoot)
+	if !ok {
+		if info, err = gatherStatusInfo(worktreeRoot); err != nil {
+			return err
+		}
 	}
 
 	if jsonOutput {
@@ -161,6 +165,8 @@ func gatherStatusInfo(worktreePath string) (*StatusInfo, error) {
 	info.Locked = git.IsWorktreeLocked(worktreePath)
 	info.LockReason = git.GetWorktreeLockReason(worktreePath)
 
+	info.Sparse = sparseLabel(worktreePath)
+
 	return info, nil
 }
 
@@ -184,11 +190,21 @@ func outputStatusDefault(info *StatusInfo) error {
 		Gone:       info.Gone,
 		NoUpstream: info.NoUpstream,
 		Detached:   info.Detached,
+		Sparse:     info.Sparse,
 	}
 
 	// Use consistent single-line format (same as list)
 	fmt.Println(formatter.WorktreeRow(wtInfo, true, 0, 0))
 
+	// A sparse checkout explains missing files, so always mention it
+	if info.Sparse != "" {
+		prefix := formatter.SubItemPrefix()
+		if !config.IsPlain() {
+			prefix = styles.Render(&styles.Dimmed, prefix)
+		}
+		fmt.Printf("    %s sparse: %s\n", prefix, info.Sparse)
+	}
+
 	return nil
 }
 
@@ -206,6 +222,7 @@ func outputStatusVerbose(info *StatusInfo) error {
 		Gone:       info.Gone,
 		NoUpstream: info.NoUpstream,
 		Detached:   info.Detached,
+		Sparse:     info.Sparse,
 	}
 
 	// Print the worktree row (same format as default)
@@ -11,6 +11,8 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/sqve/grove/internal/fs"
 	"github.com/sqve/grove/internal/git"
+	"github.com/sqve/grove/internal/hooks"
+	"github.com/sqve/grove/internal/picker"
 	"github.com/sqve/grove/internal/workspace"
 )
 
@@ -35,27 +37,56 @@ const (
 )
 
 func NewSwitchCmd() *cobra.Command {
+	var allRepos bool
+
 	cmd := &cobra.Command{
-		Use:   "switch <worktree>",
+		Use:   "switch [worktree]",
 		Short: "Switch to a worktree",
 		Long: `Switch to a worktree by name or branch.
 
 Requires shell integration:
   eval "$(grove switch shell-init)"
 
-Accepts worktree name (directory) or branch name.
+Accepts worktree name (directory) or branch name. Without an argument in
+an interactive terminal, opens a picker to choose the worktree.
+
+In a grove.workspace.toml project, a worktree of the current repo is
+preferred. From outside the repos, with --all-repos, or when the current repo
+has no such worktree, switches to the directory grouping the worktrees of the
+branch across repos.
+
+With --tmux (or grove.tmux), focuses a tmux window named after the worktree
+directory instead, creating it if needed. Set grove.tmuxMode to "session" to
+use one tmux session per worktree.
 
 Examples:
-  grove switch main        # Switch to main worktree
-  grove switch feat-auth   # Switch by directory name
-  grove switch feat/auth   # Switch by branch name`,
-		Args:              cobra.ExactArgs(1),
+  grove switch                        # Pick a worktree interactively
+  grove switch main                   # Switch to main worktree
+  grove switch feat-auth              # Switch by directory name
+  grove switch feat/auth              # Switch by branch name
+  grove switch --tmux api             # Focus or create the tmux window for api
+  grove switch --all-repos feat/auth  # Group directory of feat/auth across repos`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 && pickerAvailable() {
+				return nil
+			}
+			return cobra.ExactArgs(1)(cmd, args)
+		},
 		ValidArgsFunction: completeSwitchArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runSwitch(args[0])
+			if len(args) == 0 {
+				picked, err := pickWorktrees(picker.Options{Prompt: "Switch to"}, skipCurrentWorktree)
+				if err != nil {
+					return err
+				}
+				args = picked
+			}
+			return runSwitch(args[0], tmuxEnabled(cmd), allRepos)
 		},
 	}
 
+	cmd.Flags().Bool("tmux", false, "Focus a tmux window instead of changing directory")
+	cmd.Flags().BoolVar(&allRepos, "all-repos", false, "Switch to the group directory of the branch across project repos")
 	cmd.Flags().BoolP("help", "h", false, "Help for switch")
 
 	cmd.AddCommand(newShellInitCmd())
@@ -135,7 +166,7 @@ func printShellIntegration(shell string) error {
 	return nil
 }
 
-func runSwitch(target string) error {
+func runSwitch(target string, useTmux, allRepos bool) error {
 	target = strings.TrimSpace(target)
 
 	cwd, err := os.Getwd()
@@ -143,35 +174,51 @@ func runSwitch(target string) error {
 		return fmt.Errorf("failed to get current directory: %w", err)
 	}
 
-	bareDir, err := workspace.FindBareDir(cwd)
-	if err != nil {
-		return err
+	// Inside a workspace, its own worktrees come first
+	bareDir, bareErr := workspace.FindBareDir(cwd)
+	if bareErr == nil && !allRepos {
+		infos, err := git.ListWorktreesWithInfo(bareDir, true)
+		if err != nil {
+			return fmt.Errorf("failed to list worktrees: %w", err)
+		}
+		if info := git.FindWorktree(infos, target); info != nil {
+			runSwitchHooks(bareDir, info)
+			return switchToWorktree(info.Path, useTmux)
+		}
 	}
 
-	infos, err := git.ListWorktreesWithInfo(bareDir, true)
+	// In a project, land in the directory grouping the branch's worktrees
+	// of every repository
+	groupDir, members, err := findProjectGroup(cwd, target)
 	if err != nil {
-		return fmt.Errorf("failed to list worktrees: %w", err)
+		return err
 	}
-
-	// First try to match by worktree name (directory basename)
-	for _, info := range infos {
-		if filepath.Base(info.Path) == target {
-			fmt.Println(info.Path)
-			return nil
+	if groupDir != "" {
+		for _, member := range members {
+			runSwitchHooks(member.repo.BareDir(), member.info)
 		}
+		return switchToWorktree(groupDir, useTmux)
 	}
 
-	// Fall back to matching by branch name (backwards compatibility)
-	for _, info := range infos {
-		if info.Branch == target {
-			fmt.Println(info.Path)
-			return nil
-		}
+	if bareErr != nil && !allRepos {
+		return bareErr
 	}
-
 	return fmt.Errorf("%w: %s", ErrWorktreeNotFound, target)
 }
 
+// runSwitchHooks runs the post-switch hooks of the workspace in a worktree.
+// Hook output goes to stderr; stdout carries only the path for the shell
+// wrapper.
+func runSwitchHooks(bareDir string, info *git.WorktreeInfo) {
+	runPostHooks(bareDir, loadHooks(bareDir, hooks.EventPostSwitch, info.Path), info.Path, &hooks.Context{
+		Event:     hooks.EventPostSwitch,
+		Command:   "switch",
+		Workspace: filepath.Dir(bareDir),
+		Worktree:  info.Path,
+		Branch:    info.Branch,
+	})
+}
+
 func completeSwitchArgs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 	if len(args) != 0 {
 		return nil, cobra.ShellCompDirectiveNoFileComp
@@ -197,8 +244,8 @@ func completeSwitchArgs(cmd *cobra.Command, args []string, toComplete string) ([
 		// Exclude current worktree (check if cwd is at root or inside this worktree)
 		inWorktree := fs.PathsEqual(cwd, info.Path) || fs.PathHasPrefix(cwd, info.Path)
 		if !inWorktree {
-			// Suggest worktree name (directory basename)
-			completions = append(completions, filepath.Base(info.Path))
+			// Suggest worktree name (directory basename, or branch if ambiguous)
+			completions = append(completions, git.WorktreeName(infos, info))
 		}
 	}
 
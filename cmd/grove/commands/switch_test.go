@@ -15,8 +15,8 @@ import (
 
 func TestNewSwitchCmd(t *testing.T) {
 	cmd := NewSwitchCmd()
-	if cmd.Use != "switch <worktree>" {
-		t.Errorf("expected Use to be 'switch <worktree>', got %q", cmd.Use)
+	if cmd.Use != "switch [worktree]" {
+		t.Errorf("expected Use to be 'switch [worktree]', got %q", cmd.Use)
 	}
 	if cmd.Short == "" {
 		t.Error("expected Short description to be set")
@@ -24,9 +24,10 @@ func TestNewSwitchCmd(t *testing.T) {
 }
 
 func TestNewSwitchCmd_RequiresOneArg(t *testing.T) {
+	defer stubPickerAvailable(false)()
 	cmd := NewSwitchCmd()
 
-	// Check that Args is set to expect exactly 1 argument
+	// Without a terminal for the picker, exactly 1 argument is required
 	err := cmd.Args(cmd, []string{})
 	if err == nil {
 		t.Error("expected error when no arguments provided")
@@ -43,6 +44,19 @@ func TestNewSwitchCmd_RequiresOneArg(t *testing.T) {
 	}
 }
 
+func TestNewSwitchCmd_NoArgWithPicker(t *testing.T) {
+	defer stubPickerAvailable(true)()
+	cmd := NewSwitchCmd()
+
+	if err := cmd.Args(cmd, []string{}); err != nil {
+		t.Errorf("expected no argument to be accepted when the picker is available, got: %v", err)
+	}
+
+	if err := cmd.Args(cmd, []string{"branch1", "branch2"}); err == nil {
+		t.Error("expected error when too many arguments provided")
+	}
+}
+
 func TestRunSwitch_NotInWorkspace(t *testing.T) {
 	origDir, err := os.Getwd()
 	if err != nil {
@@ -55,7 +69,7 @@ func TestRunSwitch_NotInWorkspace(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	err = runSwitch("main")
+	err = runSwitch("main", false, false)
 	if !errors.Is(err, workspace.ErrNotInWorkspace) {
 		t.Errorf("expected ErrNotInWorkspace, got %v", err)
 	}
@@ -0,0 +1,296 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/sqve/grove/internal/formatter"
+	"github.com/sqve/grove/internal/git"
+	"github.com/sqve/grove/internal/logger"
+	"github.com/sqve/grove/internal/workspace"
+)
+
+// syncAction describes what sync does (or would do) to a worktree
+type syncAction string
+
+const (
+	syncUpToDate    syncAction = "up-to-date"
+	syncFastForward syncAction = "fast-forward"
+	syncRebase      syncAction = "rebase"
+	syncSkip        syncAction = "skip"
+)
+
+// syncResult is the outcome of syncing a single worktree
+type syncResult struct {
+	info   *git.WorktreeInfo
+	action syncAction
+	reason string // Why the worktree was skipped
+	err    error  // Set when the update was attempted and failed
+}
+
+type syncWorktreeJSON struct {
+	Name     string `json:"name"`
+	Branch   string `json:"branch,omitempty"`
+	Path     string `json:"path"`
+	Upstream string `json:"upstream,omitempty"`
+	Ahead    int    `json:"ahead,omitempty"`
+	Behind   int    `json:"behind,omitempty"`
+	Action   string `json:"action"`
+	Reason   string `json:"reason,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+type syncResultJSON struct {
+	DryRun     bool               `json:"dry_run"`
+	FetchError string             `json:"fetch_error,omitempty"`
+	Worktrees  []syncWorktreeJSON `json:"worktrees"`
+}
+
+// NewSyncCmd creates the sync command
+func NewSyncCmd() *cobra.Command {
+	var rebase bool
+	var dryRun bool
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "sync",
+		Short: "Fetch and update worktrees from their upstreams",
+		Long: `Fetch all remotes, then update each clean worktree from its upstream.
+
+Worktrees that are behind are fast-forwarded. Diverged worktrees are
+skipped unless --rebase is given. Dirty, locked and detached worktrees, and
+worktrees with a merge or rebase in progress, are never touched.
+
+Examples:
+  grove sync              # Fast-forward worktrees that are behind
+  grove sync --rebase     # Also rebase diverged worktrees
+  grove sync --dry-run    # Show what would be updated`,
+		Args: cobra.NoArgs,
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSync(rebase, dryRun, jsonOutput)
+		},
+	}
+
+	cmd.Flags().BoolVar(&rebase, "rebase", false, "Rebase diverged worktrees onto their upstream")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would be updated without changing worktrees")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output as JSON")
+	cmd.Flags().BoolP("help", "h", false, "Help for sync")
+
+	return cmd
+}
+
+func runSync(rebase, dryRun, jsonOutput bool) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	bareDir, err := workspace.FindBareDir(cwd)
+	if err != nil {
+		return err
+	}
+
+	// Non-fatal: worktrees can still be synced against already-fetched refs
+	spin := logger.StartSpinner("Fetching remote changes...")
+	fetchErr := git.FetchPrune(bareDir)
+	spin.Stop()
+	if fetchErr != nil {
+		logger.Warning("Failed to fetch: %v", fetchErr)
+	}
+
+	infos, err := git.ListWorktreesWithInfo(bareDir, false)
+	if err != nil {
+		return fmt.Errorf("failed to list worktrees: %w", err)
+	}
+
+	results := make([]syncResult, 0, len(infos))
+	for _, info := range infos {
+		action, reason := planSync(info, ongoingOperation(info.Path), rebase)
+		results = append(results, syncResult{info: info, action: action, reason: reason})
+	}
+
+	if !dryRun {
+		applySync(results)
+	}
+
+	if jsonOutput {
+		return outputSyncJSON(results, dryRun, fetchErr)
+	}
+	return outputSyncResults(results, dryRun)
+}
+
+// ongoingOperation returns the name of the git operation in progress in path,
+// or an empty string if there is none.
+func ongoingOperation(path string) string {
+	ongoing, err := git.HasOngoingOperation(path)
+	if err != nil || !ongoing {
+		return ""
+	}
+	operation, err := git.GetOngoingOperation(path)
+	if err != nil || operation == "" {
+		return "operation"
+	}
+	return operation
+}
+
+// planSync decides how to update a worktree. operation is the git operation
+// in progress in the worktree, if any.
+func planSync(info *git.WorktreeInfo, operation string, rebase bool) (syncAction, string) {
+	switch {
+	case info.Detached:
+		return syncSkip, "detached"
+	case info.Locked:
+		return syncSkip, "locked"
+	case operation != "":
+		return syncSkip, operation + " in progress"
+	case info.Dirty:
+		return syncSkip, "dirty"
+	case info.Gone:
+		return syncSkip, "upstream gone"
+	case info.NoUpstream || info.Upstream == "":
+		return syncSkip, "no upstream"
+	case info.Behind == 0:
+		return syncUpToDate, ""
+	case info.Ahead == 0:
+		return syncFastForward, ""
+	case rebase:
+		return syncRebase, ""
+	default:
+		return syncSkip, "diverged, use --rebase"
+	}
+}
+
+func applySync(results []syncResult) {
+	pending := 0
+	for _, result := range results {
+		if result.action == syncFastForward || result.action == syncRebase {
+			pending++
+		}
+	}
+	if pending == 0 {
+		return
+	}
+
+	spin := logger.StartSpinner("Syncing worktrees...")
+	defer spin.Stop()
+
+	for i := range results {
+		result := &results[i]
+		switch result.action {
+		case syncFastForward:
+			result.err = git.FastForward(result.info.Path, result.info.Upstream)
+		case syncRebase:
+			result.err = git.Rebase(result.info.Path, result.info.Upstream)
+		}
+	}
+}
+
+func outputSyncJSON(results []syncResult, dryRun bool, fetchErr error) error {
+	output := syncResultJSON{
+		DryRun:    dryRun,
+		Worktrees: make([]syncWorktreeJSON, 0, len(results)),
+	}
+	if fetchErr != nil {
+		output.FetchError = fetchErr.Error()
+	}
+
+	failed := 0
+	for _, result := range results {
+		entry := syncWorktreeJSON{
+			Name:     filepath.Base(result.info.Path),
+			Path:     result.info.Path,
+			Upstream: result.info.Upstream,
+			Ahead:    result.info.Ahead,
+			Behind:   result.info.Behind,
+			Action:   string(result.action),
+			Reason:   result.reason,
+		}
+		if !result.info.Detached {
+			entry.Branch = result.info.Branch
+		}
+		if result.err != nil {
+			entry.Error = result.err.Error()
+			failed++
+		}
+		output.Worktrees = append(output.Worktrees, entry)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(output); err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to sync %d worktree(s)", failed)
+	}
+	return nil
+}
+
+func outputSyncResults(results []syncResult, dryRun bool) error {
+	var fastForwarded, rebased, skipped, failed []string
+
+	for _, result := range results {
+		label := formatter.WorktreeLabel(result.info)
+		switch {
+		case result.err != nil:
+			failed = append(failed, fmt.Sprintf("%s (%v)", label, result.err))
+		case result.action == syncFastForward:
+			fastForwarded = append(fastForwarded, fmt.Sprintf("%s (%s)", label, formatCommitCount(result.info.Behind)))
+		case result.action == syncRebase:
+			rebased = append(rebased, fmt.Sprintf("%s (%s onto %s)", label, formatCommitCount(result.info.Ahead), result.info.Upstream))
+		case result.action == syncSkip:
+			skipped = append(skipped, fmt.Sprintf("%s (%s)", label, result.reason))
+		}
+	}
+
+	if len(fastForwarded) == 0 && len(rebased) == 0 && len(skipped) == 0 && len(failed) == 0 {
+		logger.Success("All worktrees up to date")
+		return nil
+	}
+
+	if dryRun {
+		printSyncGroup(logger.Info, "Would fast-forward", fastForwarded)
+		printSyncGroup(logger.Info, "Would rebase", rebased)
+		printSyncGroup(logger.Warning, "Would skip", skipped)
+		return nil
+	}
+
+	printSyncGroup(logger.Success, "Fast-forwarded", fastForwarded)
+	printSyncGroup(logger.Success, "Rebased", rebased)
+	printSyncGroup(logger.Warning, "Skipped", skipped)
+	printSyncGroup(logger.Error, "Failed to sync", failed)
+
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to sync %d worktree(s)", len(failed))
+	}
+	return nil
+}
+
+// printSyncGroup prints a heading such as "Skipped 2 worktrees:" followed by items
+func printSyncGroup(log func(string, ...any), verb string, items []string) {
+	if len(items) == 0 {
+		return
+	}
+	if len(items) == 1 {
+		log("%s 1 worktree:", verb)
+	} else {
+		log("%s %d worktrees:", verb, len(items))
+	}
+	for _, item := range items {
+		logger.Dimmed("    %s", item)
+	}
+}
+
+func formatCommitCount(n int) string {
+	if n == 1 {
+		return "1 commit"
+	}
+	return fmt.Sprintf("%d commits", n)
+}
@@ -0,0 +1,92 @@
+package commands
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/sqve/grove/internal/git"
+	"github.com/sqve/grove/internal/testutil"
+	"github.com/sqve/grove/internal/workspace"
+)
+
+func TestNewSyncCmd(t *testing.T) {
+	cmd := NewSyncCmd()
+
+	if cmd.Use != "sync" {
+		t.Errorf("expected Use 'sync', got '%s'", cmd.Use)
+	}
+
+	for _, name := range []string{"rebase", "dry-run", "json"} {
+		if cmd.Flags().Lookup(name) == nil {
+			t.Errorf("expected --%s flag", name)
+		}
+	}
+
+	if err := cmd.Args(cmd, []string{"extra"}); err == nil {
+		t.Error("expected error for extra args")
+	}
+}
+
+func TestRunSync(t *testing.T) {
+	t.Run("returns error when not in workspace", func(t *testing.T) {
+		defer testutil.SaveCwd(t)()
+
+		tmpDir := testutil.TempDir(t)
+		testutil.Chdir(t, tmpDir)
+
+		err := runSync(false, true, false)
+		if !errors.Is(err, workspace.ErrNotInWorkspace) {
+			t.Errorf("expected ErrNotInWorkspace, got: %v", err)
+		}
+	})
+}
+
+func TestPlanSync(t *testing.T) {
+	tracking := func(ahead, behind int) git.WorktreeInfo {
+		return git.WorktreeInfo{Branch: "feat", Upstream: "origin/feat", Ahead: ahead, Behind: behind}
+	}
+
+	tests := []struct {
+		name       string
+		info       git.WorktreeInfo
+		operation  string
+		rebase     bool
+		wantAction syncAction
+		wantReason string
+	}{
+		{"up to date", tracking(0, 0), "", false, syncUpToDate, ""},
+		{"ahead only", tracking(2, 0), "", false, syncUpToDate, ""},
+		{"behind", tracking(0, 3), "", false, syncFastForward, ""},
+		{"behind with rebase", tracking(0, 3), "", true, syncFastForward, ""},
+		{"diverged", tracking(1, 3), "", false, syncSkip, "diverged, use --rebase"},
+		{"diverged with rebase", tracking(1, 3), "", true, syncRebase, ""},
+		{"dirty", func() git.WorktreeInfo { i := tracking(0, 1); i.Dirty = true; return i }(), "", false, syncSkip, "dirty"},
+		{"locked", func() git.WorktreeInfo { i := tracking(0, 1); i.Locked = true; return i }(), "", false, syncSkip, "locked"},
+		{"operation in progress", tracking(0, 1), "rebasing", false, syncSkip, "rebasing in progress"},
+		{"operation wins over dirty", func() git.WorktreeInfo { i := tracking(0, 1); i.Dirty = true; return i }(), "merging", false, syncSkip, "merging in progress"},
+		{"detached", git.WorktreeInfo{Branch: "abc1234", Detached: true}, "", false, syncSkip, "detached"},
+		{"no upstream", git.WorktreeInfo{Branch: "feat", NoUpstream: true}, "", false, syncSkip, "no upstream"},
+		{"upstream gone", git.WorktreeInfo{Branch: "feat", Upstream: "origin/feat", Gone: true}, "", false, syncSkip, "upstream gone"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			action, reason := planSync(&tt.info, tt.operation, tt.rebase)
+			if action != tt.wantAction {
+				t.Errorf("expected action %q, got %q", tt.wantAction, action)
+			}
+			if reason != tt.wantReason {
+				t.Errorf("expected reason %q, got %q", tt.wantReason, reason)
+			}
+		})
+	}
+}
+
+func TestFormatCommitCount(t *testing.T) {
+	if got := formatCommitCount(1); got != "1 commit" {
+		t.Errorf("expected '1 commit', got %q", got)
+	}
+	if got := formatCommitCount(3); got != "3 commits" {
+		t.Errorf("expected '3 commits', got %q", got)
+	}
+}
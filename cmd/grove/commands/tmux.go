@@ -0,0 +1,67 @@
+package commands
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/sqve/grove/internal/config"
+	"github.com/sqve/grove/internal/logger"
+	"github.com/sqve/grove/internal/tmux"
+)
+
+// tmuxEnabled resolves the --tmux flag against grove.tmux. An explicit flag
+// wins, so --tmux=false overrides the config for a single run.
+func tmuxEnabled(cmd *cobra.Command) bool {
+	if cmd.Flags().Changed("tmux") {
+		enabled, _ := cmd.Flags().GetBool("tmux")
+		return enabled
+	}
+	return config.IsTmux()
+}
+
+// tmuxMode returns the configured tmux mode
+func tmuxMode() tmux.Mode {
+	if mode, ok := tmux.ParseMode(config.GetTmuxMode()); ok {
+		return mode
+	}
+	return tmux.ModeWindow
+}
+
+// switchToWorktree focuses the tmux window or session for worktreePath when
+// useTmux is set. Otherwise, or when not inside tmux, it prints the path for
+// the shell wrapper to cd into.
+func switchToWorktree(worktreePath string, useTmux bool) error {
+	if useTmux {
+		if tmux.InSession() {
+			mode := tmuxMode()
+			if err := tmux.Focus(mode, filepath.Base(worktreePath), worktreePath); err != nil {
+				return fmt.Errorf("failed to focus tmux %s: %w", mode, err)
+			}
+			return nil
+		}
+		logger.Warning("Not inside tmux, changing directory instead")
+	}
+
+	fmt.Println(worktreePath) // Raw path for shell wrapper to cd into
+	return nil
+}
+
+// closeTmuxWindow closes the tmux window or session of a removed worktree.
+// Failures only warn since the worktree itself is already gone.
+func closeTmuxWindow(worktreePath string) {
+	if !config.IsTmux() || !tmux.InSession() {
+		return
+	}
+
+	mode := tmuxMode()
+	name := filepath.Base(worktreePath)
+	killed, err := tmux.Kill(mode, name)
+	if err != nil {
+		logger.Warning("Failed to close tmux %s %s: %v", mode, name, err)
+		return
+	}
+	if killed {
+		logger.Debug("Closed tmux %s %s", mode, name)
+	}
+}
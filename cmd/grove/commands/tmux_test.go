@@ -0,0 +1,53 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/sqve/grove/internal/config"
+	"github.com/sqve/grove/internal/tmux"
+)
+
+func TestTmuxEnabled(t *testing.T) {
+	tests := []struct {
+		name   string
+		args   []string
+		config bool
+		want   bool
+	}{
+		{"disabled by default", nil, false, false},
+		{"enabled by config", nil, true, true},
+		{"enabled by flag", []string{"--tmux"}, false, true},
+		{"flag overrides config", []string{"--tmux=false"}, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orig := config.Global.Tmux
+			config.Global.Tmux = tt.config
+			defer func() { config.Global.Tmux = orig }()
+
+			cmd := NewSwitchCmd()
+			if err := cmd.ParseFlags(tt.args); err != nil {
+				t.Fatalf("ParseFlags() error = %v", err)
+			}
+			if got := tmuxEnabled(cmd); got != tt.want {
+				t.Errorf("tmuxEnabled() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTmuxMode(t *testing.T) {
+	orig := config.Global.TmuxMode
+	defer func() { config.Global.TmuxMode = orig }()
+
+	config.Global.TmuxMode = "session"
+	if got := tmuxMode(); got != tmux.ModeSession {
+		t.Errorf("tmuxMode() = %q, want %q", got, tmux.ModeSession)
+	}
+
+	config.Global.TmuxMode = ""
+	if got := tmuxMode(); got != tmux.ModeWindow {
+		t.Errorf("tmuxMode() = %q, want %q", got, tmux.ModeWindow)
+	}
+}
@@ -0,0 +1,324 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/sqve/grove/internal/formatter"
+	"github.com/sqve/grove/internal/fs"
+	"github.com/sqve/grove/internal/git"
+	"github.com/sqve/grove/internal/journal"
+	"github.com/sqve/grove/internal/logger"
+	"github.com/sqve/grove/internal/styles"
+	"github.com/sqve/grove/internal/workspace"
+)
+
+// NewUndoCmd creates the undo command
+func NewUndoCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "undo [id]",
+		Short: "Undo a remove, prune or move",
+		Long: `Reverse an operation recorded by remove, prune --commit or move.
+
+Without an ID, undoes the most recent operation that has not been undone.
+Removed worktrees are recreated at their last commit, along with their
+branch, upstream, lock and any uncommitted files removed with --force.
+Moved worktrees get their old branch name and directory back.
+
+Examples:
+  grove undo        # Undo the last operation
+  grove undo 12     # Undo operation 12 from 'grove history'`,
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeUndoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id := ""
+			if len(args) > 0 {
+				id = args[0]
+			}
+			return runUndo(id)
+		},
+	}
+
+	cmd.Flags().BoolP("help", "h", false, "Help for undo")
+
+	return cmd
+}
+
+func runUndo(id string) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	bareDir, err := workspace.FindBareDir(cwd)
+	if err != nil {
+		return err
+	}
+
+	var entry *journal.Entry
+	if id == "" {
+		entry, err = journal.Latest(bareDir)
+		if errors.Is(err, journal.ErrNotFound) {
+			return fmt.Errorf("no operations to undo")
+		}
+	} else {
+		n, convErr := strconv.Atoi(strings.TrimPrefix(strings.TrimSpace(id), "#"))
+		if convErr != nil || n <= 0 {
+			return fmt.Errorf("invalid operation ID: %s", id)
+		}
+		entry, err = journal.Get(bareDir, n)
+	}
+	if err != nil {
+		return err
+	}
+
+	if entry.Undone() {
+		return fmt.Errorf("operation %d was already undone", entry.ID)
+	}
+
+	switch entry.Op {
+	case journal.OpRemove:
+		err = undoRemoval(bareDir, entry)
+	case journal.OpMove:
+		err = undoMove(bareDir, cwd, entry)
+	default:
+		return fmt.Errorf("operation %d cannot be undone: unknown type %q", entry.ID, entry.Op)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := journal.MarkUndone(bareDir, entry); err != nil {
+		logger.Warning("Failed to update history: %v", err)
+	}
+	return nil
+}
+
+// undoRemoval recreates a removed worktree, and its branch if it was deleted
+func undoRemoval(bareDir string, entry *journal.Entry) error {
+	if _, err := os.Stat(entry.Worktree); err == nil {
+		return fmt.Errorf("cannot restore %s: path already exists", entry.Worktree)
+	}
+
+	recreated := false
+	if entry.Branch != "" {
+		exists, err := git.LocalBranchExists(bareDir, entry.Branch)
+		if err != nil {
+			return fmt.Errorf("failed to check branch: %w", err)
+		}
+
+		if !exists {
+			if err := git.UpdateBranchRef(bareDir, entry.Branch, entry.Head); err != nil {
+				return fmt.Errorf("failed to recreate branch %s: %w", entry.Branch, err)
+			}
+			recreated = true
+
+			if entry.UpstreamRemote != "" && entry.UpstreamMerge != "" {
+				if err := git.SetBranchUpstream(bareDir, entry.Branch, entry.UpstreamRemote, entry.UpstreamMerge); err != nil {
+					logger.Warning("Failed to restore upstream for %s: %v", entry.Branch, err)
+				}
+			}
+			if entry.StackParent != "" && entry.StackBase != "" {
+				if err := git.SetBranchParent(bareDir, entry.Branch, entry.StackParent, entry.StackBase); err != nil {
+					logger.Warning("Failed to restore stack parent for %s: %v", entry.Branch, err)
+				}
+			}
+		} else if tip, err := git.RevParse(bareDir, "refs/heads/"+entry.Branch); err == nil && tip != entry.Head {
+			logger.Warning("Branch %s has moved since it was removed; restoring at its current commit", entry.Branch)
+		}
+
+		if err := git.CreateWorktree(bareDir, entry.Worktree, entry.Branch, true); err != nil {
+			if recreated {
+				_ = git.DeleteBranch(bareDir, entry.Branch, true)
+			}
+			return git.HintGitTooOld(fmt.Errorf("failed to create worktree: %w", err))
+		}
+	} else if err := git.CreateWorktreeDetached(bareDir, entry.Worktree, entry.Head, true); err != nil {
+		return git.HintGitTooOld(fmt.Errorf("failed to create worktree: %w", err))
+	}
+
+	restored, err := journal.RestoreSnapshot(bareDir, entry, entry.Worktree)
+	if err != nil {
+		return fmt.Errorf("restored worktree %s, but not its uncommitted files (snapshot kept in %s): %w",
+			entry.Worktree, journal.Dir(bareDir), err)
+	}
+	if entry.SnapshotRef != "" {
+		n, err := restoreRemovalSnapshot(bareDir, entry)
+		if err != nil {
+			return fmt.Errorf("restored worktree %s, but not its uncommitted files: %w", entry.Worktree, err)
+		}
+		restored += n
+	}
+
+	if entry.Locked {
+		if err := git.LockWorktree(bareDir, entry.Worktree, entry.LockReason); err != nil {
+			logger.Warning("Failed to lock worktree: %v", err)
+		}
+	}
+	values := allocateWorktreeEnv(bareDir, entry.Worktree, entry.Branch, findConfigWorktree(bareDir))
+
+	logger.Success("Restored worktree %s", styles.RenderPath(entry.Worktree))
+	if recreated {
+		logger.ListSubItem("recreated branch %s", entry.Branch)
+	}
+	if restored > 0 {
+		logger.ListSubItem("restored %d uncommitted file(s)", restored)
+	}
+	if entry.Locked {
+		logger.ListSubItem("locked")
+	}
+	logEnvResult(values)
+	return nil
+}
+
+// restoreRemovalSnapshot applies the snapshot a forced removal saved to the
+// recreated worktree and returns the number of files it restored. The
+// snapshot is kept, as with grove restore.
+func restoreRemovalSnapshot(bareDir string, entry *journal.Entry) (int, error) {
+	id := strings.TrimPrefix(entry.SnapshotRef, git.SnapshotRefPrefix)
+	commit, err := git.RevParse(bareDir, entry.SnapshotRef)
+	if err != nil {
+		return 0, fmt.Errorf("snapshot %s no longer exists", id)
+	}
+	if err := git.ApplyStash(entry.Worktree, commit); err != nil {
+		return 0, fmt.Errorf("failed to apply snapshot %s: %w", id, err)
+	}
+	files, _ := git.SnapshotFiles(bareDir, commit)
+	return len(files), nil
+}
+
+// undoMove renames a moved branch back and returns its worktree to the old path
+func undoMove(bareDir, cwd string, entry *journal.Entry) error {
+	if fs.PathsEqual(cwd, entry.NewWorktree) || fs.PathHasPrefix(cwd, entry.NewWorktree) {
+		return fmt.Errorf("cannot move current worktree\n\nHint: Switch to a different worktree first with 'grove switch <worktree>'")
+	}
+	if _, err := os.Stat(entry.NewWorktree); err != nil {
+		return fmt.Errorf("cannot undo move: %s no longer exists", entry.NewWorktree)
+	}
+	if _, err := os.Stat(entry.Worktree); err == nil {
+		return fmt.Errorf("cannot undo move: %s already exists", entry.Worktree)
+	}
+	if branch, err := git.GetCurrentBranch(entry.NewWorktree); err != nil || branch != entry.NewBranch {
+		return fmt.Errorf("cannot undo move: %s is no longer on branch %s", entry.NewWorktree, entry.NewBranch)
+	}
+	exists, err := git.LocalBranchExists(bareDir, entry.Branch)
+	if err != nil {
+		return fmt.Errorf("failed to check branch: %w", err)
+	}
+	if exists {
+		return fmt.Errorf("cannot undo move: branch %q already exists", entry.Branch)
+	}
+
+	lockFile := filepath.Join(filepath.Dir(bareDir), ".grove-worktree.lock")
+	lockHandle, err := workspace.AcquireWorkspaceLock(lockFile)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = lockHandle.Close()
+		_ = os.Remove(lockFile)
+	}()
+
+	if err := git.RenameBranch(bareDir, entry.NewBranch, entry.Branch); err != nil {
+		return fmt.Errorf("failed to rename branch: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(entry.Worktree), fs.DirGit); err != nil {
+		_ = git.RenameBranch(bareDir, entry.Branch, entry.NewBranch)
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(entry.Worktree), err)
+	}
+	if err := os.Rename(entry.NewWorktree, entry.Worktree); err != nil {
+		_ = git.RenameBranch(bareDir, entry.Branch, entry.NewBranch)
+		return fmt.Errorf("failed to move worktree directory: %w", err)
+	}
+	if err := git.RepairWorktree(bareDir, entry.Worktree); err != nil {
+		_ = os.Rename(entry.Worktree, entry.NewWorktree)
+		_ = git.RenameBranch(bareDir, entry.Branch, entry.NewBranch)
+		_ = git.RepairWorktree(bareDir, entry.NewWorktree)
+		return fmt.Errorf("failed to repair worktree: %w", err)
+	}
+	renameWorktreeEnv(bareDir, entry.NewWorktree, entry.Worktree)
+	workspace.RemoveEmptyParents(entry.NewWorktree, filepath.Dir(bareDir))
+	if err := git.RenameBranchParent(bareDir, entry.NewBranch, entry.Branch); err != nil {
+		logger.Warning("Failed to update branches stacked on %s: %v", entry.NewBranch, err)
+	}
+
+	if entry.UpstreamRemote != "" && entry.UpstreamMerge != "" {
+		if err := git.SetBranchUpstream(bareDir, entry.Branch, entry.UpstreamRemote, entry.UpstreamMerge); err != nil {
+			logger.Warning("Failed to restore upstream for %s: %v", entry.Branch, err)
+		}
+	}
+
+	logger.Success("Renamed %s back to %s", entry.NewBranch, entry.Branch)
+	logger.ListSubItem("%s", styles.RenderPath(entry.Worktree))
+	return nil
+}
+
+// journalRemoval captures a worktree that is about to be removed so it can be
+// restored with 'grove undo'. With force, uncommitted files are archived too,
+// and failing to archive them is an error. Failing to capture anything else
+// only costs the ability to undo, so it returns a nil entry with a warning.
+func journalRemoval(bareDir, command string, info *git.WorktreeInfo, force bool) (*journal.Entry, error) {
+	entry, err := journal.NewRemoval(bareDir, command, info)
+	if err != nil {
+		logger.Warning("%s: cannot be undone: %v", formatter.WorktreeLabel(info), err)
+		return nil, nil
+	}
+	if force {
+		if err := journal.Snapshot(bareDir, entry); err != nil {
+			return nil, fmt.Errorf("failed to save uncommitted changes: %w", err)
+		}
+	}
+	return entry, nil
+}
+
+// recordJournal writes entry to the workspace journal. Nil entries are ignored.
+func recordJournal(bareDir string, entry *journal.Entry) {
+	if entry == nil {
+		return
+	}
+	if err := journal.Record(bareDir, entry); err != nil {
+		journal.Discard(bareDir, entry)
+		logger.Warning("Failed to record operation for undo: %v", err)
+	}
+}
+
+// discardJournal drops an entry whose operation did not happen
+func discardJournal(bareDir string, entry *journal.Entry) {
+	if entry != nil {
+		journal.Discard(bareDir, entry)
+	}
+}
+
+func completeUndoArgs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) != 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	bareDir, err := workspace.FindBareDir(cwd)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	entries, err := journal.List(bareDir)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	var completions []string
+	for _, entry := range entries {
+		if !entry.Undone() {
+			completions = append(completions, fmt.Sprintf("%d\t%s %s", entry.ID, entry.Command, describeEntry(entry)))
+		}
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveKeepOrder
+}
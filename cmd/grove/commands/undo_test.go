@@ -0,0 +1,197 @@
+package commands
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/sqve/grove/internal/git"
+	"github.com/sqve/grove/internal/journal"
+	"github.com/sqve/grove/internal/testutil"
+	testgit "github.com/sqve/grove/internal/testutil/git"
+	"github.com/sqve/grove/internal/workspace"
+)
+
+func TestNewUndoCmd(t *testing.T) {
+	cmd := NewUndoCmd()
+
+	if cmd.Use != "undo [id]" {
+		t.Errorf("expected Use 'undo [id]', got %q", cmd.Use)
+	}
+	if cmd.Short == "" {
+		t.Error("expected Short description")
+	}
+	if err := cmd.Args(cmd, []string{"1", "2"}); err == nil {
+		t.Error("expected error for more than one argument")
+	}
+}
+
+func TestRunUndo_NotInWorkspace(t *testing.T) {
+	defer testutil.SaveCwd(t)()
+	testutil.Chdir(t, testutil.TempDir(t))
+
+	err := runUndo("")
+	if !errors.Is(err, workspace.ErrNotInWorkspace) {
+		t.Errorf("expected ErrNotInWorkspace, got %v", err)
+	}
+}
+
+func TestRunUndo_Errors(t *testing.T) {
+	defer testutil.SaveCwd(t)()
+
+	ws := testgit.NewGroveWorkspace(t, "main")
+	testutil.Chdir(t, ws.WorktreePath("main"))
+
+	testutil.AssertErrorContains(t, runUndo(""), "no operations to undo")
+	testutil.AssertErrorContains(t, runUndo("abc"), "invalid operation ID: abc")
+	testutil.AssertErrorContains(t, runUndo("0"), "invalid operation ID: 0")
+
+	if err := runUndo("7"); !errors.Is(err, journal.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+
+	entry := &journal.Entry{Command: "remove", Op: journal.OpRemove, Worktree: filepath.Join(ws.Dir, "gone")}
+	if err := journal.Record(ws.BareDir, entry); err != nil {
+		t.Fatal(err)
+	}
+	if err := journal.MarkUndone(ws.BareDir, entry); err != nil {
+		t.Fatal(err)
+	}
+	testutil.AssertErrorContains(t, runUndo("1"), "operation 1 was already undone")
+}
+
+func TestRunUndo_RemovalPathExists(t *testing.T) {
+	defer testutil.SaveCwd(t)()
+
+	ws := testgit.NewGroveWorkspace(t, "main", "feat")
+	testutil.Chdir(t, ws.WorktreePath("main"))
+
+	entry := &journal.Entry{Command: "remove", Op: journal.OpRemove, Worktree: ws.WorktreePath("feat"), Branch: "feat"}
+	if err := journal.Record(ws.BareDir, entry); err != nil {
+		t.Fatal(err)
+	}
+
+	testutil.AssertErrorContains(t, runUndo(""), "path already exists")
+
+	reloaded, err := journal.Get(ws.BareDir, entry.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reloaded.Undone() {
+		t.Error("failed undo must not mark the entry undone")
+	}
+}
+
+func TestRunUndo_Move(t *testing.T) {
+	defer testutil.SaveCwd(t)()
+
+	ws := testgit.NewGroveWorkspace(t, "main", "feat")
+	testutil.Chdir(t, ws.WorktreePath("main"))
+
+	oldPath := ws.WorktreePath("feat")
+	newPath := filepath.Join(ws.Dir, "renamed")
+	testutil.MustExec(t, ws.BareDir, "git", "config", "branch.feat.remote", "origin")
+	testutil.MustExec(t, ws.BareDir, "git", "config", "branch.feat.merge", "refs/heads/feat")
+
+	// Simulate what grove move does
+	testutil.MustExec(t, ws.BareDir, "git", "branch", "-m", "feat", "renamed")
+	if err := os.Rename(oldPath, newPath); err != nil {
+		t.Fatal(err)
+	}
+	testutil.MustExec(t, ws.BareDir, "git", "worktree", "repair", newPath)
+	testutil.MustExec(t, ws.BareDir, "git", "config", "--unset", "branch.renamed.merge")
+
+	if err := journal.Record(ws.BareDir, &journal.Entry{
+		Command:        "move",
+		Op:             journal.OpMove,
+		Worktree:       oldPath,
+		Branch:         "feat",
+		UpstreamRemote: "origin",
+		UpstreamMerge:  "refs/heads/feat",
+		NewWorktree:    newPath,
+		NewBranch:      "renamed",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := runUndo(""); err != nil {
+		t.Fatalf("runUndo() error = %v", err)
+	}
+
+	branch, err := git.GetCurrentBranch(oldPath)
+	if err != nil || branch != "feat" {
+		t.Errorf("worktree at old path is on %q (%v), want feat", branch, err)
+	}
+	if _, err := os.Stat(newPath); !os.IsNotExist(err) {
+		t.Error("expected new path to be gone")
+	}
+	if remote, merge := git.GetBranchUpstream(ws.BareDir, "feat"); remote != "origin" || merge != "refs/heads/feat" {
+		t.Errorf("upstream = %q %q, want origin refs/heads/feat", remote, merge)
+	}
+
+	testutil.AssertErrorContains(t, runUndo(""), "no operations to undo")
+}
+
+func TestJournalRemoval(t *testing.T) {
+	ws := testgit.NewGroveWorkspace(t, "main", "feat")
+	featPath := ws.WorktreePath("feat")
+
+	testutil.MustExec(t, ws.BareDir, "git", "worktree", "lock", "--reason", "WIP", featPath)
+	testutil.MustExec(t, ws.BareDir, "git", "config", "branch.feat.remote", "origin")
+	testutil.MustExec(t, ws.BareDir, "git", "config", "branch.feat.merge", "refs/heads/feat")
+	testutil.WriteFile(t, filepath.Join(featPath, "scratch.txt"), "dirty")
+
+	info := &git.WorktreeInfo{Path: featPath, Branch: "feat"}
+
+	entry, err := journalRemoval(ws.BareDir, "remove", info, false)
+	if err != nil {
+		t.Fatalf("journalRemoval() error = %v", err)
+	}
+	head := strings.TrimSpace(testutil.MustExec(t, featPath, "git", "rev-parse", "HEAD"))
+	if entry.Head != head || entry.Branch != "feat" {
+		t.Errorf("entry = %+v, want head %s on feat", entry, head)
+	}
+	if !entry.Locked || entry.LockReason != "WIP" {
+		t.Errorf("lock = %v %q, want locked with reason WIP", entry.Locked, entry.LockReason)
+	}
+	if entry.UpstreamRemote != "origin" || entry.UpstreamMerge != "refs/heads/feat" {
+		t.Errorf("upstream = %q %q", entry.UpstreamRemote, entry.UpstreamMerge)
+	}
+	if entry.Snapshot != "" {
+		t.Error("expected no snapshot without force")
+	}
+
+	entry, err = journalRemoval(ws.BareDir, "remove", info, true)
+	if err != nil {
+		t.Fatalf("journalRemoval(force) error = %v", err)
+	}
+	if entry.Snapshot == "" {
+		t.Error("expected snapshot of dirty worktree with force")
+	}
+	discardJournal(ws.BareDir, entry)
+}
+
+func TestCompleteUndoArgs(t *testing.T) {
+	defer testutil.SaveCwd(t)()
+
+	ws := testgit.NewGroveWorkspace(t, "main")
+	testutil.Chdir(t, ws.WorktreePath("main"))
+
+	for _, branch := range []string{"one", "two"} {
+		if err := journal.Record(ws.BareDir, &journal.Entry{Command: "remove", Op: journal.OpRemove, Worktree: filepath.Join(ws.Dir, branch), Branch: branch}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	completions, directive := completeUndoArgs(&cobra.Command{}, nil, "")
+	if directive&cobra.ShellCompDirectiveNoFileComp == 0 {
+		t.Error("expected NoFileComp directive")
+	}
+	want := []string{"2\tremove two", "1\tremove one"}
+	if strings.Join(completions, "|") != strings.Join(want, "|") {
+		t.Errorf("completions = %q, want %q", completions, want)
+	}
+}
@@ -131,7 +131,7 @@ func completeUnlockArgs(cmd *cobra.Command, args []string, toComplete string) ([
 
 	var completions []string
 	for _, info := range infos {
-		name := filepath.Base(info.Path)
+		name := git.WorktreeName(infos, info)
 
 		// Skip already-used (check both path basename and branch name)
 		if alreadyUsed[name] || alreadyUsed[info.Branch] {
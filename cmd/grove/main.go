@@ -1,7 +1,9 @@
 package main
 
 import (
+	"fmt"
 	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/sqve/grove/cmd/grove/commands"
@@ -10,8 +12,17 @@ import (
 	"github.com/sqve/grove/internal/version"
 )
 
+// stopEvents restores stdout and stderr after --output=ndjson
+var stopEvents func()
+
 func main() {
-	config.LoadFromGitConfig()
+	// Prompts run before every shell prompt, so they skip the git processes
+	// that reading config takes
+	if isPromptCommand(os.Args[1:]) {
+		config.LoadDefaults()
+	} else {
+		config.LoadFromGitConfig()
+	}
 	logger.Init(config.IsPlain(), config.IsDebug())
 
 	rootCmd := &cobra.Command{
@@ -21,8 +32,23 @@ func main() {
 		Version:       version.Full(),
 		SilenceErrors: true,
 		SilenceUsage:  true,
-		PersistentPreRun: func(cmd *cobra.Command, args []string) {
-			if cmd.Flags().Changed("plain") {
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			output, _ := cmd.Flags().GetString("output")
+			switch output {
+			case "text":
+			case "ndjson":
+				// Event messages carry no colors or symbols
+				config.SetPlain(true)
+				stop, err := logger.StartEventStream()
+				if err != nil {
+					return fmt.Errorf("failed to start event stream: %w", err)
+				}
+				stopEvents = stop
+			default:
+				return fmt.Errorf("invalid output format %q (use text or ndjson)", output)
+			}
+
+			if cmd.Flags().Changed("plain") && output == "text" {
 				plain, _ := cmd.Flags().GetBool("plain")
 				config.SetPlain(plain)
 			}
@@ -34,6 +60,7 @@ func main() {
 			logger.Init(config.IsPlain(), config.IsDebug())
 			logger.Debug("Grove starting with config: plain=%v, debug=%v",
 				config.IsPlain(), config.IsDebug())
+			return nil
 		},
 		Run: func(cmd *cobra.Command, args []string) {
 			if err := cmd.Help(); err != nil {
@@ -46,27 +73,59 @@ func main() {
 
 	rootCmd.PersistentFlags().Bool("plain", false, "Disable colors and symbols")
 	rootCmd.PersistentFlags().Bool("debug", false, "Enable debug logging")
+	rootCmd.PersistentFlags().String("output", "text", "Output format: text or ndjson (one JSON event per line)")
 	rootCmd.Flags().BoolP("help", "h", false, "Help for grove")
 
 	rootCmd.AddCommand(commands.NewAddCmd())
+	rootCmd.AddCommand(commands.NewAdoptCmd())
+	rootCmd.AddCommand(commands.NewCarryCmd())
 	rootCmd.AddCommand(commands.NewCloneCmd())
+	rootCmd.AddCommand(commands.NewCompareCmd())
 	rootCmd.AddCommand(commands.NewConfigCmd())
+	rootCmd.AddCommand(commands.NewDaemonCmd())
+	rootCmd.AddCommand(commands.NewDiffCmd())
 	rootCmd.AddCommand(commands.NewDoctorCmd())
 	rootCmd.AddCommand(commands.NewExecCmd())
 	rootCmd.AddCommand(commands.NewFetchCmd())
+	rootCmd.AddCommand(commands.NewHistoryCmd())
 	rootCmd.AddCommand(commands.NewInitCmd())
 	rootCmd.AddCommand(commands.NewListCmd())
 	rootCmd.AddCommand(commands.NewLockCmd())
 	rootCmd.AddCommand(commands.NewMoveCmd())
+	rootCmd.AddCommand(commands.NewPromptCmd())
 	rootCmd.AddCommand(commands.NewPruneCmd())
 	rootCmd.AddCommand(commands.NewRemoveCmd())
+	rootCmd.AddCommand(commands.NewRestoreCmd())
+	rootCmd.AddCommand(commands.NewSnapshotCmd())
+	rootCmd.AddCommand(commands.NewSparseCmd())
+	rootCmd.AddCommand(commands.NewStackCmd())
 	rootCmd.AddCommand(commands.NewStatusCmd())
 	rootCmd.AddCommand(commands.NewSwitchCmd())
+	rootCmd.AddCommand(commands.NewSyncCmd())
+	rootCmd.AddCommand(commands.NewUndoCmd())
 	rootCmd.AddCommand(commands.NewUnlockCmd())
 
-	if err := rootCmd.Execute(); err != nil {
-		logger.Error("%s", err)
-		logger.Dimmed("Run 'grove --help' for usage.")
+	err := rootCmd.Execute()
+	if stopEvents != nil {
+		stopEvents()
+	}
+	if err != nil {
+		if logger.EventsEnabled() {
+			logger.Emit(logger.Event{Type: logger.EventError, Message: err.Error()})
+		} else {
+			logger.Error("%s", err)
+			logger.Dimmed("Run 'grove --help' for usage.")
+		}
 		os.Exit(1)
 	}
 }
+
+// isPromptCommand reports whether args run grove prompt
+func isPromptCommand(args []string) bool {
+	for _, arg := range args {
+		if !strings.HasPrefix(arg, "-") {
+			return arg == "prompt"
+		}
+	}
+	return false
+}
@@ -0,0 +1,225 @@
+// Package branchmatch matches branch names against the patterns used by
+// branch-scoped settings such as autolock, git profiles and grove list
+// --filter branch:<pattern>.
+//
+// A pattern is a glob matched against the whole branch name:
+//
+//	main          the branch main only
+//	release/*     * matches within one path segment: release/1.0, not release/1.0/fix
+//	release/**    ** matches across segments: release/1.0 and release/1.0/fix
+//	**/wip        a leading **/ matches any number of segments, including none
+//	hotfix-?      ? matches one character other than /
+//	v[0-9]*       [...] matches one character in the class, [!...] or [^...] one outside it
+//	re:^v\d+$     re: starts a Go regular expression, unanchored unless it says so
+//	!release/old  ! negates the rest of the pattern
+//
+// A backslash makes the next character literal, so \! matches a leading !.
+//
+// In a list of patterns, the last pattern that matches a branch decides, as
+// in .gitignore: a branch matches when that pattern is not negated. A
+// negation therefore only excludes branches that earlier patterns include,
+// as in ["release/**", "!release/experimental"].
+package branchmatch
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// regexPrefix starts a pattern that is a regular expression
+const regexPrefix = "re:"
+
+// Pattern is a compiled branch pattern
+type Pattern struct {
+	raw    string
+	negate bool
+	re     *regexp.Regexp
+}
+
+// Compile parses a branch pattern
+func Compile(pattern string) (*Pattern, error) {
+	body, negate := strings.CutPrefix(pattern, "!")
+	if body == "" {
+		return nil, errors.New("empty branch pattern")
+	}
+
+	var expr string
+	if rest, ok := strings.CutPrefix(body, regexPrefix); ok {
+		if rest == "" {
+			return nil, fmt.Errorf("invalid branch pattern %q: empty regular expression", pattern)
+		}
+		expr = rest
+	} else {
+		var err error
+		if expr, err = globToRegexp(body); err != nil {
+			return nil, fmt.Errorf("invalid branch pattern %q: %w", pattern, err)
+		}
+	}
+
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid branch pattern %q: %w", pattern, err)
+	}
+	return &Pattern{raw: pattern, negate: negate, re: re}, nil
+}
+
+// String returns the pattern as written
+func (p *Pattern) String() string {
+	return p.raw
+}
+
+// Negated reports whether the pattern starts with !
+func (p *Pattern) Negated() bool {
+	return p.negate
+}
+
+// Matches reports whether branch matches the pattern, ignoring negation
+func (p *Pattern) Matches(branch string) bool {
+	return p.re.MatchString(branch)
+}
+
+// Matcher is a compiled list of branch patterns
+type Matcher struct {
+	patterns []*Pattern
+}
+
+// New compiles a list of branch patterns
+func New(patterns []string) (*Matcher, error) {
+	m, errs := NewSkipInvalid(patterns)
+	if len(errs) > 0 {
+		return nil, errs[0]
+	}
+	return m, nil
+}
+
+// NewSkipInvalid compiles the valid patterns of a list, leaving out the
+// invalid ones and returning their errors
+func NewSkipInvalid(patterns []string) (*Matcher, []error) {
+	m := &Matcher{patterns: make([]*Pattern, 0, len(patterns))}
+	var errs []error
+	for _, pattern := range patterns {
+		p, err := Compile(pattern)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		m.patterns = append(m.patterns, p)
+	}
+	return m, errs
+}
+
+// Match reports whether branch matches the list: the last pattern matching
+// it must not be negated. An empty branch, such as a detached HEAD, never
+// matches.
+func (m *Matcher) Match(branch string) bool {
+	if branch == "" {
+		return false
+	}
+	for i := len(m.patterns) - 1; i >= 0; i-- {
+		if m.patterns[i].Matches(branch) {
+			return !m.patterns[i].negate
+		}
+	}
+	return false
+}
+
+// Match compiles patterns and reports whether branch matches them
+func Match(patterns []string, branch string) (bool, error) {
+	m, err := New(patterns)
+	if err != nil {
+		return false, err
+	}
+	return m.Match(branch), nil
+}
+
+// Validate reports whether pattern is a valid branch pattern
+func Validate(pattern string) error {
+	_, err := Compile(pattern)
+	return err
+}
+
+// globToRegexp translates a glob into an anchored regular expression
+func globToRegexp(glob string) (string, error) {
+	var b strings.Builder
+	b.WriteString("^")
+
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch c {
+		case '*':
+			if i+1 < len(glob) && glob[i+1] == '*' {
+				i++
+				// A **/ segment also matches no segments at all
+				if i+1 < len(glob) && glob[i+1] == '/' && (i == 1 || glob[i-2] == '/') {
+					i++
+					b.WriteString("(?:.*/)?")
+				} else {
+					b.WriteString(".*")
+				}
+			} else {
+				b.WriteString("[^/]*")
+			}
+		case '?':
+			b.WriteString("[^/]")
+		case '[':
+			class, n, err := globClass(glob[i:])
+			if err != nil {
+				return "", err
+			}
+			b.WriteString(class)
+			i += n - 1
+		case '\\':
+			if i+1 == len(glob) {
+				return "", errors.New("trailing backslash")
+			}
+			i++
+			b.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+		default:
+			b.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+		}
+	}
+
+	b.WriteString("$")
+	return b.String(), nil
+}
+
+// globClass translates the character class at the start of glob and returns
+// it with the number of bytes it spans
+func globClass(glob string) (string, int, error) {
+	var b strings.Builder
+	b.WriteString("[")
+
+	i := 1
+	if i < len(glob) && (glob[i] == '!' || glob[i] == '^') {
+		// Like the other wildcards, a negated class never matches /
+		b.WriteString("^/")
+		i++
+	}
+	start := i
+	for ; i < len(glob); i++ {
+		c := glob[i]
+		switch {
+		case c == ']' && i > start:
+			b.WriteString("]")
+			return b.String(), i + 1, nil
+		case c == '\\' && i+1 < len(glob):
+			i++
+			b.WriteString(classLiteral(glob[i]))
+		case c == '[' || c == ']' || c == '\\':
+			b.WriteString(classLiteral(c))
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", 0, errors.New("unterminated [ in character class")
+}
+
+// classLiteral returns c for use as a literal in a regexp character class
+func classLiteral(c byte) string {
+	if strings.IndexByte(`\-]^[`, c) >= 0 {
+		return `\` + string(c)
+	}
+	return string(c)
+}
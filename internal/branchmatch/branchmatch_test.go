@@ -0,0 +1,128 @@
+package branchmatch
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPatternMatches(t *testing.T) {
+	tests := []struct {
+		pattern string
+		branch  string
+		want    bool
+	}{
+		{"main", "main", true},
+		{"main", "main2", false},
+		{"main", "feat/main", false},
+		{"release/*", "release/1.0", true},
+		{"release/*", "release/1.0/fix", false},
+		{"release/*", "release", false},
+		{"release/**", "release/1.0", true},
+		{"release/**", "release/1.0/fix", true},
+		{"release/**", "release", false},
+		{"**/wip", "wip", true},
+		{"**/wip", "feat/auth/wip", true},
+		{"**/wip", "feat/nowip", false},
+		{"feat/**/wip", "feat/wip", true},
+		{"feat/**/wip", "feat/a/b/wip", true},
+		{"hotfix-*", "hotfix-login", true},
+		{"hotfix-*", "hotfix/login", false},
+		{"hotfix-?", "hotfix-1", true},
+		{"hotfix-?", "hotfix-12", false},
+		{"v[0-9]*", "v2.1", true},
+		{"v[0-9]*", "vnext", false},
+		{"v[!0-9]*", "vnext", true},
+		{"v[^0-9]*", "v2", false},
+		{"a[!b]c", "a/c", false},
+		{"[]]", "]", true},
+		{`v[a\-z]`, "v-", true},
+		{`v[a\-z]`, "vb", false},
+		{"fix.*", "fix.1", true},
+		{"fix.*", "fixx1", false},
+		{`\!bang`, "!bang", true},
+		{`wip\*`, "wip*", true},
+		{`wip\*`, "wipx", false},
+		{`re:^v\d+$`, "v12", true},
+		{`re:^v\d+$`, "v12a", false},
+		{"re:exp", "release/experimental", true},
+		{"!release/old", "release/old", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.branch, func(t *testing.T) {
+			p, err := Compile(tt.pattern)
+			if err != nil {
+				t.Fatalf("Compile(%q) failed: %v", tt.pattern, err)
+			}
+			if got := p.Matches(tt.branch); got != tt.want {
+				t.Errorf("%q matches %q = %v, want %v", tt.pattern, tt.branch, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	tests := []struct {
+		pattern string
+		wantErr string
+	}{
+		{"", "empty branch pattern"},
+		{"!", "empty branch pattern"},
+		{"v[0-9", "unterminated ["},
+		{`wip\`, "trailing backslash"},
+		{"re:", "empty regular expression"},
+		{"re:(", "invalid branch pattern"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			err := Validate(tt.pattern)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate(%q) = %v, want error containing %q", tt.pattern, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMatcher(t *testing.T) {
+	m, err := New([]string{"release/**", "!release/experimental", "release/experimental/keep"})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	tests := []struct {
+		branch string
+		want   bool
+	}{
+		{"release/1.0", true},
+		{"release/experimental", false},
+		{"release/experimental/keep", true},
+		{"main", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := m.Match(tt.branch); got != tt.want {
+			t.Errorf("Match(%q) = %v, want %v", tt.branch, got, tt.want)
+		}
+	}
+
+	if matched, err := Match([]string{"!main"}, "main"); err != nil || matched {
+		t.Errorf("expected a lone negation to match nothing, got %v (%v)", matched, err)
+	}
+	if _, err := Match([]string{"main", "v[0-9"}, "main"); err == nil {
+		t.Error("expected an error for an invalid pattern")
+	}
+}
+
+func TestNewSkipInvalid(t *testing.T) {
+	m, errs := NewSkipInvalid([]string{"main", "v[0-9", "re:(", "release/*"})
+	if len(errs) != 2 {
+		t.Fatalf("expected 2 errors, got %v", errs)
+	}
+	if !strings.Contains(errs[0].Error(), `"v[0-9"`) || !strings.Contains(errs[1].Error(), `"re:("`) {
+		t.Errorf("unexpected errors: %v", errs)
+	}
+	if !m.Match("main") || !m.Match("release/1.0") || m.Match("v1") {
+		t.Error("expected the valid patterns to keep matching")
+	}
+}
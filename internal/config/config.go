@@ -5,10 +5,13 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/sqve/grove/internal/branchmatch"
 )
 
 // globalMu protects access to the Global struct
@@ -26,6 +29,9 @@ var Global struct {
 	StaleThreshold          string        // Default threshold for stale worktree detection (e.g., "30d")
 	AutoLockPatterns        []string      // Patterns for branches to auto-lock when creating worktrees
 	Timeout                 time.Duration // Command timeout (0 = no timeout)
+	Tmux                    bool          // Focus tmux windows instead of printing paths on switch
+	TmuxMode                string        // "window" or "session"
+	GitLabHosts             []string      // Self-hosted GitLab domains, in addition to gitlab.com
 }
 
 // DefaultConfig contains the default configuration values
@@ -36,16 +42,24 @@ var DefaultConfig = struct {
 	PreservePatterns        []string
 	PreserveExcludePatterns []string
 	PreserveDirectories     []string
+	PreserveCopyStrategy    string
+	WorktreePathTemplate    string
 	LinkPatterns            []string
 	StaleThreshold          string
 	AutoLockPatterns        []string
 	Timeout                 time.Duration
+	Tmux                    bool
+	TmuxMode                string
+	GitLabHosts             []string
+	DiffRedactPatterns      []string
 }{
 	Plain:          false,
 	Debug:          false,
 	NerdFonts:      true,
 	StaleThreshold: "30d",
 	Timeout:        30 * time.Second,
+	Tmux:           false,
+	TmuxMode:       "window",
 	PreservePatterns: []string{
 		".env",
 		".env.keys",
@@ -68,13 +82,27 @@ var DefaultConfig = struct {
 		"vendor",
 		"venv",
 	},
-	PreserveDirectories: []string{},
-	LinkPatterns:        []string{},
+	PreserveDirectories:  []string{},
+	PreserveCopyStrategy: "auto",
+	WorktreePathTemplate: "{{.Flat}}",
+	LinkPatterns:         []string{},
 	AutoLockPatterns: []string{
 		"develop",
 		"main",
 		"master",
 	},
+	GitLabHosts: []string{},
+	DiffRedactPatterns: []string{
+		"*CREDENTIAL*",
+		"*DATABASE_URL*",
+		"*DSN*",
+		"*KEY*",
+		"*PASSWD*",
+		"*PASSWORD*",
+		"*PRIVATE*",
+		"*SECRET*",
+		"*TOKEN*",
+	},
 }
 
 // IsPlain returns true if plain output mode is enabled
@@ -141,44 +169,90 @@ func GetTimeout() time.Duration {
 	return Global.Timeout
 }
 
-// ShouldAutoLock checks if a branch name matches any auto-lock pattern.
+// IsTmux returns true if switching should focus a tmux window or session
+func IsTmux() bool {
+	globalMu.RLock()
+	defer globalMu.RUnlock()
+	return Global.Tmux
+}
+
+// GetTmuxMode returns the configured tmux mode or default
+func GetTmuxMode() string {
+	globalMu.RLock()
+	defer globalMu.RUnlock()
+	if Global.TmuxMode != "" {
+		return Global.TmuxMode
+	}
+	return DefaultConfig.TmuxMode
+}
+
+// GetGitLabHosts returns the configured self-hosted GitLab domains.
+// Returns a copy to prevent callers from mutating the original slice.
+func GetGitLabHosts() []string {
+	globalMu.RLock()
+	defer globalMu.RUnlock()
+	return append([]string{}, Global.GitLabHosts...)
+}
+
+// autoLock caches the compiled auto-lock patterns, so that invalid patterns
+// warn once rather than on every branch checked
+var autoLock struct {
+	sync.Mutex
+	patterns []string
+	matcher  *branchmatch.Matcher
+}
+
+// ShouldAutoLock checks if a branch name matches the auto-lock patterns.
+// Invalid patterns are skipped with a warning; the others still match.
 func ShouldAutoLock(branch string) bool {
 	patterns := GetAutoLockPatterns()
-	for _, pattern := range patterns {
-		if pattern == branch || matchGlobPattern(pattern, branch) {
-			return true
+
+	autoLock.Lock()
+	defer autoLock.Unlock()
+	if autoLock.matcher == nil || !slices.Equal(autoLock.patterns, patterns) {
+		matcher, errs := branchmatch.NewSkipInvalid(autoLockPatterns(patterns))
+		for _, err := range errs {
+			fmt.Fprintf(os.Stderr, "Warning: grove.autoLock: %v (skipped)\n", err)
 		}
+		autoLock.patterns = patterns
+		autoLock.matcher = matcher
 	}
-	return false
+	return autoLock.matcher.Match(branch)
 }
 
-func matchGlobPattern(pattern, name string) bool {
-	if strings.HasSuffix(pattern, "/*") {
-		prefix := strings.TrimSuffix(pattern, "/*")
-		return strings.HasPrefix(name, prefix+"/")
+// autoLockPatterns keeps the meaning grove.autoLock has always given a
+// trailing /*: release/* locks release/1.0/fix too, as release/** does.
+// Elsewhere * stays within one segment, but a pattern that guards worktrees
+// against removal must not quietly stop matching.
+func autoLockPatterns(patterns []string) []string {
+	converted := make([]string, len(patterns))
+	for i, pattern := range patterns {
+		if strings.HasSuffix(pattern, "/*") && !strings.HasPrefix(strings.TrimPrefix(pattern, "!"), "re:") {
+			pattern += "*"
+		}
+		converted[i] = pattern
 	}
-	return pattern == name
+	return converted
+}
+
+// GetPostCloneHooks returns the post-clone hook commands from git config (grove.hooks.postClone).
+func GetPostCloneHooks() []string {
+	return getGitConfigs("grove.hooks.postClone")
+}
+
+// LoadDefaults resets configuration to defaults without reading git config,
+// for commands that must start faster than spawning git allows
+func LoadDefaults() {
+	globalMu.Lock()
+	defer globalMu.Unlock()
+	resetToDefaults()
 }
 
 // LoadFromGitConfig loads configuration from git config, merging with defaults
 func LoadFromGitConfig() {
 	globalMu.Lock()
 	defer globalMu.Unlock()
-	Global.Plain = DefaultConfig.Plain
-	Global.Debug = DefaultConfig.Debug
-	Global.NerdFonts = DefaultConfig.NerdFonts
-	Global.StaleThreshold = DefaultConfig.StaleThreshold
-	Global.Timeout = DefaultConfig.Timeout
-	Global.PreservePatterns = make([]string, len(DefaultConfig.PreservePatterns))
-	copy(Global.PreservePatterns, DefaultConfig.PreservePatterns)
-	Global.PreserveExcludePatterns = make([]string, len(DefaultConfig.PreserveExcludePatterns))
-	copy(Global.PreserveExcludePatterns, DefaultConfig.PreserveExcludePatterns)
-	Global.PreserveDirectories = make([]string, len(DefaultConfig.PreserveDirectories))
-	copy(Global.PreserveDirectories, DefaultConfig.PreserveDirectories)
-	Global.LinkPatterns = make([]string, len(DefaultConfig.LinkPatterns))
-	copy(Global.LinkPatterns, DefaultConfig.LinkPatterns)
-	Global.AutoLockPatterns = make([]string, len(DefaultConfig.AutoLockPatterns))
-	copy(Global.AutoLockPatterns, DefaultConfig.AutoLockPatterns)
+	resetToDefaults()
 
 	if value := getGitConfig("grove.plain"); value != "" {
 		Global.Plain = isTruthy(value)
@@ -205,6 +279,17 @@ func LoadFromGitConfig() {
 		}
 	}
 
+	if value := getGitConfig("grove.tmux"); value != "" {
+		Global.Tmux = isTruthy(value)
+	}
+
+	if value := getGitConfig("grove.tmuxMode"); value != "" {
+		if mode := strings.ToLower(value); mode == "window" || mode == "session" {
+			Global.TmuxMode = mode
+		}
+		// Invalid values are silently ignored, using default
+	}
+
 	patterns := getGitConfigs("grove.preserve")
 	if len(patterns) > 0 {
 		Global.PreservePatterns = patterns
@@ -229,6 +314,34 @@ func LoadFromGitConfig() {
 	if len(autoLockPatterns) > 0 {
 		Global.AutoLockPatterns = autoLockPatterns
 	}
+
+	gitlabHosts := getGitConfigs("grove.gitlabHost")
+	if len(gitlabHosts) > 0 {
+		Global.GitLabHosts = gitlabHosts
+	}
+}
+
+// resetToDefaults sets Global to the defaults. The caller holds globalMu.
+func resetToDefaults() {
+	Global.Plain = DefaultConfig.Plain
+	Global.Debug = DefaultConfig.Debug
+	Global.NerdFonts = DefaultConfig.NerdFonts
+	Global.StaleThreshold = DefaultConfig.StaleThreshold
+	Global.Timeout = DefaultConfig.Timeout
+	Global.Tmux = DefaultConfig.Tmux
+	Global.TmuxMode = DefaultConfig.TmuxMode
+	Global.PreservePatterns = make([]string, len(DefaultConfig.PreservePatterns))
+	copy(Global.PreservePatterns, DefaultConfig.PreservePatterns)
+	Global.PreserveExcludePatterns = make([]string, len(DefaultConfig.PreserveExcludePatterns))
+	copy(Global.PreserveExcludePatterns, DefaultConfig.PreserveExcludePatterns)
+	Global.PreserveDirectories = make([]string, len(DefaultConfig.PreserveDirectories))
+	copy(Global.PreserveDirectories, DefaultConfig.PreserveDirectories)
+	Global.LinkPatterns = make([]string, len(DefaultConfig.LinkPatterns))
+	copy(Global.LinkPatterns, DefaultConfig.LinkPatterns)
+	Global.AutoLockPatterns = make([]string, len(DefaultConfig.AutoLockPatterns))
+	copy(Global.AutoLockPatterns, DefaultConfig.AutoLockPatterns)
+	Global.GitLabHosts = make([]string, len(DefaultConfig.GitLabHosts))
+	copy(Global.GitLabHosts, DefaultConfig.GitLabHosts)
 }
 
 // getGitConfig gets a single config value, returns empty string if not found
@@ -236,6 +349,17 @@ func getGitConfig(key string) string {
 	return getGitConfigInDir(key, "")
 }
 
+// debugConfigErrors reports whether failed git config reads are logged.
+// LoadFromGitConfig holds globalMu while reading, so this does not wait for it
+// and stays quiet while configuration is loading.
+func debugConfigErrors() bool {
+	if !globalMu.TryRLock() {
+		return false
+	}
+	defer globalMu.RUnlock()
+	return Global.Debug
+}
+
 // getGitConfigInDir gets a single config value from a specific directory
 func getGitConfigInDir(key, dir string) string {
 	cmd := exec.Command("git", "config", "--get", key) //nolint:gosec
@@ -248,7 +372,7 @@ func getGitConfigInDir(key, dir string) string {
 		if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
 			return ""
 		}
-		if IsDebug() {
+		if debugConfigErrors() {
 			fmt.Fprintf(os.Stderr, "[DEBUG] git config error for %s: %v\n", key, err)
 		}
 		return ""
@@ -273,7 +397,7 @@ func getGitConfigsInDir(key, dir string) []string {
 		if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
 			return nil
 		}
-		if IsDebug() {
+		if debugConfigErrors() {
 			fmt.Fprintf(os.Stderr, "[DEBUG] git config error for %s: %v\n", key, err)
 		}
 		return nil
@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"os/exec"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -94,6 +95,9 @@ func resetGlobal() {
 	Global.StaleThreshold = ""
 	Global.AutoLockPatterns = nil
 	Global.Timeout = 0
+	Global.Tmux = false
+	Global.TmuxMode = ""
+	Global.GitLabHosts = nil
 }
 
 func TestLoadFromGitConfig(t *testing.T) {
@@ -523,6 +527,48 @@ func TestShouldAutoLock(t *testing.T) {
 			t.Error("Expected 'release' (without slash) not to match 'release/*'")
 		}
 	})
+
+	t.Run("matches recursive patterns and negations", func(t *testing.T) {
+		resetGlobal()
+		Global.AutoLockPatterns = []string{"release/**", "!release/experimental", "re:^v\\d+$"}
+
+		if !ShouldAutoLock("release/v1/hotfix") {
+			t.Error("Expected 'release/v1/hotfix' to match 'release/**'")
+		}
+		if ShouldAutoLock("release/experimental") {
+			t.Error("Expected 'release/experimental' to be excluded")
+		}
+		if !ShouldAutoLock("v2") {
+			t.Error("Expected 'v2' to match the regular expression")
+		}
+	})
+
+	t.Run("trailing /* also matches nested branches", func(t *testing.T) {
+		resetGlobal()
+		Global.AutoLockPatterns = []string{"release/*", "!release/old/*"}
+
+		if !ShouldAutoLock("release/1.0") || !ShouldAutoLock("release/1.0/fix") {
+			t.Error("Expected release/* to lock release branches at any depth")
+		}
+		if ShouldAutoLock("release/old/1.0") {
+			t.Error("Expected !release/old/* to exclude nested branches too")
+		}
+		if ShouldAutoLock("release") {
+			t.Error("Expected release/* not to match release itself")
+		}
+	})
+
+	t.Run("skips invalid patterns and keeps matching the rest", func(t *testing.T) {
+		resetGlobal()
+		Global.AutoLockPatterns = []string{"main", "v[0-9", "release/*"}
+
+		if !ShouldAutoLock("main") || !ShouldAutoLock("release/1.0") {
+			t.Error("Expected the valid patterns to still lock")
+		}
+		if ShouldAutoLock("v1") {
+			t.Error("Expected the invalid pattern to be skipped")
+		}
+	})
 }
 
 func TestGetTimeout(t *testing.T) {
@@ -559,3 +605,109 @@ func TestGetTimeout(t *testing.T) {
 		}
 	})
 }
+
+func TestLoadFromGitConfigTmux(t *testing.T) {
+	cleanup := setupGitRepo(t)
+	defer cleanup()
+
+	t.Run("defaults to disabled window mode", func(t *testing.T) {
+		resetGlobal()
+		LoadFromGitConfig()
+
+		if IsTmux() {
+			t.Error("Expected tmux to be disabled by default")
+		}
+		if mode := GetTmuxMode(); mode != "window" {
+			t.Errorf("Expected default mode 'window', got %q", mode)
+		}
+	})
+
+	t.Run("loads grove.tmux and grove.tmuxMode", func(t *testing.T) {
+		resetGlobal()
+
+		if err := exec.Command("git", "config", "grove.tmux", "true").Run(); err != nil {
+			t.Fatal(err)
+		}
+		if err := exec.Command("git", "config", "grove.tmuxMode", "Session").Run(); err != nil {
+			t.Fatal(err)
+		}
+		defer func() {
+			_ = exec.Command("git", "config", "--unset", "grove.tmux").Run()
+			_ = exec.Command("git", "config", "--unset", "grove.tmuxMode").Run()
+		}()
+
+		LoadFromGitConfig()
+
+		if !IsTmux() {
+			t.Error("Expected tmux to be enabled")
+		}
+		if mode := GetTmuxMode(); mode != "session" {
+			t.Errorf("Expected mode 'session', got %q", mode)
+		}
+	})
+
+	t.Run("ignores invalid grove.tmuxMode", func(t *testing.T) {
+		resetGlobal()
+
+		if err := exec.Command("git", "config", "grove.tmuxMode", "pane").Run(); err != nil {
+			t.Fatal(err)
+		}
+		defer func() { _ = exec.Command("git", "config", "--unset", "grove.tmuxMode").Run() }()
+
+		LoadFromGitConfig()
+
+		if mode := GetTmuxMode(); mode != "window" {
+			t.Errorf("Expected invalid mode to fall back to 'window', got %q", mode)
+		}
+	})
+}
+
+func TestLoadFromGitConfigGitLabHosts(t *testing.T) {
+	cleanup := setupGitRepo(t)
+	defer cleanup()
+
+	resetGlobal()
+	if err := exec.Command("git", "config", "--add", "grove.gitlabHost", "git.example.com").Run(); err != nil {
+		t.Fatal(err)
+	}
+	if err := exec.Command("git", "config", "--add", "grove.gitlabHost", "code.example.org").Run(); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = exec.Command("git", "config", "--unset-all", "grove.gitlabHost").Run() }()
+
+	LoadFromGitConfig()
+
+	hosts := GetGitLabHosts()
+	if len(hosts) != 2 || hosts[0] != "git.example.com" || hosts[1] != "code.example.org" {
+		t.Errorf("Expected configured GitLab hosts, got %v", hosts)
+	}
+}
+
+func TestLoadFromGitConfigBrokenRepository(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found in PATH, skipping test")
+	}
+
+	// A worktree whose repository moved points to a gitdir that is gone,
+	// which makes git config fail instead of reporting an unset key
+	dir := testutil.TempDir(t)
+	testutil.WriteFile(t, filepath.Join(dir, ".git"), "gitdir: /nonexistent/.bare/worktrees/main\n")
+	defer testutil.SaveCwd(t)()
+	testutil.Chdir(t, dir)
+
+	resetGlobal()
+	done := make(chan struct{})
+	go func() {
+		LoadFromGitConfig()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("LoadFromGitConfig did not return")
+	}
+	if !IsNerdFonts() {
+		t.Error("Expected defaults when git config fails")
+	}
+}
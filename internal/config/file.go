@@ -4,11 +4,17 @@ import (
 	_ "embed"
 	"errors"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/BurntSushi/toml"
+	"github.com/sqve/grove/internal/branchmatch"
 )
 
 const FileName = ".grove.toml"
@@ -18,23 +24,94 @@ var initTemplate string
 
 type FileConfig struct {
 	Preserve struct {
-		Patterns    []string `toml:"patterns"`
-		Exclude     []string `toml:"exclude"`
-		Directories []string `toml:"directories"`
+		Patterns     []string `toml:"patterns"`
+		Exclude      []string `toml:"exclude"`
+		Directories  []string `toml:"directories"`
+		CopyStrategy string   `toml:"copy_strategy"`
 	} `toml:"preserve"`
 	Link struct {
 		Patterns []string `toml:"patterns"`
 	} `toml:"link"`
 	Hooks struct {
-		Add []string `toml:"add"`
+		PreAdd     []string `toml:"pre_add"`
+		Add        []string `toml:"add"`
+		PostSwitch []string `toml:"post_switch"`
+		PreRemove  []string `toml:"pre_remove"`
+		PostRemove []string `toml:"post_remove"`
+		PostMove   []string `toml:"post_move"`
 	} `toml:"hooks"`
 	Autolock struct {
 		Patterns []string `toml:"patterns"`
 	} `toml:"autolock"`
-	Plain          *bool  `toml:"plain"`
-	Debug          *bool  `toml:"debug"`
-	NerdFonts      *bool  `toml:"nerd_fonts"`
-	StaleThreshold string `toml:"stale_threshold"`
+	Sparse struct {
+		Profiles map[string]SparseProfile `toml:"profiles"`
+	} `toml:"sparse"`
+	Worktree struct {
+		PathTemplate string `toml:"path_template"`
+	} `toml:"worktree"`
+	Diff struct {
+		Redact []string `toml:"redact"`
+	} `toml:"diff"`
+	Profiles       map[string]GitProfile `toml:"profiles"`
+	Seed           map[string]string     `toml:"seed"`
+	Env            map[string]EnvVar     `toml:"env"`
+	Plain          *bool                 `toml:"plain"`
+	Debug          *bool                 `toml:"debug"`
+	NerdFonts      *bool                 `toml:"nerd_fonts"`
+	StaleThreshold string                `toml:"stale_threshold"`
+}
+
+// SparseProfile is a named set of directories to check out in sparse worktrees
+type SparseProfile struct {
+	Paths []string `toml:"paths"`
+}
+
+// GitProfile is a set of git config values for worktrees whose branch
+// matches one of Branches
+type GitProfile struct {
+	Branches []string          `toml:"branches"`
+	Config   map[string]string `toml:"config"`
+}
+
+// GitConfigValue is a git config key and its value
+type GitConfigValue struct {
+	Key   string
+	Value string
+}
+
+// MatchedGitProfile is a [profiles] entry matching a branch, with its values
+// sorted by key
+type MatchedGitProfile struct {
+	Name   string
+	Values []GitConfigValue
+}
+
+// SeedRule seeds Directory of a new worktree from a sibling worktree whose
+// Lockfile has the same content
+type SeedRule struct {
+	Lockfile  string
+	Directory string
+}
+
+// EnvVar declares a value allocated to each worktree: a number from Range,
+// such as "3000-3999", or Template expanded for the worktree
+type EnvVar struct {
+	Range    string `toml:"range"`
+	Template string `toml:"template"`
+}
+
+// EnvSpec is a validated [env] entry. Min and Max are set for ranges,
+// Template for templates.
+type EnvSpec struct {
+	Name     string
+	Min      int
+	Max      int
+	Template string
+}
+
+// IsRange reports whether the value is allocated from a range
+func (s EnvSpec) IsRange() bool {
+	return s.Template == ""
 }
 
 // LoadFromFile returns empty config if file missing, error if file invalid.
@@ -124,6 +201,35 @@ func GetMergedPreserveExcludePatterns(worktreeDir string) []string {
 		DefaultConfig.PreserveExcludePatterns)
 }
 
+// GetMergedPreserveCopyStrategy: TOML > git config > default
+func GetMergedPreserveCopyStrategy(worktreeDir string) string {
+	if cfg, ok := loadConfigWithWarning(worktreeDir); ok && cfg.Preserve.CopyStrategy != "" {
+		return cfg.Preserve.CopyStrategy
+	}
+	if value := getGitConfigInDir("grove.preserveCopyStrategy", worktreeDir); value != "" {
+		return value
+	}
+	return DefaultConfig.PreserveCopyStrategy
+}
+
+// GetMergedWorktreePathTemplate: TOML > git config > default
+func GetMergedWorktreePathTemplate(worktreeDir string) string {
+	if cfg, ok := loadConfigWithWarning(worktreeDir); ok && cfg.Worktree.PathTemplate != "" {
+		return cfg.Worktree.PathTemplate
+	}
+	if value := getGitConfigInDir("grove.worktreePathTemplate", worktreeDir); value != "" {
+		return value
+	}
+	return DefaultConfig.WorktreePathTemplate
+}
+
+// GetMergedDiffRedactPatterns: TOML > git config > defaults
+func GetMergedDiffRedactPatterns(worktreeDir string) []string {
+	return getMergedPatterns(worktreeDir, "grove.diffRedact",
+		func(cfg FileConfig) []string { return cfg.Diff.Redact },
+		DefaultConfig.DiffRedactPatterns)
+}
+
 // GetMergedPlain: git config > TOML > default
 func GetMergedPlain(worktreeDir string) bool {
 	return getMergedBool(worktreeDir, "grove.plain",
@@ -138,6 +244,194 @@ func GetMergedDebug(worktreeDir string) bool {
 		DefaultConfig.Debug)
 }
 
+// GetSparseProfile returns the directories of the named sparse profile in the
+// .grove.toml of worktreeDir. Paths are cleaned and must be relative to the
+// repository root.
+func GetSparseProfile(worktreeDir, name string) ([]string, error) {
+	cfg, err := LoadFromFile(worktreeDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", FileName, err)
+	}
+
+	profile, ok := cfg.Sparse.Profiles[name]
+	if !ok {
+		names := SparseProfileNames(cfg)
+		if len(names) == 0 {
+			return nil, fmt.Errorf("unknown sparse profile %q: no profiles configured in %s", name, FileName)
+		}
+		return nil, fmt.Errorf("unknown sparse profile %q (available: %s)", name, strings.Join(names, ", "))
+	}
+
+	var paths []string
+	for _, p := range profile.Paths {
+		clean, ok := cleanRepoPath(p)
+		if !ok {
+			return nil, fmt.Errorf("sparse profile %q: invalid path %q (must be a directory inside the repository)", name, p)
+		}
+		paths = append(paths, clean)
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("sparse profile %q has no paths", name)
+	}
+	return paths, nil
+}
+
+// GetSeedRules returns the [seed] rules in the .grove.toml of worktreeDir,
+// sorted by lockfile. Paths are cleaned and must be relative to the
+// repository root.
+func GetSeedRules(worktreeDir string) ([]SeedRule, error) {
+	cfg, err := LoadFromFile(worktreeDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", FileName, err)
+	}
+
+	rules := make([]SeedRule, 0, len(cfg.Seed))
+	for lockfile, dir := range cfg.Seed {
+		cleanLockfile, ok := cleanRepoPath(lockfile)
+		if !ok {
+			return nil, fmt.Errorf("seed: invalid lockfile %q (must be a file inside the repository)", lockfile)
+		}
+		cleanDir, ok := cleanRepoPath(dir)
+		if !ok {
+			return nil, fmt.Errorf("seed: invalid directory %q for %s (must be a directory inside the repository)", dir, lockfile)
+		}
+		rules = append(rules, SeedRule{Lockfile: cleanLockfile, Directory: cleanDir})
+	}
+	sort.Slice(rules, func(i, j int) bool { return rules[i].Lockfile < rules[j].Lockfile })
+	return rules, nil
+}
+
+// GetGitProfiles returns the [profiles] entries in the .grove.toml of
+// worktreeDir whose branch patterns match branch, sorted by name. Patterns
+// follow the branchmatch package, like autolock patterns. A profile with an
+// invalid pattern or key is skipped with a warning; the others still apply.
+func GetGitProfiles(worktreeDir, branch string) ([]MatchedGitProfile, error) {
+	cfg, err := LoadFromFile(worktreeDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", FileName, err)
+	}
+
+	var matched []MatchedGitProfile
+	for _, name := range slices.Sorted(maps.Keys(cfg.Profiles)) {
+		values, ok, err := matchGitProfile(cfg.Profiles[name], branch)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: profile %q: %v (skipped)\n", name, err)
+			continue
+		}
+		if ok {
+			matched = append(matched, MatchedGitProfile{Name: name, Values: values})
+		}
+	}
+	return matched, nil
+}
+
+// matchGitProfile returns the config of profile sorted by key when its
+// branch patterns match branch
+func matchGitProfile(profile GitProfile, branch string) ([]GitConfigValue, bool, error) {
+	ok, err := branchmatch.Match(profile.Branches, branch)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+
+	values := make([]GitConfigValue, 0, len(profile.Config))
+	for key, value := range profile.Config {
+		if !isGitConfigKey(key) {
+			return nil, false, fmt.Errorf("invalid git config key %q (expected section.name)", key)
+		}
+		values = append(values, GitConfigValue{Key: key, Value: value})
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i].Key < values[j].Key })
+	return values, true, nil
+}
+
+// isGitConfigKey reports whether key has the section.name form git config
+// expects. Git validates the rest when the value is written.
+func isGitConfigKey(key string) bool {
+	dot := strings.IndexByte(key, '.')
+	return dot > 0 && !strings.HasSuffix(key, ".") && !strings.ContainsAny(key, " \t\n")
+}
+
+// GetEnvSpecs returns the [env] entries in the .grove.toml of worktreeDir,
+// sorted by name
+func GetEnvSpecs(worktreeDir string) ([]EnvSpec, error) {
+	cfg, err := LoadFromFile(worktreeDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", FileName, err)
+	}
+
+	specs := make([]EnvSpec, 0, len(cfg.Env))
+	for name, v := range cfg.Env {
+		if !isEnvName(name) {
+			return nil, fmt.Errorf("env: invalid variable name %q", name)
+		}
+		hasRange := strings.TrimSpace(v.Range) != ""
+		if hasRange == (v.Template != "") {
+			return nil, fmt.Errorf("env: %s must set exactly one of range or template", name)
+		}
+
+		spec := EnvSpec{Name: name, Template: v.Template}
+		if hasRange {
+			spec.Min, spec.Max, err = parseEnvRange(v.Range)
+			if err != nil {
+				return nil, fmt.Errorf("env: %s: %w", name, err)
+			}
+		}
+		specs = append(specs, spec)
+	}
+	sort.Slice(specs, func(i, j int) bool { return specs[i].Name < specs[j].Name })
+	return specs, nil
+}
+
+// isEnvName reports whether name is a valid environment variable name
+func isEnvName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, r := range name {
+		switch {
+		case r == '_', r >= 'A' && r <= 'Z', r >= 'a' && r <= 'z':
+		case r >= '0' && r <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func parseEnvRange(s string) (lo, hi int, err error) {
+	loStr, hiStr, ok := strings.Cut(strings.TrimSpace(s), "-")
+	if ok {
+		lo, err = strconv.Atoi(strings.TrimSpace(loStr))
+	}
+	if ok && err == nil {
+		hi, err = strconv.Atoi(strings.TrimSpace(hiStr))
+	}
+	if !ok || err != nil || lo < 0 || lo > hi {
+		return 0, 0, fmt.Errorf("invalid range %q (expected e.g. \"3000-3999\")", s)
+	}
+	return lo, hi, nil
+}
+
+// cleanRepoPath cleans p and reports whether it is a path inside the
+// repository, relative to its root
+func cleanRepoPath(p string) (string, bool) {
+	clean := filepath.ToSlash(filepath.Clean(strings.TrimSpace(p)))
+	if clean == "." || clean == ".." || strings.HasPrefix(clean, "../") || strings.HasPrefix(clean, "/") || filepath.IsAbs(p) {
+		return "", false
+	}
+	return clean, true
+}
+
+// SparseProfileNames returns the sparse profile names of cfg in sorted order
+func SparseProfileNames(cfg FileConfig) []string {
+	names := make([]string, 0, len(cfg.Sparse.Profiles))
+	for name := range cfg.Sparse.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 // WriteToFile uses atomic write (temp file + rename) to prevent corruption.
 func WriteToFile(dir string, cfg *FileConfig) error {
 	path := filepath.Join(dir, FileName)
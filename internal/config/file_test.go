@@ -4,6 +4,8 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/sqve/grove/internal/testutil"
@@ -439,6 +441,24 @@ exclude = ["vendor", ".cache"]
 		}
 	})
 
+	t.Run("GetMergedDiffRedactPatterns uses TOML first", func(t *testing.T) {
+		tmpDir, cleanup := setupGitRepoForFileTests(t)
+		defer cleanup()
+
+		_ = exec.Command("git", "config", "grove.diffRedact", "*PASS*").Run() //nolint:gosec
+		if patterns := GetMergedDiffRedactPatterns(tmpDir); len(patterns) != 1 || patterns[0] != "*PASS*" {
+			t.Errorf("Expected git config patterns, got %v", patterns)
+		}
+
+		tomlContent := `[diff]
+redact = ["STRIPE_*"]
+`
+		_ = os.WriteFile(filepath.Join(tmpDir, ".grove.toml"), []byte(tomlContent), 0o644) //nolint:gosec
+		if patterns := GetMergedDiffRedactPatterns(tmpDir); len(patterns) != 1 || patterns[0] != "STRIPE_*" {
+			t.Errorf("Expected TOML patterns, got %v", patterns)
+		}
+	})
+
 	t.Run("defaults to empty when no link config", func(t *testing.T) {
 		tmpDir, cleanup := setupGitRepoForFileTests(t)
 		defer cleanup()
@@ -529,4 +549,292 @@ directories = ["toml-dir"]
 			t.Errorf("Expected default patterns, got %v", patterns)
 		}
 	})
+
+	t.Run("TOML takes precedence for preserve copy strategy", func(t *testing.T) {
+		tmpDir, cleanup := setupGitRepoForFileTests(t)
+		defer cleanup()
+
+		_ = exec.Command("git", "config", "grove.preserveCopyStrategy", "copy").Run() //nolint:gosec
+		tomlContent := `[preserve]
+copy_strategy = "hardlink"
+`
+		_ = os.WriteFile(filepath.Join(tmpDir, ".grove.toml"), []byte(tomlContent), 0o644) //nolint:gosec
+
+		if strategy := GetMergedPreserveCopyStrategy(tmpDir); strategy != "hardlink" {
+			t.Errorf("Expected TOML copy strategy, got %q", strategy)
+		}
+	})
+
+	t.Run("git config used when no TOML preserve copy strategy", func(t *testing.T) {
+		tmpDir, cleanup := setupGitRepoForFileTests(t)
+		defer cleanup()
+
+		_ = exec.Command("git", "config", "grove.preserveCopyStrategy", "reflink").Run() //nolint:gosec
+
+		if strategy := GetMergedPreserveCopyStrategy(tmpDir); strategy != "reflink" {
+			t.Errorf("Expected git config copy strategy, got %q", strategy)
+		}
+	})
+
+	t.Run("preserve copy strategy defaults to auto", func(t *testing.T) {
+		tmpDir, cleanup := setupGitRepoForFileTests(t)
+		defer cleanup()
+
+		if strategy := GetMergedPreserveCopyStrategy(tmpDir); strategy != "auto" {
+			t.Errorf("Expected default copy strategy auto, got %q", strategy)
+		}
+	})
+
+	t.Run("TOML takes precedence for worktree path template", func(t *testing.T) {
+		tmpDir, cleanup := setupGitRepoForFileTests(t)
+		defer cleanup()
+
+		_ = exec.Command("git", "config", "grove.worktreePathTemplate", "{{.Branch}}").Run() //nolint:gosec
+		tomlContent := `[worktree]
+path_template = "{{.Prefix}}/{{.Name}}"
+`
+		_ = os.WriteFile(filepath.Join(tmpDir, ".grove.toml"), []byte(tomlContent), 0o644) //nolint:gosec
+
+		if tmpl := GetMergedWorktreePathTemplate(tmpDir); tmpl != "{{.Prefix}}/{{.Name}}" {
+			t.Errorf("Expected TOML path template, got %q", tmpl)
+		}
+	})
+
+	t.Run("worktree path template defaults to flat names", func(t *testing.T) {
+		tmpDir, cleanup := setupGitRepoForFileTests(t)
+		defer cleanup()
+
+		if tmpl := GetMergedWorktreePathTemplate(tmpDir); tmpl != "{{.Flat}}" {
+			t.Errorf("Expected default path template, got %q", tmpl)
+		}
+	})
+}
+
+func TestGetSparseProfile(t *testing.T) {
+	tmpDir := testutil.TempDir(t)
+	tomlContent := `[sparse.profiles.web]
+paths = ["apps/web/", "packages/ui"]
+
+[sparse.profiles.api]
+paths = ["services/api"]
+
+[sparse.profiles.empty]
+paths = []
+
+[sparse.profiles.escape]
+paths = ["../outside"]
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, FileName), []byte(tomlContent), 0o644); err != nil { //nolint:gosec
+		t.Fatal(err)
+	}
+
+	t.Run("returns cleaned paths", func(t *testing.T) {
+		paths, err := GetSparseProfile(tmpDir, "web")
+		if err != nil {
+			t.Fatalf("GetSparseProfile failed: %v", err)
+		}
+		if !slices.Equal(paths, []string{"apps/web", "packages/ui"}) {
+			t.Errorf("GetSparseProfile = %v, want [apps/web packages/ui]", paths)
+		}
+	})
+
+	t.Run("lists available profiles for unknown name", func(t *testing.T) {
+		_, err := GetSparseProfile(tmpDir, "docs")
+		if err == nil || !strings.Contains(err.Error(), "available: api, empty, escape, web") {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("rejects profile without paths", func(t *testing.T) {
+		if _, err := GetSparseProfile(tmpDir, "empty"); err == nil {
+			t.Error("expected error for empty profile")
+		}
+	})
+
+	t.Run("rejects paths outside the repository", func(t *testing.T) {
+		if _, err := GetSparseProfile(tmpDir, "escape"); err == nil {
+			t.Error("expected error for path outside repository")
+		}
+	})
+
+	t.Run("reports missing config", func(t *testing.T) {
+		_, err := GetSparseProfile(testutil.TempDir(t), "web")
+		if err == nil || !strings.Contains(err.Error(), "no profiles configured") {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+}
+
+func TestGetSeedRules(t *testing.T) {
+	writeConfig := func(t *testing.T, content string) string {
+		t.Helper()
+		dir := testutil.TempDir(t)
+		if err := os.WriteFile(filepath.Join(dir, FileName), []byte(content), 0o644); err != nil { //nolint:gosec
+			t.Fatal(err)
+		}
+		return dir
+	}
+
+	t.Run("returns rules sorted by lockfile", func(t *testing.T) {
+		dir := writeConfig(t, `[seed]
+"package-lock.json" = "node_modules/"
+"Cargo.lock" = "target"
+`)
+		rules, err := GetSeedRules(dir)
+		if err != nil {
+			t.Fatalf("GetSeedRules failed: %v", err)
+		}
+		want := []SeedRule{{Lockfile: "Cargo.lock", Directory: "target"}, {Lockfile: "package-lock.json", Directory: "node_modules"}}
+		if !slices.Equal(rules, want) {
+			t.Errorf("GetSeedRules = %v, want %v", rules, want)
+		}
+	})
+
+	t.Run("rejects directories outside the repository", func(t *testing.T) {
+		dir := writeConfig(t, `[seed]
+"package-lock.json" = "../node_modules"
+`)
+		if _, err := GetSeedRules(dir); err == nil || !strings.Contains(err.Error(), "invalid directory") {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("returns nothing without config", func(t *testing.T) {
+		rules, err := GetSeedRules(testutil.TempDir(t))
+		if err != nil || len(rules) != 0 {
+			t.Errorf("expected no rules, got %v (err: %v)", rules, err)
+		}
+	})
+}
+
+func TestGetGitProfiles(t *testing.T) {
+	writeConfig := func(t *testing.T, content string) string {
+		t.Helper()
+		dir := testutil.TempDir(t)
+		if err := os.WriteFile(filepath.Join(dir, FileName), []byte(content), 0o644); err != nil { //nolint:gosec
+			t.Fatal(err)
+		}
+		return dir
+	}
+
+	dir := writeConfig(t, `[profiles.oss]
+branches = ["oss/*", "docs"]
+config = { "user.email" = "me@users.noreply.github.com", "core.hooksPath" = ".githooks" }
+
+[profiles.lfs]
+branches = ["oss/*"]
+[profiles.lfs.config]
+"lfs.fetchexclude" = "assets"
+`)
+
+	t.Run("returns matching profiles sorted by name", func(t *testing.T) {
+		profiles, err := GetGitProfiles(dir, "oss/cli")
+		if err != nil {
+			t.Fatalf("GetGitProfiles failed: %v", err)
+		}
+		if len(profiles) != 2 || profiles[0].Name != "lfs" || profiles[1].Name != "oss" {
+			t.Fatalf("expected lfs and oss, got %+v", profiles)
+		}
+		want := []GitConfigValue{{Key: "core.hooksPath", Value: ".githooks"}, {Key: "user.email", Value: "me@users.noreply.github.com"}}
+		if !slices.Equal(profiles[1].Values, want) {
+			t.Errorf("oss values = %v, want %v", profiles[1].Values, want)
+		}
+	})
+
+	t.Run("matches exact branches", func(t *testing.T) {
+		profiles, err := GetGitProfiles(dir, "docs")
+		if err != nil || len(profiles) != 1 || profiles[0].Name != "oss" {
+			t.Errorf("expected oss, got %+v (err: %v)", profiles, err)
+		}
+	})
+
+	t.Run("returns nothing for other branches", func(t *testing.T) {
+		profiles, err := GetGitProfiles(dir, "feat/oss")
+		if err != nil || len(profiles) != 0 {
+			t.Errorf("expected no profiles, got %+v (err: %v)", profiles, err)
+		}
+	})
+
+	skipped := func(t *testing.T, bad string) {
+		t.Helper()
+		dir := writeConfig(t, bad+`
+[profiles.good]
+branches = ["main"]
+config = { "user.email" = "me@example.com" }
+`)
+		profiles, err := GetGitProfiles(dir, "main")
+		if err != nil {
+			t.Fatalf("GetGitProfiles failed: %v", err)
+		}
+		if len(profiles) != 1 || profiles[0].Name != "good" {
+			t.Errorf("expected only the good profile, got %+v", profiles)
+		}
+	}
+
+	t.Run("skips profiles with invalid patterns", func(t *testing.T) {
+		skipped(t, `[profiles.bad]
+branches = ["main", "v[0-9"]
+config = { "user.name" = "Bad" }
+`)
+	})
+
+	t.Run("skips profiles with keys without a section", func(t *testing.T) {
+		skipped(t, `[profiles.bad]
+branches = ["main"]
+config = { email = "me@example.com" }
+`)
+	})
+}
+
+func TestGetEnvSpecs(t *testing.T) {
+	writeConfig := func(t *testing.T, content string) string {
+		t.Helper()
+		dir := testutil.TempDir(t)
+		if err := os.WriteFile(filepath.Join(dir, FileName), []byte(content), 0o644); err != nil { //nolint:gosec
+			t.Fatal(err)
+		}
+		return dir
+	}
+
+	t.Run("returns specs sorted by name", func(t *testing.T) {
+		dir := writeConfig(t, `[env]
+PORT = { range = "3000-3999" }
+DB_NAME = { template = "app_{{branch_slug}}" }
+`)
+		specs, err := GetEnvSpecs(dir)
+		if err != nil {
+			t.Fatalf("GetEnvSpecs failed: %v", err)
+		}
+		want := []EnvSpec{
+			{Name: "DB_NAME", Template: "app_{{branch_slug}}"},
+			{Name: "PORT", Min: 3000, Max: 3999},
+		}
+		if !slices.Equal(specs, want) {
+			t.Errorf("GetEnvSpecs = %v, want %v", specs, want)
+		}
+	})
+
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"invalid name", `[env]
+"1PORT" = { range = "3000-3999" }`, "invalid variable name"},
+		{"range and template", `[env]
+PORT = { range = "3000-3999", template = "3000" }`, "exactly one"},
+		{"neither range nor template", `[env]
+PORT = {}`, "exactly one"},
+		{"reversed range", `[env]
+PORT = { range = "3999-3000" }`, "invalid range"},
+		{"malformed range", `[env]
+PORT = { range = "3000" }`, "invalid range"},
+	}
+	for _, tt := range tests {
+		t.Run("rejects "+tt.name, func(t *testing.T) {
+			if _, err := GetEnvSpecs(writeConfig(t, tt.content)); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
 }
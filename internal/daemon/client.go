@@ -0,0 +1,145 @@
+package daemon
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/sqve/grove/internal/git"
+)
+
+const (
+	// dialTimeout keeps commands fast when no daemon is running
+	dialTimeout = 200 * time.Millisecond
+
+	// callTimeout covers a refresh of every worktree before the daemon answers
+	callTimeout = 30 * time.Second
+)
+
+// ErrNotRunning is returned by Dial when no daemon serves the workspace
+var ErrNotRunning = errors.New("daemon is not running")
+
+// Client is a connection to the daemon of a workspace
+type Client struct {
+	conn    net.Conn
+	scanner *bufio.Scanner
+	nextID  int
+}
+
+// Dial connects to the daemon for the workspace of bareDir
+func Dial(bareDir string) (*Client, error) {
+	conn, err := net.DialTimeout("unix", SocketPath(bareDir), dialTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNotRunning, err)
+	}
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*maxMessageSize)
+	return &Client{conn: conn, scanner: scanner}, nil
+}
+
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// Call invokes method and decodes its result into result, which may be nil
+func (c *Client) Call(method string, params, result any) error {
+	c.nextID++
+	id := json.RawMessage(strconv.Itoa(c.nextID))
+
+	req := Message{JSONRPC: "2.0", ID: id, Method: method}
+	if params != nil {
+		data, err := json.Marshal(params)
+		if err != nil {
+			return err
+		}
+		req.Params = data
+	}
+
+	if err := c.conn.SetDeadline(time.Now().Add(callTimeout)); err != nil {
+		return err
+	}
+	defer func() { _ = c.conn.SetDeadline(time.Time{}) }()
+
+	if err := json.NewEncoder(c.conn).Encode(req); err != nil {
+		return err
+	}
+
+	for {
+		msg, err := c.read()
+		if err != nil {
+			return err
+		}
+		if string(msg.ID) != string(id) {
+			continue // Notification or stale response
+		}
+		if msg.Error != nil {
+			return msg.Error
+		}
+		if result == nil {
+			return nil
+		}
+		return json.Unmarshal(msg.Result, result)
+	}
+}
+
+// List returns the cached info of all worktrees
+func (c *Client) List() ([]*git.WorktreeInfo, error) {
+	var result ListResult
+	if err := c.Call(MethodList, nil, &result); err != nil {
+		return nil, err
+	}
+	return result.Worktrees, nil
+}
+
+// Status decodes the status of the worktree containing path into status
+func (c *Client) Status(path string, status any) error {
+	return c.Call(MethodStatus, StatusParams{Path: path}, status)
+}
+
+// Subscribe calls fn with the current worktrees and again whenever they
+// change. Blocks until the connection closes or fn returns an error.
+func (c *Client) Subscribe(fn func([]*git.WorktreeInfo) error) error {
+	if err := c.Call(MethodSubscribe, nil, nil); err != nil {
+		return err
+	}
+
+	for {
+		msg, err := c.read()
+		if err != nil {
+			return err
+		}
+		if msg.Method != NotifyWorktrees {
+			continue
+		}
+		var result ListResult
+		if err := json.Unmarshal(msg.Params, &result); err != nil {
+			return err
+		}
+		if err := fn(result.Worktrees); err != nil {
+			return err
+		}
+	}
+}
+
+// Shutdown asks the daemon to stop
+func (c *Client) Shutdown() error {
+	return c.Call(MethodShutdown, nil, nil)
+}
+
+func (c *Client) read() (*Message, error) {
+	if !c.scanner.Scan() {
+		if err := c.scanner.Err(); err != nil {
+			return nil, err
+		}
+		return nil, errors.New("daemon closed the connection")
+	}
+	var msg Message
+	if err := json.Unmarshal(c.scanner.Bytes(), &msg); err != nil {
+		return nil, err
+	}
+	return &msg, nil
+}
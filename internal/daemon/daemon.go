@@ -0,0 +1,112 @@
+// Package daemon caches worktree info for a workspace in a long-running
+// process and serves it over a Unix socket with JSON-RPC 2.0, one message per
+// line.
+package daemon
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/sqve/grove/internal/fs"
+	"github.com/sqve/grove/internal/git"
+)
+
+// Methods served by the daemon
+const (
+	MethodList      = "list"      // Result: ListResult
+	MethodStatus    = "status"    // Params: StatusParams, result: status of the worktree
+	MethodSubscribe = "subscribe" // Result: null, followed by worktrees notifications
+	MethodShutdown  = "shutdown"  // Result: null
+
+	// NotifyWorktrees is sent to subscribers with ListResult as params, once
+	// when subscribing and again whenever the worktrees change
+	NotifyWorktrees = "worktrees"
+)
+
+// JSON-RPC error codes
+const (
+	CodeParseError     = -32700
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeServerError    = -32000
+)
+
+// Message is a JSON-RPC 2.0 request, response or notification
+type Message struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+// Error is a JSON-RPC error object
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// ListResult is the result of list and the params of worktrees notifications
+type ListResult struct {
+	Worktrees []*git.WorktreeInfo `json:"worktrees"`
+}
+
+// StatusParams selects the worktree containing Path
+type StatusParams struct {
+	Path string `json:"path"`
+}
+
+// SocketPath returns the socket of the daemon for the workspace of bareDir.
+// Sockets live in a directory of their own rather than the workspace, since
+// paths in deep workspaces can exceed the length limit of Unix socket
+// addresses.
+func SocketPath(bareDir string) string {
+	if abs, err := filepath.Abs(bareDir); err == nil {
+		bareDir = abs
+	}
+	if resolved, err := filepath.EvalSymlinks(bareDir); err == nil {
+		bareDir = resolved
+	}
+	sum := sha256.Sum256([]byte(bareDir))
+	return filepath.Join(socketDir(), fmt.Sprintf("%x.sock", sum[:8]))
+}
+
+// socketDir returns the directory holding the sockets of the current user:
+// grove in $XDG_RUNTIME_DIR, or a per-user directory in the shared temp
+// directory on systems without one
+func socketDir() string {
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); filepath.IsAbs(dir) {
+		return filepath.Join(dir, "grove")
+	}
+	return filepath.Join(os.TempDir(), fmt.Sprintf("grove-%d", os.Getuid()))
+}
+
+// ensureSocketDir creates dir for the current user only. An existing dir
+// must be a private directory of the current user, since whoever can write
+// to it can put their own socket in place of the daemon's.
+func ensureSocketDir(dir string) error {
+	if err := os.MkdirAll(dir, fs.DirPrivate); err != nil {
+		return fmt.Errorf("failed to create socket directory: %w", err)
+	}
+	info, err := os.Lstat(dir)
+	if err != nil {
+		return fmt.Errorf("failed to check socket directory: %w", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("socket directory %s is not a directory", dir)
+	}
+	return checkPrivate(dir, info)
+}
+
+// LogPath returns where a daemon started in the background writes its output
+func LogPath(bareDir string) string {
+	return filepath.Join(bareDir, "grove", "daemon.log")
+}
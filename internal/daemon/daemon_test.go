@@ -0,0 +1,356 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sqve/grove/internal/fs"
+	"github.com/sqve/grove/internal/git"
+	"github.com/sqve/grove/internal/testutil"
+)
+
+// fakeWorkspace is a bare directory with a worktree registry, listed by a
+// function that reports the registered worktree names
+type fakeWorkspace struct {
+	bareDir     string
+	lists       atomic.Int32
+	dirtyChecks atomic.Int32
+	dirty       sync.Map // Paths of worktrees with changes
+}
+
+func newFakeWorkspace(t *testing.T, names ...string) *fakeWorkspace {
+	t.Helper()
+	ws := &fakeWorkspace{bareDir: filepath.Join(testutil.TempDir(t), ".bare")}
+	for _, name := range names {
+		ws.addWorktree(t, name)
+	}
+	return ws
+}
+
+func (ws *fakeWorkspace) addWorktree(t *testing.T, name string) {
+	t.Helper()
+	dir := filepath.Join(ws.bareDir, "worktrees", name)
+	if err := os.MkdirAll(dir, fs.DirGit); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "index"), []byte(name), fs.FileGit); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func (ws *fakeWorkspace) list() ([]*git.WorktreeInfo, error) {
+	ws.lists.Add(1)
+	entries, err := os.ReadDir(filepath.Join(ws.bareDir, "worktrees"))
+	if err != nil {
+		return nil, err
+	}
+	var infos []*git.WorktreeInfo
+	for _, entry := range entries {
+		infos = append(infos, &git.WorktreeInfo{
+			Path:   filepath.Join(filepath.Dir(ws.bareDir), entry.Name()),
+			Branch: entry.Name(),
+		})
+	}
+	return infos, nil
+}
+
+func (ws *fakeWorkspace) status(path string) (any, error) {
+	_, dirty := ws.dirty.Load(path)
+	return map[string]any{"path": path, "dirty": dirty}, nil
+}
+
+func (ws *fakeWorkspace) isDirty(path string) (bool, error) {
+	ws.dirtyChecks.Add(1)
+	_, dirty := ws.dirty.Load(path)
+	return dirty, nil
+}
+
+// serve starts a server for ws and returns a connected client
+func serve(t *testing.T, ws *fakeWorkspace) *Client {
+	t.Helper()
+	return serveServer(t, NewServer(ws.bareDir, ws.list, ws.status, ws.isDirty))
+}
+
+// serveServer starts server and returns a connected client
+func serveServer(t *testing.T, server *Server) *Client {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- server.Serve(ctx) }()
+	t.Cleanup(func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("Serve failed: %v", err)
+		}
+	})
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		client, err := Dial(server.bareDir)
+		if err == nil {
+			t.Cleanup(func() { _ = client.Close() })
+			return client
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("daemon did not start: %v", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestDialWithoutDaemon(t *testing.T) {
+	t.Parallel()
+
+	ws := newFakeWorkspace(t)
+	if _, err := Dial(ws.bareDir); !errors.Is(err, ErrNotRunning) {
+		t.Errorf("expected ErrNotRunning, got %v", err)
+	}
+}
+
+func TestServerList(t *testing.T) {
+	t.Parallel()
+
+	ws := newFakeWorkspace(t, "main")
+	client := serve(t, ws)
+
+	worktrees, err := client.List()
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(worktrees) != 1 || worktrees[0].Branch != "main" {
+		t.Fatalf("expected main, got %+v", worktrees)
+	}
+
+	// Unchanged metadata is served from the cache
+	before := ws.lists.Load()
+	if _, err := client.List(); err != nil {
+		t.Fatal(err)
+	}
+	if ws.lists.Load() != before {
+		t.Error("expected cached worktrees to be served without listing")
+	}
+
+	// A new worktree is picked up by the next request without waiting for
+	// the watcher
+	ws.addWorktree(t, "feat")
+	worktrees, err = client.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(worktrees) != 2 {
+		t.Errorf("expected 2 worktrees after adding one, got %+v", worktrees)
+	}
+}
+
+func TestServerListDirty(t *testing.T) {
+	t.Parallel()
+
+	ws := newFakeWorkspace(t, "main", "feat")
+	server := NewServer(ws.bareDir, ws.list, ws.status, ws.isDirty)
+	server.dirtyTTL = 50 * time.Millisecond
+	client := serveServer(t, server)
+
+	if _, err := client.List(); err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if got := ws.dirtyChecks.Load(); got != 2 {
+		t.Fatalf("expected one dirty check per worktree, got %d", got)
+	}
+
+	// A burst of requests reuses the dirty state
+	ws.dirty.Store(filepath.Join(filepath.Dir(ws.bareDir), "main"), true)
+	if _, err := client.List(); err != nil {
+		t.Fatal(err)
+	}
+	if got := ws.dirtyChecks.Load(); got != 2 {
+		t.Errorf("expected cached dirty state within the TTL, got %d checks", got)
+	}
+
+	// Edits touch no watched file, yet show up as dirty once it expires
+	time.Sleep(server.dirtyTTL)
+	before := ws.lists.Load()
+	worktrees, err := client.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ws.lists.Load() != before {
+		t.Error("expected dirty state to be checked without listing")
+	}
+	for _, info := range worktrees {
+		if info.Dirty != (info.Branch == "main") {
+			t.Errorf("expected only main to be dirty, got %s dirty=%v", info.Branch, info.Dirty)
+		}
+	}
+}
+
+func TestServerStatus(t *testing.T) {
+	t.Parallel()
+
+	ws := newFakeWorkspace(t, "main")
+	client := serve(t, ws)
+	worktree := filepath.Join(filepath.Dir(ws.bareDir), "main")
+
+	var status map[string]any
+	if err := client.Status(filepath.Join(worktree, "src"), &status); err != nil {
+		t.Fatalf("Status failed: %v", err)
+	}
+	if status["path"] != worktree || status["dirty"] != false {
+		t.Errorf("expected clean status of %s, got %v", worktree, status)
+	}
+
+	// Status is read on every request, so edits show up right away
+	ws.dirty.Store(worktree, true)
+	if err := client.Status(worktree, &status); err != nil {
+		t.Fatal(err)
+	}
+	if status["dirty"] != true {
+		t.Errorf("expected dirty status after an edit, got %v", status)
+	}
+
+	var rpcErr *Error
+	err := client.Status(filepath.Join(filepath.Dir(ws.bareDir), "other"), &status)
+	if !errors.As(err, &rpcErr) || rpcErr.Code != CodeInvalidParams {
+		t.Errorf("expected invalid params error outside worktrees, got %v", err)
+	}
+
+	if err := client.Call("nope", nil, nil); !errors.As(err, &rpcErr) || rpcErr.Code != CodeMethodNotFound {
+		t.Errorf("expected method not found error, got %v", err)
+	}
+}
+
+func TestServerSubscribe(t *testing.T) {
+	t.Parallel()
+
+	ws := newFakeWorkspace(t, "main")
+	client := serve(t, ws)
+
+	var mu sync.Mutex
+	var updates [][]*git.WorktreeInfo
+	received := make(chan struct{}, 10)
+	go func() {
+		_ = client.Subscribe(func(worktrees []*git.WorktreeInfo) error {
+			mu.Lock()
+			updates = append(updates, worktrees)
+			mu.Unlock()
+			received <- struct{}{}
+			return nil
+		})
+	}()
+
+	wait := func(want int) {
+		t.Helper()
+		timeout := time.After(10 * time.Second)
+		for {
+			mu.Lock()
+			got := len(updates)
+			var last []*git.WorktreeInfo
+			if got > 0 {
+				last = updates[got-1]
+			}
+			mu.Unlock()
+			if len(last) == want {
+				return
+			}
+			select {
+			case <-received:
+			case <-timeout:
+				t.Fatalf("expected an update with %d worktrees, got %d updates", want, got)
+			}
+		}
+	}
+
+	wait(1)
+	ws.addWorktree(t, "feat")
+	wait(2)
+}
+
+func TestServerShutdown(t *testing.T) {
+	t.Parallel()
+
+	ws := newFakeWorkspace(t, "main")
+	server := NewServer(ws.bareDir, ws.list, ws.status, ws.isDirty)
+	done := make(chan error, 1)
+	go func() { done <- server.Serve(context.Background()) }()
+
+	var client *Client
+	for i := 0; client == nil; i++ {
+		if i == 500 {
+			t.Fatal("daemon did not start")
+		}
+		time.Sleep(10 * time.Millisecond)
+		client, _ = Dial(ws.bareDir)
+	}
+	defer func() { _ = client.Close() }()
+
+	if err := NewServer(ws.bareDir, ws.list, ws.status, ws.isDirty).Serve(context.Background()); !errors.Is(err, ErrRunning) {
+		t.Errorf("expected second daemon to fail with ErrRunning, got %v", err)
+	}
+
+	if err := client.Shutdown(); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Serve failed: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("daemon did not stop")
+	}
+	if fs.PathExists(SocketPath(ws.bareDir)) {
+		t.Error("expected socket to be removed")
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	t.Parallel()
+
+	ws := newFakeWorkspace(t, "main")
+	before := fingerprint(watchedDirs(ws.bareDir))
+	if again := fingerprint(watchedDirs(ws.bareDir)); again != before {
+		t.Fatal("expected fingerprint to be stable")
+	}
+
+	index := filepath.Join(ws.bareDir, "worktrees", "main", "index")
+	if err := os.WriteFile(index, []byte("changed index"), fs.FileGit); err != nil {
+		t.Fatal(err)
+	}
+	if fingerprint(watchedDirs(ws.bareDir)) == before {
+		t.Error("expected fingerprint to change with the index")
+	}
+}
+
+func TestEnsureSocketDir(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join(testutil.TempDir(t), "grove")
+	if err := ensureSocketDir(dir); err != nil {
+		t.Fatalf("ensureSocketDir failed: %v", err)
+	}
+	if runtime.GOOS == "windows" {
+		return
+	}
+	info, err := os.Stat(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != fs.DirPrivate {
+		t.Errorf("expected socket directory mode %o, got %o", fs.DirPrivate, perm)
+	}
+
+	// A directory others can write to could hold someone else's socket
+	if err := os.Chmod(dir, 0o777); err != nil { //nolint:gosec // Deliberately insecure
+		t.Fatal(err)
+	}
+	if err := ensureSocketDir(dir); err == nil {
+		t.Error("expected a directory accessible by other users to be rejected")
+	}
+}
@@ -0,0 +1,440 @@
+package daemon
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/sqve/grove/internal/fs"
+	"github.com/sqve/grove/internal/git"
+	"github.com/sqve/grove/internal/logger"
+)
+
+const (
+	// debounceDelay lets a burst of file changes, such as a checkout,
+	// settle into a single refresh
+	debounceDelay = 100 * time.Millisecond
+
+	// refreshInterval bounds how long edits that leave the index untouched
+	// take to reach subscribers as dirty. Requests check it within dirtyTTL.
+	refreshInterval = 30 * time.Second
+
+	// dirtyTTL is how long a request reuses the dirty state of a worktree, so
+	// a burst of requests runs git status once per worktree
+	dirtyTTL = 2 * time.Second
+
+	// maxMessageSize bounds a single request line
+	maxMessageSize = 1 << 20
+)
+
+// ErrRunning is returned by Serve when a daemon already serves the workspace
+var ErrRunning = errors.New("daemon is already running")
+
+// ListFunc reads the info of all worktrees
+type ListFunc func() ([]*git.WorktreeInfo, error)
+
+// StatusFunc reads the detailed status of the worktree at path
+type StatusFunc func(path string) (any, error)
+
+// DirtyFunc reports whether the worktree at path has uncommitted changes
+type DirtyFunc func(path string) (bool, error)
+
+// Server caches worktree info and serves it to clients
+type Server struct {
+	bareDir string
+	list    ListFunc
+	status  StatusFunc
+	dirty   DirtyFunc
+
+	// refreshMu serializes refreshes, which run git without holding mu
+	refreshMu  sync.Mutex
+	dirtyTTL   time.Duration
+	dirtyCache map[string]dirtyState
+
+	mu          sync.Mutex
+	worktrees   []*git.WorktreeInfo
+	stamp       string
+	subscribers map[chan []*git.WorktreeInfo]struct{}
+	cancel      context.CancelFunc
+}
+
+// dirtyState is the dirty state of a worktree and when it was checked
+type dirtyState struct {
+	dirty   bool
+	checked time.Time
+}
+
+// NewServer returns a server for the workspace of bareDir
+func NewServer(bareDir string, list ListFunc, status StatusFunc, dirty DirtyFunc) *Server {
+	return &Server{
+		bareDir:     bareDir,
+		list:        list,
+		status:      status,
+		dirty:       dirty,
+		dirtyTTL:    dirtyTTL,
+		dirtyCache:  make(map[string]dirtyState),
+		subscribers: make(map[chan []*git.WorktreeInfo]struct{}),
+	}
+}
+
+// Serve listens on the socket of the workspace until ctx is cancelled, a
+// client asks for shutdown, or the workspace is deleted
+func (s *Server) Serve(ctx context.Context) error {
+	if client, err := Dial(s.bareDir); err == nil {
+		_ = client.Close()
+		return ErrRunning
+	}
+
+	socket := SocketPath(s.bareDir)
+	if err := ensureSocketDir(filepath.Dir(socket)); err != nil {
+		return err
+	}
+
+	// Nothing answered, so a socket left behind by a crashed daemon is stale
+	if err := os.Remove(socket); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove stale socket: %w", err)
+	}
+
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", socket, err)
+	}
+	defer func() { _ = listener.Close() }()
+
+	// Connections close when ctx is cancelled, so cancel before waiting
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	s.mu.Lock()
+	s.cancel = cancel
+	s.mu.Unlock()
+
+	if err := s.refresh(true); err != nil {
+		return err
+	}
+
+	w, err := newWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to watch workspace: %w", err)
+	}
+	defer func() { _ = w.Close() }()
+	w.Watch(watchedDirs(s.bareDir))
+	go s.watch(ctx, w)
+
+	go func() {
+		<-ctx.Done()
+		_ = listener.Close()
+	}()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.handle(ctx, conn)
+		}()
+	}
+}
+
+// watch refreshes the cache after changes settle, and periodically for edits
+// that no watched file reflects
+func (s *Server) watch(ctx context.Context, w watcher) {
+	debounce := time.NewTimer(debounceDelay)
+	debounce.Stop()
+	ticker := time.NewTicker(refreshInterval)
+	defer ticker.Stop()
+
+	refresh := func(force bool) {
+		if !fs.DirectoryExists(s.bareDir) {
+			logger.Info("Workspace was removed, stopping")
+			s.shutdown()
+			return
+		}
+		if err := s.refresh(force); err != nil {
+			logger.Warning("Failed to refresh worktrees: %v", err)
+		}
+		w.Watch(watchedDirs(s.bareDir))
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case _, ok := <-w.Changes():
+			if !ok {
+				return
+			}
+			debounce.Reset(debounceDelay)
+		case <-debounce.C:
+			refresh(false)
+		case <-ticker.C:
+			refresh(true)
+		}
+	}
+}
+
+// refresh reloads the worktrees when the watched files changed since the
+// last load, or always with force. Subscribers are notified of changes.
+func (s *Server) refresh(force bool) error {
+	s.refreshMu.Lock()
+	defer s.refreshMu.Unlock()
+
+	stamp := fingerprint(watchedDirs(s.bareDir))
+	s.mu.Lock()
+	current := s.worktrees != nil && stamp == s.stamp
+	s.mu.Unlock()
+	if !force && current {
+		return nil
+	}
+
+	worktrees, err := s.list()
+	if err != nil {
+		return err
+	}
+	if worktrees == nil {
+		worktrees = []*git.WorktreeInfo{}
+	}
+	clear(s.dirtyCache)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stamp = stamp
+	s.publish(worktrees)
+	return nil
+}
+
+// refreshDirty rereads whether each worktree has uncommitted changes. Edits
+// to files in a worktree touch none of the watched files, so dirty state is
+// only reused for dirtyTTL.
+func (s *Server) refreshDirty() {
+	s.refreshMu.Lock()
+	defer s.refreshMu.Unlock()
+
+	s.mu.Lock()
+	worktrees := make([]*git.WorktreeInfo, len(s.worktrees))
+	for i, info := range s.worktrees {
+		fresh := *info
+		worktrees[i] = &fresh
+	}
+	s.mu.Unlock()
+
+	now := time.Now()
+	states := make([]dirtyState, len(worktrees))
+	git.ForEachWorktree(len(worktrees), func(i int) {
+		path := worktrees[i].Path
+		if state, ok := s.dirtyCache[path]; ok && now.Sub(state.checked) < s.dirtyTTL {
+			states[i] = state
+			return
+		}
+		dirty, err := s.dirty(path)
+		if err != nil {
+			logger.Debug("Failed to check %s for changes: %v", path, err)
+			states[i] = dirtyState{dirty: worktrees[i].Dirty}
+			return
+		}
+		states[i] = dirtyState{dirty: dirty, checked: now}
+	})
+
+	// Rebuilt from the current worktrees, which drops removed ones
+	cache := make(map[string]dirtyState, len(worktrees))
+	for i, info := range worktrees {
+		info.Dirty = states[i].dirty
+		if !states[i].checked.IsZero() {
+			cache[info.Path] = states[i]
+		}
+	}
+	s.dirtyCache = cache
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.publish(worktrees)
+}
+
+// publish caches worktrees and notifies subscribers when they changed.
+// Callers hold s.mu.
+func (s *Server) publish(worktrees []*git.WorktreeInfo) {
+	changed := !reflect.DeepEqual(worktrees, s.worktrees)
+	s.worktrees = worktrees
+
+	if changed {
+		logger.Debug("Worktrees changed, notifying %d subscribers", len(s.subscribers))
+		for ch := range s.subscribers {
+			notifyLatest(ch, worktrees)
+		}
+	}
+}
+
+// notifyLatest queues worktrees on ch, replacing an update the subscriber
+// has not picked up yet
+func notifyLatest(ch chan []*git.WorktreeInfo, worktrees []*git.WorktreeInfo) {
+	for {
+		select {
+		case ch <- worktrees:
+			return
+		default:
+			select {
+			case <-ch:
+			default:
+			}
+		}
+	}
+}
+
+func (s *Server) shutdown() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cancel != nil {
+		s.cancel()
+	}
+}
+
+// handle serves requests from a single connection until it is closed
+func (s *Server) handle(ctx context.Context, conn net.Conn) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		<-ctx.Done()
+		_ = conn.Close()
+	}()
+
+	var writeMu sync.Mutex
+	write := func(msg Message) {
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		msg.JSONRPC = "2.0"
+		if err := json.NewEncoder(conn).Encode(msg); err != nil {
+			cancel()
+		}
+	}
+
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxMessageSize)
+	for scanner.Scan() {
+		var req Message
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			write(Message{ID: json.RawMessage("null"), Error: &Error{Code: CodeParseError, Message: "parse error"}})
+			continue
+		}
+
+		result, rpcErr := s.call(req)
+		if req.ID == nil {
+			continue // Notifications get no response
+		}
+		if rpcErr != nil {
+			write(Message{ID: req.ID, Error: rpcErr})
+			continue
+		}
+		data, err := json.Marshal(result)
+		if err != nil {
+			write(Message{ID: req.ID, Error: &Error{Code: CodeServerError, Message: err.Error()}})
+			continue
+		}
+		write(Message{ID: req.ID, Result: data})
+
+		switch req.Method {
+		case MethodSubscribe:
+			go s.subscribe(ctx, write)
+		case MethodShutdown:
+			s.shutdown() // After responding, since shutdown closes the connection
+		}
+	}
+}
+
+// call runs the method of req and returns its result
+func (s *Server) call(req Message) (any, *Error) {
+	switch req.Method {
+	case MethodList:
+		if err := s.refresh(false); err != nil {
+			return nil, &Error{Code: CodeServerError, Message: err.Error()}
+		}
+		s.refreshDirty()
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return ListResult{Worktrees: s.worktrees}, nil
+
+	case MethodStatus:
+		var params StatusParams
+		if err := json.Unmarshal(req.Params, &params); err != nil || params.Path == "" {
+			return nil, &Error{Code: CodeInvalidParams, Message: "status needs the path of a worktree"}
+		}
+		if err := s.refresh(false); err != nil {
+			return nil, &Error{Code: CodeServerError, Message: err.Error()}
+		}
+		return s.worktreeStatus(params.Path)
+
+	case MethodSubscribe, MethodShutdown:
+		return nil, nil // Handled once the response is written
+
+	default:
+		return nil, &Error{Code: CodeMethodNotFound, Message: fmt.Sprintf("method not found: %s", req.Method)}
+	}
+}
+
+// worktreeStatus reads the status of the cached worktree containing path.
+// The status is mostly changed files, so it is read on every request rather
+// than cached.
+func (s *Server) worktreeStatus(path string) (any, *Error) {
+	s.mu.Lock()
+	var worktree string
+	for _, info := range s.worktrees {
+		if fs.PathsEqual(path, info.Path) || fs.PathHasPrefix(path, info.Path) {
+			worktree = info.Path
+			break
+		}
+	}
+	s.mu.Unlock()
+	if worktree == "" {
+		return nil, &Error{Code: CodeInvalidParams, Message: fmt.Sprintf("not inside a worktree: %s", path)}
+	}
+
+	status, err := s.status(worktree)
+	if err != nil {
+		return nil, &Error{Code: CodeServerError, Message: err.Error()}
+	}
+	return status, nil
+}
+
+// subscribe sends the current worktrees, then every change, until the
+// connection closes
+func (s *Server) subscribe(ctx context.Context, write func(Message)) {
+	ch := make(chan []*git.WorktreeInfo, 1)
+
+	s.mu.Lock()
+	s.subscribers[ch] = struct{}{}
+	ch <- s.worktrees
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.subscribers, ch)
+		s.mu.Unlock()
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case worktrees := <-ch:
+			params, err := json.Marshal(ListResult{Worktrees: worktrees})
+			if err != nil {
+				continue
+			}
+			write(Message{Method: NotifyWorktrees, Params: params})
+		}
+	}
+}
@@ -0,0 +1,21 @@
+//go:build !windows
+
+package daemon
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// checkPrivate fails unless dir belongs to the current user and no one else
+// can access it
+func checkPrivate(dir string, info os.FileInfo) error {
+	if st, ok := info.Sys().(*syscall.Stat_t); ok && int(st.Uid) != os.Getuid() {
+		return fmt.Errorf("socket directory %s belongs to another user", dir)
+	}
+	if info.Mode().Perm()&0o077 != 0 {
+		return fmt.Errorf("socket directory %s is accessible by other users", dir)
+	}
+	return nil
+}
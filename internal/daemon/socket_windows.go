@@ -0,0 +1,9 @@
+package daemon
+
+import "os"
+
+// checkPrivate accepts dir, since Windows guards the user's temp directory
+// with ACLs rather than permission bits
+func checkPrivate(_ string, _ os.FileInfo) error {
+	return nil
+}
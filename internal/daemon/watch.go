@@ -0,0 +1,66 @@
+package daemon
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// watcher reports changes to the files in a set of directories. Changes are
+// coalesced, so one value on Changes may stand for many changed files.
+type watcher interface {
+	// Watch replaces the watched directories
+	Watch(dirs []string)
+	Changes() <-chan struct{}
+	Close() error
+}
+
+// watchedDirs returns the directories worktree info is read from: the bare
+// repository, the worktree registry with each worktree's HEAD, index and lock
+// files, and the refs that sync status compares.
+func watchedDirs(bareDir string) []string {
+	registry := filepath.Join(bareDir, "worktrees")
+	dirs := []string{bareDir, registry}
+
+	entries, _ := os.ReadDir(registry)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			dirs = append(dirs, filepath.Join(registry, entry.Name()))
+		}
+	}
+
+	for _, refs := range []string{"heads", "remotes"} {
+		_ = filepath.WalkDir(filepath.Join(bareDir, "refs", refs), func(path string, d fs.DirEntry, err error) error {
+			if err == nil && d.IsDir() {
+				dirs = append(dirs, path)
+			}
+			return nil
+		})
+	}
+
+	return dirs
+}
+
+// fingerprint summarizes the files in dirs. It changes whenever a file in
+// them is created, removed or written, which costs a few stat calls instead
+// of running git.
+func fingerprint(dirs []string) string {
+	h := sha256.New()
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			info, err := entry.Info()
+			if err != nil {
+				continue
+			}
+			_, _ = fmt.Fprintf(h, "%s\x00%d\x00%d\n", filepath.Join(dir, entry.Name()), info.Size(), info.ModTime().UnixNano())
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
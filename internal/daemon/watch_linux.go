@@ -0,0 +1,108 @@
+package daemon
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+
+	"golang.org/x/sys/unix"
+)
+
+const inotifyMask = unix.IN_CREATE | unix.IN_DELETE | unix.IN_MODIFY | unix.IN_ATTRIB |
+	unix.IN_CLOSE_WRITE | unix.IN_MOVED_FROM | unix.IN_MOVED_TO
+
+// inotifyPollTimeout bounds how long Close waits for the reader to notice
+const inotifyPollTimeout = 200 // Milliseconds
+
+// inotifyWatcher watches directories with inotify
+type inotifyWatcher struct {
+	fd      int
+	mu      sync.Mutex
+	watches map[string]int
+	changes chan struct{}
+	closed  atomic.Bool
+	done    chan struct{}
+}
+
+func newWatcher() (watcher, error) {
+	fd, err := unix.InotifyInit1(unix.IN_CLOEXEC | unix.IN_NONBLOCK)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &inotifyWatcher{
+		fd:      fd,
+		watches: make(map[string]int),
+		changes: make(chan struct{}, 1),
+		done:    make(chan struct{}),
+	}
+	go w.read()
+	return w, nil
+}
+
+func (w *inotifyWatcher) Watch(dirs []string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	wanted := make(map[string]bool, len(dirs))
+	for _, dir := range dirs {
+		wanted[dir] = true
+		if _, ok := w.watches[dir]; ok {
+			continue
+		}
+		wd, err := unix.InotifyAddWatch(w.fd, dir, inotifyMask)
+		if err != nil {
+			continue // Removed since it was listed
+		}
+		w.watches[dir] = wd
+	}
+
+	for dir, wd := range w.watches {
+		if !wanted[dir] {
+			_, _ = unix.InotifyRmWatch(w.fd, uint32(wd)) //nolint:gosec // Watch descriptors are non-negative
+			delete(w.watches, dir)
+		}
+	}
+}
+
+func (w *inotifyWatcher) Changes() <-chan struct{} {
+	return w.changes
+}
+
+func (w *inotifyWatcher) Close() error {
+	if w.closed.Swap(true) {
+		return nil
+	}
+	<-w.done
+	return unix.Close(w.fd)
+}
+
+// read drains events and signals a change for each batch. Event details are
+// not needed, since every change leads to the same refresh.
+func (w *inotifyWatcher) read() {
+	defer close(w.done)
+	defer close(w.changes)
+
+	buf := make([]byte, 64*1024)
+	fds := []unix.PollFd{{Fd: int32(w.fd), Events: unix.POLLIN}} //nolint:gosec // File descriptors fit in int32
+	for !w.closed.Load() {
+		n, err := unix.Poll(fds, inotifyPollTimeout)
+		if err != nil && !errors.Is(err, unix.EINTR) {
+			return
+		}
+		if n <= 0 {
+			continue
+		}
+
+		read, err := unix.Read(w.fd, buf)
+		if err != nil && !errors.Is(err, unix.EAGAIN) && !errors.Is(err, unix.EINTR) {
+			return
+		}
+		if read > 0 {
+			select {
+			case w.changes <- struct{}{}:
+			default: // A change is already pending
+			}
+		}
+	}
+}
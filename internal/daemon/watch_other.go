@@ -0,0 +1,78 @@
+//go:build !linux
+
+package daemon
+
+import (
+	"sync"
+	"time"
+)
+
+// pollInterval is how often directories are checked without inotify
+const pollInterval = time.Second
+
+// pollWatcher compares fingerprints of the watched directories, since
+// inotify is only available on Linux
+type pollWatcher struct {
+	mu      sync.Mutex
+	dirs    []string
+	changes chan struct{}
+	stop    chan struct{}
+	done    chan struct{}
+	once    sync.Once
+}
+
+func newWatcher() (watcher, error) {
+	w := &pollWatcher{
+		changes: make(chan struct{}, 1),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go w.poll()
+	return w, nil
+}
+
+func (w *pollWatcher) Watch(dirs []string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.dirs = dirs
+}
+
+func (w *pollWatcher) Changes() <-chan struct{} {
+	return w.changes
+}
+
+func (w *pollWatcher) Close() error {
+	w.once.Do(func() { close(w.stop) })
+	<-w.done
+	return nil
+}
+
+func (w *pollWatcher) poll() {
+	defer close(w.done)
+	defer close(w.changes)
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	last := ""
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+		}
+
+		w.mu.Lock()
+		dirs := w.dirs
+		w.mu.Unlock()
+
+		current := fingerprint(dirs)
+		if last != "" && current != last {
+			select {
+			case w.changes <- struct{}{}:
+			default: // A change is already pending
+			}
+		}
+		last = current
+	}
+}
@@ -0,0 +1,71 @@
+package forge
+
+import (
+	"errors"
+	"net"
+	"strings"
+
+	"github.com/sqve/grove/internal/config"
+	"github.com/sqve/grove/internal/github"
+	"github.com/sqve/grove/internal/gitlab"
+)
+
+// Kind identifies the hosting service behind a remote
+type Kind string
+
+const (
+	Unknown Kind = ""
+	GitHub  Kind = "github"
+	GitLab  Kind = "gitlab"
+)
+
+// Host returns the lowercased host of a remote URL without any port, or an
+// empty string for local paths and unrecognised URLs.
+func Host(remoteURL string) string {
+	ref, err := gitlab.ParseRepoURL(remoteURL)
+	if err != nil {
+		return ""
+	}
+	host := ref.Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return strings.ToLower(host)
+}
+
+// Detect returns the forge hosting remoteURL. github.com and gitlab.com are
+// recognised out of the box; self-hosted GitLab domains come from
+// grove.gitlabHost.
+func Detect(remoteURL string) Kind {
+	host := Host(remoteURL)
+	switch host {
+	case "":
+		return Unknown
+	case "github.com":
+		return GitHub
+	case "gitlab.com":
+		return GitLab
+	}
+
+	for _, h := range config.GetGitLabHosts() {
+		if strings.EqualFold(host, strings.TrimSpace(h)) {
+			return GitLab
+		}
+	}
+	return Unknown
+}
+
+// MergedBranches returns the branches whose pull or merge requests have been
+// merged on the forge hosting remoteURL. Remotes that are not recognised as
+// GitLab are queried through gh, which resolves the repository itself.
+func MergedBranches(repoPath, remoteURL string) (map[string]bool, error) {
+	if Detect(remoteURL) != GitLab {
+		return github.GetMergedPRBranches(repoPath)
+	}
+
+	repo, err := gitlab.ParseRepoURL(remoteURL)
+	if err != nil {
+		return nil, errors.New("cannot determine GitLab project from remote URL")
+	}
+	return gitlab.GetMergedMRBranches(repo)
+}
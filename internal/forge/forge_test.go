@@ -0,0 +1,55 @@
+package forge
+
+import (
+	"testing"
+
+	"github.com/sqve/grove/internal/config"
+)
+
+func TestHost(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"git@github.com:owner/repo.git", "github.com"},
+		{"https://GitLab.com/group/repo", "gitlab.com"},
+		{"ssh://git@git.example.com:2222/group/repo.git", "git.example.com"},
+		{"https://git.example.com:8443/group/repo.git", "git.example.com"},
+		{"/srv/git/repo.git", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := Host(tt.input); got != tt.want {
+				t.Errorf("Host(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetect(t *testing.T) {
+	orig := config.Global.GitLabHosts
+	config.Global.GitLabHosts = []string{"Git.Example.com"}
+	defer func() { config.Global.GitLabHosts = orig }()
+
+	tests := []struct {
+		input string
+		want  Kind
+	}{
+		{"https://github.com/owner/repo.git", GitHub},
+		{"git@gitlab.com:group/sub/repo.git", GitLab},
+		{"git@git.example.com:group/repo.git", GitLab},
+		{"https://git.example.com:8443/group/repo", GitLab},
+		{"https://bitbucket.org/owner/repo", Unknown},
+		{"file:///srv/git/repo", Unknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := Detect(tt.input); got != tt.want {
+				t.Errorf("Detect(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
@@ -119,6 +119,27 @@ func SubItemPrefix() string {
 // WorktreeRow formats a single worktree row for list/status output
 // Format: marker name [branch] indicators
 func WorktreeRow(info *git.WorktreeInfo, isCurrent bool, namePadWidth, branchPadWidth int) string {
+	return StackedWorktreeRow(info, isCurrent, 0, namePadWidth, branchPadWidth)
+}
+
+// StackedWorktreeRow formats a worktree row like WorktreeRow, with the name
+// indented by depth levels of a branch stack. namePadWidth includes the
+// indentation.
+func StackedWorktreeRow(info *git.WorktreeInfo, isCurrent bool, depth, namePadWidth, branchPadWidth int) string {
+	return worktreeRow(info, isCurrent, "", depth, namePadWidth, branchPadWidth)
+}
+
+// RepoWorktreeRow formats a worktree row like WorktreeRow for a
+// multi-repository project, with the repository name padded to repoPadWidth
+// as the first column
+func RepoWorktreeRow(repo string, repoPadWidth int, info *git.WorktreeInfo, isCurrent bool, namePadWidth, branchPadWidth int) string {
+	if len(repo) < repoPadWidth {
+		repo += strings.Repeat(" ", repoPadWidth-len(repo))
+	}
+	return worktreeRow(info, isCurrent, repo, 0, namePadWidth, branchPadWidth)
+}
+
+func worktreeRow(info *git.WorktreeInfo, isCurrent bool, repo string, depth, namePadWidth, branchPadWidth int) string {
 	marker := CurrentMarker(isCurrent)
 	dirty := Dirty(info.Dirty)
 	lock := Lock(info.Locked)
@@ -130,9 +151,10 @@ func WorktreeRow(info *git.WorktreeInfo, isCurrent bool, namePadWidth, branchPad
 		sync = Sync(info.Ahead, info.Behind, !info.NoUpstream)
 	}
 
-	// Worktree name (directory basename)
+	// Worktree name (directory basename), indented by stack depth
+	indent := StackIndent(depth)
 	name := filepath.Base(info.Path)
-	nameLen := utf8.RuneCountInString(name)
+	nameLen := utf8.RuneCountInString(indent + name)
 	nameDisplay := name
 	if namePadWidth > 0 && nameLen < namePadWidth {
 		nameDisplay = name + strings.Repeat(" ", namePadWidth-nameLen)
@@ -155,7 +177,11 @@ func WorktreeRow(info *git.WorktreeInfo, isCurrent bool, namePadWidth, branchPad
 		branchDisplay += strings.Repeat(" ", branchPadWidth-branchVisibleLen)
 	}
 
-	parts := []string{marker, styles.Render(&styles.Worktree, nameDisplay), branchDisplay}
+	parts := []string{marker}
+	if repo != "" {
+		parts = append(parts, repo)
+	}
+	parts = append(parts, indent+styles.Render(&styles.Worktree, nameDisplay), branchDisplay)
 
 	indicators := []string{}
 	if lock != "" {
@@ -175,6 +201,38 @@ func WorktreeRow(info *git.WorktreeInfo, isCurrent bool, namePadWidth, branchPad
 	return strings.Join(parts, " ")
 }
 
+// StackIndent returns the indentation of a worktree depth levels down a
+// branch stack
+func StackIndent(depth int) string {
+	return strings.Repeat("  ", depth)
+}
+
+// StackTreePrefix returns the connector drawn before a branch in a stack
+// tree. ancestorsLast tells for each level above the branch, below the root,
+// whether that ancestor was the last of its siblings; last tells whether the
+// branch itself is.
+func StackTreePrefix(ancestorsLast []bool, last bool) string {
+	pipe, tee, elbow := "│  ", "├─ ", "└─ "
+	if config.IsPlain() {
+		pipe, tee, elbow = "|  ", "|- ", "`- "
+	}
+
+	var b strings.Builder
+	for _, ancestorLast := range ancestorsLast {
+		if ancestorLast {
+			b.WriteString("   ")
+		} else {
+			b.WriteString(pipe)
+		}
+	}
+	if last {
+		b.WriteString(elbow)
+	} else {
+		b.WriteString(tee)
+	}
+	return styles.Render(&styles.Dimmed, b.String())
+}
+
 // WorktreeLabel returns a simple label for a worktree: "directory [branch]"
 func WorktreeLabel(info *git.WorktreeInfo) string {
 	dir := filepath.Base(info.Path)
@@ -199,5 +257,9 @@ func VerboseSubItems(info *git.WorktreeInfo) []string {
 		items = append(items, fmt.Sprintf("    %s lock reason: %s", prefix, info.LockReason))
 	}
 
+	if info.Sparse != "" {
+		items = append(items, fmt.Sprintf("    %s sparse: %s", prefix, info.Sparse))
+	}
+
 	return items
 }
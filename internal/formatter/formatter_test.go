@@ -323,6 +323,45 @@ func TestWorktreeRow(t *testing.T) {
 	}
 }
 
+func TestStackedWorktreeRow(t *testing.T) {
+	config.Global.Plain = true
+	config.Global.NerdFonts = false
+
+	info := &git.WorktreeInfo{Branch: "feat/b", Path: "/tmp/feat-b"}
+	got := StackedWorktreeRow(info, false, 2, 12, 0)
+
+	// 4 spaces of indentation, then the name padded to 12 including them
+	if !strings.Contains(got, "    feat-b   [feat/b]") {
+		t.Errorf("StackedWorktreeRow() = %q, want name indented by 2 levels", got)
+	}
+}
+
+func TestStackTreePrefix(t *testing.T) {
+	tests := []struct {
+		name          string
+		ancestorsLast []bool
+		last          bool
+		plain         bool
+		want          string
+	}{
+		{"first child", nil, false, false, "├─ "},
+		{"last child", nil, true, false, "└─ "},
+		{"grandchild under middle child", []bool{false}, true, false, "│  └─ "},
+		{"grandchild under last child", []bool{true}, false, false, "   ├─ "},
+		{"plain", []bool{false}, true, true, "|  `- "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config.Global.Plain = tt.plain
+
+			if got := StackTreePrefix(tt.ancestorsLast, tt.last); !strings.Contains(got, tt.want) {
+				t.Errorf("StackTreePrefix() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestWorktreeLabel(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -422,6 +461,28 @@ func TestVerboseSubItems(t *testing.T) {
 		}
 	})
 
+	t.Run("includes sparse profile when set", func(t *testing.T) {
+		config.Global.Plain = true
+		info := &git.WorktreeInfo{
+			Branch: "feature",
+			Path:   "/tmp/workspace/feature",
+			Sparse: "web, docs",
+		}
+
+		items := VerboseSubItems(info)
+
+		found := false
+		for _, item := range items {
+			if strings.Contains(item, "sparse: web, docs") {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("VerboseSubItems() missing sparse item, got %v", items)
+		}
+	})
+
 	t.Run("includes lock reason when locked with reason", func(t *testing.T) {
 		config.Global.Plain = true
 		info := &git.WorktreeInfo{
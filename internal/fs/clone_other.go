@@ -0,0 +1,15 @@
+//go:build !linux
+
+package fs
+
+import "os"
+
+// cloneFile is only supported on Linux
+func cloneFile(_, _ *os.File) error {
+	return ErrReflinkUnsupported
+}
+
+// SharedExtents returns nothing, since extents can only be queried on Linux
+func SharedExtents(_ string) ([]Extent, error) {
+	return nil, nil
+}
@@ -0,0 +1,117 @@
+package fs
+
+import (
+	"errors"
+	"io"
+	"os"
+	"strings"
+)
+
+// CopyStrategy selects how files are copied when preserving them into new worktrees
+type CopyStrategy string
+
+const (
+	// CopyStrategyCopy writes a full copy of every file
+	CopyStrategyCopy CopyStrategy = "copy"
+	// CopyStrategyReflink clones files copy-on-write and fails where the
+	// filesystem cannot
+	CopyStrategyReflink CopyStrategy = "reflink"
+	// CopyStrategyHardlink hard links files. Both paths then refer to the same
+	// file, so changes through one are visible through the other.
+	CopyStrategyHardlink CopyStrategy = "hardlink"
+	// CopyStrategyAuto clones files where the filesystem supports it and falls
+	// back to a full copy
+	CopyStrategyAuto CopyStrategy = "auto"
+)
+
+// ErrReflinkUnsupported is returned by the reflink strategy when the
+// filesystem or platform cannot clone files
+var ErrReflinkUnsupported = errors.New("reflinks are not supported on this filesystem")
+
+// CopyStrategies returns all copy strategies in the order they are documented
+func CopyStrategies() []CopyStrategy {
+	return []CopyStrategy{CopyStrategyAuto, CopyStrategyCopy, CopyStrategyReflink, CopyStrategyHardlink}
+}
+
+// ParseCopyStrategy returns the strategy for s, or false if s is not a known strategy
+func ParseCopyStrategy(s string) (CopyStrategy, bool) {
+	strategy := CopyStrategy(strings.ToLower(strings.TrimSpace(s)))
+	for _, known := range CopyStrategies() {
+		if strategy == known {
+			return strategy, true
+		}
+	}
+	return "", false
+}
+
+// CopyFileExclusiveWithStrategy copies src to dst like CopyFileExclusive, using
+// strategy. Returns the strategy that was used, which for auto is reflink or
+// copy, and the number of bytes shared with src instead of written.
+// Returns os.ErrExist if destination file exists.
+func CopyFileExclusiveWithStrategy(src, dst string, perm os.FileMode, strategy CopyStrategy) (CopyStrategy, int64, error) {
+	switch strategy {
+	case CopyStrategyReflink, CopyStrategyAuto:
+		return cloneFileExclusive(src, dst, perm, strategy == CopyStrategyAuto)
+	case CopyStrategyHardlink:
+		info, err := os.Stat(src)
+		if err != nil {
+			return "", 0, err
+		}
+		if err := os.Link(src, dst); err != nil {
+			return "", 0, err
+		}
+		return CopyStrategyHardlink, info.Size(), nil
+	default:
+		return CopyStrategyCopy, 0, CopyFileExclusive(src, dst, perm)
+	}
+}
+
+// cloneFileExclusive clones src to dst copy-on-write. With fallback, files
+// that cannot be cloned are copied instead. The copy goes through io.Copy,
+// which uses copy_file_range on Linux so the kernel can still offload it.
+func cloneFileExclusive(src, dst string, perm os.FileMode, fallback bool) (CopyStrategy, int64, error) {
+	in, err := os.Open(src) // nolint:gosec // Controlled path from git ignored files
+	if err != nil {
+		return "", 0, err
+	}
+	defer func() { _ = in.Close() }()
+
+	info, err := in.Stat()
+	if err != nil {
+		return "", 0, err
+	}
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_EXCL|os.O_WRONLY, perm) // nolint:gosec // Controlled path for worktree files
+	if err != nil {
+		return "", 0, err
+	}
+
+	err = cloneFile(out, in)
+	if err == nil {
+		return CopyStrategyReflink, info.Size(), out.Close()
+	}
+	if !fallback || !errors.Is(err, ErrReflinkUnsupported) {
+		_ = out.Close()
+		_ = os.Remove(dst)
+		return "", 0, err
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		_ = os.Remove(dst) // Clean up partial/corrupt file
+		return "", 0, err
+	}
+	return CopyStrategyCopy, 0, out.Close()
+}
+
+// Extent is a range of file data on disk
+type Extent struct {
+	Physical uint64
+	Length   uint64
+}
+
+// FileID identifies a file on disk independently of its path
+type FileID struct {
+	Dev uint64
+	Ino uint64
+}
@@ -0,0 +1,221 @@
+package fs
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestParseCopyStrategy(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input string
+		want  CopyStrategy
+		ok    bool
+	}{
+		{"auto", CopyStrategyAuto, true},
+		{"copy", CopyStrategyCopy, true},
+		{"reflink", CopyStrategyReflink, true},
+		{" Hardlink ", CopyStrategyHardlink, true},
+		{"", "", false},
+		{"symlink", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := ParseCopyStrategy(tt.input)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("ParseCopyStrategy(%q) = %q, %v; want %q, %v", tt.input, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestCopyFileExclusiveWithStrategy(t *testing.T) {
+	t.Parallel()
+
+	content := []byte("preserved content")
+	setup := func(t *testing.T) (src, dst string) {
+		t.Helper()
+		dir := t.TempDir()
+		src = filepath.Join(dir, "source.txt")
+		if err := os.WriteFile(src, content, FileStrict); err != nil {
+			t.Fatalf("failed to create source file: %v", err)
+		}
+		return src, filepath.Join(dir, "dest.txt")
+	}
+	assertContent := func(t *testing.T, path string) {
+		t.Helper()
+		got, err := os.ReadFile(path) // nolint:gosec // Test file in temp dir
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", path, err)
+		}
+		if string(got) != string(content) {
+			t.Errorf("content = %q, want %q", got, content)
+		}
+	}
+
+	t.Run("copy writes a full copy", func(t *testing.T) {
+		t.Parallel()
+		src, dst := setup(t)
+
+		used, saved, err := CopyFileExclusiveWithStrategy(src, dst, FileStrict, CopyStrategyCopy)
+		if err != nil {
+			t.Fatalf("copy failed: %v", err)
+		}
+		if used != CopyStrategyCopy || saved != 0 {
+			t.Errorf("got %q with %d bytes saved, want copy with 0", used, saved)
+		}
+		assertContent(t, dst)
+	})
+
+	t.Run("hardlink shares the source file", func(t *testing.T) {
+		t.Parallel()
+		src, dst := setup(t)
+
+		used, saved, err := CopyFileExclusiveWithStrategy(src, dst, FileStrict, CopyStrategyHardlink)
+		if err != nil {
+			t.Fatalf("hardlink failed: %v", err)
+		}
+		if used != CopyStrategyHardlink || saved != int64(len(content)) {
+			t.Errorf("got %q with %d bytes saved, want hardlink with %d", used, saved, len(content))
+		}
+
+		srcInfo, _ := os.Stat(src)
+		dstInfo, _ := os.Stat(dst)
+		if !os.SameFile(srcInfo, dstInfo) {
+			t.Error("expected destination to be a hard link to the source")
+		}
+	})
+
+	t.Run("auto clones or falls back to copy", func(t *testing.T) {
+		t.Parallel()
+		src, dst := setup(t)
+
+		used, saved, err := CopyFileExclusiveWithStrategy(src, dst, FileStrict, CopyStrategyAuto)
+		if err != nil {
+			t.Fatalf("auto failed: %v", err)
+		}
+		switch used {
+		case CopyStrategyReflink:
+			if saved != int64(len(content)) {
+				t.Errorf("reflink saved %d bytes, want %d", saved, len(content))
+			}
+		case CopyStrategyCopy:
+			if saved != 0 {
+				t.Errorf("copy saved %d bytes, want 0", saved)
+			}
+		default:
+			t.Errorf("unexpected strategy %q", used)
+		}
+		assertContent(t, dst)
+	})
+
+	t.Run("reflink clones or reports unsupported", func(t *testing.T) {
+		t.Parallel()
+		src, dst := setup(t)
+
+		used, _, err := CopyFileExclusiveWithStrategy(src, dst, FileStrict, CopyStrategyReflink)
+		if errors.Is(err, ErrReflinkUnsupported) {
+			if PathExists(dst) {
+				t.Error("expected destination to be removed after failed reflink")
+			}
+			return
+		}
+		if err != nil {
+			t.Fatalf("reflink failed: %v", err)
+		}
+		if used != CopyStrategyReflink {
+			t.Errorf("got %q, want reflink", used)
+		}
+		assertContent(t, dst)
+	})
+
+	t.Run("never overwrites existing files", func(t *testing.T) {
+		t.Parallel()
+
+		for _, strategy := range CopyStrategies() {
+			src, dst := setup(t)
+			if err := os.WriteFile(dst, []byte("existing"), FileStrict); err != nil {
+				t.Fatalf("failed to create destination file: %v", err)
+			}
+
+			if _, _, err := CopyFileExclusiveWithStrategy(src, dst, FileStrict, strategy); !errors.Is(err, os.ErrExist) {
+				t.Errorf("%s: expected os.ErrExist, got %v", strategy, err)
+			}
+			if got, _ := os.ReadFile(dst); string(got) != "existing" { // nolint:gosec // Test file in temp dir
+				t.Errorf("%s: destination was overwritten with %q", strategy, got)
+			}
+		}
+	})
+}
+
+func TestHardLinkID(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS == OSWindows {
+		t.Skip("link counts are not available on Windows")
+	}
+
+	dir := t.TempDir()
+	single := filepath.Join(dir, "single.txt")
+	linked := filepath.Join(dir, "linked.txt")
+	link := filepath.Join(dir, "link.txt")
+	for _, path := range []string{single, linked} {
+		if err := os.WriteFile(path, []byte("content"), FileStrict); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Link(linked, link); err != nil {
+		t.Fatal(err)
+	}
+
+	info, _ := os.Stat(single)
+	if _, ok := HardLinkID(info); ok {
+		t.Error("expected no id for a file with a single link")
+	}
+
+	linkedInfo, _ := os.Stat(linked)
+	linkInfo, _ := os.Stat(link)
+	linkedID, ok1 := HardLinkID(linkedInfo)
+	linkID, ok2 := HardLinkID(linkInfo)
+	if !ok1 || !ok2 || linkedID != linkID {
+		t.Errorf("expected equal ids for hard links, got %v (%v) and %v (%v)", linkedID, ok1, linkID, ok2)
+	}
+}
+
+func TestSharedExtents(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	src := filepath.Join(dir, "source.bin")
+	dst := filepath.Join(dir, "dest.bin")
+	if err := os.WriteFile(src, make([]byte, 64*1024), FileStrict); err != nil {
+		t.Fatal(err)
+	}
+
+	extents, err := SharedExtents(src)
+	if err != nil {
+		t.Fatalf("SharedExtents failed: %v", err)
+	}
+	if len(extents) != 0 {
+		t.Errorf("expected no shared extents for a new file, got %v", extents)
+	}
+
+	used, _, err := CopyFileExclusiveWithStrategy(src, dst, FileStrict, CopyStrategyAuto)
+	if err != nil {
+		t.Fatalf("auto copy failed: %v", err)
+	}
+	if used != CopyStrategyReflink {
+		return // Filesystem cannot clone, so nothing is shared
+	}
+
+	if extents, err := SharedExtents(dst); err != nil || len(extents) == 0 {
+		t.Errorf("expected shared extents after reflink, got %v (err: %v)", extents, err)
+	}
+}
@@ -0,0 +1,18 @@
+//go:build !windows
+
+package fs
+
+import (
+	"os"
+	"syscall"
+)
+
+// HardLinkID returns the identity of a file that has more than one hard link,
+// or false for files with a single link
+func HardLinkID(info os.FileInfo) (FileID, bool) {
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok || st.Nlink < 2 {
+		return FileID{}, false
+	}
+	return FileID{Dev: uint64(st.Dev), Ino: st.Ino}, true // nolint:gosec,unconvert // Dev is signed on some platforms
+}
@@ -0,0 +1,9 @@
+package fs
+
+import "os"
+
+// HardLinkID returns false, since link counts are not available from
+// os.FileInfo on Windows
+func HardLinkID(_ os.FileInfo) (FileID, bool) {
+	return FileID{}, false
+}
@@ -15,6 +15,7 @@ const (
 	// Strict permissions (gosec-compliant defaults)
 	DirStrict  = 0o750 // rwxr-x--- - gosec-compliant directory
 	FileStrict = 0o600 // rw------- - gosec-compliant file
+	DirPrivate = 0o700 // rwx------ - directory only its owner can enter
 
 	// Git-compatible permissions (required for git operations)
 	DirGit   = 0o755 // rwxr-xr-x - git-compatible directory
@@ -2,6 +2,7 @@ package git
 
 import (
 	"bufio"
+	"bytes"
 	"errors"
 	"fmt"
 	"os"
@@ -291,6 +292,29 @@ func listLocalBranches(repoPath string) ([]string, error) {
 	return branches, scanner.Err()
 }
 
+// ListBranchCommits returns the commit of each local branch
+func ListBranchCommits(repoPath string) (map[string]string, error) {
+	cmd, cancel := GitCommand("git", "for-each-ref", "--format=%(objectname) %(refname)", "refs/heads/")
+	defer cancel()
+	cmd.Dir = repoPath
+
+	out, err := executeWithOutputBuffer(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	commits := make(map[string]string)
+	scanner := bufio.NewScanner(out)
+	for scanner.Scan() {
+		commit, ref, ok := strings.Cut(strings.TrimSpace(scanner.Text()), " ")
+		if !ok {
+			continue
+		}
+		commits[strings.TrimPrefix(ref, "refs/heads/")] = commit
+	}
+	return commits, scanner.Err()
+}
+
 func chooseBareHeadTarget(bareDir string, branches []string) string {
 	cmd, cancel := GitCommand("git", "symbolic-ref", "--short", "refs/remotes/origin/HEAD")
 	defer cancel()
@@ -356,6 +380,139 @@ func SetUpstreamBranch(worktreePath, upstream string) error {
 	return runGitCommand(cmd, true)
 }
 
+// GetBranchUpstream returns the raw branch.<name>.remote and branch.<name>.merge
+// settings of a branch. Unlike the resolved upstream, these remain readable
+// after the remote branch has been deleted. Missing settings are empty.
+func GetBranchUpstream(repoPath, branch string) (remote, merge string) {
+	read := func(key string) string {
+		cmd, cancel := GitCommand("git", "config", "--get", key) // nolint:gosec // Branch name from validated input
+		defer cancel()
+		cmd.Dir = repoPath
+		value, err := executeWithOutput(cmd)
+		if err != nil {
+			return ""
+		}
+		return value
+	}
+	return read("branch." + branch + ".remote"), read("branch." + branch + ".merge")
+}
+
+// SetBranchUpstream writes the branch.<name>.remote and branch.<name>.merge
+// settings directly, without requiring the upstream ref to exist.
+func SetBranchUpstream(repoPath, branch, remote, merge string) error {
+	if repoPath == "" || branch == "" || remote == "" || merge == "" {
+		return errors.New("repository path, branch, remote, and merge ref cannot be empty")
+	}
+
+	for _, kv := range [][2]string{{"remote", remote}, {"merge", merge}} {
+		key := "branch." + branch + "." + kv[0]
+		logger.Debug("Executing: git config %s %s in %s", key, kv[1], repoPath)
+		cmd, cancel := GitCommand("git", "config", key, kv[1]) // nolint:gosec // Values recorded from git config
+		cmd.Dir = repoPath
+		err := runGitCommand(cmd, true)
+		cancel()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetBranchParent returns the branch a stacked branch was created on and the
+// commit of that parent it was last rebased onto, from branch.<name>.groveParent
+// and branch.<name>.groveBase. Both are empty for branches that are not stacked.
+func GetBranchParent(repoPath, branch string) (parent, base string) {
+	read := func(key string) string {
+		cmd, cancel := GitCommand("git", "config", "--get", key) // nolint:gosec // Branch name from validated input
+		defer cancel()
+		cmd.Dir = repoPath
+		value, err := executeWithOutput(cmd)
+		if err != nil {
+			return ""
+		}
+		return value
+	}
+	return read("branch." + branch + ".groveParent"), read("branch." + branch + ".groveBase")
+}
+
+// SetBranchParent records parent as the stack parent of branch, and base as
+// the commit of parent that branch sits on
+func SetBranchParent(repoPath, branch, parent, base string) error {
+	if repoPath == "" || branch == "" || parent == "" || base == "" {
+		return errors.New("repository path, branch, parent, and base cannot be empty")
+	}
+
+	for _, kv := range [][2]string{{"groveParent", parent}, {"groveBase", base}} {
+		key := "branch." + branch + "." + kv[0]
+		logger.Debug("Executing: git config %s %s in %s", key, kv[1], repoPath)
+		cmd, cancel := GitCommand("git", "config", key, kv[1]) // nolint:gosec // Branch names from validated input
+		cmd.Dir = repoPath
+		err := runGitCommand(cmd, true)
+		cancel()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListBranchParents returns the stack parent of every stacked branch, keyed
+// by branch name
+func ListBranchParents(repoPath string) (map[string]string, error) {
+	logger.Debug("Executing: git config --get-regexp branch.*.groveparent in %s", repoPath)
+	cmd, cancel := GitCommand("git", "config", "--get-regexp", `^branch\..*\.groveparent$`)
+	defer cancel()
+	cmd.Dir = repoPath
+
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+	if err := cmd.Run(); err != nil {
+		if cmd.ProcessState != nil && cmd.ProcessState.ExitCode() == 1 {
+			return map[string]string{}, nil // No stacked branches
+		}
+		return nil, err
+	}
+
+	parents := make(map[string]string)
+	scanner := bufio.NewScanner(&stdout)
+	for scanner.Scan() {
+		key, parent, ok := strings.Cut(scanner.Text(), " ")
+		if !ok {
+			continue
+		}
+		// Section and key are lowercased by git, the branch name is not
+		branch := strings.TrimSuffix(strings.TrimPrefix(key, "branch."), ".groveparent")
+		if branch != "" && parent != "" {
+			parents[branch] = parent
+		}
+	}
+	return parents, scanner.Err()
+}
+
+// RenameBranchParent points branches stacked on oldParent at newParent, after
+// oldParent was renamed
+func RenameBranchParent(repoPath, oldParent, newParent string) error {
+	parents, err := ListBranchParents(repoPath)
+	if err != nil {
+		return err
+	}
+	for branch, parent := range parents {
+		if parent != oldParent {
+			continue
+		}
+		key := "branch." + branch + ".groveParent"
+		logger.Debug("Executing: git config %s %s in %s", key, newParent, repoPath)
+		cmd, cancel := GitCommand("git", "config", key, newParent) // nolint:gosec // Branch names from git config
+		cmd.Dir = repoPath
+		err := runGitCommand(cmd, true)
+		cancel()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // LocalBranchExists checks if a local branch (not remote-tracking) exists in the repository.
 func LocalBranchExists(repoPath, branch string) (bool, error) {
 	if repoPath == "" || branch == "" {
@@ -480,6 +637,91 @@ func UpdateBranchRef(repoPath, branch, targetRef string) error {
 	return runGitCommand(cmd, true)
 }
 
+// FastForward advances the branch checked out in worktreePath to upstream.
+// Fails without touching the worktree if the branch has diverged.
+func FastForward(worktreePath, upstream string) error {
+	if worktreePath == "" || upstream == "" {
+		return errors.New("worktree path and upstream cannot be empty")
+	}
+
+	logger.Debug("Executing: git merge --ff-only %s in %s", upstream, worktreePath)
+	cmd, cancel := GitCommand("git", "merge", "--ff-only", "--quiet", upstream) // nolint:gosec // Upstream from git config
+	defer cancel()
+	cmd.Dir = worktreePath
+
+	return runGitCommand(cmd, true)
+}
+
+// Rebase rebases the branch checked out in worktreePath onto upstream.
+// A rebase that stops on conflicts is aborted, leaving the worktree unchanged.
+func Rebase(worktreePath, upstream string) error {
+	if worktreePath == "" || upstream == "" {
+		return errors.New("worktree path and upstream cannot be empty")
+	}
+
+	logger.Debug("Executing: git rebase %s in %s", upstream, worktreePath)
+	cmd, cancel := GitCommand("git", "rebase", "--quiet", upstream) // nolint:gosec // Upstream from git config
+	defer cancel()
+	cmd.Dir = worktreePath
+
+	err := runGitCommand(cmd, true)
+	if err == nil {
+		return nil
+	}
+
+	if inRebase, _ := HasOngoingOperation(worktreePath); inRebase {
+		logger.Debug("Executing: git rebase --abort in %s", worktreePath)
+		abortCmd, cancelAbort := GitCommand("git", "rebase", "--abort")
+		defer cancelAbort()
+		abortCmd.Dir = worktreePath
+		if abortErr := runGitCommand(abortCmd, true); abortErr != nil {
+			return fmt.Errorf("%w (abort failed: %w)", err, abortErr)
+		}
+	}
+
+	return err
+}
+
+// RebaseOnto replays the commits of the branch checked out in worktreePath
+// that come after base onto onto. Unlike Rebase, a rebase that stops on
+// conflicts is left in progress so it can be resolved and continued.
+func RebaseOnto(worktreePath, onto, base string) error {
+	if worktreePath == "" || onto == "" || base == "" {
+		return errors.New("worktree path, onto, and base cannot be empty")
+	}
+
+	logger.Debug("Executing: git rebase --onto %s %s in %s", onto, base, worktreePath)
+	cmd, cancel := GitCommand("git", "rebase", "--quiet", "--onto", onto, base) // nolint:gosec // Commits from git config
+	defer cancel()
+	cmd.Dir = worktreePath
+
+	return runGitCommand(cmd, true)
+}
+
+// AbortRebase aborts the rebase in progress in worktreePath
+func AbortRebase(worktreePath string) error {
+	logger.Debug("Executing: git rebase --abort in %s", worktreePath)
+	cmd, cancel := GitCommand("git", "rebase", "--abort")
+	defer cancel()
+	cmd.Dir = worktreePath
+
+	return runGitCommand(cmd, true)
+}
+
+// MergeBase returns the best common ancestor of two refs
+func MergeBase(repoPath, a, b string) (string, error) {
+	logger.Debug("Executing: git merge-base %s %s in %s", a, b, repoPath)
+	cmd, cancel := GitCommand("git", "merge-base", a, b) // nolint:gosec // Refs from validated input
+	defer cancel()
+	cmd.Dir = repoPath
+
+	base, err := executeWithOutput(cmd)
+	if err != nil {
+		return "", fmt.Errorf("failed to find merge base of %s and %s: %w", a, b, err)
+	}
+	return base, nil
+}
+
 // IsBranchMerged checks if a branch has been merged into the target branch.
 // It detects both regular merges (via ancestry) and squash merges (via patch-id comparison).
 func IsBranchMerged(repoPath, branch, targetBranch string) (bool, error) {
@@ -502,6 +744,12 @@ func IsBranchMerged(repoPath, branch, targetBranch string) (bool, error) {
 	return isMergedByPatchID(repoPath, branch, targetBranch)
 }
 
+// IsAncestor reports whether commit is reachable from ref, which includes
+// ref itself. Commits missing from the repository are not ancestors.
+func IsAncestor(repoPath, commit, ref string) bool {
+	return isMergedByAncestry(repoPath, commit, ref)
+}
+
 // isMergedByAncestry checks if branch is an ancestor of targetBranch
 func isMergedByAncestry(repoPath, branch, targetBranch string) bool {
 	// git merge-base --is-ancestor returns 0 if branch is ancestor of targetBranch
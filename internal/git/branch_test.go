@@ -1320,3 +1320,239 @@ func TestRestoreBareHeadIfDangling(t *testing.T) {
 		}
 	})
 }
+
+// newBehindWorktree returns a repo whose "feature" worktree is one commit
+// behind main, along with the worktree path.
+func newBehindWorktree(t *testing.T) (*testgit.TestRepo, string) {
+	t.Helper()
+	repo := testgit.NewTestRepo(t)
+	repo.CreateBranch("feature")
+	repo.WriteFile("main.txt", "main")
+	repo.Add("main.txt")
+	repo.Commit("main commit")
+
+	worktreePath := filepath.Join(repo.TempDir, "feature")
+	repo.RunOutput("worktree", "add", worktreePath, "feature")
+	return repo, worktreePath
+}
+
+// commitInWorktree writes name with content and commits it in worktreePath.
+func commitInWorktree(t *testing.T, worktreePath, name, content string) {
+	t.Helper()
+	testutil.WriteFile(t, filepath.Join(worktreePath, name), content)
+	for _, args := range [][]string{{"add", name}, {"commit", "-m", "update " + name}} {
+		cmd := exec.Command("git", args...) // nolint:gosec // Test uses controlled arguments
+		cmd.Dir = worktreePath
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %s failed: %v\n%s", strings.Join(args, " "), err, out)
+		}
+	}
+}
+
+func TestFastForward(t *testing.T) {
+	t.Parallel()
+
+	t.Run("advances branch that is behind", func(t *testing.T) {
+		t.Parallel()
+		repo, worktreePath := newBehindWorktree(t)
+
+		if err := FastForward(worktreePath, "main"); err != nil {
+			t.Fatalf("FastForward failed: %v", err)
+		}
+
+		feature, _ := RevParse(repo.Path, "feature")
+		main, _ := RevParse(repo.Path, "main")
+		if feature != main {
+			t.Errorf("expected feature at %s, got %s", main, feature)
+		}
+	})
+
+	t.Run("fails when branch has diverged", func(t *testing.T) {
+		t.Parallel()
+		repo, worktreePath := newBehindWorktree(t)
+		commitInWorktree(t, worktreePath, "feature.txt", "feature")
+		before, _ := RevParse(repo.Path, "feature")
+
+		if err := FastForward(worktreePath, "main"); err == nil {
+			t.Fatal("expected error for diverged branch")
+		}
+
+		after, _ := RevParse(repo.Path, "feature")
+		if before != after {
+			t.Errorf("expected feature to stay at %s, got %s", before, after)
+		}
+	})
+
+	t.Run("returns error for empty arguments", func(t *testing.T) {
+		t.Parallel()
+		if err := FastForward("", "main"); err == nil {
+			t.Error("expected error for empty path")
+		}
+		if err := FastForward("/tmp", ""); err == nil {
+			t.Error("expected error for empty upstream")
+		}
+	})
+}
+
+func TestRebase(t *testing.T) {
+	t.Parallel()
+
+	t.Run("replays diverged commits onto upstream", func(t *testing.T) {
+		t.Parallel()
+		repo, worktreePath := newBehindWorktree(t)
+		commitInWorktree(t, worktreePath, "feature.txt", "feature")
+
+		if err := Rebase(worktreePath, "main"); err != nil {
+			t.Fatalf("Rebase failed: %v", err)
+		}
+
+		ahead, behind, err := CompareBranchRefs(repo.Path, "feature", "main")
+		if err != nil {
+			t.Fatalf("CompareBranchRefs failed: %v", err)
+		}
+		if ahead != 1 || behind != 0 {
+			t.Errorf("expected feature 1 ahead and 0 behind main, got %d ahead and %d behind", ahead, behind)
+		}
+	})
+
+	t.Run("aborts on conflict and leaves branch unchanged", func(t *testing.T) {
+		t.Parallel()
+		repo, worktreePath := newBehindWorktree(t)
+		commitInWorktree(t, worktreePath, "main.txt", "conflicting")
+		before, _ := RevParse(repo.Path, "feature")
+
+		if err := Rebase(worktreePath, "main"); err == nil {
+			t.Fatal("expected error for conflicting rebase")
+		}
+
+		if ongoing, _ := HasOngoingOperation(worktreePath); ongoing {
+			t.Error("expected rebase to be aborted")
+		}
+		after, _ := RevParse(repo.Path, "feature")
+		if before != after {
+			t.Errorf("expected feature to stay at %s, got %s", before, after)
+		}
+	})
+
+	t.Run("returns error for empty arguments", func(t *testing.T) {
+		t.Parallel()
+		if err := Rebase("", "main"); err == nil {
+			t.Error("expected error for empty path")
+		}
+		if err := Rebase("/tmp", ""); err == nil {
+			t.Error("expected error for empty upstream")
+		}
+	})
+}
+
+func TestBranchUpstreamConfig(t *testing.T) {
+	t.Parallel()
+	repo := testgit.NewTestRepo(t)
+	repo.CreateBranch("feature")
+
+	remote, merge := GetBranchUpstream(repo.Path, "feature")
+	if remote != "" || merge != "" {
+		t.Errorf("expected no upstream, got %q %q", remote, merge)
+	}
+
+	// The upstream ref does not need to exist
+	if err := SetBranchUpstream(repo.Path, "feature", "origin", "refs/heads/gone"); err != nil {
+		t.Fatalf("SetBranchUpstream failed: %v", err)
+	}
+
+	remote, merge = GetBranchUpstream(repo.Path, "feature")
+	if remote != "origin" || merge != "refs/heads/gone" {
+		t.Errorf("GetBranchUpstream() = %q, %q; want origin, refs/heads/gone", remote, merge)
+	}
+
+	if err := SetBranchUpstream(repo.Path, "feature", "", ""); err == nil {
+		t.Error("expected error for empty remote and merge")
+	}
+}
+
+func TestBranchParentConfig(t *testing.T) {
+	t.Parallel()
+	repo := testgit.NewTestRepo(t)
+	repo.CreateBranch("feat/a")
+	repo.CreateBranch("feat/a.b")
+
+	parents, err := ListBranchParents(repo.Path)
+	if err != nil {
+		t.Fatalf("ListBranchParents failed: %v", err)
+	}
+	if len(parents) != 0 {
+		t.Errorf("expected no stacked branches, got %v", parents)
+	}
+
+	base, _ := RevParse(repo.Path, "main")
+	if err := SetBranchParent(repo.Path, "feat/a", "main", base); err != nil {
+		t.Fatalf("SetBranchParent failed: %v", err)
+	}
+	if err := SetBranchParent(repo.Path, "feat/a.b", "feat/a", base); err != nil {
+		t.Fatalf("SetBranchParent failed: %v", err)
+	}
+
+	parent, gotBase := GetBranchParent(repo.Path, "feat/a.b")
+	if parent != "feat/a" || gotBase != base {
+		t.Errorf("GetBranchParent() = %q, %q; want feat/a, %s", parent, gotBase, base)
+	}
+
+	if err := RenameBranchParent(repo.Path, "feat/a", "feat/renamed"); err != nil {
+		t.Fatalf("RenameBranchParent failed: %v", err)
+	}
+	parents, err = ListBranchParents(repo.Path)
+	if err != nil {
+		t.Fatalf("ListBranchParents failed: %v", err)
+	}
+	if len(parents) != 2 || parents["feat/a"] != "main" || parents["feat/a.b"] != "feat/renamed" {
+		t.Errorf("ListBranchParents() = %v", parents)
+	}
+
+	if err := SetBranchParent(repo.Path, "feat/a", "", ""); err == nil {
+		t.Error("expected error for empty parent and base")
+	}
+}
+
+func TestRebaseOnto(t *testing.T) {
+	t.Parallel()
+
+	t.Run("replays commits after base onto new parent", func(t *testing.T) {
+		t.Parallel()
+		repo, worktreePath := newBehindWorktree(t)
+		base, _ := RevParse(repo.Path, "feature")
+		commitInWorktree(t, worktreePath, "feature.txt", "feature")
+
+		if err := RebaseOnto(worktreePath, "main", base); err != nil {
+			t.Fatalf("RebaseOnto failed: %v", err)
+		}
+
+		ahead, behind, err := CompareBranchRefs(repo.Path, "feature", "main")
+		if err != nil {
+			t.Fatalf("CompareBranchRefs failed: %v", err)
+		}
+		if ahead != 1 || behind != 0 {
+			t.Errorf("expected feature 1 ahead and 0 behind main, got %d ahead and %d behind", ahead, behind)
+		}
+	})
+
+	t.Run("leaves conflicting rebase in progress", func(t *testing.T) {
+		t.Parallel()
+		repo, worktreePath := newBehindWorktree(t)
+		base, _ := RevParse(repo.Path, "feature")
+		commitInWorktree(t, worktreePath, "main.txt", "conflicting")
+
+		if err := RebaseOnto(worktreePath, "main", base); err == nil {
+			t.Fatal("expected error for conflicting rebase")
+		}
+		if ongoing, _ := HasOngoingOperation(worktreePath); !ongoing {
+			t.Fatal("expected rebase to be in progress")
+		}
+
+		if err := AbortRebase(worktreePath); err != nil {
+			t.Fatalf("AbortRebase failed: %v", err)
+		}
+		if ongoing, _ := HasOngoingOperation(worktreePath); ongoing {
+			t.Error("expected rebase to be aborted")
+		}
+	})
+}
@@ -0,0 +1,149 @@
+package git
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/sqve/grove/internal/logger"
+)
+
+// FileStat is a file changed in a diff, as reported by git diff --numstat
+type FileStat struct {
+	Path    string `json:"path"`
+	OldPath string `json:"old_path,omitempty"` // Set for renames
+	Added   int    `json:"added"`
+	Deleted int    `json:"deleted"`
+	Binary  bool   `json:"binary,omitempty"`
+}
+
+// DiffStat returns the files changed by git diff with revs, such as
+// "main...feature" or "HEAD", run in repoPath
+func DiffStat(repoPath string, revs ...string) ([]FileStat, error) {
+	args := append([]string{"diff", "--numstat", "-z", "--find-renames"}, revs...)
+	args = append(args, "--")
+	logger.Debug("Executing: git %s in %s", strings.Join(args, " "), repoPath)
+	cmd, cancel := GitCommand("git", args...) // nolint:gosec // Revisions resolved by grove
+	defer cancel()
+	cmd.Dir = repoPath
+
+	out, err := executeWithOutputBuffer(cmd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to diff: %w", err)
+	}
+	return parseNumstat(out.String()), nil
+}
+
+// DiffNames returns the files that differ between two commits, or only
+// under pathspecs, relative to the repository root
+func DiffNames(repoPath, from, to string, pathspecs ...string) ([]string, error) {
+	args := append([]string{"diff", "--name-only", "-z", "--no-renames", from, to, "--"}, pathspecs...)
+	logger.Debug("Executing: git %s in %s", strings.Join(args, " "), repoPath)
+	cmd, cancel := GitCommand("git", args...) // nolint:gosec // Pathspecs are passed after --
+	defer cancel()
+	cmd.Dir = repoPath
+
+	out, err := executeWithOutputBuffer(cmd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to diff: %w", err)
+	}
+
+	var files []string
+	for _, file := range strings.Split(out.String(), "\x00") {
+		if file != "" {
+			files = append(files, file)
+		}
+	}
+	return files, nil
+}
+
+// parseNumstat parses `git diff --numstat -z` output. A rename leaves the path
+// of its record empty and is followed by its old and new paths.
+func parseNumstat(output string) []FileStat {
+	stats := []FileStat{}
+	fields := strings.Split(output, "\x00")
+	for i := 0; i < len(fields); i++ {
+		parts := strings.SplitN(fields[i], "\t", 3)
+		if len(parts) != 3 {
+			continue
+		}
+
+		stat := FileStat{Path: parts[2]}
+		if parts[0] == "-" && parts[1] == "-" {
+			stat.Binary = true
+		} else {
+			stat.Added, _ = strconv.Atoi(parts[0])
+			stat.Deleted, _ = strconv.Atoi(parts[1])
+		}
+		if stat.Path == "" && i+2 < len(fields) {
+			stat.OldPath = fields[i+1]
+			stat.Path = fields[i+2]
+			i += 2
+		}
+		stats = append(stats, stat)
+	}
+	return stats
+}
+
+// ListFiles returns the files under pathspecs in a worktree, relative to its
+// root: tracked and untracked files, or with ignored the ignored files
+func ListFiles(worktreePath string, ignored bool, pathspecs ...string) ([]string, error) {
+	args := []string{"ls-files", "-z", "--others", "--exclude-standard"}
+	if ignored {
+		args = append(args, "--ignored")
+	} else {
+		args = append(args, "--cached", "--deduplicate")
+	}
+	args = append(args, "--")
+	args = append(args, pathspecs...)
+	logger.Debug("Executing: git %s in %s", strings.Join(args, " "), worktreePath)
+	cmd, cancel := GitCommand("git", args...) // nolint:gosec // Pathspecs are passed after --
+	defer cancel()
+	cmd.Dir = worktreePath
+
+	out, err := executeWithOutputBuffer(cmd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files: %w", err)
+	}
+
+	var files []string
+	for _, file := range strings.Split(out.String(), "\x00") {
+		if file != "" {
+			files = append(files, file)
+		}
+	}
+	return files, nil
+}
+
+// DiffNoIndex writes the diff of two paths outside any repository to w, like
+// git diff --no-index. Either path may be /dev/null for an added or deleted
+// file. Reports whether the paths differ.
+func DiffNoIndex(dir string, w io.Writer, flags []string, a, b string) (bool, error) {
+	args := append([]string{"diff", "--no-index"}, flags...)
+	args = append(args, "--", a, b)
+	logger.Debug("Executing: git %s in %s", strings.Join(args, " "), dir)
+	cmd, cancel := GitCommand("git", args...) // nolint:gosec // Paths are passed after --
+	defer cancel()
+	cmd.Dir = dir
+	cmd.Stdout = w
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	// Exit status 1 means the paths differ, unless git complained
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 && stderr.Len() == 0 {
+		return true, nil
+	}
+	if err != nil {
+		if stderr.Len() > 0 {
+			return false, fmt.Errorf("failed to diff: %w: %s", err, strings.TrimSpace(stderr.String()))
+		}
+		return false, fmt.Errorf("failed to diff: %w", err)
+	}
+	return false, nil
+}
@@ -0,0 +1,87 @@
+package git
+
+import (
+	"bytes"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	testgit "github.com/sqve/grove/internal/testutil/git"
+)
+
+func TestParseNumstat(t *testing.T) {
+	output := "3\t1\tsrc/auth.go\x00-\t-\tlogo.png\x000\t0\t\x00old.txt\x00new.txt\x00"
+
+	want := []FileStat{
+		{Path: "src/auth.go", Added: 3, Deleted: 1},
+		{Path: "logo.png", Binary: true},
+		{Path: "new.txt", OldPath: "old.txt"},
+	}
+	if got := parseNumstat(output); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseNumstat() = %+v, want %+v", got, want)
+	}
+	if got := parseNumstat(""); got == nil || len(got) != 0 {
+		t.Errorf("expected an empty slice for no changes, got %#v", got)
+	}
+}
+
+func TestDiffStatAndListFiles(t *testing.T) {
+	repo := testgit.NewTestRepo(t)
+	repo.WriteFile(".gitignore", ".env\n")
+	repo.Add(".gitignore")
+	repo.Commit("Ignore .env")
+
+	repo.WriteFile("test.txt", "changed\nlines\n")
+	repo.WriteFile("new.txt", "untracked")
+	repo.WriteFile(".env", "SECRET=1")
+
+	stats, err := DiffStat(repo.Path, "HEAD")
+	if err != nil {
+		t.Fatalf("DiffStat failed: %v", err)
+	}
+	if len(stats) != 1 || stats[0].Path != "test.txt" || stats[0].Added != 2 {
+		t.Errorf("unexpected diffstat %+v", stats)
+	}
+
+	files, err := ListFiles(repo.Path, false)
+	if err != nil {
+		t.Fatalf("ListFiles failed: %v", err)
+	}
+	slices.Sort(files)
+	if want := []string{".gitignore", "new.txt", "test.txt"}; !slices.Equal(files, want) {
+		t.Errorf("ListFiles() = %v, want %v", files, want)
+	}
+
+	ignored, err := ListFiles(repo.Path, true, ".env")
+	if err != nil {
+		t.Fatalf("ListFiles failed: %v", err)
+	}
+	if !slices.Equal(ignored, []string{".env"}) {
+		t.Errorf("ListFiles(ignored) = %v, want [.env]", ignored)
+	}
+}
+
+func TestDiffNoIndex(t *testing.T) {
+	repo := testgit.NewTestRepo(t)
+	repo.WriteFile("a.txt", "one\n")
+	repo.WriteFile("b.txt", "two\n")
+
+	var out bytes.Buffer
+	differ, err := DiffNoIndex(repo.Path, &out, []string{"--no-color"}, "a.txt", "b.txt")
+	if err != nil {
+		t.Fatalf("DiffNoIndex failed: %v", err)
+	}
+	if !differ || !strings.Contains(out.String(), "+two") {
+		t.Errorf("expected a diff, got %v %q", differ, out.String())
+	}
+
+	out.Reset()
+	if differ, err = DiffNoIndex(repo.Path, &out, nil, "a.txt", "a.txt"); err != nil || differ {
+		t.Errorf("expected no difference, got %v (%v)", differ, err)
+	}
+
+	if _, err := DiffNoIndex(repo.Path, &out, nil, "a.txt", "missing.txt"); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
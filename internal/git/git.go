@@ -258,6 +258,25 @@ func FetchBranch(repoPath, remote, branch string) error {
 	return runGitCommand(cmd, true)
 }
 
+// FetchRefs fetches refspecs from source, which may be a remote, URL or path,
+// without tags. Branches are only updated when they fast-forward.
+func FetchRefs(repoPath, source string, refspecs []string) error {
+	if repoPath == "" {
+		return errors.New("repository path cannot be empty")
+	}
+	if len(refspecs) == 0 {
+		return nil
+	}
+
+	args := append([]string{"fetch", "--no-tags", source}, refspecs...)
+	logger.Debug("Executing: git %s in %s", strings.Join(args, " "), repoPath)
+	cmd, cancel := GitCommand("git", args...) // nolint:gosec // Refspecs built from branch names
+	defer cancel()
+	cmd.Dir = repoPath
+
+	return runGitCommand(cmd, true)
+}
+
 // RefExists checks if a ref (commit, tag, branch) exists
 func RefExists(repoPath, ref string) error {
 	cmd, cancel := GitCommand("git", "rev-parse", "--verify", "--quiet", ref)
@@ -418,6 +437,28 @@ func ListIgnoredFiles(dir string) ([]string, error) {
 	return files, nil
 }
 
+// ListUntrackedFiles returns the untracked files in the given directory that
+// are not ignored.
+func ListUntrackedFiles(dir string) ([]string, error) {
+	logger.Debug("Executing: git ls-files --others --exclude-standard in %s", dir)
+	cmd, cancel := GitCommand("git", "ls-files", "--others", "--exclude-standard")
+	defer cancel()
+	cmd.Dir = dir
+
+	output, err := executeWithOutput(cmd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list untracked files: %w", err)
+	}
+
+	var files []string
+	for _, line := range strings.Split(output, "\n") {
+		if line != "" {
+			files = append(files, line)
+		}
+	}
+	return files, nil
+}
+
 // IsRemoteReachable checks if a remote is accessible.
 func IsRemoteReachable(repoPath, remote string) bool {
 	if repoPath == "" || remote == "" {
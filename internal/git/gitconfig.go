@@ -5,6 +5,7 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"path/filepath"
 	"regexp"
 	"strings"
 
@@ -114,6 +115,15 @@ func SetConfig(key, value string, global bool) error {
 	return nil
 }
 
+// AddConfig adds a value to a config key, keeping its existing values
+func AddConfig(key, value string, global bool) error {
+	args := []string{"--add", key, value}
+	if global {
+		args = append([]string{"--global"}, args...)
+	}
+	return runConfigIn("", args...)
+}
+
 // UnsetConfig removes a config key and all its values
 func UnsetConfig(key string, global bool) error {
 	logger.Debug("Unsetting git config: %s (global=%v)", key, global)
@@ -169,3 +179,184 @@ func UnsetConfigValue(key, valuePattern string, global bool) error {
 
 	return nil
 }
+
+// worktreeConfigMovedKeys are moved from the shared config to the main
+// repository's config.worktree when per-worktree config is enabled. Left
+// shared, core.bare would make every worktree bare.
+var worktreeConfigMovedKeys = []string{"core.bare", "core.worktree"}
+
+// getConfigIn gets a single config value in dir, with extra args such as
+// --file or --worktree before the key
+func getConfigIn(dir string, args ...string) (string, error) {
+	cmd, cancel := GitCommand("git", append([]string{"config", "--get"}, args...)...) // nolint:gosec // Keys from validated input
+	defer cancel()
+	cmd.Dir = dir
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if cmd.ProcessState != nil && cmd.ProcessState.ExitCode() == 1 {
+			return "", ErrConfigNotFound
+		}
+		if stderr.Len() > 0 {
+			return "", fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+		}
+		return "", err
+	}
+
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// runConfigIn runs git config in dir
+func runConfigIn(dir string, args ...string) error {
+	logger.Debug("Executing: git config %s in %s", strings.Join(args, " "), dir)
+	cmd, cancel := GitCommand("git", append([]string{"config"}, args...)...) // nolint:gosec // Keys from validated input
+	defer cancel()
+	cmd.Dir = dir
+	return runGitCommand(cmd, true)
+}
+
+// WorktreeConfigEnabled reports whether the repository of path reads a
+// config.worktree per worktree (extensions.worktreeConfig)
+func WorktreeConfigEnabled(path string) (bool, error) {
+	value, err := getConfigIn(path, "--bool", "extensions.worktreeConfig")
+	if IsConfigNotFoundError(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return value == "true", nil
+}
+
+// EnableWorktreeConfig turns on extensions.worktreeConfig for the repository
+// of path. Like git sparse-checkout, it first moves core.bare and
+// core.worktree from the shared config to the main repository's own.
+func EnableWorktreeConfig(path string) error {
+	enabled, err := WorktreeConfigEnabled(path)
+	if err != nil || enabled {
+		return err
+	}
+
+	cmd, cancel := GitCommand("git", "rev-parse", "--path-format=absolute", "--git-common-dir")
+	defer cancel()
+	cmd.Dir = path
+	commonDir, err := executeWithOutput(cmd)
+	if err != nil {
+		return fmt.Errorf("failed to find repository: %w", err)
+	}
+	sharedConfig := filepath.Join(commonDir, "config")
+	bareConfig := filepath.Join(commonDir, "config.worktree")
+
+	for _, key := range worktreeConfigMovedKeys {
+		value, err := getConfigIn(path, "--file", sharedConfig, key)
+		if IsConfigNotFoundError(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if err := runConfigIn(path, "--file", bareConfig, key, value); err != nil {
+			return fmt.Errorf("failed to move %s: %w", key, err)
+		}
+		if err := runConfigIn(path, "--file", sharedConfig, "--unset-all", key); err != nil {
+			return fmt.Errorf("failed to move %s: %w", key, err)
+		}
+	}
+
+	if err := runConfigIn(path, "--file", sharedConfig, "extensions.worktreeConfig", "true"); err != nil {
+		return fmt.Errorf("failed to enable per-worktree config: %w", err)
+	}
+	return nil
+}
+
+// GetWorktreeConfig gets a value from the per-worktree config of path
+func GetWorktreeConfig(path, key string) (string, error) {
+	// Without the extension, --worktree falls back to the shared config
+	if enabled, err := WorktreeConfigEnabled(path); err != nil || !enabled {
+		if err == nil {
+			err = ErrConfigNotFound
+		}
+		return "", err
+	}
+	return getConfigIn(path, "--worktree", key)
+}
+
+// GetWorktreeConfigs returns all values in the per-worktree config of path
+func GetWorktreeConfigs(path string) (map[string][]string, error) {
+	configs := make(map[string][]string)
+	if enabled, err := WorktreeConfigEnabled(path); err != nil || !enabled {
+		return configs, err
+	}
+
+	cmd, cancel := GitCommand("git", "config", "--worktree", "--list", "-z")
+	defer cancel()
+	cmd.Dir = path
+	out, err := executeWithOutputBuffer(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	for entry := range strings.SplitSeq(out.String(), "\x00") {
+		if entry == "" {
+			continue
+		}
+		key, value, _ := strings.Cut(entry, "\n")
+		configs[key] = append(configs[key], value)
+	}
+	return configs, nil
+}
+
+// SetWorktreeConfig sets a value in the per-worktree config of path,
+// enabling per-worktree config first if needed
+func SetWorktreeConfig(path, key, value string) error {
+	if err := EnableWorktreeConfig(path); err != nil {
+		return err
+	}
+	return runConfigIn(path, "--worktree", key, value)
+}
+
+// AddWorktreeConfig adds a value to a key in the per-worktree config of
+// path, keeping its existing values
+func AddWorktreeConfig(path, key, value string) error {
+	if err := EnableWorktreeConfig(path); err != nil {
+		return err
+	}
+	return runConfigIn(path, "--worktree", "--add", key, value)
+}
+
+// UnsetWorktreeConfig removes a key from the per-worktree config of path,
+// only its values equal to value when set
+func UnsetWorktreeConfig(path, key, value string) error {
+	if enabled, err := WorktreeConfigEnabled(path); err != nil || !enabled {
+		if err == nil {
+			err = ErrConfigNotFound
+		}
+		return err
+	}
+
+	args := []string{"config", "--worktree", "--unset-all"}
+	if value != "" {
+		args = append(args, "--fixed-value", key, value)
+	} else {
+		args = append(args, key)
+	}
+
+	cmd, cancel := GitCommand("git", args...) // nolint:gosec // Key from validated input
+	defer cancel()
+	cmd.Dir = path
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if cmd.ProcessState != nil && cmd.ProcessState.ExitCode() == 5 {
+			return ErrConfigNotFound
+		}
+		if stderr.Len() > 0 {
+			return fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+		}
+		return err
+	}
+	return nil
+}
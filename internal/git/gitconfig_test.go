@@ -3,6 +3,7 @@ package git
 import (
 	"os"
 	"os/exec"
+	"strings"
 	"testing"
 
 	testgit "github.com/sqve/grove/internal/testutil/git"
@@ -73,3 +74,62 @@ func TestGetConfigs(t *testing.T) {
 		}
 	})
 }
+
+func TestWorktreeConfig(t *testing.T) {
+	ws := testgit.NewGroveWorkspace(t, "main", "feature")
+	mainPath := ws.WorktreePath("main")
+	featurePath := ws.WorktreePath("feature")
+
+	if configs, err := GetWorktreeConfigs(featurePath); err != nil || len(configs) != 0 {
+		t.Errorf("expected no per-worktree config before enabling, got %v (%v)", configs, err)
+	}
+	if _, err := GetWorktreeConfig(featurePath, "user.email"); !IsConfigNotFoundError(err) {
+		t.Errorf("expected ErrConfigNotFound before enabling, got %v", err)
+	}
+
+	if err := SetWorktreeConfig(featurePath, "user.email", "oss@example.com"); err != nil {
+		t.Fatalf("SetWorktreeConfig failed: %v", err)
+	}
+	if enabled, err := WorktreeConfigEnabled(mainPath); err != nil || !enabled {
+		t.Errorf("expected per-worktree config enabled, got %v (%v)", enabled, err)
+	}
+
+	if value, err := GetWorktreeConfig(featurePath, "user.email"); err != nil || value != "oss@example.com" {
+		t.Errorf("expected oss@example.com in feature, got %q (%v)", value, err)
+	}
+	if _, err := GetWorktreeConfig(mainPath, "user.email"); !IsConfigNotFoundError(err) {
+		t.Errorf("expected user.email unset in main, got %v", err)
+	}
+	configs, err := GetWorktreeConfigs(featurePath)
+	if err != nil {
+		t.Fatalf("GetWorktreeConfigs failed: %v", err)
+	}
+	if values := configs["user.email"]; len(values) != 1 || values[0] != "oss@example.com" {
+		t.Errorf("unexpected per-worktree config %v", configs)
+	}
+
+	// core.bare moves to the bare repository, so worktrees stay non-bare
+	for _, dir := range []string{mainPath, featurePath} {
+		out, err := ws.Run("-C", dir, "rev-parse", "--is-bare-repository")
+		if err != nil || strings.TrimSpace(out) != "false" {
+			t.Errorf("expected %s not bare, got %q (%v)", dir, out, err)
+		}
+	}
+	if out, err := ws.Run("rev-parse", "--is-bare-repository"); err != nil || strings.TrimSpace(out) != "true" {
+		t.Errorf("expected the bare repository to stay bare, got %q (%v)", out, err)
+	}
+
+	if err := AddWorktreeConfig(featurePath, "user.email", "cli@example.com"); err != nil {
+		t.Fatalf("AddWorktreeConfig failed: %v", err)
+	}
+	if configs, err := GetWorktreeConfigs(featurePath); err != nil || len(configs["user.email"]) != 2 {
+		t.Errorf("expected both values after adding, got %v (%v)", configs, err)
+	}
+
+	if err := UnsetWorktreeConfig(featurePath, "user.email", ""); err != nil {
+		t.Fatalf("UnsetWorktreeConfig failed: %v", err)
+	}
+	if err := UnsetWorktreeConfig(featurePath, "user.email", ""); !IsConfigNotFoundError(err) {
+		t.Errorf("expected ErrConfigNotFound for a removed key, got %v", err)
+	}
+}
@@ -0,0 +1,365 @@
+package git
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sqve/grove/internal/logger"
+)
+
+// SnapshotRefPrefix is the private namespace of worktree snapshots. Refs
+// outside refs/heads and refs/tags are neither fetched nor pushed by default.
+const SnapshotRefPrefix = "refs/grove/snapshots/"
+
+// SnapshotRef is a snapshot commit stored under SnapshotRefPrefix
+type SnapshotRef struct {
+	Ref     string
+	Commit  string
+	Time    time.Time
+	Message string
+}
+
+// CreateSnapshot records the index and working tree of a worktree as a commit
+// shaped like `git stash -u`: its first parent is HEAD, its second a commit of
+// the index, its third a commit of the untracked files, and its tree the
+// working tree of tracked files. extraFiles are ignored files to record with
+// the untracked ones, so they too come back unstaged. Neither the worktree,
+// its index nor the stash list are touched. Returns an empty string when
+// there is nothing to record.
+func CreateSnapshot(worktreePath, message string, extraFiles []string) (string, error) {
+	head, err := RevParse(worktreePath, "HEAD")
+	if err != nil {
+		return "", errors.New("cannot snapshot a worktree without commits")
+	}
+
+	indexTree, err := writeTree(worktreePath, "")
+	if err != nil {
+		return "", fmt.Errorf("failed to record the index: %w", err)
+	}
+
+	// Stage tracked changes in a copy of the index, keeping its stat cache
+	indexFile, err := copyIndex(worktreePath)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = os.Remove(indexFile) }()
+
+	if err := addToIndex(worktreePath, indexFile, []string{"--update"}, nil); err != nil {
+		return "", fmt.Errorf("failed to record the working tree: %w", err)
+	}
+	workTree, err := writeTree(worktreePath, indexFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to record the working tree: %w", err)
+	}
+
+	untracked, err := listUntracked(worktreePath, nil)
+	if err != nil {
+		return "", err
+	}
+	untrackedTree, err := writeUntrackedTree(worktreePath, append(untracked, extraFiles...))
+	if err != nil {
+		return "", err
+	}
+	return snapshotCommit(worktreePath, message, head, indexTree, workTree, untrackedTree)
+}
+
+// CreatePathSnapshot is CreateSnapshot for the changes under pathspecs only.
+// The index commit holds what is staged under pathspecs and matches HEAD
+// elsewhere.
+func CreatePathSnapshot(worktreePath, message string, pathspecs []string) (string, error) {
+	head, err := RevParse(worktreePath, "HEAD")
+	if err != nil {
+		return "", errors.New("cannot snapshot a worktree without commits")
+	}
+
+	indexFile, err := copyIndex(worktreePath)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = os.Remove(indexFile) }()
+
+	// Unstage everything outside pathspecs in the copy
+	args := []string{"reset", "--quiet", "HEAD", "--"}
+	for _, pathspec := range pathspecs {
+		args = append(args, ":(exclude)"+pathspec)
+	}
+	logger.Debug("Executing: git %s in %s", strings.Join(args, " "), worktreePath)
+	cmd, cancel := GitCommand("git", args...) // nolint:gosec // Pathspecs are passed after --
+	defer cancel()
+	cmd.Dir = worktreePath
+	cmd.Env = append(os.Environ(), "GIT_INDEX_FILE="+indexFile)
+	if err := runGitCommand(cmd, true); err != nil {
+		return "", fmt.Errorf("failed to record the index: %w", err)
+	}
+	indexTree, err := writeTree(worktreePath, indexFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to record the index: %w", err)
+	}
+
+	if err := addToIndex(worktreePath, indexFile, []string{"--update"}, pathspecs); err != nil {
+		return "", fmt.Errorf("failed to record the working tree: %w", err)
+	}
+	workTree, err := writeTree(worktreePath, indexFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to record the working tree: %w", err)
+	}
+
+	untracked, err := listUntracked(worktreePath, pathspecs)
+	if err != nil {
+		return "", err
+	}
+	untrackedTree, err := writeUntrackedTree(worktreePath, untracked)
+	if err != nil {
+		return "", err
+	}
+	return snapshotCommit(worktreePath, message, head, indexTree, workTree, untrackedTree)
+}
+
+// snapshotCommit commits indexTree, workTree and untrackedTree, when set, on
+// top of head in the shape of a stash, or returns an empty string when
+// nothing differs from HEAD
+func snapshotCommit(worktreePath, message, head, indexTree, workTree, untrackedTree string) (string, error) {
+	if headTree, err := RevParse(worktreePath, "HEAD^{tree}"); err == nil && indexTree == headTree && workTree == headTree && untrackedTree == "" {
+		return "", nil
+	}
+
+	indexCommit, err := commitTree(worktreePath, indexTree, "index on "+message, head)
+	if err != nil {
+		return "", err
+	}
+	if untrackedTree == "" {
+		return commitTree(worktreePath, workTree, message, head, indexCommit)
+	}
+	untrackedCommit, err := commitTree(worktreePath, untrackedTree, "untracked files on "+message)
+	if err != nil {
+		return "", err
+	}
+	return commitTree(worktreePath, workTree, message, head, indexCommit, untrackedCommit)
+}
+
+// SnapshotFiles returns the files a snapshot commit changes, its untracked
+// files included
+func SnapshotFiles(repoPath, commit string) ([]string, error) {
+	stats, err := DiffStat(repoPath, commit+"^1", commit)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, stat := range stats {
+		files = append(files, stat.Path)
+	}
+
+	if _, err := RevParse(repoPath, commit+"^3"); err != nil {
+		return files, nil // No untracked files
+	}
+	cmd, cancel := GitCommand("git", "ls-tree", "-r", "-z", "--name-only", commit+"^3") // nolint:gosec // Hash from git
+	defer cancel()
+	cmd.Dir = repoPath
+
+	out, err := executeWithOutputBuffer(cmd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list untracked files: %w", err)
+	}
+	for _, file := range strings.Split(out.String(), "\x00") {
+		if file != "" {
+			files = append(files, file)
+		}
+	}
+	return files, nil
+}
+
+// listUntracked returns the untracked files under pathspecs, or the whole
+// worktree without any, that are not ignored
+func listUntracked(worktreePath string, pathspecs []string) ([]string, error) {
+	args := append([]string{"ls-files", "-z", "--others", "--exclude-standard", "--"}, pathspecs...)
+	cmd, cancel := GitCommand("git", args...) // nolint:gosec // Pathspecs are passed after --
+	defer cancel()
+	cmd.Dir = worktreePath
+
+	out, err := executeWithOutputBuffer(cmd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list untracked files: %w", err)
+	}
+
+	var files []string
+	for _, file := range strings.Split(out.String(), "\x00") {
+		if file != "" {
+			files = append(files, file)
+		}
+	}
+	return files, nil
+}
+
+// writeUntrackedTree writes a tree of only the given files, or returns an
+// empty string without any. git stash apply checks it out without staging
+// it, so the files come back untracked.
+func writeUntrackedTree(worktreePath string, files []string) (string, error) {
+	if len(files) == 0 {
+		return "", nil
+	}
+
+	indexFile, err := tempIndexFile()
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = os.Remove(indexFile) }()
+
+	if err := addToIndex(worktreePath, indexFile, []string{"--force"}, files); err != nil {
+		return "", fmt.Errorf("failed to record untracked files: %w", err)
+	}
+	tree, err := writeTree(worktreePath, indexFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to record untracked files: %w", err)
+	}
+	return tree, nil
+}
+
+// tempIndexFile returns the path of a temporary index that does not exist
+// yet, since git refuses an empty index file
+func tempIndexFile() (string, error) {
+	tmp, err := os.CreateTemp("", "grove-snapshot-index-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary index: %w", err)
+	}
+	_ = tmp.Close()
+	_ = os.Remove(tmp.Name())
+	return tmp.Name(), nil
+}
+
+// copyIndex copies the index of a worktree to a temporary file
+func copyIndex(worktreePath string) (string, error) {
+	cmd, cancel := GitCommand("git", "rev-parse", "--path-format=absolute", "--git-path", "index")
+	defer cancel()
+	cmd.Dir = worktreePath
+
+	indexPath, err := executeWithOutput(cmd)
+	if err != nil {
+		return "", fmt.Errorf("failed to locate the index: %w", err)
+	}
+
+	tmp, err := os.CreateTemp("", "grove-snapshot-index-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary index: %w", err)
+	}
+	defer func() { _ = tmp.Close() }()
+
+	src, err := os.Open(filepath.Clean(indexPath))
+	if errors.Is(err, os.ErrNotExist) {
+		return tmp.Name(), nil // Nothing staged yet
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return "", fmt.Errorf("failed to read the index: %w", err)
+	}
+	defer func() { _ = src.Close() }()
+
+	if _, err := io.Copy(tmp, src); err != nil {
+		_ = os.Remove(tmp.Name())
+		return "", fmt.Errorf("failed to copy the index: %w", err)
+	}
+	return tmp.Name(), nil
+}
+
+// addToIndex runs git add with the index in indexFile. Paths are passed on
+// stdin, so any number fits.
+func addToIndex(worktreePath, indexFile string, flags, paths []string) error {
+	args := append([]string{"add"}, flags...)
+	if len(paths) > 0 {
+		args = append(args, "--pathspec-from-file=-", "--pathspec-file-nul")
+	}
+	logger.Debug("Executing: git %s in %s", strings.Join(args, " "), worktreePath)
+	cmd, cancel := GitCommand("git", args...) // nolint:gosec // Flags are constants
+	defer cancel()
+	cmd.Dir = worktreePath
+	cmd.Env = append(os.Environ(), "GIT_INDEX_FILE="+indexFile)
+	if len(paths) > 0 {
+		cmd.Stdin = strings.NewReader(strings.Join(paths, "\x00"))
+	}
+
+	return runGitCommand(cmd, true)
+}
+
+// writeTree writes the index of a worktree, or indexFile when set, as a tree
+func writeTree(worktreePath, indexFile string) (string, error) {
+	cmd, cancel := GitCommand("git", "write-tree")
+	defer cancel()
+	cmd.Dir = worktreePath
+	if indexFile != "" {
+		cmd.Env = append(os.Environ(), "GIT_INDEX_FILE="+indexFile)
+	}
+
+	return executeWithOutput(cmd)
+}
+
+func commitTree(repoPath, tree, message string, parents ...string) (string, error) {
+	args := []string{"commit-tree", tree, "-m", message}
+	for _, parent := range parents {
+		args = append(args, "-p", parent)
+	}
+	logger.Debug("Executing: git commit-tree %s in %s", tree, repoPath)
+	cmd, cancel := GitCommand("git", args...) // nolint:gosec // Hashes from git
+	defer cancel()
+	cmd.Dir = repoPath
+
+	commit, err := executeWithOutput(cmd)
+	if err != nil {
+		return "", fmt.Errorf("failed to create snapshot commit: %w", err)
+	}
+	return commit, nil
+}
+
+// ListSnapshotRefs returns the snapshots of a repository, sorted by ref
+func ListSnapshotRefs(repoPath string) ([]SnapshotRef, error) {
+	cmd, cancel := GitCommand("git", "for-each-ref",
+		"--format=%(refname)%00%(objectname)%00%(committerdate:unix)%00%(contents:subject)",
+		SnapshotRefPrefix)
+	defer cancel()
+	cmd.Dir = repoPath
+
+	out, err := executeWithOutputBuffer(cmd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list snapshots: %w", err)
+	}
+
+	var refs []SnapshotRef
+	scanner := bufio.NewScanner(out)
+	for scanner.Scan() {
+		fields := strings.SplitN(scanner.Text(), "\x00", 4)
+		if len(fields) != 4 {
+			continue
+		}
+		ref := SnapshotRef{Ref: fields[0], Commit: fields[1], Message: fields[3]}
+		if unix, err := strconv.ParseInt(fields[2], 10, 64); err == nil {
+			ref.Time = time.Unix(unix, 0)
+		}
+		refs = append(refs, ref)
+	}
+	return refs, scanner.Err()
+}
+
+// CreateRef points a new ref at commit, failing if the ref already exists
+func CreateRef(repoPath, ref, commit string) error {
+	logger.Debug("Executing: git update-ref %s %s in %s", ref, commit, repoPath)
+	cmd, cancel := GitCommand("git", "update-ref", ref, commit, "") // nolint:gosec // Ref built by grove
+	defer cancel()
+	cmd.Dir = repoPath
+
+	return runGitCommand(cmd, true)
+}
+
+// DeleteRef deletes a ref
+func DeleteRef(repoPath, ref string) error {
+	logger.Debug("Executing: git update-ref -d %s in %s", ref, repoPath)
+	cmd, cancel := GitCommand("git", "update-ref", "-d", ref) // nolint:gosec // Ref built by grove
+	defer cancel()
+	cmd.Dir = repoPath
+
+	return runGitCommand(cmd, true)
+}
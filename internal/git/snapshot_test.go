@@ -0,0 +1,113 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	testgit "github.com/sqve/grove/internal/testutil/git"
+)
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	repo := testgit.NewTestRepo(t)
+
+	commit, err := CreateSnapshot(repo.Path, "clean", nil)
+	if err != nil {
+		t.Fatalf("CreateSnapshot failed: %v", err)
+	}
+	if commit != "" {
+		t.Errorf("expected no snapshot for a clean worktree, got %q", commit)
+	}
+
+	repo.WriteFile("test.txt", "staged")
+	repo.Add("test.txt")
+	repo.WriteFile("new.txt", "untracked")
+	repo.WriteFile(".gitignore", ".env\n")
+	repo.WriteFile(".env", "SECRET=1")
+
+	if commit, err = CreateSnapshot(repo.Path, "before rebase", []string{".env"}); err != nil {
+		t.Fatalf("CreateSnapshot failed: %v", err)
+	}
+	if commit == "" {
+		t.Fatal("expected a snapshot commit")
+	}
+	if status := repo.RunOutput("status", "--porcelain"); !strings.Contains(status, "M  test.txt") {
+		t.Errorf("expected the worktree untouched, got status %q", status)
+	}
+	if stashes, _ := ListStashes(repo.Path); len(stashes) != 0 {
+		t.Errorf("expected the stash list untouched, got %v", stashes)
+	}
+
+	ref := SnapshotRefPrefix + "main/1"
+	if err := CreateRef(repo.Path, ref, commit); err != nil {
+		t.Fatalf("CreateRef failed: %v", err)
+	}
+	if err := CreateRef(repo.Path, ref, commit); err == nil {
+		t.Error("expected CreateRef to refuse an existing ref")
+	}
+	refs, err := ListSnapshotRefs(repo.Path)
+	if err != nil {
+		t.Fatalf("ListSnapshotRefs failed: %v", err)
+	}
+	if len(refs) != 1 || refs[0].Ref != ref || refs[0].Commit != commit || refs[0].Message != "before rebase" {
+		t.Errorf("unexpected snapshot refs %+v", refs)
+	}
+
+	repo.RunOutput("reset", "--hard", "--quiet")
+	repo.RunOutput("clean", "-fdxq")
+	if err := ApplyStash(repo.Path, commit); err != nil {
+		t.Fatalf("ApplyStash failed: %v", err)
+	}
+	status := repo.RunOutput("status", "--porcelain")
+	for _, want := range []string{"M  test.txt", "?? new.txt"} {
+		if !strings.Contains(status, want) {
+			t.Errorf("expected %q after restoring, got %q", want, status)
+		}
+	}
+	if content, err := os.ReadFile(filepath.Join(repo.Path, ".env")); err != nil || string(content) != "SECRET=1" {
+		t.Errorf("expected .env restored, got %q (%v)", content, err)
+	}
+
+	if err := DeleteRef(repo.Path, ref); err != nil {
+		t.Fatalf("DeleteRef failed: %v", err)
+	}
+	if refs, _ := ListSnapshotRefs(repo.Path); len(refs) != 0 {
+		t.Errorf("expected no snapshots after deleting, got %+v", refs)
+	}
+}
+
+func TestSnapshotNothingStaged(t *testing.T) {
+	repo := testgit.NewTestRepo(t)
+
+	repo.WriteFile("test.txt", "unstaged")
+	repo.WriteFile("new.txt", "untracked")
+	repo.WriteFile(".git/info/exclude", ".env\n")
+	repo.WriteFile(".env", "SECRET=1")
+
+	commit, err := CreateSnapshot(repo.Path, "nothing staged", []string{".env"})
+	if err != nil {
+		t.Fatalf("CreateSnapshot failed: %v", err)
+	}
+	files, err := SnapshotFiles(repo.Path, commit)
+	if err != nil {
+		t.Fatalf("SnapshotFiles failed: %v", err)
+	}
+	if strings.Join(files, ",") != "test.txt,.env,new.txt" {
+		t.Errorf("expected the snapshot to hold test.txt, .env and new.txt, got %v", files)
+	}
+
+	repo.RunOutput("reset", "--hard", "--quiet")
+	repo.RunOutput("clean", "-fdxq")
+	if err := ApplyStash(repo.Path, commit); err != nil {
+		t.Fatalf("ApplyStash failed: %v", err)
+	}
+
+	// Without an index to restore, git would stage the files it creates
+	status := repo.RunOutput("status", "--porcelain", "--ignored")
+	for _, want := range []string{" M test.txt", "?? new.txt", "!! .env"} {
+		if !strings.Contains(status, want) {
+			t.Errorf("expected %q after restoring, got %q", want, status)
+		}
+	}
+}
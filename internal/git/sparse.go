@@ -0,0 +1,159 @@
+package git
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/sqve/grove/internal/logger"
+)
+
+// sparseProfileKey records which grove sparse profiles a worktree checks out.
+// It lives in the per-worktree config, which sparse-checkout set enables.
+const sparseProfileKey = "grove.sparseProfile"
+
+// CreateSparseWorktree creates a worktree for an existing branch, or a
+// detached worktree at ref when detach is set, that checks out only dirs.
+// The worktree is added with --no-checkout so files outside dirs are never
+// written, then cone-mode sparse checkout is configured before checking out.
+func CreateSparseWorktree(bareRepo, worktreePath, ref string, detach bool, dirs []string) error {
+	if bareRepo == "" {
+		return errors.New("bare repository path cannot be empty")
+	}
+	if worktreePath == "" {
+		return errors.New("worktree path cannot be empty")
+	}
+	if ref == "" {
+		return errors.New("ref cannot be empty")
+	}
+	if len(dirs) == 0 {
+		return errors.New("sparse checkout requires at least one directory")
+	}
+
+	args := []string{gitWorktreeSubcommand, "add", "--relative-paths", "--no-checkout"}
+	if detach {
+		args = append(args, "--detach")
+	}
+	args = append(args, worktreePath, ref)
+
+	logger.Debug("Executing: git %s", strings.Join(args, " "))
+	cmd, cancel := GitCommand("git", args...) // nolint:gosec // Ref and path from validated input
+	defer cancel()
+	cmd.Dir = bareRepo
+	if err := WrapGitTooOldError(runGitCommand(cmd, true)); err != nil {
+		return err
+	}
+
+	if err := SparseCheckoutSet(worktreePath, dirs); err != nil {
+		_ = RemoveWorktree(bareRepo, worktreePath, true)
+		return err
+	}
+
+	logger.Debug("Executing: git checkout in %s", worktreePath)
+	checkout, cancelCheckout := GitCommand("git", "checkout")
+	defer cancelCheckout()
+	checkout.Dir = worktreePath
+	if err := runGitCommand(checkout, true); err != nil {
+		_ = RemoveWorktree(bareRepo, worktreePath, true)
+		return err
+	}
+	return nil
+}
+
+// CreateBranch creates branch at base without checking it out. An empty base
+// uses HEAD. Uses: git branch <branch> [<base>]
+func CreateBranch(repoPath, branch, base string) error {
+	if repoPath == "" || branch == "" {
+		return errors.New("repository path and branch name cannot be empty")
+	}
+
+	args := []string{"branch", branch}
+	if base != "" {
+		args = append(args, base)
+	}
+
+	logger.Debug("Executing: git %s in %s", strings.Join(args, " "), repoPath)
+	cmd, cancel := GitCommand("git", args...) // nolint:gosec // Branch names from validated input
+	defer cancel()
+	cmd.Dir = repoPath
+	return runGitCommand(cmd, true)
+}
+
+// SparseCheckoutSet restricts a worktree to dirs using cone-mode sparse
+// checkout. Files at the repository root are always checked out.
+func SparseCheckoutSet(worktreePath string, dirs []string) error {
+	return runSparseCheckout(worktreePath, append([]string{"set", "--cone", "--"}, dirs...)...)
+}
+
+// SparseCheckoutAdd adds dirs to the sparse checkout of a worktree. The
+// worktree must already use sparse checkout.
+func SparseCheckoutAdd(worktreePath string, dirs []string) error {
+	return runSparseCheckout(worktreePath, append([]string{"add", "--"}, dirs...)...)
+}
+
+// SparseCheckoutDisable restores a full checkout of a worktree
+func SparseCheckoutDisable(worktreePath string) error {
+	return runSparseCheckout(worktreePath, "disable")
+}
+
+func runSparseCheckout(worktreePath string, args ...string) error {
+	if worktreePath == "" {
+		return errors.New("worktree path cannot be empty")
+	}
+
+	logger.Debug("Executing: git sparse-checkout %s in %s", strings.Join(args, " "), worktreePath)
+	cmd, cancel := GitCommand("git", append([]string{"sparse-checkout"}, args...)...) // nolint:gosec // Directories from .grove.toml
+	defer cancel()
+	cmd.Dir = worktreePath
+	return runGitCommand(cmd, true)
+}
+
+// IsSparseCheckout reports whether a worktree uses sparse checkout
+func IsSparseCheckout(worktreePath string) bool {
+	cmd, cancel := GitCommand("git", "config", "--bool", "--get", "core.sparseCheckout")
+	defer cancel()
+	cmd.Dir = worktreePath
+	value, err := executeWithOutput(cmd)
+	return err == nil && value == "true"
+}
+
+// GetSparseProfiles returns the grove sparse profiles recorded for a worktree
+func GetSparseProfiles(worktreePath string) []string {
+	cmd, cancel := GitCommand("git", "config", "--get-all", sparseProfileKey)
+	defer cancel()
+	cmd.Dir = worktreePath
+	output, err := executeWithOutput(cmd)
+	if err != nil || output == "" {
+		return nil
+	}
+	return strings.Split(output, "\n")
+}
+
+// SetSparseProfiles records the grove sparse profiles a worktree checks out,
+// replacing any previous ones. An empty list clears them.
+func SetSparseProfiles(worktreePath string, profiles []string) error {
+	if worktreePath == "" {
+		return errors.New("worktree path cannot be empty")
+	}
+
+	logger.Debug("Executing: git config --worktree --unset-all %s in %s", sparseProfileKey, worktreePath)
+	unset, cancel := GitCommand("git", "config", "--worktree", "--unset-all", sparseProfileKey)
+	unset.Dir = worktreePath
+	err := executeWithStderr(unset)
+	cancel()
+	// Exit code 5 means the key was not set
+	if err != nil && (unset.ProcessState == nil || unset.ProcessState.ExitCode() != 5) {
+		return err
+	}
+
+	for _, profile := range profiles {
+		logger.Debug("Executing: git config --worktree --add %s %s in %s", sparseProfileKey, profile, worktreePath)
+		cmd, cancel := GitCommand("git", "config", "--worktree", "--add", sparseProfileKey, profile) // nolint:gosec // Profile name from .grove.toml
+		cmd.Dir = worktreePath
+		err := runGitCommand(cmd, true)
+		cancel()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
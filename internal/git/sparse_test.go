@@ -0,0 +1,176 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	testgit "github.com/sqve/grove/internal/testutil/git"
+)
+
+// newMonorepo returns a repository with files in apps/web, apps/api and at the root
+func newMonorepo(t *testing.T) *testgit.TestRepo {
+	t.Helper()
+
+	repo := testgit.NewTestRepo(t)
+	repo.WriteFile("apps/web/index.js", "web")
+	repo.WriteFile("apps/api/main.go", "api")
+	repo.WriteFile("README.md", "readme")
+	repo.Add(".")
+	repo.Commit("add apps")
+	return repo
+}
+
+func assertExists(t *testing.T, path string, want bool) {
+	t.Helper()
+	_, err := os.Stat(path)
+	if exists := err == nil; exists != want {
+		t.Errorf("%s exists = %v, want %v", path, exists, want)
+	}
+}
+
+func TestCreateSparseWorktree(t *testing.T) {
+	t.Run("fails with empty bare repo path", func(t *testing.T) {
+		err := CreateSparseWorktree("", "/wt", "main", false, []string{"apps/web"})
+		if err == nil || err.Error() != errBareRepoPathEmpty {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("fails with empty worktree path", func(t *testing.T) {
+		err := CreateSparseWorktree("/repo", "", "main", false, []string{"apps/web"})
+		if err == nil || err.Error() != errWorktreePathEmpty {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("fails with empty ref", func(t *testing.T) {
+		err := CreateSparseWorktree("/repo", "/wt", "", false, []string{"apps/web"})
+		if err == nil || err.Error() != errRefEmpty {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("fails without directories", func(t *testing.T) {
+		if err := CreateSparseWorktree("/repo", "/wt", "main", false, nil); err == nil {
+			t.Error("expected error without directories")
+		}
+	})
+
+	t.Run("checks out only the given directories", func(t *testing.T) {
+		repo := newMonorepo(t)
+		repo.CreateBranch("feature")
+		worktreeDir := filepath.Join(repo.TempDir, "feature")
+
+		if err := CreateSparseWorktree(repo.Path, worktreeDir, "feature", false, []string{"apps/web"}); err != nil {
+			t.Fatalf("CreateSparseWorktree failed: %v", err)
+		}
+
+		assertExists(t, filepath.Join(worktreeDir, "apps", "web", "index.js"), true)
+		assertExists(t, filepath.Join(worktreeDir, "README.md"), true)
+		assertExists(t, filepath.Join(worktreeDir, "apps", "api"), false)
+
+		if branch, err := GetCurrentBranch(worktreeDir); err != nil || branch != "feature" {
+			t.Errorf("branch = %q, %v; want feature", branch, err)
+		}
+		if dirty, _, err := CheckGitChanges(worktreeDir); err != nil || dirty {
+			t.Errorf("expected clean worktree, dirty=%v err=%v", dirty, err)
+		}
+		if !IsSparseCheckout(worktreeDir) {
+			t.Error("expected sparse checkout to be enabled")
+		}
+	})
+
+	t.Run("creates detached worktree", func(t *testing.T) {
+		repo := newMonorepo(t)
+		worktreeDir := filepath.Join(repo.TempDir, "detached")
+
+		if err := CreateSparseWorktree(repo.Path, worktreeDir, "HEAD", true, []string{"apps/api"}); err != nil {
+			t.Fatalf("CreateSparseWorktree failed: %v", err)
+		}
+
+		assertExists(t, filepath.Join(worktreeDir, "apps", "api", "main.go"), true)
+		assertExists(t, filepath.Join(worktreeDir, "apps", "web"), false)
+		if detached, err := IsDetachedHead(worktreeDir); err != nil || !detached {
+			t.Errorf("expected detached HEAD, got detached=%v err=%v", detached, err)
+		}
+	})
+}
+
+func TestCreateBranch(t *testing.T) {
+	repo := newMonorepo(t)
+
+	if err := CreateBranch(repo.Path, "from-head", ""); err != nil {
+		t.Fatalf("CreateBranch failed: %v", err)
+	}
+	repo.AssertBranchExists("from-head")
+
+	if err := CreateBranch(repo.Path, "from-base", "from-head"); err != nil {
+		t.Fatalf("CreateBranch with base failed: %v", err)
+	}
+	repo.AssertBranchExists("from-base")
+
+	if err := CreateBranch(repo.Path, "from-base", ""); err == nil {
+		t.Error("expected error for existing branch")
+	}
+	if err := CreateBranch("", "x", ""); err == nil {
+		t.Error("expected error for empty repository path")
+	}
+}
+
+func TestSparseCheckout(t *testing.T) {
+	repo := newMonorepo(t)
+	web := filepath.Join(repo.Path, "apps", "web")
+	api := filepath.Join(repo.Path, "apps", "api")
+
+	if IsSparseCheckout(repo.Path) {
+		t.Fatal("expected full checkout initially")
+	}
+	if profiles := GetSparseProfiles(repo.Path); len(profiles) != 0 {
+		t.Fatalf("expected no profiles, got %v", profiles)
+	}
+
+	if err := SparseCheckoutSet(repo.Path, []string{"apps/web"}); err != nil {
+		t.Fatalf("SparseCheckoutSet failed: %v", err)
+	}
+	if !IsSparseCheckout(repo.Path) {
+		t.Error("expected sparse checkout after set")
+	}
+	assertExists(t, web, true)
+	assertExists(t, api, false)
+
+	if err := SetSparseProfiles(repo.Path, []string{"web"}); err != nil {
+		t.Fatalf("SetSparseProfiles failed: %v", err)
+	}
+
+	if err := SparseCheckoutAdd(repo.Path, []string{"apps/api"}); err != nil {
+		t.Fatalf("SparseCheckoutAdd failed: %v", err)
+	}
+	assertExists(t, api, true)
+
+	if err := SetSparseProfiles(repo.Path, []string{"web", "api"}); err != nil {
+		t.Fatalf("SetSparseProfiles failed: %v", err)
+	}
+	if profiles := GetSparseProfiles(repo.Path); !slices.Equal(profiles, []string{"web", "api"}) {
+		t.Errorf("GetSparseProfiles = %v, want [web api]", profiles)
+	}
+
+	if err := SparseCheckoutDisable(repo.Path); err != nil {
+		t.Fatalf("SparseCheckoutDisable failed: %v", err)
+	}
+	if err := SetSparseProfiles(repo.Path, nil); err != nil {
+		t.Fatalf("SetSparseProfiles(nil) failed: %v", err)
+	}
+	if IsSparseCheckout(repo.Path) {
+		t.Error("expected full checkout after disable")
+	}
+	if profiles := GetSparseProfiles(repo.Path); len(profiles) != 0 {
+		t.Errorf("expected no profiles after clearing, got %v", profiles)
+	}
+
+	// Clearing again is a no-op
+	if err := SetSparseProfiles(repo.Path, nil); err != nil {
+		t.Errorf("SetSparseProfiles(nil) on unset key failed: %v", err)
+	}
+}
@@ -0,0 +1,155 @@
+package git
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+
+	"github.com/sqve/grove/internal/logger"
+)
+
+// Stash is an entry of the stash list
+type Stash struct {
+	Commit  string
+	Message string
+}
+
+// ListStashes returns the stash entries of a repository, newest first
+func ListStashes(path string) ([]Stash, error) {
+	cmd, cancel := GitCommand("git", "stash", "list", "--format=%H %gs")
+	defer cancel()
+	cmd.Dir = path
+
+	out, err := executeWithOutputBuffer(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	var stashes []Stash
+	scanner := bufio.NewScanner(out)
+	for scanner.Scan() {
+		commit, message, ok := strings.Cut(scanner.Text(), " ")
+		if !ok || commit == "" {
+			continue
+		}
+		stashes = append(stashes, Stash{Commit: commit, Message: message})
+	}
+	return stashes, scanner.Err()
+}
+
+// CreateStash records the staged and unstaged changes of tracked files as a
+// stash commit without touching the worktree or the stash list. Returns an
+// empty string when there are no changes.
+func CreateStash(path string) (string, error) {
+	logger.Debug("Executing: git stash create in %s", path)
+	cmd, cancel := GitCommand("git", "stash", "create")
+	defer cancel()
+	cmd.Dir = path
+
+	return executeWithOutput(cmd)
+}
+
+// StoreStash adds a stash commit to the stash list
+func StoreStash(path, commit, message string) error {
+	logger.Debug("Executing: git stash store -m %q %s in %s", message, commit, path)
+	cmd, cancel := GitCommand("git", "stash", "store", "-m", message, commit)
+	defer cancel()
+	cmd.Dir = path
+
+	return runGitCommand(cmd, true)
+}
+
+// ApplyStash applies a stash commit to a worktree, restoring which changes
+// were staged
+func ApplyStash(path, commit string) error {
+	logger.Debug("Executing: git stash apply --index %s in %s", commit, path)
+	cmd, cancel := GitCommand("git", "stash", "apply", "--index", commit)
+	defer cancel()
+	cmd.Dir = path
+
+	return runGitCommand(cmd, true)
+}
+
+// StashIndexApplies reports whether the staged changes of a stash commit
+// apply to the index of a worktree, which ApplyStash needs
+func StashIndexApplies(path, commit string) bool {
+	diff, cancelDiff := GitCommand("git", "diff-tree", "--binary", commit+"^2^", commit+"^2") // nolint:gosec // Hash from git
+	defer cancelDiff()
+	diff.Dir = path
+
+	patch, err := executeWithOutputBuffer(diff)
+	if err != nil {
+		return false
+	}
+	if patch.Len() == 0 {
+		return true
+	}
+
+	logger.Debug("Executing: git apply --cached --check in %s", path)
+	apply, cancelApply := GitCommand("git", "apply", "--cached", "--check")
+	defer cancelApply()
+	apply.Dir = path
+	apply.Stdin = patch
+	return runGitCommand(apply, true) == nil
+}
+
+// MergeStash merges the changes of a stash commit into a worktree with a
+// three-way merge against the commit the stash was made on. Unlike
+// ApplyStash it does not restore the index, so conflicts are left in the
+// worktree as conflict markers.
+func MergeStash(path, commit string) error {
+	logger.Debug("Executing: git stash apply %s in %s", commit, path)
+	cmd, cancel := GitCommand("git", "stash", "apply", "--quiet", commit)
+	defer cancel()
+	cmd.Dir = path
+
+	return runGitCommand(cmd, true)
+}
+
+// DiscardChanges resets the files under pathspecs, or the whole worktree
+// when none are given, to HEAD and deletes untracked files. Ignored files
+// are kept. Pathspecs that match only untracked files are fine, unlike with
+// git restore.
+func DiscardChanges(path string, pathspecs []string) error {
+	if len(pathspecs) == 0 {
+		pathspecs = []string{"."}
+	}
+
+	resetArgs := append([]string{"reset", "--quiet", "--"}, pathspecs...)
+	logger.Debug("Executing: git %s in %s", strings.Join(resetArgs, " "), path)
+	reset, cancelReset := GitCommand("git", resetArgs...) // nolint:gosec // Pathspecs are passed after --
+	defer cancelReset()
+	reset.Dir = path
+	if err := runGitCommand(reset, true); err != nil {
+		return fmt.Errorf("failed to unstage changes: %w", err)
+	}
+
+	listArgs := append([]string{"ls-files", "-z", "--modified", "--"}, pathspecs...)
+	list, cancelList := GitCommand("git", listArgs...) // nolint:gosec // Pathspecs are passed after --
+	defer cancelList()
+	list.Dir = path
+	modified, err := executeWithOutputBuffer(list)
+	if err != nil {
+		return fmt.Errorf("failed to list changed files: %w", err)
+	}
+	if modified.Len() > 0 {
+		logger.Debug("Executing: git checkout-index --force -z --stdin in %s", path)
+		checkout, cancelCheckout := GitCommand("git", "checkout-index", "--force", "-z", "--stdin")
+		defer cancelCheckout()
+		checkout.Dir = path
+		checkout.Stdin = modified
+		if err := runGitCommand(checkout, true); err != nil {
+			return fmt.Errorf("failed to discard changes: %w", err)
+		}
+	}
+
+	cleanArgs := append([]string{"clean", "-d", "--force", "--quiet", "--"}, pathspecs...)
+	logger.Debug("Executing: git %s in %s", strings.Join(cleanArgs, " "), path)
+	clean, cancelClean := GitCommand("git", cleanArgs...) // nolint:gosec // Pathspecs are passed after --
+	defer cancelClean()
+	clean.Dir = path
+	if err := runGitCommand(clean, true); err != nil {
+		return fmt.Errorf("failed to delete untracked files: %w", err)
+	}
+	return nil
+}
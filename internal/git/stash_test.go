@@ -0,0 +1,136 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	testgit "github.com/sqve/grove/internal/testutil/git"
+)
+
+func TestStashRoundTrip(t *testing.T) {
+	repo := testgit.NewTestRepo(t)
+
+	stashes, err := ListStashes(repo.Path)
+	if err != nil {
+		t.Fatalf("ListStashes failed: %v", err)
+	}
+	if len(stashes) != 0 {
+		t.Errorf("expected no stashes, got %v", stashes)
+	}
+
+	commit, err := CreateStash(repo.Path)
+	if err != nil {
+		t.Fatalf("CreateStash failed: %v", err)
+	}
+	if commit != "" {
+		t.Errorf("expected no stash commit for a clean worktree, got %q", commit)
+	}
+
+	repo.WriteFile("test.txt", "changed")
+	repo.Add("test.txt")
+	if commit, err = CreateStash(repo.Path); err != nil {
+		t.Fatalf("CreateStash failed: %v", err)
+	}
+	if commit == "" {
+		t.Fatal("expected a stash commit for staged changes")
+	}
+
+	if err := StoreStash(repo.Path, commit, "saved work"); err != nil {
+		t.Fatalf("StoreStash failed: %v", err)
+	}
+	if stashes, err = ListStashes(repo.Path); err != nil {
+		t.Fatalf("ListStashes failed: %v", err)
+	}
+	if len(stashes) != 1 || stashes[0].Commit != commit || stashes[0].Message != "saved work" {
+		t.Errorf("expected stored stash %s, got %v", commit, stashes)
+	}
+
+	repo.RunOutput("reset", "--hard", "--quiet")
+	if err := ApplyStash(repo.Path, commit); err != nil {
+		t.Fatalf("ApplyStash failed: %v", err)
+	}
+	if staged := repo.RunOutput("diff", "--cached", "--name-only"); strings.TrimSpace(staged) != "test.txt" {
+		t.Errorf("expected test.txt staged again, got %q", staged)
+	}
+}
+
+func TestMergeStash(t *testing.T) {
+	repo := testgit.NewTestRepo(t)
+	repo.WriteFile("test.txt", "carried")
+	repo.WriteFile("new.txt", "new")
+	repo.WriteFile("other.txt", "left behind")
+
+	commit, err := CreatePathSnapshot(repo.Path, "carry", []string{"test.txt", "new.txt"})
+	if err != nil {
+		t.Fatalf("CreatePathSnapshot failed: %v", err)
+	}
+	if commit == "" {
+		t.Fatal("expected a snapshot commit")
+	}
+	if files, err := SnapshotFiles(repo.Path, commit); err != nil || strings.Join(files, ",") != "test.txt,new.txt" {
+		t.Errorf("expected only test.txt and new.txt recorded, got %v (%v)", files, err)
+	}
+
+	if err := DiscardChanges(repo.Path, []string{"test.txt", "new.txt"}); err != nil {
+		t.Fatalf("DiscardChanges failed: %v", err)
+	}
+	if status := repo.RunOutput("status", "--porcelain"); strings.TrimSpace(status) != "?? other.txt" {
+		t.Errorf("expected only other.txt left, got status %q", status)
+	}
+
+	if err := MergeStash(repo.Path, commit); err != nil {
+		t.Fatalf("MergeStash failed: %v", err)
+	}
+	if content, _ := os.ReadFile(filepath.Join(repo.Path, "test.txt")); string(content) != "carried" {
+		t.Errorf("expected test.txt carried, got %q", content)
+	}
+
+	if err := DiscardChanges(repo.Path, nil); err != nil {
+		t.Fatalf("DiscardChanges failed: %v", err)
+	}
+	repo.AssertClean()
+
+	repo.WriteFile("test.txt", "committed")
+	repo.Add("test.txt")
+	repo.Commit("Change test.txt")
+	if err := MergeStash(repo.Path, commit); err == nil {
+		t.Fatal("expected MergeStash to fail on conflicts")
+	}
+	conflicts, err := ListConflictedFiles(repo.Path)
+	if err != nil {
+		t.Fatalf("ListConflictedFiles failed: %v", err)
+	}
+	if len(conflicts) != 1 || conflicts[0] != "test.txt" {
+		t.Errorf("expected test.txt conflicted, got %v", conflicts)
+	}
+}
+
+func TestStashIndexApplies(t *testing.T) {
+	repo := testgit.NewTestRepo(t)
+	repo.WriteFile("test.txt", "staged")
+	repo.Add("test.txt")
+	repo.WriteFile("other.txt", "left behind")
+	repo.Add("other.txt")
+
+	commit, err := CreatePathSnapshot(repo.Path, "carry", []string{"test.txt"})
+	if err != nil {
+		t.Fatalf("CreatePathSnapshot failed: %v", err)
+	}
+	if staged := repo.RunOutput("diff", "--name-only", commit+"^1", commit+"^2"); strings.TrimSpace(staged) != "test.txt" {
+		t.Errorf("expected only test.txt in the index commit, got %q", staged)
+	}
+
+	repo.RunOutput("reset", "--hard", "--quiet")
+	if !StashIndexApplies(repo.Path, commit) {
+		t.Error("expected staged changes to apply to a clean index")
+	}
+
+	repo.WriteFile("test.txt", "committed")
+	repo.Add("test.txt")
+	repo.Commit("Change test.txt")
+	if StashIndexApplies(repo.Path, commit) {
+		t.Error("expected staged changes to conflict with a changed file")
+	}
+}
@@ -79,30 +79,38 @@ func HasUnresolvedConflicts(path string) (bool, error) {
 
 // GetConflictCount returns the number of files with unresolved merge conflicts
 func GetConflictCount(path string) (int, error) {
+	files, err := ListConflictedFiles(path)
+	return len(files), err
+}
+
+// ListConflictedFiles returns the files with unresolved merge conflicts,
+// sorted by path
+func ListConflictedFiles(path string) ([]string, error) {
 	cmd, cancel := GitCommand("git", "ls-files", "-u")
 	defer cancel()
 	cmd.Dir = path
 
 	output, err := executeWithOutput(cmd)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 
 	if output == "" {
-		return 0, nil
+		return nil, nil
 	}
 
-	files := make(map[string]bool)
+	var files []string
 	for _, line := range strings.Split(output, "\n") {
 		// git ls-files -u format: <mode> <hash> <stage>\t<filename>
-		// Split on TAB to correctly handle filenames with spaces
+		// Split on TAB to correctly handle filenames with spaces. The stages
+		// of a file are listed together.
 		parts := strings.SplitN(line, "\t", 2)
-		if len(parts) == 2 {
-			files[parts[1]] = true
+		if len(parts) == 2 && (len(files) == 0 || files[len(files)-1] != parts[1]) {
+			files = append(files, parts[1])
 		}
 	}
 
-	return len(files), nil
+	return files, nil
 }
 
 // HasOngoingOperation checks for merge/rebase/cherry-pick operations
@@ -330,3 +338,38 @@ func GetStashCount(path string) (int, error) {
 	}
 	return count, nil
 }
+
+// ListChangedPaths returns every path git status reports for a worktree, or
+// only under pathspecs, relative to its root. Includes untracked files and
+// both sides of renames, so paths may no longer exist on disk.
+func ListChangedPaths(path string, pathspecs ...string) ([]string, error) {
+	args := append([]string{"status", "--porcelain", "-z", "--untracked-files=all", "--"}, pathspecs...)
+	cmd, cancel := GitCommand("git", args...) // nolint:gosec // Pathspecs are passed after --
+	defer cancel()
+	cmd.Dir = path
+
+	out, err := executeWithOutputBuffer(cmd)
+	if err != nil {
+		return nil, err
+	}
+	return parseStatusPaths(out.String()), nil
+}
+
+// parseStatusPaths extracts paths from `git status --porcelain -z` output.
+// Rename and copy entries are followed by their source path as a separate field.
+func parseStatusPaths(output string) []string {
+	var paths []string
+	fields := strings.Split(output, "\x00")
+	for i := 0; i < len(fields); i++ {
+		entry := fields[i]
+		if len(entry) < 4 {
+			continue
+		}
+		paths = append(paths, entry[3:])
+		if (entry[0] == 'R' || entry[0] == 'C') && i+1 < len(fields) {
+			i++
+			paths = append(paths, fields[i])
+		}
+	}
+	return paths
+}
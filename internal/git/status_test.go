@@ -5,6 +5,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -1060,3 +1061,37 @@ func TestGetStashCount(t *testing.T) {
 		}
 	})
 }
+
+func TestParseStatusPaths(t *testing.T) {
+	t.Parallel()
+
+	output := " M src/app.go\x00?? notes.txt\x00 D gone.txt\x00R  new.go\x00old.go\x00"
+	got := parseStatusPaths(output)
+	want := []string{"src/app.go", "notes.txt", "gone.txt", "new.go", "old.go"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("parseStatusPaths() = %v, want %v", got, want)
+	}
+}
+
+func TestListChangedPaths(t *testing.T) {
+	t.Parallel()
+	repo := testgit.NewTestRepo(t)
+
+	if err := os.WriteFile(filepath.Join(repo.Path, "test.txt"), []byte("changed"), fs.FileStrict); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(repo.Path, "new"), fs.DirStrict); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(repo.Path, "new", "file.txt"), []byte("untracked"), fs.FileStrict); err != nil {
+		t.Fatal(err)
+	}
+
+	paths, err := ListChangedPaths(repo.Path)
+	if err != nil {
+		t.Fatalf("ListChangedPaths failed: %v", err)
+	}
+	if strings.Join(paths, ",") != "test.txt,new/file.txt" {
+		t.Errorf("ListChangedPaths() = %v, want [test.txt new/file.txt]", paths)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"sync"
 
 	"github.com/sqve/grove/internal/fs"
 	"github.com/sqve/grove/internal/logger"
@@ -16,19 +17,20 @@ import (
 
 // WorktreeInfo contains status information about a worktree
 type WorktreeInfo struct {
-	Path           string // Absolute path to worktree
-	Branch         string // Branch name (or commit hash if detached)
-	Upstream       string // Upstream branch name (e.g., "origin/main")
-	Dirty          bool   // Has uncommitted changes
-	Ahead          int    // Commits ahead of upstream
-	Behind         int    // Commits behind upstream
-	Gone           bool   // Upstream branch deleted
-	NoUpstream     bool   // No upstream configured
-	Locked         bool   // Worktree is locked
-	LockReason     string // Reason for lock (empty if not locked)
-	LastCommitTime int64  // Unix timestamp of last commit (0 if unknown)
-	Detached       bool   // Worktree is in detached HEAD state
-	Prunable       bool   // Git marks the worktree metadata as prunable
+	Path           string `json:"path"`                  // Absolute path to worktree
+	Branch         string `json:"branch"`                // Branch name (or commit hash if detached)
+	Upstream       string `json:"upstream,omitempty"`    // Upstream branch name (e.g., "origin/main")
+	Dirty          bool   `json:"dirty"`                 // Has uncommitted changes
+	Ahead          int    `json:"ahead"`                 // Commits ahead of upstream
+	Behind         int    `json:"behind"`                // Commits behind upstream
+	Gone           bool   `json:"gone"`                  // Upstream branch deleted
+	NoUpstream     bool   `json:"no_upstream"`           // No upstream configured
+	Locked         bool   `json:"locked"`                // Worktree is locked
+	LockReason     string `json:"lock_reason,omitempty"` // Reason for lock (empty if not locked)
+	LastCommitTime int64  `json:"last_commit_time"`      // Unix timestamp of last commit (0 if unknown)
+	Detached       bool   `json:"detached"`              // Worktree is in detached HEAD state
+	Prunable       bool   `json:"prunable"`              // Git marks the worktree metadata as prunable
+	Sparse         string `json:"sparse,omitempty"`      // Sparse checkout profiles (only filled in when requested)
 }
 
 type worktreeListEntry struct {
@@ -141,6 +143,16 @@ func RemoveWorktree(bareDir, worktreePath string, force bool) error {
 	return runGitCommand(cmd, true)
 }
 
+// MoveWorktree moves a worktree to newPath. The parent of newPath must exist.
+func MoveWorktree(bareDir, worktreePath, newPath string) error {
+	args := []string{gitWorktreeSubcommand, "move", worktreePath, newPath}
+	logger.Debug("Executing: git %s in %s", strings.Join(args, " "), bareDir)
+	cmd, cancel := GitCommand("git", args...) // nolint:gosec // Worktree paths come from git worktree list
+	defer cancel()
+	cmd.Dir = bareDir
+	return runGitCommand(cmd, true)
+}
+
 // PruneWorktrees removes git-prunable worktree metadata.
 func PruneWorktrees(bareDir string) error {
 	if bareDir == "" {
@@ -279,45 +291,50 @@ func worktreeFallbackInfo(path string, entry worktreeListEntry, err error) (*Wor
 	}
 }
 
+// worktreeInfoWorkers caps how many worktrees ListWorktreesWithInfo and
+// ForEachWorktree inspect at once. Workers mostly wait on git subprocesses, so the pool does not depend
+// on the core count; the limit keeps large workspaces from exhausting file
+// descriptors.
+const worktreeInfoWorkers = 8
+
 // ListWorktreesWithInfo returns info for all worktrees in a grove workspace.
 func ListWorktreesWithInfo(bareDir string, fast bool) ([]*WorktreeInfo, error) {
+	return listWorktreesWithInfo(bareDir, fast, worktreeInfoWorkers)
+}
+
+// worktreeInfoResult holds the outcome of inspecting one worktree entry.
+type worktreeInfoResult struct {
+	info *WorktreeInfo
+	err  error
+}
+
+func listWorktreesWithInfo(bareDir string, fast bool, workers int) ([]*WorktreeInfo, error) {
 	entries, err := listWorktreeEntries(bareDir)
 	if err != nil {
 		return nil, err
 	}
 
-	var infos []*WorktreeInfo
+	// git-prunable entries have no backing path, so they are not usable
+	// worktrees for callers that switch/exec/add against them. Only
+	// `grove prune` acts on them, via ListPrunableWorktrees.
+	usable := make([]worktreeListEntry, 0, len(entries))
 	for _, entry := range entries {
-		path := entry.Path
-		var info *WorktreeInfo
-		// git-prunable entries have no backing path, so they are not usable
-		// worktrees for callers that switch/exec/add against them. Only
-		// `grove prune` acts on them, via ListPrunableWorktrees.
-		if entry.Prunable {
-			continue
+		if !entry.Prunable {
+			usable = append(usable, entry)
 		}
+	}
 
-		// Both modes validate the worktree by reading its HEAD, so a registered
-		// entry whose gitdir is present but unreadable is skipped as corrupt
-		// rather than returned as usable. Fast mode reads only the branch;
-		// full mode also collects status.
-		if fast {
-			branch, detached, err := GetCurrentBranchOrDetached(path)
-			if err != nil {
-				var ok bool
-				if info, ok = worktreeFallbackInfo(path, entry, err); !ok {
-					continue
-				}
-			} else {
-				info = &WorktreeInfo{Path: path, Branch: branch, Detached: detached}
-			}
-		} else {
-			var err error
-			if info, err = GetWorktreeInfo(path); err != nil {
-				var ok bool
-				if info, ok = worktreeFallbackInfo(path, entry, err); !ok {
-					continue
-				}
+	results := collectWorktreeInfo(usable, fast, workers)
+
+	// Results are index-aligned with the entries, so fallback handling and
+	// warnings stay in git's listing order regardless of completion order.
+	var infos []*WorktreeInfo
+	for i, entry := range usable {
+		info, err := results[i].info, results[i].err
+		if err != nil {
+			var ok bool
+			if info, ok = worktreeFallbackInfo(entry.Path, entry, err); !ok {
+				continue
 			}
 		}
 
@@ -334,6 +351,64 @@ func ListWorktreesWithInfo(bareDir string, fast bool) ([]*WorktreeInfo, error) {
 	return infos, nil
 }
 
+// collectWorktreeInfo inspects entries with at most workers goroutines and
+// returns results in the same order as entries.
+func collectWorktreeInfo(entries []worktreeListEntry, fast bool, workers int) []worktreeInfoResult {
+	results := make([]worktreeInfoResult, len(entries))
+	forEachIndex(len(entries), workers, func(i int) {
+		results[i] = readWorktreeInfo(entries[i].Path, fast)
+	})
+	return results
+}
+
+// ForEachWorktree calls fn with each index below n from the worker pool
+// ListWorktreesWithInfo uses, for callers that run git in many worktrees.
+// It returns once every call has.
+func ForEachWorktree(n int, fn func(i int)) {
+	forEachIndex(n, worktreeInfoWorkers, fn)
+}
+
+// forEachIndex calls fn with each index below n from at most workers
+// goroutines.
+func forEachIndex(n, workers int, fn func(i int)) {
+	workers = max(1, min(workers, n))
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				fn(i)
+			}
+		}()
+	}
+
+	for i := range n {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+}
+
+// readWorktreeInfo validates a worktree by reading its HEAD, so a registered
+// entry whose gitdir is present but unreadable is reported as an error rather
+// than returned as usable. Fast mode reads only the branch; full mode also
+// collects status.
+func readWorktreeInfo(path string, fast bool) worktreeInfoResult {
+	if !fast {
+		info, err := GetWorktreeInfo(path)
+		return worktreeInfoResult{info: info, err: err}
+	}
+
+	branch, detached, err := GetCurrentBranchOrDetached(path)
+	if err != nil {
+		return worktreeInfoResult{err: err}
+	}
+	return worktreeInfoResult{info: &WorktreeInfo{Path: path, Branch: branch, Detached: detached}}
+}
+
 // ListPrunableWorktrees returns the worktrees git has marked prunable, i.e.
 // their backing path is gone. These are excluded from ListWorktreesWithInfo
 // because they are not usable worktrees; only `grove prune` acts on them.
@@ -511,14 +586,24 @@ func UnlockWorktree(bareDir, worktreePath string) error {
 	return runGitCommand(cmd, true)
 }
 
-// FindWorktree finds a worktree by name (directory) or branch.
-// Matches by worktree directory basename first, then by branch name.
+// FindWorktree finds a worktree by name (directory), branch or path.
+// Matches by worktree directory basename first, unless several worktrees
+// share it, then by branch name, then by absolute path.
 func FindWorktree(infos []*WorktreeInfo, target string) *WorktreeInfo {
 	// First try worktree name (directory basename)
+	var byName *WorktreeInfo
 	for _, info := range infos {
-		if filepath.Base(info.Path) == target {
-			return info
+		if filepath.Base(info.Path) != target {
+			continue
+		}
+		if byName != nil {
+			byName = nil // Ambiguous in nested layouts, e.g. feat/auth and fix/auth
+			break
 		}
+		byName = info
+	}
+	if byName != nil {
+		return byName
 	}
 
 	// Fall back to branch name
@@ -528,5 +613,28 @@ func FindWorktree(infos []*WorktreeInfo, target string) *WorktreeInfo {
 		}
 	}
 
+	if filepath.IsAbs(target) {
+		for _, info := range infos {
+			if fs.PathsEqual(info.Path, target) {
+				return info
+			}
+		}
+	}
+
 	return nil
 }
+
+// WorktreeName returns the name FindWorktree resolves to info: the directory
+// basename, or the branch or path when other worktrees share the basename
+func WorktreeName(infos []*WorktreeInfo, info *WorktreeInfo) string {
+	name := filepath.Base(info.Path)
+	for _, other := range infos {
+		if other != info && filepath.Base(other.Path) == name {
+			if info.Branch != "" {
+				return info.Branch
+			}
+			return info.Path
+		}
+	}
+	return name
+}
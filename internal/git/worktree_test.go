@@ -1,11 +1,14 @@
 package git
 
 import (
+	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/sqve/grove/internal/fs"
 	"github.com/sqve/grove/internal/testutil"
@@ -652,6 +655,130 @@ func TestListWorktreesWithInfo(t *testing.T) {
 			t.Error("expected Branch to contain commit hash, got empty string")
 		}
 	})
+
+	t.Run("concurrent collection matches serial results", func(t *testing.T) {
+		repo := testgit.NewTestRepo(t)
+
+		for _, branch := range []string{"zebra", "alpha", "mid", "beta", "omega"} {
+			cmd := exec.Command("git", "worktree", "add", filepath.Join(repo.TempDir, branch+"-wt"), "-b", branch) // nolint:gosec // Test uses controlled temp directory
+			cmd.Dir = repo.Path
+			if err := cmd.Run(); err != nil {
+				t.Fatalf("failed to create worktree %s: %v", branch, err)
+			}
+		}
+		_ = os.WriteFile(filepath.Join(repo.TempDir, "mid-wt", "dirty.txt"), []byte("dirty"), fs.FileStrict)
+
+		serial, err := listWorktreesWithInfo(repo.Path, false, 1)
+		if err != nil {
+			t.Fatalf("serial listWorktreesWithInfo failed: %v", err)
+		}
+		concurrent, err := listWorktreesWithInfo(repo.Path, false, 4)
+		if err != nil {
+			t.Fatalf("concurrent listWorktreesWithInfo failed: %v", err)
+		}
+
+		if len(serial) != len(concurrent) {
+			t.Fatalf("expected %d worktrees, got %d", len(serial), len(concurrent))
+		}
+		for i := range serial {
+			if *serial[i] != *concurrent[i] {
+				t.Errorf("at index %d: expected %+v, got %+v", i, *serial[i], *concurrent[i])
+			}
+		}
+	})
+}
+
+// newBenchmarkWorktrees creates a repository with count linked worktrees and
+// returns the repository path.
+func newBenchmarkWorktrees(b *testing.B, count int) string {
+	b.Helper()
+
+	dir := b.TempDir()
+	repoPath := filepath.Join(dir, "repo")
+	run := func(workDir string, args ...string) {
+		b.Helper()
+		cmd := exec.Command("git", args...) // nolint:gosec // Benchmark uses controlled arguments
+		cmd.Dir = workDir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			b.Fatalf("git %s failed: %v\n%s", strings.Join(args, " "), err, out)
+		}
+	}
+
+	if err := os.MkdirAll(repoPath, fs.DirGit); err != nil {
+		b.Fatalf("failed to create repo dir: %v", err)
+	}
+	run(repoPath, "init", "-b", "main")
+	run(repoPath, "config", "user.name", "Bench")
+	run(repoPath, "config", "user.email", "bench@example.com")
+	run(repoPath, "config", "commit.gpgsign", "false")
+	if err := os.WriteFile(filepath.Join(repoPath, "file.txt"), []byte("bench"), fs.FileGit); err != nil {
+		b.Fatalf("failed to write file: %v", err)
+	}
+	run(repoPath, "add", ".")
+	run(repoPath, "commit", "-m", "initial")
+
+	for i := range count {
+		branch := fmt.Sprintf("feature-%02d", i)
+		run(repoPath, "worktree", "add", "-b", branch, filepath.Join(dir, branch))
+	}
+
+	return repoPath
+}
+
+// BenchmarkListWorktreesWithInfo compares serial collection (workers=1) with
+// the default worker pool. Run with:
+//
+//	go test -run '^$' -bench ListWorktreesWithInfo ./internal/git/
+func BenchmarkListWorktreesWithInfo(b *testing.B) {
+	repoPath := newBenchmarkWorktrees(b, 16)
+
+	cases := []struct {
+		name    string
+		workers int
+	}{
+		{"serial", 1},
+		{"concurrent", worktreeInfoWorkers},
+	}
+
+	for _, tc := range cases {
+		b.Run(tc.name, func(b *testing.B) {
+			for b.Loop() {
+				if _, err := listWorktreesWithInfo(repoPath, false, tc.workers); err != nil {
+					b.Fatalf("listWorktreesWithInfo failed: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestForEachWorktree(t *testing.T) {
+	const n = 50
+	var calls [n]atomic.Int32
+	var running, peak atomic.Int32
+
+	ForEachWorktree(n, func(i int) {
+		current := running.Add(1)
+		for {
+			p := peak.Load()
+			if current <= p || peak.CompareAndSwap(p, current) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		calls[i].Add(1)
+		running.Add(-1)
+	})
+
+	for i := range calls {
+		if got := calls[i].Load(); got != 1 {
+			t.Errorf("index %d called %d times, want 1", i, got)
+		}
+	}
+	if got := peak.Load(); got > worktreeInfoWorkers {
+		t.Errorf("ran %d calls at once, want at most %d", got, worktreeInfoWorkers)
+	}
+
+	ForEachWorktree(0, func(int) { t.Error("called without worktrees") })
 }
 
 func TestListPrunableWorktrees(t *testing.T) {
@@ -1415,3 +1542,35 @@ func TestFindWorktree(t *testing.T) {
 		}
 	})
 }
+
+func TestFindWorktreeNested(t *testing.T) {
+	infos := []*WorktreeInfo{
+		{Path: "/workspace/main", Branch: "main"},
+		{Path: "/workspace/feat/auth", Branch: "feat/auth"},
+		{Path: "/workspace/fix/auth", Branch: "fix/auth"},
+	}
+
+	t.Run("ambiguous name falls back to branch", func(t *testing.T) {
+		if result := FindWorktree(infos, "auth"); result != nil {
+			t.Errorf("expected nil for ambiguous name, got %+v", result)
+		}
+		if result := FindWorktree(infos, "fix/auth"); result == nil || result.Path != "/workspace/fix/auth" {
+			t.Errorf("expected /workspace/fix/auth, got %+v", result)
+		}
+	})
+
+	t.Run("names resolve back to their worktree", func(t *testing.T) {
+		for _, info := range infos {
+			name := WorktreeName(infos, info)
+			if result := FindWorktree(infos, name); result != info {
+				t.Errorf("FindWorktree(%q) = %+v, want %+v", name, result, info)
+			}
+		}
+	})
+
+	t.Run("unique basename is the name", func(t *testing.T) {
+		if name := WorktreeName(infos, infos[0]); name != "main" {
+			t.Errorf("expected main, got %q", name)
+		}
+	})
+}
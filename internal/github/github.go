@@ -57,6 +57,18 @@ func ParsePRReference(s string) (*PRRef, error) {
 	return nil, errors.New("invalid PR reference: expected #N or GitHub PR URL")
 }
 
+// PRHeadRef returns the ref GitHub publishes for every pull request's head
+// commit, including those opened from forks.
+func PRHeadRef(number int) string {
+	return fmt.Sprintf("refs/pull/%d/head", number)
+}
+
+// CloneURL returns the https clone URL for a repository. Used when gh is not
+// available to pick the user's preferred protocol.
+func CloneURL(owner, repo string) string {
+	return fmt.Sprintf("https://github.com/%s/%s.git", owner, repo)
+}
+
 // RepoRef holds owner and repo parsed from a remote URL.
 type RepoRef struct {
 	Owner string
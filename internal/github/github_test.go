@@ -225,6 +225,22 @@ func TestParsePRReference_Invalid(t *testing.T) {
 	}
 }
 
+func TestPRHeadRef(t *testing.T) {
+	t.Parallel()
+
+	if got := PRHeadRef(42); got != "refs/pull/42/head" {
+		t.Errorf("PRHeadRef(42) = %q, want %q", got, "refs/pull/42/head")
+	}
+}
+
+func TestCloneURL(t *testing.T) {
+	t.Parallel()
+
+	if got := CloneURL("owner", "repo"); got != "https://github.com/owner/repo.git" {
+		t.Errorf("CloneURL() = %q, want %q", got, "https://github.com/owner/repo.git")
+	}
+}
+
 func TestParseRepoURL(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
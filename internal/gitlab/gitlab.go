@@ -0,0 +1,239 @@
+package gitlab
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// MRRef represents a parsed GitLab merge request reference.
+type MRRef struct {
+	Host    string // Empty when only the number is known (--mr N)
+	Project string // Full project path including groups, e.g. group/sub/repo
+	Number  int
+}
+
+// RepoRef holds the host and project path parsed from a remote URL.
+type RepoRef struct {
+	Host    string
+	Project string
+}
+
+var (
+	// Matches MR URLs on any host; the /-/merge_requests/ path is GitLab specific.
+	// Allows optional suffixes (/diffs, /commits, etc), query params and fragments.
+	mrURLRegex = regexp.MustCompile(`^https?://([^/]+)/(.+?)/-/merge_requests/(\d+)(?:/[^?#]*)?(?:[?#].*)?$`)
+
+	sshURLRegex    = regexp.MustCompile(`^[^@/]+@([^:/]+):(.+?)(?:\.git)?/?$`)
+	sshURIRegex    = regexp.MustCompile(`^ssh://(?:[^@/]+@)?([^:/]+)(?::\d+)?/(.+?)(?:\.git)?/?$`)
+	httpsURLRegex  = regexp.MustCompile(`^https?://(?:[^@/]+@)?([^/]+)/(.+?)(?:\.git)?/?$`)
+	remoteURLRegex = []*regexp.Regexp{sshURIRegex, httpsURLRegex, sshURLRegex}
+)
+
+// IsMRURL returns true if the input is a GitLab merge request URL.
+func IsMRURL(s string) bool {
+	return mrURLRegex.MatchString(s)
+}
+
+// ParseMRURL parses a merge request URL into its components.
+func ParseMRURL(s string) (*MRRef, error) {
+	matches := mrURLRegex.FindStringSubmatch(s)
+	if matches == nil {
+		return nil, errors.New("invalid MR reference: expected GitLab merge request URL")
+	}
+
+	num, err := strconv.Atoi(matches[3])
+	if err != nil {
+		return nil, err
+	}
+	return &MRRef{Host: matches[1], Project: matches[2], Number: num}, nil
+}
+
+// ParseRepoURL extracts the host and project path from a git remote URL.
+// Supports scp-style SSH (git@host:group/repo.git), ssh:// and https:// URLs.
+func ParseRepoURL(url string) (*RepoRef, error) {
+	url = strings.TrimSpace(url)
+
+	for _, re := range remoteURLRegex {
+		if matches := re.FindStringSubmatch(url); matches != nil {
+			return &RepoRef{Host: matches[1], Project: matches[2]}, nil
+		}
+	}
+
+	return nil, errors.New("invalid GitLab remote URL")
+}
+
+// WebURL returns the project's https URL, which glab accepts for --repo on any host.
+func (r *RepoRef) WebURL() string {
+	return fmt.Sprintf("https://%s/%s", r.Host, r.Project)
+}
+
+// CloneURL returns the project's https clone URL.
+func (r *RepoRef) CloneURL() string {
+	return r.WebURL() + ".git"
+}
+
+// SameProject reports whether other names the same project on the same host.
+// Ports are ignored, since SSH and https remotes of one host use different ones.
+func (r *RepoRef) SameProject(other *RepoRef) bool {
+	return strings.EqualFold(hostname(r.Host), hostname(other.Host)) &&
+		strings.EqualFold(strings.Trim(r.Project, "/"), strings.Trim(other.Project, "/"))
+}
+
+// hostname strips an optional port, and the brackets of an IPv6 address,
+// from host
+func hostname(host string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		return h
+	}
+	return strings.TrimSuffix(strings.TrimPrefix(host, "["), "]")
+}
+
+// MRHeadRef returns the ref GitLab publishes for every merge request's head
+// commit, including those opened from forks.
+func MRHeadRef(number int) string {
+	return fmt.Sprintf("refs/merge-requests/%d/head", number)
+}
+
+// MRInfo contains information about a GitLab merge request.
+type MRInfo struct {
+	SourceBranch string // Branch name in the source project
+	IsFork       bool   // True if the MR comes from a different project
+}
+
+// glabMRResponse represents the JSON response from `glab mr view --output json`.
+type glabMRResponse struct {
+	SourceBranch    string `json:"source_branch"`
+	SourceProjectID int    `json:"source_project_id"`
+	TargetProjectID int    `json:"target_project_id"`
+}
+
+// parseMRInfoJSON parses the JSON output from `glab mr view --output json`.
+func parseMRInfoJSON(data []byte) (*MRInfo, error) {
+	var resp glabMRResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse glab output: %w", err)
+	}
+
+	if resp.SourceBranch == "" {
+		return nil, errors.New("missing source_branch in glab output")
+	}
+
+	return &MRInfo{
+		SourceBranch: resp.SourceBranch,
+		IsFork:       resp.SourceProjectID != resp.TargetProjectID,
+	}, nil
+}
+
+// FetchMRInfo fetches merge request information using the glab CLI.
+func FetchMRInfo(repo *RepoRef, number int) (*MRInfo, error) {
+	cmd := exec.Command("glab", "mr", "view", strconv.Itoa(number), //nolint:gosec // Args are constructed from validated input
+		"--repo", repo.WebURL(),
+		"--output", "json")
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		stderrStr := strings.TrimSpace(stderr.String())
+		if strings.Contains(stderrStr, "404") {
+			return nil, fmt.Errorf("MR !%d not found in %s", number, repo.Project)
+		}
+		if stderrStr != "" {
+			return nil, fmt.Errorf("glab failed: %s", stderrStr)
+		}
+		return nil, fmt.Errorf("glab failed: %w", err)
+	}
+
+	return parseMRInfoJSON(stdout.Bytes())
+}
+
+// CheckGlabAvailable checks if the glab CLI is installed.
+func CheckGlabAvailable() error {
+	if _, err := exec.LookPath("glab"); err != nil {
+		return errors.New("glab CLI not found. Install from https://gitlab.com/gitlab-org/cli")
+	}
+	return nil
+}
+
+// mergedMRsPerPage is the page size for glab mr list, the most GitLab returns
+const mergedMRsPerPage = 100
+
+// parseMergedMRBranchesJSON adds the source branches in the JSON output from
+// `glab mr list --merged` to branches and returns the number of MRs listed.
+func parseMergedMRBranchesJSON(data []byte, branches map[string]bool) (int, error) {
+	var mrs []struct {
+		SourceBranch string `json:"source_branch"`
+	}
+	if err := json.Unmarshal(data, &mrs); err != nil {
+		return 0, fmt.Errorf("failed to parse glab output: %w", err)
+	}
+
+	for _, mr := range mrs {
+		if mr.SourceBranch != "" {
+			branches[mr.SourceBranch] = true
+		}
+	}
+	return len(mrs), nil
+}
+
+// GetMergedMRBranches returns a set of branch names that have merged MRs,
+// reading every page of merged MRs.
+func GetMergedMRBranches(repo *RepoRef) (map[string]bool, error) {
+	if err := CheckGlabAvailable(); err != nil {
+		return nil, err
+	}
+
+	return collectMergedMRBranches(func(page int) ([]byte, error) {
+		return listMergedMRs(repo, page)
+	})
+}
+
+// collectMergedMRBranches reads pages from fetch, starting at 1, until a page
+// has fewer than mergedMRsPerPage MRs
+func collectMergedMRBranches(fetch func(page int) ([]byte, error)) (map[string]bool, error) {
+	branches := make(map[string]bool)
+	for page := 1; ; page++ {
+		data, err := fetch(page)
+		if err != nil {
+			return nil, err
+		}
+		n, err := parseMergedMRBranchesJSON(data, branches)
+		if err != nil {
+			return nil, err
+		}
+		if n < mergedMRsPerPage {
+			return branches, nil
+		}
+	}
+}
+
+// listMergedMRs returns one page of `glab mr list --merged` as JSON
+func listMergedMRs(repo *RepoRef, page int) ([]byte, error) {
+	cmd := exec.Command("glab", "mr", "list", //nolint:gosec // Args are constructed from validated input
+		"--merged",
+		"--repo", repo.WebURL(),
+		"--output", "json",
+		"--per-page", strconv.Itoa(mergedMRsPerPage),
+		"--page", strconv.Itoa(page))
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		stderrStr := strings.TrimSpace(stderr.String())
+		if stderrStr != "" {
+			return nil, fmt.Errorf("glab mr list failed: %s", stderrStr)
+		}
+		return nil, fmt.Errorf("glab mr list failed: %w", err)
+	}
+
+	return stdout.Bytes(), nil
+}
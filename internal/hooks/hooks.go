@@ -1,10 +1,74 @@
 package hooks
 
 import (
+	"maps"
+	"slices"
+
 	"github.com/sqve/grove/internal/config"
 	"github.com/sqve/grove/internal/logger"
 )
 
+// Event identifies a point in a worktree's lifecycle where hooks can run.
+type Event string
+
+const (
+	EventPreAdd     Event = "pre-add"
+	EventPostAdd    Event = "post-add"
+	EventPostSwitch Event = "post-switch"
+	EventPreRemove  Event = "pre-remove"
+	EventPostRemove Event = "post-remove"
+	EventPostMove   Event = "post-move"
+	EventPostClone  Event = "post-clone"
+)
+
+// Context describes the operation that triggered a hook. It is exposed to
+// hook commands as GROVE_* environment variables:
+//
+//	GROVE_EVENT            event name (e.g. pre-remove)
+//	GROVE_COMMAND          grove command that triggered the event (add, remove, prune, ...)
+//	GROVE_WORKSPACE        workspace root (parent of .bare)
+//	GROVE_WORKTREE         worktree path (new path for post-move)
+//	GROVE_BRANCH           branch name, empty for detached worktrees
+//	GROVE_BASE             base branch or ref the worktree was created from (add only)
+//	GROVE_SOURCE_WORKTREE  worktree files were preserved from (add only)
+//	GROVE_OLD_WORKTREE     previous worktree path (post-move only)
+//	GROVE_OLD_BRANCH       previous branch name (post-move only)
+//
+// Values allocated to the worktree through [env] in .grove.toml are exposed
+// under their own names.
+type Context struct {
+	Event          Event
+	Command        string
+	Workspace      string
+	Worktree       string
+	Branch         string
+	Base           string
+	SourceWorktree string
+	OldWorktree    string
+	OldBranch      string
+	Vars           map[string]string
+}
+
+// Env returns the GROVE_* environment variables for the hook context.
+// All variables are always set so values never leak in from a parent grove process.
+func (c *Context) Env() []string {
+	env := []string{
+		"GROVE_EVENT=" + string(c.Event),
+		"GROVE_COMMAND=" + c.Command,
+		"GROVE_WORKSPACE=" + c.Workspace,
+		"GROVE_WORKTREE=" + c.Worktree,
+		"GROVE_BRANCH=" + c.Branch,
+		"GROVE_BASE=" + c.Base,
+		"GROVE_SOURCE_WORKTREE=" + c.SourceWorktree,
+		"GROVE_OLD_WORKTREE=" + c.OldWorktree,
+		"GROVE_OLD_BRANCH=" + c.OldBranch,
+	}
+	for _, name := range slices.Sorted(maps.Keys(c.Vars)) {
+		env = append(env, name+"="+c.Vars[name])
+	}
+	return env
+}
+
 type HookResult struct {
 	Command  string
 	ExitCode int
@@ -17,7 +81,14 @@ type RunResult struct {
 	Failed    *HookResult
 }
 
-func GetAddHooks(worktreeDir string) []string {
+// GetHooks returns the hooks configured for event in the .grove.toml of worktreeDir.
+// Post-clone hooks come from git config (grove.hooks.postClone) instead, since a
+// freshly cloned repository's .grove.toml has not been reviewed yet.
+func GetHooks(worktreeDir string, event Event) []string {
+	if event == EventPostClone {
+		return config.GetPostCloneHooks()
+	}
+
 	cfg, err := config.LoadFromFile(worktreeDir)
 	if err != nil {
 		// LoadFromFile returns nil error when file doesn't exist,
@@ -26,5 +97,24 @@ func GetAddHooks(worktreeDir string) []string {
 		return nil
 	}
 
-	return cfg.Hooks.Add
+	switch event {
+	case EventPreAdd:
+		return cfg.Hooks.PreAdd
+	case EventPostAdd:
+		return cfg.Hooks.Add
+	case EventPostSwitch:
+		return cfg.Hooks.PostSwitch
+	case EventPreRemove:
+		return cfg.Hooks.PreRemove
+	case EventPostRemove:
+		return cfg.Hooks.PostRemove
+	case EventPostMove:
+		return cfg.Hooks.PostMove
+	default:
+		return nil
+	}
+}
+
+func GetAddHooks(worktreeDir string) []string {
+	return GetHooks(worktreeDir, EventPostAdd)
 }
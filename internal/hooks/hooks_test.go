@@ -3,6 +3,7 @@ package hooks
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/sqve/grove/internal/fs"
@@ -62,3 +63,100 @@ patterns = [".env"]
 		}
 	})
 }
+
+func TestGetHooks(t *testing.T) {
+	tmpDir := testutil.TempDir(t)
+
+	tomlContent := `[hooks]
+pre_add = ["echo pre-add"]
+add = ["echo add"]
+post_switch = ["echo post-switch"]
+pre_remove = ["docker compose down"]
+post_remove = ["echo post-remove"]
+post_move = ["direnv allow"]
+`
+	tomlPath := filepath.Join(tmpDir, ".grove.toml")
+	if err := os.WriteFile(tomlPath, []byte(tomlContent), fs.FileStrict); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		event Event
+		want  string
+	}{
+		{EventPreAdd, "echo pre-add"},
+		{EventPostAdd, "echo add"},
+		{EventPostSwitch, "echo post-switch"},
+		{EventPreRemove, "docker compose down"},
+		{EventPostRemove, "echo post-remove"},
+		{EventPostMove, "direnv allow"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.event), func(t *testing.T) {
+			hooks := GetHooks(tmpDir, tt.event)
+			if len(hooks) != 1 || hooks[0] != tt.want {
+				t.Errorf("GetHooks(%s) = %v, want [%s]", tt.event, hooks, tt.want)
+			}
+		})
+	}
+
+	t.Run("unknown event returns nil", func(t *testing.T) {
+		if hooks := GetHooks(tmpDir, Event("post-nothing")); hooks != nil {
+			t.Errorf("expected nil, got %v", hooks)
+		}
+	})
+
+	t.Run("invalid TOML disables hooks", func(t *testing.T) {
+		badDir := testutil.TempDir(t)
+		if err := os.WriteFile(filepath.Join(badDir, ".grove.toml"), []byte("[hooks\n"), fs.FileStrict); err != nil {
+			t.Fatal(err)
+		}
+		if hooks := GetHooks(badDir, EventPreRemove); len(hooks) != 0 {
+			t.Errorf("expected no hooks, got %v", hooks)
+		}
+	})
+}
+
+func TestContext_Env(t *testing.T) {
+	ctx := &Context{
+		Event:          EventPostMove,
+		Command:        "move",
+		Workspace:      "/ws",
+		Worktree:       "/ws/feat-new",
+		Branch:         "feat/new",
+		SourceWorktree: "/ws/main",
+		OldWorktree:    "/ws/feat-old",
+		OldBranch:      "feat/old",
+		Vars:           map[string]string{"PORT": "3001"},
+	}
+
+	env := ctx.Env()
+	want := map[string]string{
+		"GROVE_EVENT":           "post-move",
+		"GROVE_COMMAND":         "move",
+		"GROVE_WORKSPACE":       "/ws",
+		"GROVE_WORKTREE":        "/ws/feat-new",
+		"GROVE_BRANCH":          "feat/new",
+		"GROVE_BASE":            "",
+		"GROVE_SOURCE_WORKTREE": "/ws/main",
+		"GROVE_OLD_WORKTREE":    "/ws/feat-old",
+		"GROVE_OLD_BRANCH":      "feat/old",
+		"PORT":                  "3001",
+	}
+
+	if len(env) != len(want) {
+		t.Fatalf("expected %d variables, got %d: %v", len(want), len(env), env)
+	}
+	for _, kv := range env {
+		key, value, _ := strings.Cut(kv, "=")
+		expected, ok := want[key]
+		if !ok {
+			t.Errorf("unexpected variable %s", key)
+			continue
+		}
+		if value != expected {
+			t.Errorf("%s = %q, want %q", key, value, expected)
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"os"
 	"os/exec"
 	"sync"
 
@@ -12,18 +13,20 @@ import (
 	"github.com/sqve/grove/internal/styles"
 )
 
-type prefixWriter struct {
+// PrefixWriter prefixes each complete line written to it. Writers sharing a
+// mutex never interleave partial lines on the shared target.
+type PrefixWriter struct {
 	prefix string
 	target io.Writer
 	buf    bytes.Buffer
 	mu     *sync.Mutex
 }
 
-func newPrefixWriter(prefix string, target io.Writer, mu *sync.Mutex) *prefixWriter {
-	return &prefixWriter{prefix: prefix, target: target, mu: mu}
+func NewPrefixWriter(prefix string, target io.Writer, mu *sync.Mutex) *PrefixWriter {
+	return &PrefixWriter{prefix: prefix, target: target, mu: mu}
 }
 
-func (w *prefixWriter) Write(p []byte) (n int, err error) {
+func (w *PrefixWriter) Write(p []byte) (n int, err error) {
 	w.mu.Lock()
 	defer w.mu.Unlock()
 
@@ -50,7 +53,8 @@ func (w *prefixWriter) Write(p []byte) (n int, err error) {
 	return n, nil
 }
 
-func (w *prefixWriter) Flush() error {
+// Flush writes any buffered partial line followed by a newline.
+func (w *PrefixWriter) Flush() error {
 	w.mu.Lock()
 	defer w.mu.Unlock()
 
@@ -64,27 +68,33 @@ func (w *prefixWriter) Flush() error {
 }
 
 func RunAddHooksStreaming(workDir string, commands []string, output io.Writer) *RunResult {
+	return RunStreaming(workDir, commands, nil, output)
+}
+
+// RunStreaming runs commands sequentially in workDir, streaming prefixed output.
+// env is appended to the inherited environment. Stops on the first failure.
+func RunStreaming(workDir string, commands []string, env []string, output io.Writer) *RunResult {
 	result := &RunResult{}
 	if len(commands) == 0 {
 		return result
 	}
 
-	logger.Debug("Running %d add hooks in %s (streaming)", len(commands), workDir)
+	logger.Debug("Running %d hooks in %s (streaming)", len(commands), workDir)
 
 	for _, cmdStr := range commands {
 		logger.Debug("Executing hook: %s", cmdStr)
 
 		cmd := exec.Command("sh", "-c", cmdStr) //nolint:gosec // User-configured hooks are intentionally executed
 		cmd.Dir = workDir
+		if len(env) > 0 {
+			cmd.Env = append(os.Environ(), env...)
+		}
 
-		var mu sync.Mutex
-		prefix := styles.Render(&styles.Dimmed, fmt.Sprintf("  [%s]", cmdStr))
-		stdout := newPrefixWriter(prefix, output, &mu)
-		stderr := newPrefixWriter(prefix, output, &mu)
-
+		stdout, stderr := hookOutputWriters(cmdStr, output)
 		cmd.Stdout = stdout
 		cmd.Stderr = stderr
 
+		logger.Emit(logger.Event{Type: logger.EventHookStarted, Worktree: workDir, Hook: cmdStr})
 		err := cmd.Start()
 		if err != nil {
 			result.Failed = &HookResult{
@@ -93,6 +103,7 @@ func RunAddHooksStreaming(workDir string, commands []string, output io.Writer) *
 				Stdout:   "",
 				Stderr:   err.Error(),
 			}
+			logger.Emit(logger.Event{Type: logger.EventHookFinished, Worktree: workDir, Hook: cmdStr, ExitCode: logger.ExitCode(1)})
 			return result
 		}
 
@@ -117,12 +128,32 @@ func RunAddHooksStreaming(workDir string, commands []string, output io.Writer) *
 				Stdout:   "",
 				Stderr:   "",
 			}
+			logger.Emit(logger.Event{Type: logger.EventHookFinished, Worktree: workDir, Hook: cmdStr, ExitCode: logger.ExitCode(exitCode)})
 			logger.Debug("Hook failed with exit code %d: %s", exitCode, cmdStr)
 			return result
 		}
 
+		logger.Emit(logger.Event{Type: logger.EventHookFinished, Worktree: workDir, Hook: cmdStr, ExitCode: logger.ExitCode(0)})
 		result.Succeeded = append(result.Succeeded, cmdStr)
 		logger.Debug("Hook succeeded: %s", cmdStr)
 	}
 	return result
 }
+
+type flushWriter interface {
+	io.Writer
+	Flush() error
+}
+
+// hookOutputWriters returns the writers for a hook's stdout and stderr: hook
+// output events when events are enabled, prefixed lines on output otherwise.
+func hookOutputWriters(cmdStr string, output io.Writer) (stdout, stderr flushWriter) {
+	if logger.EventsEnabled() {
+		return logger.NewEventWriter(logger.Event{Type: logger.EventHookOutput, Hook: cmdStr, Stream: "stdout"}),
+			logger.NewEventWriter(logger.Event{Type: logger.EventHookOutput, Hook: cmdStr, Stream: "stderr"})
+	}
+
+	mu := &sync.Mutex{}
+	prefix := styles.Render(&styles.Dimmed, fmt.Sprintf("  [%s]", cmdStr))
+	return NewPrefixWriter(prefix, output, mu), NewPrefixWriter(prefix, output, mu)
+}
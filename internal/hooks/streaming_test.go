@@ -2,6 +2,7 @@ package hooks
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
 	"strings"
 	"sync"
@@ -25,11 +26,11 @@ func (w *errorWriter) Write(p []byte) (int, error) {
 	return len(p), nil
 }
 
-func testPrefixWriter(prefix string, target *bytes.Buffer) *prefixWriter {
-	return newPrefixWriter(prefix, target, &sync.Mutex{})
+func testPrefixWriter(prefix string, target *bytes.Buffer) *PrefixWriter {
+	return NewPrefixWriter(prefix, target, &sync.Mutex{})
 }
 
-func Test_prefixWriter(t *testing.T) {
+func TestPrefixWriter(t *testing.T) {
 	t.Run("single complete line", func(t *testing.T) {
 		var buf bytes.Buffer
 		pw := testPrefixWriter("[prefix]", &buf)
@@ -161,7 +162,7 @@ func Test_prefixWriter(t *testing.T) {
 
 	t.Run("returns error when target writer fails during Write", func(t *testing.T) {
 		ew := &errorWriter{errAfter: 0}
-		pw := newPrefixWriter("[prefix]", ew, &sync.Mutex{})
+		pw := NewPrefixWriter("[prefix]", ew, &sync.Mutex{})
 
 		_, err := pw.Write([]byte("line\n"))
 		if err == nil {
@@ -174,7 +175,7 @@ func Test_prefixWriter(t *testing.T) {
 
 	t.Run("returns error when target writer fails during Flush", func(t *testing.T) {
 		ew := &errorWriter{errAfter: 0}
-		pw := newPrefixWriter("[prefix]", ew, &sync.Mutex{})
+		pw := NewPrefixWriter("[prefix]", ew, &sync.Mutex{})
 
 		_, err := pw.Write([]byte("partial"))
 		if err != nil {
@@ -313,3 +314,76 @@ func TestRunAddHooksStreaming(t *testing.T) {
 		}
 	})
 }
+
+func TestRunStreaming(t *testing.T) {
+	logger.Init(true, false)
+	config.SetPlain(true)
+
+	t.Run("passes environment to hooks", func(t *testing.T) {
+		workDir := testutil.TempDir(t)
+		var output bytes.Buffer
+
+		ctx := &Context{Event: EventPreRemove, Command: "remove", Branch: "feat/env"}
+		commands := []string{`echo "$GROVE_EVENT $GROVE_COMMAND $GROVE_BRANCH"`}
+		result := RunStreaming(workDir, commands, ctx.Env(), &output)
+
+		if result.Failed != nil {
+			t.Fatalf("unexpected failure: %+v", result.Failed)
+		}
+		if !strings.Contains(output.String(), "pre-remove remove feat/env") {
+			t.Errorf("expected environment in output, got %q", output.String())
+		}
+	})
+
+	t.Run("overrides inherited variables", func(t *testing.T) {
+		t.Setenv("GROVE_BRANCH", "stale")
+		workDir := testutil.TempDir(t)
+		var output bytes.Buffer
+
+		ctx := &Context{Event: EventPostSwitch}
+		result := RunStreaming(workDir, []string{`test -z "$GROVE_BRANCH"`}, ctx.Env(), &output)
+
+		if result.Failed != nil {
+			t.Errorf("expected GROVE_BRANCH to be reset, hook failed with exit code %d", result.Failed.ExitCode)
+		}
+	})
+
+	t.Run("emits hook events instead of prefixed output", func(t *testing.T) {
+		workDir := testutil.TempDir(t)
+		var output, events bytes.Buffer
+		logger.EnableEvents(&events)
+		defer logger.EnableEvents(nil)
+
+		result := RunStreaming(workDir, []string{"echo out; echo err >&2; exit 3"}, nil, &output)
+
+		if result.Failed == nil || result.Failed.ExitCode != 3 {
+			t.Fatalf("expected hook to fail with exit code 3, got %+v", result.Failed)
+		}
+		if output.Len() > 0 {
+			t.Errorf("expected no prefixed output, got %q", output.String())
+		}
+		for _, want := range []string{
+			`"type":"hook_started"`,
+			`"type":"hook_output","time":`,
+			`"stream":"stdout","line":"out"`,
+			`"stream":"stderr","line":"err"`,
+			`"type":"hook_finished"`,
+			`"exit_code":3`,
+		} {
+			if !strings.Contains(events.String(), want) {
+				t.Errorf("expected %s in events, got %q", want, events.String())
+			}
+		}
+
+		// Both events name the worktree, to tell hooks of parallel worktrees apart
+		for line := range strings.Lines(events.String()) {
+			var event logger.Event
+			if err := json.Unmarshal([]byte(line), &event); err != nil {
+				t.Fatalf("invalid event %q: %v", line, err)
+			}
+			if (event.Type == logger.EventHookStarted || event.Type == logger.EventHookFinished) && event.Worktree != workDir {
+				t.Errorf("expected %s to name worktree %s, got %q", event.Type, workDir, event.Worktree)
+			}
+		}
+	})
+}
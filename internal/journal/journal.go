@@ -0,0 +1,259 @@
+package journal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sqve/grove/internal/fs"
+	"github.com/sqve/grove/internal/git"
+	"github.com/sqve/grove/internal/logger"
+)
+
+// MaxEntries is the number of operations kept in the journal. Older entries
+// and their snapshots are deleted when a new operation is recorded.
+const MaxEntries = 50
+
+// Op identifies what kind of change an entry can undo
+type Op string
+
+const (
+	OpRemove Op = "remove" // Worktree removed, optionally with its branch
+	OpMove   Op = "move"   // Branch renamed and worktree directory moved
+)
+
+// ErrNotFound is returned when no journal entry matches
+var ErrNotFound = errors.New("operation not found")
+
+// Entry records a mutating operation with enough state to reverse it
+type Entry struct {
+	ID      int       `json:"id"`
+	Time    time.Time `json:"time"`
+	Command string    `json:"command"`
+	Op      Op        `json:"op"`
+
+	Worktree string `json:"worktree"`
+	Branch   string `json:"branch,omitempty"` // Empty if the worktree was detached
+	Head     string `json:"head"`
+
+	BranchDeleted  bool   `json:"branchDeleted,omitempty"`
+	Locked         bool   `json:"locked,omitempty"`
+	LockReason     string `json:"lockReason,omitempty"`
+	UpstreamRemote string `json:"upstreamRemote,omitempty"`
+	UpstreamMerge  string `json:"upstreamMerge,omitempty"`
+	StackParent    string `json:"stackParent,omitempty"`
+	StackBase      string `json:"stackBase,omitempty"`
+
+	// Snapshot is the file name of a tarball of uncommitted files, and
+	// DeletedFiles lists tracked files that were deleted but not committed
+	Snapshot     string   `json:"snapshot,omitempty"`
+	DeletedFiles []string `json:"deletedFiles,omitempty"`
+
+	// SnapshotRef is the grove snapshot holding the uncommitted changes
+	// instead, for removals that saved one
+	SnapshotRef string `json:"snapshotRef,omitempty"`
+
+	// Set for moves: where the worktree and branch ended up
+	NewWorktree string `json:"newWorktree,omitempty"`
+	NewBranch   string `json:"newBranch,omitempty"`
+
+	UndoneAt *time.Time `json:"undoneAt,omitempty"`
+}
+
+// Undone reports whether the entry has already been reversed
+func (e *Entry) Undone() bool {
+	return e.UndoneAt != nil
+}
+
+// Dir returns the journal directory of the workspace owning bareDir
+func Dir(bareDir string) string {
+	return filepath.Join(bareDir, "grove", "journal")
+}
+
+// NewRemoval captures the state of a worktree that is about to be removed.
+// Must be called while the worktree still exists.
+func NewRemoval(bareDir, command string, info *git.WorktreeInfo) (*Entry, error) {
+	head, err := git.RevParse(info.Path, "HEAD")
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &Entry{
+		Command:  command,
+		Op:       OpRemove,
+		Worktree: info.Path,
+		Head:     head,
+		Locked:   git.IsWorktreeLocked(info.Path),
+	}
+	if entry.Locked {
+		entry.LockReason = git.GetWorktreeLockReason(info.Path)
+	}
+	if !info.Detached {
+		entry.Branch = info.Branch
+		entry.UpstreamRemote, entry.UpstreamMerge = git.GetBranchUpstream(bareDir, info.Branch)
+		entry.StackParent, entry.StackBase = git.GetBranchParent(bareDir, info.Branch)
+	}
+	return entry, nil
+}
+
+// Record assigns the next ID to entry and writes it to the journal
+func Record(bareDir string, entry *Entry) error {
+	dir := Dir(bareDir)
+	if err := os.MkdirAll(dir, fs.DirStrict); err != nil {
+		return fmt.Errorf("failed to create journal directory: %w", err)
+	}
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+
+	ids, err := listIDs(dir)
+	if err != nil {
+		return err
+	}
+	next := 1
+	if len(ids) > 0 {
+		next = ids[len(ids)-1] + 1
+	}
+
+	// O_EXCL guards against a concurrent grove process taking the same ID
+	for attempts := 0; attempts < 10; attempts++ {
+		entry.ID = next
+		data, err := json.MarshalIndent(entry, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		f, err := os.OpenFile(entryPath(dir, next), os.O_WRONLY|os.O_CREATE|os.O_EXCL, fs.FileStrict)
+		if errors.Is(err, os.ErrExist) {
+			next++
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to write journal entry: %w", err)
+		}
+		_, err = f.Write(data)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return fmt.Errorf("failed to write journal entry: %w", err)
+		}
+
+		trim(dir, append(ids, next))
+		return nil
+	}
+	return errors.New("failed to allocate journal entry ID")
+}
+
+// List returns all journal entries, newest first
+func List(bareDir string) ([]*Entry, error) {
+	dir := Dir(bareDir)
+	ids, err := listIDs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]*Entry, 0, len(ids))
+	for i := len(ids) - 1; i >= 0; i-- {
+		entry, err := load(dir, ids[i])
+		if err != nil {
+			logger.Debug("Skipping unreadable journal entry %d: %v", ids[i], err)
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// Get returns the entry with the given ID
+func Get(bareDir string, id int) (*Entry, error) {
+	entry, err := load(Dir(bareDir), id)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %d", ErrNotFound, id)
+	}
+	return entry, err
+}
+
+// Latest returns the most recent entry that has not been undone
+func Latest(bareDir string) (*Entry, error) {
+	entries, err := List(bareDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if !entry.Undone() {
+			return entry, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// MarkUndone records that entry has been reversed and removes its snapshot
+func MarkUndone(bareDir string, entry *Entry) error {
+	now := time.Now()
+	entry.UndoneAt = &now
+	Discard(bareDir, entry)
+
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return err
+	}
+	return fs.WriteFileAtomic(entryPath(Dir(bareDir), entry.ID), data, fs.FileStrict)
+}
+
+func entryPath(dir string, id int) string {
+	return filepath.Join(dir, strconv.Itoa(id)+".json")
+}
+
+func load(dir string, id int) (*Entry, error) {
+	data, err := os.ReadFile(entryPath(dir, id))
+	if err != nil {
+		return nil, err
+	}
+	var entry Entry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, fmt.Errorf("invalid journal entry %d: %w", id, err)
+	}
+	return &entry, nil
+}
+
+// listIDs returns the IDs of all entries in dir in ascending order
+func listIDs(dir string) ([]int, error) {
+	files, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read journal: %w", err)
+	}
+
+	var ids []int
+	for _, file := range files {
+		name, ok := strings.CutSuffix(file.Name(), ".json")
+		if !ok {
+			continue
+		}
+		if id, err := strconv.Atoi(name); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+	return ids, nil
+}
+
+// trim deletes the oldest entries beyond MaxEntries
+func trim(dir string, ids []int) {
+	for len(ids) > MaxEntries {
+		if entry, err := load(dir, ids[0]); err == nil && entry.Snapshot != "" {
+			_ = os.Remove(filepath.Join(dir, entry.Snapshot))
+		}
+		_ = os.Remove(entryPath(dir, ids[0]))
+		ids = ids[1:]
+	}
+}
@@ -0,0 +1,137 @@
+package journal
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sqve/grove/internal/fs"
+	"github.com/sqve/grove/internal/testutil"
+)
+
+func TestRecordAssignsSequentialIDs(t *testing.T) {
+	bareDir := testutil.TempDir(t)
+
+	for i := 1; i <= 3; i++ {
+		entry := &Entry{Command: "remove", Op: OpRemove, Worktree: "/ws/feat", Branch: "feat", Head: "abc"}
+		if err := Record(bareDir, entry); err != nil {
+			t.Fatalf("Record() error = %v", err)
+		}
+		if entry.ID != i {
+			t.Errorf("entry.ID = %d, want %d", entry.ID, i)
+		}
+		if entry.Time.IsZero() {
+			t.Error("expected Record to set Time")
+		}
+	}
+
+	entries, err := List(bareDir)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(entries) != 3 || entries[0].ID != 3 || entries[2].ID != 1 {
+		t.Errorf("List() returned IDs in wrong order: %v", entryIDs(entries))
+	}
+}
+
+func TestListEmptyJournal(t *testing.T) {
+	entries, err := List(testutil.TempDir(t))
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("expected no entries, got %d", len(entries))
+	}
+}
+
+func TestGetAndLatest(t *testing.T) {
+	bareDir := testutil.TempDir(t)
+
+	if _, err := Latest(bareDir); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Latest() on empty journal error = %v, want ErrNotFound", err)
+	}
+
+	first := &Entry{Command: "remove", Op: OpRemove, Branch: "first"}
+	second := &Entry{Command: "move", Op: OpMove, Branch: "second", NewBranch: "renamed"}
+	for _, e := range []*Entry{first, second} {
+		if err := Record(bareDir, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := Get(bareDir, first.ID)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got.Branch != "first" || got.Op != OpRemove {
+		t.Errorf("Get() = %+v, want first removal", got)
+	}
+
+	if _, err := Get(bareDir, 99); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get(99) error = %v, want ErrNotFound", err)
+	}
+
+	latest, err := Latest(bareDir)
+	if err != nil || latest.ID != second.ID {
+		t.Fatalf("Latest() = %v, %v; want entry %d", latest, err, second.ID)
+	}
+
+	// Undone entries are skipped
+	if err := MarkUndone(bareDir, latest); err != nil {
+		t.Fatalf("MarkUndone() error = %v", err)
+	}
+	reloaded, err := Get(bareDir, second.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reloaded.Undone() {
+		t.Error("expected entry to be marked undone")
+	}
+	latest, err = Latest(bareDir)
+	if err != nil || latest.ID != first.ID {
+		t.Errorf("Latest() after undo = %v, %v; want entry %d", latest, err, first.ID)
+	}
+}
+
+func TestRecordTrimsOldEntries(t *testing.T) {
+	bareDir := testutil.TempDir(t)
+	dir := Dir(bareDir)
+
+	// The oldest entry owns a snapshot that must be deleted with it
+	if err := os.MkdirAll(dir, fs.DirStrict); err != nil {
+		t.Fatal(err)
+	}
+	testutil.WriteFile(t, filepath.Join(dir, "snapshot-old.tar.gz"), "data")
+	if err := Record(bareDir, &Entry{Op: OpRemove, Snapshot: "snapshot-old.tar.gz"}); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < MaxEntries; i++ {
+		if err := Record(bareDir, &Entry{Op: OpRemove}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	entries, err := List(bareDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != MaxEntries {
+		t.Errorf("expected %d entries, got %d", MaxEntries, len(entries))
+	}
+	if entries[len(entries)-1].ID != 2 {
+		t.Errorf("oldest kept entry = %d, want 2", entries[len(entries)-1].ID)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "snapshot-old.tar.gz")); !os.IsNotExist(err) {
+		t.Error("expected snapshot of trimmed entry to be deleted")
+	}
+}
+
+func entryIDs(entries []*Entry) []int {
+	ids := make([]int, len(entries))
+	for i, e := range entries {
+		ids[i] = e.ID
+	}
+	return ids
+}
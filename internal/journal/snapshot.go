@@ -0,0 +1,219 @@
+package journal
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sqve/grove/internal/fs"
+	"github.com/sqve/grove/internal/git"
+)
+
+// Snapshot archives the uncommitted files of a worktree into the journal so
+// they can be restored after a forced removal. Ignored files are not included.
+// Sets entry.Snapshot and entry.DeletedFiles; does nothing for a clean worktree.
+func Snapshot(bareDir string, entry *Entry) error {
+	paths, err := git.ListChangedPaths(entry.Worktree)
+	if err != nil {
+		return fmt.Errorf("failed to list changed files: %w", err)
+	}
+	if len(paths) == 0 {
+		return nil
+	}
+
+	dir := Dir(bareDir)
+	if err := os.MkdirAll(dir, fs.DirStrict); err != nil {
+		return fmt.Errorf("failed to create journal directory: %w", err)
+	}
+	f, err := os.CreateTemp(dir, "snapshot-*.tar.gz")
+	if err != nil {
+		return fmt.Errorf("failed to create snapshot: %w", err)
+	}
+
+	deleted, err := writeSnapshot(f, entry.Worktree, paths)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(f.Name())
+		return fmt.Errorf("failed to create snapshot: %w", err)
+	}
+
+	entry.Snapshot = filepath.Base(f.Name())
+	entry.DeletedFiles = deleted
+	return nil
+}
+
+// Discard deletes the snapshot of an entry, if any
+func Discard(bareDir string, entry *Entry) {
+	if entry.Snapshot == "" {
+		return
+	}
+	_ = os.Remove(filepath.Join(Dir(bareDir), entry.Snapshot))
+	entry.Snapshot = ""
+}
+
+// RestoreSnapshot writes the files saved by Snapshot back into worktreePath
+// and deletes the files that had been deleted. Returns the number of paths
+// restored.
+func RestoreSnapshot(bareDir string, entry *Entry, worktreePath string) (int, error) {
+	restored := 0
+	if entry.Snapshot != "" {
+		f, err := os.Open(filepath.Join(Dir(bareDir), entry.Snapshot))
+		if err != nil {
+			return 0, fmt.Errorf("failed to open snapshot: %w", err)
+		}
+		defer func() { _ = f.Close() }()
+
+		restored, err = extractSnapshot(f, worktreePath)
+		if err != nil {
+			return restored, fmt.Errorf("failed to restore snapshot: %w", err)
+		}
+	}
+
+	for _, rel := range entry.DeletedFiles {
+		target, err := safeJoin(worktreePath, rel)
+		if err != nil {
+			return restored, err
+		}
+		if err := os.Remove(target); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return restored, err
+		}
+		restored++
+	}
+	return restored, nil
+}
+
+// writeSnapshot archives paths relative to root into w and returns the paths
+// that no longer exist on disk
+func writeSnapshot(w io.Writer, root string, paths []string) ([]string, error) {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	var deleted []string
+	for _, rel := range paths {
+		path := filepath.Join(root, filepath.FromSlash(rel))
+		info, err := os.Lstat(path)
+		if errors.Is(err, os.ErrNotExist) {
+			deleted = append(deleted, rel)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if info.IsDir() {
+			// Submodules are reported as directories; their contents are not ours
+			continue
+		}
+
+		link := ""
+		if info.Mode()&os.ModeSymlink != 0 {
+			if link, err = os.Readlink(path); err != nil {
+				return nil, err
+			}
+		}
+		hdr, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return nil, err
+		}
+		hdr.Name = rel
+		if err := tw.WriteHeader(hdr); err != nil {
+			return nil, err
+		}
+
+		if info.Mode().IsRegular() {
+			if err := copyFileTo(tw, path); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	return deleted, gz.Close()
+}
+
+func copyFileTo(w io.Writer, path string) error {
+	f, err := os.Open(path) //nolint:gosec // Path reported by git status
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+	_, err = io.Copy(w, f)
+	return err
+}
+
+// extractSnapshot unpacks a snapshot archive into root
+func extractSnapshot(r io.Reader, root string) (int, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = gz.Close() }()
+	tr := tar.NewReader(gz)
+
+	count := 0
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return count, nil
+		}
+		if err != nil {
+			return count, err
+		}
+
+		target, err := safeJoin(root, hdr.Name)
+		if err != nil {
+			return count, err
+		}
+		if err := os.MkdirAll(filepath.Dir(target), fs.DirGit); err != nil {
+			return count, err
+		}
+		// Replace whatever the checkout put there
+		if err := os.Remove(target); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return count, err
+		}
+
+		switch hdr.Typeflag {
+		case tar.TypeSymlink:
+			if err := os.Symlink(hdr.Linkname, target); err != nil {
+				return count, err
+			}
+		case tar.TypeReg:
+			if err := writeFileFrom(tr, target, hdr.FileInfo().Mode().Perm()); err != nil {
+				return count, err
+			}
+		default:
+			continue
+		}
+		count++
+	}
+}
+
+func writeFileFrom(r io.Reader, path string, perm os.FileMode) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm) //nolint:gosec // Path validated by safeJoin
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil { //nolint:gosec // Archive written by grove
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// safeJoin joins a slash-separated relative path onto root, rejecting paths
+// that would escape it
+func safeJoin(root, rel string) (string, error) {
+	clean := filepath.Clean(filepath.FromSlash(rel))
+	if filepath.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid path in snapshot: %s", rel)
+	}
+	return filepath.Join(root, clean), nil
+}
@@ -0,0 +1,129 @@
+package journal
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/sqve/grove/internal/testutil"
+	testgit "github.com/sqve/grove/internal/testutil/git"
+)
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	repo := testgit.NewTestRepo(t)
+	bareDir := testutil.TempDir(t)
+
+	repo.WriteFile("tracked.txt", "committed")
+	repo.Add("tracked.txt")
+	repo.Commit("add tracked")
+
+	repo.WriteFile("test.txt", "modified")
+	testutil.WriteFile(t, filepath.Join(repo.Path, "notes", "todo.txt"), "untracked")
+	if err := os.Remove(filepath.Join(repo.Path, "tracked.txt")); err != nil {
+		t.Fatal(err)
+	}
+	if runtime.GOOS != "windows" {
+		if err := os.Symlink("test.txt", filepath.Join(repo.Path, "link")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	entry := &Entry{Worktree: repo.Path}
+	if err := Snapshot(bareDir, entry); err != nil {
+		t.Fatalf("Snapshot() error = %v", err)
+	}
+	if entry.Snapshot == "" {
+		t.Fatal("expected snapshot to be written")
+	}
+	if len(entry.DeletedFiles) != 1 || entry.DeletedFiles[0] != "tracked.txt" {
+		t.Errorf("DeletedFiles = %v, want [tracked.txt]", entry.DeletedFiles)
+	}
+
+	// Restore into a fresh checkout of HEAD
+	restoreDir := filepath.Join(testutil.TempDir(t), "restored")
+	testutil.MustExec(t, repo.Path, "git", "worktree", "add", "--detach", restoreDir)
+
+	if _, err := RestoreSnapshot(bareDir, entry, restoreDir); err != nil {
+		t.Fatalf("RestoreSnapshot() error = %v", err)
+	}
+
+	assertFileContent(t, filepath.Join(restoreDir, "test.txt"), "modified")
+	assertFileContent(t, filepath.Join(restoreDir, "notes", "todo.txt"), "untracked")
+	if _, err := os.Stat(filepath.Join(restoreDir, "tracked.txt")); !os.IsNotExist(err) {
+		t.Error("expected deleted file to stay deleted")
+	}
+	if runtime.GOOS != "windows" {
+		if target, err := os.Readlink(filepath.Join(restoreDir, "link")); err != nil || target != "test.txt" {
+			t.Errorf("symlink = %q, %v; want test.txt", target, err)
+		}
+	}
+}
+
+func TestSnapshotCleanWorktree(t *testing.T) {
+	repo := testgit.NewTestRepo(t)
+	bareDir := testutil.TempDir(t)
+
+	entry := &Entry{Worktree: repo.Path}
+	if err := Snapshot(bareDir, entry); err != nil {
+		t.Fatalf("Snapshot() error = %v", err)
+	}
+	if entry.Snapshot != "" {
+		t.Errorf("expected no snapshot for clean worktree, got %q", entry.Snapshot)
+	}
+}
+
+func TestDiscard(t *testing.T) {
+	repo := testgit.NewTestRepo(t)
+	bareDir := testutil.TempDir(t)
+	repo.WriteFile("test.txt", "modified")
+
+	entry := &Entry{Worktree: repo.Path}
+	if err := Snapshot(bareDir, entry); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(Dir(bareDir), entry.Snapshot)
+
+	Discard(bareDir, entry)
+	if entry.Snapshot != "" {
+		t.Error("expected Discard to clear entry.Snapshot")
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("expected snapshot file to be deleted")
+	}
+}
+
+func TestSafeJoin(t *testing.T) {
+	root := filepath.Join("ws", "feat")
+
+	tests := []struct {
+		rel     string
+		wantErr bool
+	}{
+		{"src/app.go", false},
+		{"a/../b.txt", false},
+		{"../escape.txt", true},
+		{"..", true},
+		{"a/../../escape.txt", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rel, func(t *testing.T) {
+			_, err := safeJoin(root, tt.rel)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("safeJoin(%q) error = %v, wantErr %v", tt.rel, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func assertFileContent(t *testing.T, path, want string) {
+	t.Helper()
+	data, err := os.ReadFile(path) //nolint:gosec // Test path
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != want {
+		t.Errorf("%s = %q, want %q", filepath.Base(path), data, want)
+	}
+}
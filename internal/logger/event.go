@@ -0,0 +1,208 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// EventSchema identifies the event format described by event.schema.json.
+// Adding event types or optional fields keeps the version; removing or
+// changing the meaning of a field bumps it.
+const EventSchema = "grove.event/v1"
+
+// EventType names the kind of an event
+type EventType string
+
+const (
+	EventLog             EventType = "log"              // Level, Message
+	EventStepStarted     EventType = "step_started"     // Message
+	EventStepFinished    EventType = "step_finished"    // Message, Status
+	EventFilePreserved   EventType = "file_preserved"   // Worktree, Path
+	EventHookStarted     EventType = "hook_started"     // Worktree, Hook
+	EventHookOutput      EventType = "hook_output"      // Hook, Stream, Line
+	EventHookFinished    EventType = "hook_finished"    // Worktree, Hook, ExitCode
+	EventWorktreeCreated EventType = "worktree_created" // Worktree, Branch
+	EventWorktreeRemoved EventType = "worktree_removed" // Worktree, Branch
+	EventExecFinished    EventType = "exec_finished"    // Worktree, ExitCode
+	EventOutput          EventType = "output"           // Stream, Line, Worktree for exec
+	EventError           EventType = "error"            // Message
+)
+
+// EventTypes lists every event type in the schema
+var EventTypes = []EventType{
+	EventLog, EventStepStarted, EventStepFinished, EventFilePreserved,
+	EventHookStarted, EventHookOutput, EventHookFinished,
+	EventWorktreeCreated, EventWorktreeRemoved, EventExecFinished,
+	EventOutput, EventError,
+}
+
+// Event is a single line of --output=ndjson. Schema and Time are set by Emit.
+type Event struct {
+	Schema   string    `json:"schema"`
+	Type     EventType `json:"type"`
+	Time     time.Time `json:"time"`
+	Level    string    `json:"level,omitempty"`
+	Message  string    `json:"message,omitempty"`
+	Status   string    `json:"status,omitempty"`
+	Worktree string    `json:"worktree,omitempty"`
+	Branch   string    `json:"branch,omitempty"`
+	Path     string    `json:"path,omitempty"`
+	Hook     string    `json:"hook,omitempty"`
+	Stream   string    `json:"stream,omitempty"`
+	Line     *string   `json:"line,omitempty"`
+	ExitCode *int      `json:"exit_code,omitempty"`
+}
+
+var (
+	eventsMu  sync.Mutex
+	eventsOut atomic.Pointer[io.Writer]
+)
+
+// EnableEvents writes events to w instead of printing messages. Pass nil to
+// go back to text output.
+func EnableEvents(w io.Writer) {
+	if w == nil {
+		eventsOut.Store(nil)
+		return
+	}
+	eventsOut.Store(&w)
+}
+
+// EventsEnabled returns true if messages are emitted as events
+func EventsEnabled() bool {
+	return eventsOut.Load() != nil
+}
+
+// Emit writes e as a single JSON line. Does nothing unless events are enabled,
+// so callers can emit unconditionally next to their text output.
+func Emit(e Event) {
+	w := eventsOut.Load()
+	if w == nil {
+		return
+	}
+	e.Schema = EventSchema
+	e.Time = time.Now().UTC()
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	data = append(data, '\n')
+
+	eventsMu.Lock()
+	defer eventsMu.Unlock()
+	_, _ = (*w).Write(data)
+}
+
+// emitLog emits a log event, reporting whether events are enabled
+func emitLog(level, message string) bool {
+	if !EventsEnabled() {
+		return false
+	}
+	Emit(Event{Type: EventLog, Level: level, Message: message})
+	return true
+}
+
+// ExitCode returns a pointer to code for Event.ExitCode
+func ExitCode(code int) *int {
+	return &code
+}
+
+// EventWriter emits each complete line written to it as a copy of its
+// template event with Line set.
+type EventWriter struct {
+	template Event
+	buf      bytes.Buffer
+	mu       sync.Mutex
+}
+
+func NewEventWriter(template Event) *EventWriter {
+	return &EventWriter{template: template}
+}
+
+func (w *EventWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	n, _ := w.buf.Write(p)
+	for {
+		line, err := w.buf.ReadString('\n')
+		if err != nil {
+			w.buf.WriteString(line)
+			return n, nil
+		}
+		w.emit(line)
+	}
+}
+
+// Flush emits any buffered partial line
+func (w *EventWriter) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.buf.Len() > 0 {
+		w.emit(w.buf.String())
+		w.buf.Reset()
+	}
+	return nil
+}
+
+func (w *EventWriter) emit(line string) {
+	e := w.template
+	line = strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r")
+	e.Line = &line
+	Emit(e)
+}
+
+// StartEventStream switches to --output=ndjson. Events are written to stdout,
+// and anything else written to os.Stdout or os.Stderr, such as command output,
+// is wrapped in output events so stdout stays valid NDJSON. The returned
+// function restores both files and must be called before exiting.
+func StartEventStream() (func(), error) {
+	EnableEvents(os.Stdout)
+
+	var wg sync.WaitGroup
+	var restores []func()
+	restore := func() {
+		for _, r := range restores {
+			r()
+		}
+		wg.Wait()
+	}
+
+	for _, stream := range []struct {
+		file **os.File
+		name string
+	}{{&os.Stdout, "stdout"}, {&os.Stderr, "stderr"}} {
+		r, w, err := os.Pipe()
+		if err != nil {
+			restore()
+			EnableEvents(nil)
+			return nil, err
+		}
+
+		original := *stream.file
+		*stream.file = w
+		restores = append(restores, func() {
+			*stream.file = original
+			_ = w.Close()
+		})
+
+		out := NewEventWriter(Event{Type: EventOutput, Stream: stream.name})
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = io.Copy(out, r)
+			_ = out.Flush()
+			_ = r.Close()
+		}()
+	}
+
+	return restore, nil
+}
@@ -0,0 +1,270 @@
+package logger
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/sqve/grove/internal/config"
+)
+
+// loadEventSchema reads the checked-in JSON Schema for events
+func loadEventSchema(t *testing.T) map[string]any {
+	t.Helper()
+
+	data, err := os.ReadFile("event.schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var schema map[string]any
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatalf("event.schema.json is not valid JSON: %v", err)
+	}
+	return schema
+}
+
+// validate checks value against the subset of JSON Schema that
+// event.schema.json uses and returns the violations.
+func validate(schema map[string]any, value any) []string {
+	var errs []string
+
+	if want, ok := schema["const"]; ok && !reflect.DeepEqual(want, value) {
+		errs = append(errs, fmt.Sprintf("%v is not %v", value, want))
+	}
+	if enum, ok := schema["enum"].([]any); ok && !slices.ContainsFunc(enum, func(v any) bool { return reflect.DeepEqual(v, value) }) {
+		errs = append(errs, fmt.Sprintf("%v is not one of %v", value, enum))
+	}
+
+	switch schema["type"] {
+	case "string":
+		if _, ok := value.(string); !ok {
+			errs = append(errs, fmt.Sprintf("%v is not a string", value))
+		}
+	case "integer":
+		if n, ok := value.(float64); !ok || n != float64(int64(n)) {
+			errs = append(errs, fmt.Sprintf("%v is not an integer", value))
+		}
+	case "object":
+		if _, ok := value.(map[string]any); !ok {
+			return append(errs, fmt.Sprintf("%v is not an object", value))
+		}
+	}
+
+	object, isObject := value.(map[string]any)
+	if !isObject {
+		return errs
+	}
+
+	if required, ok := schema["required"].([]any); ok {
+		for _, name := range required {
+			if _, ok := object[name.(string)]; !ok {
+				errs = append(errs, fmt.Sprintf("missing %q", name))
+			}
+		}
+	}
+
+	properties, _ := schema["properties"].(map[string]any)
+	for name, v := range object {
+		property, ok := properties[name].(map[string]any)
+		if !ok {
+			if schema["additionalProperties"] == false {
+				errs = append(errs, fmt.Sprintf("unknown property %q", name))
+			}
+			continue
+		}
+		for _, err := range validate(property, v) {
+			errs = append(errs, name+": "+err)
+		}
+	}
+
+	if allOf, ok := schema["allOf"].([]any); ok {
+		for _, sub := range allOf {
+			sub := sub.(map[string]any)
+			if cond, ok := sub["if"].(map[string]any); ok {
+				if len(validate(cond, value)) == 0 {
+					errs = append(errs, validate(sub["then"].(map[string]any), value)...)
+				}
+				continue
+			}
+			errs = append(errs, validate(sub, value)...)
+		}
+	}
+
+	return errs
+}
+
+// captureEvents enables events for the duration of fn and returns the
+// decoded events
+func captureEvents(t *testing.T, fn func()) []map[string]any {
+	t.Helper()
+
+	var buf bytes.Buffer
+	EnableEvents(&buf)
+	defer EnableEvents(nil)
+	fn()
+
+	var events []map[string]any
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var event map[string]any
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			t.Fatalf("line is not JSON: %q: %v", scanner.Text(), err)
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+func TestEventSchemaListsEventTypes(t *testing.T) {
+	schema := loadEventSchema(t)
+	properties := schema["properties"].(map[string]any)
+
+	if got := properties["schema"].(map[string]any)["const"]; got != EventSchema {
+		t.Errorf("schema version = %v, want %v", got, EventSchema)
+	}
+
+	var types []string
+	for _, v := range properties["type"].(map[string]any)["enum"].([]any) {
+		types = append(types, v.(string))
+	}
+	var want []string
+	for _, eventType := range EventTypes {
+		want = append(want, string(eventType))
+	}
+	if !slices.Equal(types, want) {
+		t.Errorf("schema types = %v, want %v", types, want)
+	}
+}
+
+func TestEmittedEventsMatchSchema(t *testing.T) {
+	config.SetPlain(true)
+	Init(true, true)
+	defer func() {
+		config.SetPlain(false)
+		Init(false, false)
+	}()
+	schema := loadEventSchema(t)
+
+	events := captureEvents(t, func() {
+		Debug("debug %d", 1)
+		Info("info")
+		Success("success")
+		Warning("warning")
+		Error("error")
+		Dimmed("")
+		ListItemWithNote("main", "note")
+		ListItemGroup("preserved 1 file:", []string{".env"})
+
+		spin := StartSpinner("Setting up worktree...")
+		spin.StopWithError("Failed")
+		spin.Stop()
+
+		w := NewEventWriter(Event{Type: EventHookOutput, Hook: "make", Stream: "stdout"})
+		_, _ = w.Write([]byte("one\n\ntwo"))
+		_ = w.Flush()
+
+		Emit(Event{Type: EventFilePreserved, Worktree: "/ws/feat", Path: ".env"})
+		Emit(Event{Type: EventHookStarted, Worktree: "/ws/feat", Hook: "make"})
+		Emit(Event{Type: EventHookFinished, Worktree: "/ws/feat", Hook: "make", ExitCode: ExitCode(0)})
+		Emit(Event{Type: EventWorktreeCreated, Worktree: "/ws/feat", Branch: "feat"})
+		Emit(Event{Type: EventWorktreeRemoved, Worktree: "/ws/feat"})
+		Emit(Event{Type: EventExecFinished, Worktree: "/ws/feat", ExitCode: ExitCode(2)})
+		Emit(Event{Type: EventError, Message: "failed"})
+	})
+
+	if len(events) != 22 {
+		t.Fatalf("expected 22 events, got %d: %v", len(events), events)
+	}
+	for _, event := range events {
+		if errs := validate(schema, event); len(errs) > 0 {
+			t.Errorf("event %v does not match schema: %s", event, strings.Join(errs, "; "))
+		}
+	}
+
+	finished := events[10]
+	if finished["type"] != string(EventStepFinished) || finished["status"] != "error" {
+		t.Errorf("expected step to finish with error once, got %v", finished)
+	}
+	if line, ok := events[13]["line"]; !ok || line != "" {
+		t.Errorf("expected empty line to be kept, got %v", events[13])
+	}
+}
+
+func TestSchemaRejectsInvalidEvents(t *testing.T) {
+	schema := loadEventSchema(t)
+
+	tests := []struct {
+		name  string
+		event string
+	}{
+		{"unknown type", `{"schema":"grove.event/v1","type":"nope","time":"2026-01-01T00:00:00Z"}`},
+		{"other version", `{"schema":"grove.event/v2","type":"error","time":"2026-01-01T00:00:00Z","message":"x"}`},
+		{"missing field for type", `{"schema":"grove.event/v1","type":"hook_finished","time":"2026-01-01T00:00:00Z","hook":"make"}`},
+		{"unknown field", `{"schema":"grove.event/v1","type":"error","time":"2026-01-01T00:00:00Z","message":"x","extra":1}`},
+		{"wrong type", `{"schema":"grove.event/v1","type":"exec_finished","time":"2026-01-01T00:00:00Z","worktree":"/ws","exit_code":"1"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var event map[string]any
+			if err := json.Unmarshal([]byte(tt.event), &event); err != nil {
+				t.Fatal(err)
+			}
+			if errs := validate(schema, event); len(errs) == 0 {
+				t.Errorf("expected %s to be rejected", tt.event)
+			}
+		})
+	}
+}
+
+func TestStartEventStream(t *testing.T) {
+	oldStdout, oldStderr := os.Stdout, os.Stderr
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+	defer func() {
+		os.Stdout, os.Stderr = oldStdout, oldStderr
+		EnableEvents(nil)
+	}()
+
+	stop, err := StartEventStream()
+	if err != nil {
+		t.Fatalf("StartEventStream failed: %v", err)
+	}
+	fmt.Fprintln(os.Stdout, "table row")
+	fmt.Fprint(os.Stderr, "partial")
+	Info("message")
+	stop()
+	EnableEvents(nil)
+	_ = w.Close()
+
+	var events []map[string]any
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		var event map[string]any
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			t.Fatalf("line is not JSON: %q: %v", scanner.Text(), err)
+		}
+		delete(event, "schema")
+		delete(event, "time")
+		events = append(events, event)
+	}
+
+	for _, want := range []map[string]any{
+		{"type": "output", "stream": "stdout", "line": "table row"},
+		{"type": "output", "stream": "stderr", "line": "partial"},
+		{"type": "log", "level": "info", "message": "message"},
+	} {
+		if !slices.ContainsFunc(events, func(e map[string]any) bool { return reflect.DeepEqual(e, want) }) {
+			t.Errorf("expected %v in %v", want, events)
+		}
+	}
+	if os.Stdout != w || os.Stderr != oldStderr {
+		t.Error("expected stop to restore stdout and stderr")
+	}
+}
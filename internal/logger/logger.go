@@ -58,15 +58,18 @@ func isDebug() bool {
 
 // Debug prints debug information when debug mode is enabled
 func Debug(format string, args ...any) {
-	if isDebug() {
+	if isDebug() && !emitLog("debug", fmt.Sprintf(format, args...)) {
 		_, _ = fmt.Fprintf(getOutput(), "[DEBUG] "+format+"\n", args...)
 	}
 }
 
 // Success prints success messages to stderr
 func Success(format string, args ...any) {
-	out := getOutput()
 	message := fmt.Sprintf(format, args...)
+	if emitLog("success", message) {
+		return
+	}
+	out := getOutput()
 	if isPlain() {
 		_, _ = fmt.Fprintln(out, message)
 	} else {
@@ -76,8 +79,11 @@ func Success(format string, args ...any) {
 
 // Error prints error messages to stderr
 func Error(format string, args ...any) {
-	out := getOutput()
 	message := fmt.Sprintf(format, args...)
+	if emitLog("error", message) {
+		return
+	}
+	out := getOutput()
 	if isPlain() {
 		_, _ = fmt.Fprintf(out, "Error: %s\n", message)
 	} else {
@@ -87,8 +93,11 @@ func Error(format string, args ...any) {
 
 // Info prints informational messages to stderr
 func Info(format string, args ...any) {
-	out := getOutput()
 	message := fmt.Sprintf(format, args...)
+	if emitLog("info", message) {
+		return
+	}
+	out := getOutput()
 	if isPlain() {
 		_, _ = fmt.Fprintln(out, message)
 	} else {
@@ -98,8 +107,11 @@ func Info(format string, args ...any) {
 
 // Warning prints warning messages to stderr
 func Warning(format string, args ...any) {
-	out := getOutput()
 	message := fmt.Sprintf(format, args...)
+	if emitLog("warning", message) {
+		return
+	}
+	out := getOutput()
 	if isPlain() {
 		_, _ = fmt.Fprintf(out, "Warning: %s\n", message)
 	} else {
@@ -109,8 +121,11 @@ func Warning(format string, args ...any) {
 
 // Dimmed prints dimmed/secondary messages to stderr
 func Dimmed(format string, args ...any) {
-	out := getOutput()
 	message := fmt.Sprintf(format, args...)
+	if emitLog("info", message) {
+		return
+	}
+	out := getOutput()
 	if isPlain() {
 		_, _ = fmt.Fprintln(out, message)
 	} else {
@@ -120,6 +135,13 @@ func Dimmed(format string, args ...any) {
 
 // ListItemWithNote prints a list item with an optional note in parentheses to stderr
 func ListItemWithNote(main, note string) {
+	message := main
+	if note != "" {
+		message += " (" + note + ")"
+	}
+	if emitLog("success", message) {
+		return
+	}
 	out := getOutput()
 	if isPlain() {
 		if note != "" {
@@ -143,8 +165,11 @@ func ListItemWithNote(main, note string) {
 
 // ListSubItem prints an indented sub-item to stderr (used for additional details under a list item)
 func ListSubItem(format string, args ...any) {
-	out := getOutput()
 	message := fmt.Sprintf(format, args...)
+	if emitLog("info", message) {
+		return
+	}
+	out := getOutput()
 	if isPlain() {
 		_, _ = fmt.Fprintf(out, "    > %s\n", message)
 	} else {
@@ -161,6 +186,9 @@ func ListItemGroup(header string, items []string) {
 	out := getOutput()
 	ListSubItem("%s", header)
 	for _, item := range items {
+		if emitLog("info", item) {
+			continue
+		}
 		if isPlain() {
 			_, _ = fmt.Fprintf(out, "        %s\n", item)
 		} else {
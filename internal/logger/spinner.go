@@ -15,12 +15,21 @@ type Spinner struct {
 	done    chan struct{}
 	once    sync.Once
 	wg      sync.WaitGroup
+	events  bool
+	emitted sync.Once
 }
 
 func StartSpinner(message string) *Spinner {
 	s := &Spinner{done: make(chan struct{})}
 	s.message.Store(message)
 
+	if EventsEnabled() {
+		s.events = true
+		Emit(Event{Type: EventStepStarted, Message: message})
+		s.once.Do(func() { close(s.done) })
+		return s
+	}
+
 	if isPlain() {
 		fmt.Fprintf(os.Stderr, "%s %s\n", styles.Render(&styles.Info, "→"), message)
 		s.once.Do(func() { close(s.done) })
@@ -59,16 +68,27 @@ func (s *Spinner) Update(message string) {
 }
 
 func (s *Spinner) Stop() {
-	s.once.Do(func() { close(s.done) })
-	s.wg.Wait()
+	s.stop("ok")
 }
 
 func (s *Spinner) StopWithSuccess(message string) {
-	s.Stop()
+	s.stop("ok")
 	Success("%s", message)
 }
 
 func (s *Spinner) StopWithError(message string) {
-	s.Stop()
+	s.stop("error")
 	Error("%s", message)
 }
+
+// stop ends the spinner, reporting the first status to event consumers
+func (s *Spinner) stop(status string) {
+	s.once.Do(func() { close(s.done) })
+	s.wg.Wait()
+	if s.events {
+		s.emitted.Do(func() {
+			msg, _ := s.message.Load().(string)
+			Emit(Event{Type: EventStepFinished, Message: msg, Status: status})
+		})
+	}
+}
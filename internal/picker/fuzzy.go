@@ -0,0 +1,85 @@
+package picker
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Scoring weights for fuzzy matches
+const (
+	scoreMatch       = 1 // Each matched rune
+	scoreConsecutive = 8 // Matched rune directly follows the previous match
+	scoreBoundary    = 6 // Matched rune starts a word (after / - _ . or space)
+)
+
+// match reports whether every rune of pattern appears in text in order,
+// ignoring case, and returns the score of the best alignment. Higher scores
+// rank first; runs of consecutive runes and word starts score highest.
+func match(pattern, text string) (int, bool) {
+	if pattern == "" {
+		return 0, true
+	}
+
+	p := []rune(strings.ToLower(pattern))
+	t := []rune(strings.ToLower(text))
+	if len(p) > len(t) {
+		return 0, false
+	}
+
+	// prev[j] is the best score with the previous pattern rune matched at
+	// t[j], or -1 if it cannot be matched there.
+	prev := make([]int, len(t))
+	for j := range t {
+		prev[j] = -1
+		if t[j] == p[0] {
+			prev[j] = scoreMatch + boundaryBonus(t, j)
+		}
+	}
+
+	for i := 1; i < len(p); i++ {
+		cur := make([]int, len(t))
+		best := -1 // Best prev[k] for k < j-1 (a gap before t[j])
+		for j := range t {
+			cur[j] = -1
+			if j >= 2 && prev[j-2] > best {
+				best = prev[j-2]
+			}
+			if j == 0 || t[j] != p[i] {
+				continue
+			}
+
+			score := best
+			if prev[j-1] >= 0 && prev[j-1]+scoreConsecutive > score {
+				score = prev[j-1] + scoreConsecutive
+			}
+			if score >= 0 {
+				cur[j] = score + scoreMatch + boundaryBonus(t, j)
+			}
+		}
+		prev = cur
+	}
+
+	best := -1
+	for _, score := range prev {
+		best = max(best, score)
+	}
+	if best < 0 {
+		return 0, false
+	}
+	return best, true
+}
+
+func boundaryBonus(t []rune, j int) int {
+	if j == 0 || isBoundary(t[j-1]) {
+		return scoreBoundary
+	}
+	return 0
+}
+
+func isBoundary(r rune) bool {
+	switch r {
+	case '/', '-', '_', '.':
+		return true
+	}
+	return unicode.IsSpace(r)
+}
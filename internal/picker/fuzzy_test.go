@@ -0,0 +1,50 @@
+package picker
+
+import "testing"
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		text    string
+		want    bool
+	}{
+		{"empty pattern matches anything", "", "main", true},
+		{"exact", "main", "main", true},
+		{"subsequence", "fa", "feat-auth", true},
+		{"case insensitive", "FEAT", "feat/auth", true},
+		{"out of order", "af", "fa", false},
+		{"missing rune", "mainx", "main", false},
+		{"unicode", "öl", "Öl-branch", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, ok := match(tt.pattern, tt.text); ok != tt.want {
+				t.Errorf("match(%q, %q) = %v, want %v", tt.pattern, tt.text, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatch_Ranking(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		better  string
+		worse   string
+	}{
+		{"consecutive beats scattered", "auth", "feat-auth", "a-u-t-h"},
+		{"word boundary beats mid-word", "fa", "feat/auth", "sofa"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			better, _ := match(tt.pattern, tt.better)
+			worse, _ := match(tt.pattern, tt.worse)
+			if better <= worse {
+				t.Errorf("expected %q (%d) to outscore %q (%d) for %q", tt.better, better, tt.worse, worse, tt.pattern)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package picker
+
+import "unicode/utf8"
+
+// keyKind identifies a key press the picker responds to
+type keyKind int
+
+const (
+	keyRune keyKind = iota
+	keyEnter
+	keyEscape
+	keyInterrupt
+	keyUp
+	keyDown
+	keyTab
+	keyBackspace
+	keyClearQuery
+)
+
+type key struct {
+	kind keyKind
+	r    rune // Set for keyRune
+}
+
+// parseKeys decodes a chunk of raw terminal input into key presses.
+// Unknown control characters and escape sequences are dropped.
+func parseKeys(input []byte) []key {
+	var keys []key
+	for len(input) > 0 {
+		b := input[0]
+		switch {
+		case b == 0x1b:
+			k, n := parseEscape(input)
+			if k != nil {
+				keys = append(keys, *k)
+			}
+			input = input[n:]
+			continue
+		case b == '\r' || b == '\n':
+			keys = append(keys, key{kind: keyEnter})
+		case b == 0x03: // Ctrl-C
+			keys = append(keys, key{kind: keyInterrupt})
+		case b == 0x10 || b == 0x0b: // Ctrl-P, Ctrl-K
+			keys = append(keys, key{kind: keyUp})
+		case b == 0x0e: // Ctrl-N
+			keys = append(keys, key{kind: keyDown})
+		case b == '\t':
+			keys = append(keys, key{kind: keyTab})
+		case b == 0x7f || b == 0x08: // Backspace, Ctrl-H
+			keys = append(keys, key{kind: keyBackspace})
+		case b == 0x15: // Ctrl-U
+			keys = append(keys, key{kind: keyClearQuery})
+		case b < 0x20:
+			// Ignore other control characters
+		default:
+			r, size := utf8.DecodeRune(input)
+			if r != utf8.RuneError {
+				keys = append(keys, key{kind: keyRune, r: r})
+			}
+			input = input[size:]
+			continue
+		}
+		input = input[1:]
+	}
+	return keys
+}
+
+// parseEscape decodes an escape sequence at the start of input. It returns
+// the key (nil if unrecognised) and the number of bytes consumed.
+func parseEscape(input []byte) (*key, int) {
+	// A lone ESC is the Escape key itself
+	if len(input) == 1 {
+		return &key{kind: keyEscape}, 1
+	}
+
+	// CSI (ESC [) and SS3 (ESC O) sequences carry arrow keys
+	if input[1] != '[' && input[1] != 'O' {
+		return &key{kind: keyEscape}, 1
+	}
+
+	for i := 2; i < len(input); i++ {
+		b := input[i]
+		if b < 0x40 || b > 0x7e {
+			continue // Parameter or intermediate byte
+		}
+		switch b {
+		case 'A':
+			return &key{kind: keyUp}, i + 1
+		case 'B':
+			return &key{kind: keyDown}, i + 1
+		case 'Z': // Shift-Tab
+			return &key{kind: keyUp}, i + 1
+		default:
+			return nil, i + 1
+		}
+	}
+
+	// Incomplete sequence: drop the rest of the chunk
+	return nil, len(input)
+}
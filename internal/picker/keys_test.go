@@ -0,0 +1,37 @@
+package picker
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseKeys(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []key
+	}{
+		{"printable runes", "ab", []key{{kind: keyRune, r: 'a'}, {kind: keyRune, r: 'b'}}},
+		{"multibyte rune", "ö", []key{{kind: keyRune, r: 'ö'}}},
+		{"enter", "\r", []key{{kind: keyEnter}}},
+		{"lone escape", "\x1b", []key{{kind: keyEscape}}},
+		{"ctrl-c", "\x03", []key{{kind: keyInterrupt}}},
+		{"csi arrows", "\x1b[A\x1b[B", []key{{kind: keyUp}, {kind: keyDown}}},
+		{"ss3 arrows", "\x1bOA\x1bOB", []key{{kind: keyUp}, {kind: keyDown}}},
+		{"ctrl-p and ctrl-n", "\x10\x0e", []key{{kind: keyUp}, {kind: keyDown}}},
+		{"tab and shift-tab", "\t\x1b[Z", []key{{kind: keyTab}, {kind: keyUp}}},
+		{"backspace", "\x7f", []key{{kind: keyBackspace}}},
+		{"ctrl-u", "\x15", []key{{kind: keyClearQuery}}},
+		{"unknown sequence is dropped", "\x1b[3~x", []key{{kind: keyRune, r: 'x'}}},
+		{"other control characters are ignored", "\x01a", []key{{kind: keyRune, r: 'a'}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseKeys([]byte(tt.input))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseKeys(%q) = %+v, want %+v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
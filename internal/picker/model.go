@@ -0,0 +1,187 @@
+package picker
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/sqve/grove/internal/config"
+	"github.com/sqve/grove/internal/styles"
+)
+
+// maxVisibleItems caps the list height so the picker stays compact
+const maxVisibleItems = 10
+
+// model holds picker state. It has no terminal dependencies so key handling
+// and rendering can be tested directly.
+type model struct {
+	items     []Item
+	multi     bool
+	prompt    string
+	query     []rune
+	matches   []int // Indexes into items, best match first
+	cursor    int   // Index into matches
+	offset    int   // First visible match
+	selected  map[int]bool
+	done      bool
+	cancelled bool
+}
+
+func newModel(items []Item, opts Options) *model {
+	m := &model{
+		items:    items,
+		multi:    opts.Multi,
+		prompt:   opts.Prompt,
+		selected: make(map[int]bool),
+	}
+	m.filter()
+	return m
+}
+
+// filter recomputes matches for the current query, keeping the original item
+// order among equal scores.
+func (m *model) filter() {
+	query := string(m.query)
+	scores := make(map[int]int, len(m.items))
+	m.matches = m.matches[:0]
+	for i, item := range m.items {
+		if score, ok := match(query, item.Text); ok {
+			scores[i] = score
+			m.matches = append(m.matches, i)
+		}
+	}
+	sort.SliceStable(m.matches, func(a, b int) bool {
+		return scores[m.matches[a]] > scores[m.matches[b]]
+	})
+	m.cursor = 0
+	m.offset = 0
+}
+
+func (m *model) handle(k key) {
+	switch k.kind {
+	case keyRune:
+		m.query = append(m.query, k.r)
+		m.filter()
+	case keyBackspace:
+		if len(m.query) > 0 {
+			m.query = m.query[:len(m.query)-1]
+			m.filter()
+		}
+	case keyClearQuery:
+		m.query = m.query[:0]
+		m.filter()
+	case keyUp:
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case keyDown:
+		if m.cursor < len(m.matches)-1 {
+			m.cursor++
+		}
+	case keyTab:
+		if m.multi && len(m.matches) > 0 {
+			idx := m.matches[m.cursor]
+			m.selected[idx] = !m.selected[idx]
+			if m.cursor < len(m.matches)-1 {
+				m.cursor++
+			}
+		}
+	case keyEnter:
+		if len(m.matches) > 0 || len(m.selectedIndexes()) > 0 {
+			m.done = true
+		}
+	case keyEscape, keyInterrupt:
+		m.cancelled = true
+	}
+}
+
+// result returns the chosen values. In multi mode the explicitly selected
+// items are returned in list order; with none selected, the item under the
+// cursor is used.
+func (m *model) result() []string {
+	if m.multi {
+		if indexes := m.selectedIndexes(); len(indexes) > 0 {
+			values := make([]string, 0, len(indexes))
+			for _, idx := range indexes {
+				values = append(values, m.items[idx].Value)
+			}
+			return values
+		}
+	}
+	if len(m.matches) == 0 {
+		return nil
+	}
+	return []string{m.items[m.matches[m.cursor]].Value}
+}
+
+func (m *model) selectedIndexes() []int {
+	var indexes []int
+	for i := range m.items {
+		if m.selected[i] {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
+
+// view renders the prompt, the visible slice of matches and a status line,
+// each truncated to width.
+func (m *model) view(width, height int) []string {
+	rows := min(maxVisibleItems, len(m.items))
+	if height > 2 {
+		rows = min(rows, height-2)
+	}
+	rows = max(rows, 1)
+
+	// Keep the cursor within the visible window
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+rows {
+		m.offset = m.cursor - rows + 1
+	}
+
+	pointer, mark := "▌", "◆"
+	if config.IsPlain() {
+		pointer, mark = ">", "*"
+	}
+
+	lines := make([]string, 0, rows+2)
+	lines = append(lines, fmt.Sprintf("%s %s %s", styles.Render(&styles.Info, "?"), m.prompt, string(m.query)))
+
+	for i := m.offset; i < m.offset+rows; i++ {
+		if i >= len(m.matches) {
+			lines = append(lines, "")
+			continue
+		}
+		idx := m.matches[i]
+
+		prefix := " "
+		if i == m.cursor {
+			prefix = styles.Render(&styles.Info, pointer)
+		}
+		if m.multi {
+			if m.selected[idx] {
+				prefix += styles.Render(&styles.Success, mark)
+			} else {
+				prefix += " "
+			}
+		}
+		lines = append(lines, prefix+" "+m.items[idx].Label)
+	}
+
+	status := fmt.Sprintf("  %d/%d", len(m.matches), len(m.items))
+	if m.multi {
+		status += fmt.Sprintf(" (%d selected, tab to toggle)", len(m.selectedIndexes()))
+	}
+	lines = append(lines, styles.Render(&styles.Dimmed, status))
+
+	if width > 1 {
+		truncate := lipgloss.NewStyle().MaxWidth(width - 1)
+		for i, line := range lines {
+			lines[i] = truncate.Render(line)
+		}
+	}
+
+	return lines
+}
@@ -0,0 +1,180 @@
+package picker
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sqve/grove/internal/config"
+)
+
+func testItems() []Item {
+	return []Item{
+		{Label: "main [main]", Text: "main main", Value: "main"},
+		{Label: "feat-auth [feat/auth]", Text: "feat-auth feat/auth", Value: "feat-auth"},
+		{Label: "bugfix [fix/login]", Text: "bugfix fix/login", Value: "bugfix"},
+	}
+}
+
+func typeKeys(m *model, input string) {
+	for _, k := range parseKeys([]byte(input)) {
+		m.handle(k)
+	}
+}
+
+func TestModel_Single(t *testing.T) {
+	t.Run("enter picks item under cursor", func(t *testing.T) {
+		m := newModel(testItems(), Options{})
+		typeKeys(m, "\x1b[B\r")
+
+		if !m.done {
+			t.Fatal("expected picker to be done")
+		}
+		if got := m.result(); !reflect.DeepEqual(got, []string{"feat-auth"}) {
+			t.Errorf("expected [feat-auth], got %v", got)
+		}
+	})
+
+	t.Run("query filters and ranks matches", func(t *testing.T) {
+		m := newModel(testItems(), Options{})
+		typeKeys(m, "login\r")
+
+		if got := m.result(); !reflect.DeepEqual(got, []string{"bugfix"}) {
+			t.Errorf("expected [bugfix], got %v", got)
+		}
+	})
+
+	t.Run("backspace and ctrl-u widen the filter", func(t *testing.T) {
+		m := newModel(testItems(), Options{})
+		typeKeys(m, "mainx")
+		if len(m.matches) != 0 {
+			t.Fatalf("expected no matches, got %d", len(m.matches))
+		}
+		typeKeys(m, "\x7f")
+		if len(m.matches) != 1 {
+			t.Fatalf("expected 1 match after backspace, got %d", len(m.matches))
+		}
+		typeKeys(m, "\x15")
+		if len(m.matches) != 3 {
+			t.Fatalf("expected all matches after clearing, got %d", len(m.matches))
+		}
+	})
+
+	t.Run("enter with no matches does nothing", func(t *testing.T) {
+		m := newModel(testItems(), Options{})
+		typeKeys(m, "zzz\r")
+		if m.done {
+			t.Error("expected picker to stay open with no matches")
+		}
+	})
+
+	t.Run("escape cancels", func(t *testing.T) {
+		m := newModel(testItems(), Options{})
+		typeKeys(m, "\x1b")
+		if !m.cancelled {
+			t.Error("expected picker to be cancelled")
+		}
+	})
+
+	t.Run("cursor stays in bounds", func(t *testing.T) {
+		m := newModel(testItems(), Options{})
+		typeKeys(m, "\x1b[A\x1b[B\x1b[B\x1b[B\x1b[B")
+		if m.cursor != 2 {
+			t.Errorf("expected cursor 2, got %d", m.cursor)
+		}
+	})
+
+	t.Run("tab does not select", func(t *testing.T) {
+		m := newModel(testItems(), Options{})
+		typeKeys(m, "\t\r")
+		if got := m.result(); !reflect.DeepEqual(got, []string{"main"}) {
+			t.Errorf("expected [main], got %v", got)
+		}
+	})
+}
+
+func TestModel_Multi(t *testing.T) {
+	t.Run("tab toggles selection in list order", func(t *testing.T) {
+		m := newModel(testItems(), Options{Multi: true})
+		// Select bugfix, then main, via the filter
+		typeKeys(m, "bug\t\x15\t\r")
+
+		if got := m.result(); !reflect.DeepEqual(got, []string{"main", "bugfix"}) {
+			t.Errorf("expected [main bugfix], got %v", got)
+		}
+	})
+
+	t.Run("enter without selection picks cursor item", func(t *testing.T) {
+		m := newModel(testItems(), Options{Multi: true})
+		typeKeys(m, "\x1b[B\r")
+
+		if got := m.result(); !reflect.DeepEqual(got, []string{"feat-auth"}) {
+			t.Errorf("expected [feat-auth], got %v", got)
+		}
+	})
+
+	t.Run("tab twice deselects", func(t *testing.T) {
+		m := newModel(testItems(), Options{Multi: true})
+		typeKeys(m, "\t\x1b[A\t")
+		if len(m.selectedIndexes()) != 0 {
+			t.Errorf("expected no selection, got %v", m.selectedIndexes())
+		}
+	})
+}
+
+func TestModel_View(t *testing.T) {
+	config.SetPlain(true)
+	defer config.SetPlain(false)
+
+	t.Run("renders prompt, rows and status", func(t *testing.T) {
+		m := newModel(testItems(), Options{Prompt: "Switch to"})
+		typeKeys(m, "ma")
+
+		lines := m.view(80, 24)
+		if !strings.Contains(lines[0], "Switch to ma") {
+			t.Errorf("expected prompt with query, got %q", lines[0])
+		}
+		if !strings.HasPrefix(lines[1], "> main [main]") {
+			t.Errorf("expected cursor on main, got %q", lines[1])
+		}
+		if !strings.Contains(lines[len(lines)-1], "/3") {
+			t.Errorf("expected match count in status, got %q", lines[len(lines)-1])
+		}
+	})
+
+	t.Run("marks selected items in multi mode", func(t *testing.T) {
+		m := newModel(testItems(), Options{Multi: true})
+		typeKeys(m, "\t")
+
+		lines := m.view(80, 24)
+		if !strings.HasPrefix(lines[1], " * main") {
+			t.Errorf("expected main marked as selected, got %q", lines[1])
+		}
+		if !strings.Contains(lines[len(lines)-1], "1 selected") {
+			t.Errorf("expected selection count, got %q", lines[len(lines)-1])
+		}
+	})
+
+	t.Run("scrolls to keep cursor visible", func(t *testing.T) {
+		m := newModel(testItems(), Options{})
+		typeKeys(m, "\x1b[B\x1b[B")
+
+		// Height 3 leaves room for a single row between prompt and status
+		lines := m.view(80, 3)
+		if len(lines) != 3 {
+			t.Fatalf("expected 3 lines, got %d", len(lines))
+		}
+		if !strings.Contains(lines[1], "bugfix") {
+			t.Errorf("expected bugfix visible, got %q", lines[1])
+		}
+	})
+
+	t.Run("truncates to width", func(t *testing.T) {
+		m := newModel(testItems(), Options{})
+		for _, line := range m.view(10, 24) {
+			if len(line) > 9 {
+				t.Errorf("expected line truncated to 9 columns, got %q", line)
+			}
+		}
+	})
+}
@@ -0,0 +1,114 @@
+package picker
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/x/term"
+)
+
+// ErrCancelled is returned when the user dismisses the picker
+var ErrCancelled = errors.New("selection cancelled")
+
+// Item is a single choice in the picker
+type Item struct {
+	Label string // Rendered row shown in the list
+	Text  string // Plain text the query is matched against
+	Value string // Returned when the item is chosen
+}
+
+// Options configures a picker session
+type Options struct {
+	Prompt string // Shown before the query, e.g. "Switch to"
+	Multi  bool   // Allow selecting several items with tab
+}
+
+// Available reports whether an interactive picker can be shown. The picker
+// reads keys from stdin and draws on stderr, leaving stdout free for output
+// that the shell wrapper captures (such as the path printed by switch).
+func Available() bool {
+	return term.IsTerminal(os.Stdin.Fd()) && term.IsTerminal(os.Stderr.Fd())
+}
+
+// Run shows the picker and returns the values of the chosen items.
+// Returns ErrCancelled if the user presses Escape or Ctrl-C.
+func Run(items []Item, opts Options) ([]string, error) {
+	if len(items) == 0 {
+		return nil, errors.New("nothing to select")
+	}
+
+	inFd := os.Stdin.Fd()
+	state, err := term.MakeRaw(inFd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to enable raw terminal mode: %w", err)
+	}
+	defer func() { _ = term.Restore(inFd, state) }()
+
+	m := newModel(items, opts)
+	screen := &screen{out: os.Stderr}
+	defer screen.close()
+
+	buf := make([]byte, 256)
+	for !m.done && !m.cancelled {
+		width, height, err := term.GetSize(os.Stderr.Fd())
+		if err != nil {
+			width, height = 80, 24
+		}
+		screen.draw(m.view(width, height))
+
+		n, err := os.Stdin.Read(buf)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil, ErrCancelled
+			}
+			return nil, err
+		}
+		for _, k := range parseKeys(buf[:n]) {
+			m.handle(k)
+			if m.done || m.cancelled {
+				break
+			}
+		}
+	}
+
+	if m.cancelled {
+		return nil, ErrCancelled
+	}
+	return m.result(), nil
+}
+
+// screen redraws a block of lines in place using ANSI cursor movement
+type screen struct {
+	out   io.Writer
+	lines int // Lines drawn by the previous frame
+}
+
+func (s *screen) draw(lines []string) {
+	var b strings.Builder
+	if s.lines == 0 {
+		b.WriteString("\033[?25l") // Hide cursor
+	}
+	s.rewind(&b)
+	b.WriteString(strings.Join(lines, "\r\n"))
+	s.lines = len(lines)
+	_, _ = io.WriteString(s.out, b.String())
+}
+
+// close erases the picker and restores the cursor
+func (s *screen) close() {
+	var b strings.Builder
+	s.rewind(&b)
+	b.WriteString("\033[?25h")
+	_, _ = io.WriteString(s.out, b.String())
+}
+
+// rewind moves to the first line of the previous frame and clears below it
+func (s *screen) rewind(b *strings.Builder) {
+	if s.lines > 1 {
+		fmt.Fprintf(b, "\033[%dA", s.lines-1)
+	}
+	b.WriteString("\r\033[J")
+}
@@ -0,0 +1,131 @@
+// Package stack orders stacked branches and records restacks that stopped
+// on conflicts.
+package stack
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/sqve/grove/internal/fs"
+)
+
+// Roots returns the parents of stacked branches that are not stacked
+// themselves, sorted by name
+func Roots(parents map[string]string) []string {
+	seen := make(map[string]bool)
+	var roots []string
+	for _, parent := range parents {
+		if _, stacked := parents[parent]; !stacked && !seen[parent] {
+			seen[parent] = true
+			roots = append(roots, parent)
+		}
+	}
+	sort.Strings(roots)
+	return roots
+}
+
+// Children returns the branches stacked directly on each branch, sorted by
+// name
+func Children(parents map[string]string) map[string][]string {
+	children := make(map[string][]string)
+	for branch, parent := range parents {
+		children[parent] = append(children[parent], branch)
+	}
+	for _, list := range children {
+		sort.Strings(list)
+	}
+	return children
+}
+
+// Order returns the stacked branches of parents so that each comes after its
+// parent, walking each stack depth-first from its root. Branches stacked in a
+// cycle are left out.
+func Order(parents map[string]string) []string {
+	children := Children(parents)
+	order := make([]string, 0, len(parents))
+
+	var walk func(branch string)
+	walk = func(branch string) {
+		for _, child := range children[branch] {
+			order = append(order, child)
+			walk(child)
+		}
+	}
+	for _, root := range Roots(parents) {
+		walk(root)
+	}
+	return order
+}
+
+// Depth returns the number of stacked ancestors of branch, which is 0 for the
+// root of a stack and for branches that are not stacked
+func Depth(parents map[string]string, branch string) int {
+	depth := 0
+	seen := map[string]bool{branch: true}
+	for {
+		parent, ok := parents[branch]
+		if !ok || seen[parent] {
+			return depth
+		}
+		seen[parent] = true
+		depth++
+		branch = parent
+	}
+}
+
+// State records a restack that stopped on conflicts so it can be continued
+type State struct {
+	Branch   string   `json:"branch"`   // Branch whose rebase stopped
+	Worktree string   `json:"worktree"` // Worktree the rebase runs in
+	Parent   string   `json:"parent"`
+	Onto     string   `json:"onto"`    // Commit of parent the branch is rebased onto
+	Pending  []string `json:"pending"` // Branches left to restack, in order
+}
+
+// StatePath returns the file recording the stopped restack of the workspace
+// owning bareDir
+func StatePath(bareDir string) string {
+	return filepath.Join(bareDir, "grove", "restack.json")
+}
+
+// LoadState returns the stopped restack, or nil if there is none
+func LoadState(bareDir string) (*State, error) {
+	data, err := os.ReadFile(StatePath(bareDir))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var state State
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("invalid restack state %s: %w", StatePath(bareDir), err)
+	}
+	return &state, nil
+}
+
+// SaveState records a stopped restack
+func SaveState(bareDir string, state *State) error {
+	path := StatePath(bareDir)
+	if err := os.MkdirAll(filepath.Dir(path), fs.DirStrict); err != nil {
+		return fmt.Errorf("failed to create restack state directory: %w", err)
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return fs.WriteFileAtomic(path, data, fs.FileStrict)
+}
+
+// ClearState forgets the stopped restack
+func ClearState(bareDir string) error {
+	if err := os.Remove(StatePath(bareDir)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
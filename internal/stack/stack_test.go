@@ -0,0 +1,113 @@
+package stack
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/sqve/grove/internal/testutil"
+)
+
+func TestOrder(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		parents map[string]string
+		want    []string
+	}{
+		{"empty", map[string]string{}, []string{}},
+		{
+			"parents before children",
+			map[string]string{"feat/c": "feat/b", "feat/b": "feat/a", "feat/a": "main"},
+			[]string{"feat/a", "feat/b", "feat/c"},
+		},
+		{
+			"siblings by name, each stack depth-first",
+			map[string]string{"b": "main", "a": "main", "a2": "a", "x": "develop"},
+			[]string{"x", "a", "a2", "b"},
+		},
+		{
+			"cycles are left out",
+			map[string]string{"a": "b", "b": "a", "c": "main"},
+			[]string{"c"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := Order(tt.parents); !slices.Equal(got, tt.want) {
+				t.Errorf("Order() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRoots(t *testing.T) {
+	t.Parallel()
+
+	parents := map[string]string{"feat/b": "feat/a", "feat/a": "main", "fix": "main", "docs": "develop"}
+	want := []string{"develop", "main"}
+	if got := Roots(parents); !slices.Equal(got, want) {
+		t.Errorf("Roots() = %v, want %v", got, want)
+	}
+}
+
+func TestDepth(t *testing.T) {
+	t.Parallel()
+
+	parents := map[string]string{"feat/c": "feat/b", "feat/b": "feat/a", "feat/a": "main", "x": "y", "y": "x"}
+
+	tests := []struct {
+		branch string
+		want   int
+	}{
+		{"main", 0},
+		{"feat/a", 1},
+		{"feat/c", 3},
+		{"x", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.branch, func(t *testing.T) {
+			t.Parallel()
+
+			if got := Depth(parents, tt.branch); got != tt.want {
+				t.Errorf("Depth(%q) = %d, want %d", tt.branch, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestState(t *testing.T) {
+	t.Parallel()
+
+	bareDir := filepath.Join(testutil.TempDir(t), ".bare")
+
+	state, err := LoadState(bareDir)
+	if err != nil || state != nil {
+		t.Fatalf("LoadState() = %v, %v; want no state", state, err)
+	}
+
+	want := &State{Branch: "feat/b", Worktree: "/ws/feat-b", Parent: "feat/a", Onto: "abc123", Pending: []string{"feat/c"}}
+	if err := SaveState(bareDir, want); err != nil {
+		t.Fatalf("SaveState failed: %v", err)
+	}
+
+	got, err := LoadState(bareDir)
+	if err != nil {
+		t.Fatalf("LoadState failed: %v", err)
+	}
+	if got.Branch != want.Branch || got.Onto != want.Onto || !slices.Equal(got.Pending, want.Pending) {
+		t.Errorf("LoadState() = %+v, want %+v", got, want)
+	}
+
+	if err := ClearState(bareDir); err != nil {
+		t.Fatalf("ClearState failed: %v", err)
+	}
+	if state, _ := LoadState(bareDir); state != nil {
+		t.Errorf("expected no state after ClearState, got %+v", state)
+	}
+}
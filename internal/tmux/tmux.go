@@ -0,0 +1,137 @@
+package tmux
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Mode selects whether each worktree gets its own window or its own session
+type Mode string
+
+const (
+	ModeWindow  Mode = "window"
+	ModeSession Mode = "session"
+)
+
+// ParseMode returns the mode for s, or false if s is not a known mode
+func ParseMode(s string) (Mode, bool) {
+	switch Mode(strings.ToLower(strings.TrimSpace(s))) {
+	case ModeWindow:
+		return ModeWindow, true
+	case ModeSession:
+		return ModeSession, true
+	}
+	return "", false
+}
+
+// InSession reports whether grove is running inside a tmux client
+func InSession() bool {
+	return os.Getenv("TMUX") != ""
+}
+
+// SessionName converts a worktree name into a valid tmux session name.
+// tmux rejects '.' and ':' in session names.
+func SessionName(name string) string {
+	return strings.NewReplacer(".", "_", ":", "_").Replace(name)
+}
+
+// Focus selects the window or session named name, creating it with its
+// working directory set to dir if it does not exist yet.
+func Focus(mode Mode, name, dir string) error {
+	if mode == ModeSession {
+		return focusSession(SessionName(name), dir)
+	}
+	return focusWindow(name, dir)
+}
+
+// Kill closes the window or session named name. Returns false if there was
+// nothing to close.
+func Kill(mode Mode, name string) (bool, error) {
+	if mode == ModeSession {
+		session := SessionName(name)
+		if !hasSession(session) {
+			return false, nil
+		}
+		if _, err := run("kill-session", "-t", "="+session); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+
+	id, err := findWindow(name)
+	if err != nil || id == "" {
+		return false, err
+	}
+	if _, err := run("kill-window", "-t", id); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func focusWindow(name, dir string) error {
+	id, err := findWindow(name)
+	if err != nil {
+		return err
+	}
+	if id != "" {
+		_, err = run("select-window", "-t", id)
+		return err
+	}
+	_, err = run("new-window", "-n", name, "-c", dir)
+	return err
+}
+
+func focusSession(session, dir string) error {
+	if !hasSession(session) {
+		if _, err := run("new-session", "-d", "-s", session, "-c", dir); err != nil {
+			return err
+		}
+	}
+	_, err := run("switch-client", "-t", "="+session)
+	return err
+}
+
+// findWindow returns the ID of the first window in the current session named
+// name, or an empty string if there is none. Windows are matched by ID
+// because names may contain characters tmux treats specially in targets.
+func findWindow(name string) (string, error) {
+	out, err := run("list-windows", "-F", "#{window_id}\t#{window_name}")
+	if err != nil {
+		return "", err
+	}
+	for _, line := range strings.Split(out, "\n") {
+		id, windowName, ok := strings.Cut(line, "\t")
+		if ok && windowName == name {
+			return id, nil
+		}
+	}
+	return "", nil
+}
+
+func hasSession(session string) bool {
+	_, err := run("has-session", "-t", "="+session)
+	return err == nil
+}
+
+// run executes tmux with args and returns its trimmed stdout
+func run(args ...string) (string, error) {
+	cmd := exec.Command("tmux", args...) //nolint:gosec // Args are constructed from worktree names
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			return "", errors.New("tmux not found in PATH")
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("tmux %s failed: %s", args[0], msg)
+		}
+		return "", fmt.Errorf("tmux %s failed: %w", args[0], err)
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
@@ -0,0 +1,217 @@
+package tmux
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/sqve/grove/internal/testutil"
+)
+
+// fakeTmux is a stand-in tmux binary. It appends its arguments to
+// $FAKE_TMUX_LOG, prints $FAKE_TMUX_WINDOWS for list-windows and succeeds
+// for has-session only when the target is listed in $FAKE_TMUX_SESSIONS.
+const fakeTmux = `#!/bin/sh
+echo "$*" >> "$FAKE_TMUX_LOG"
+case "$1" in
+list-windows)
+	[ -n "$FAKE_TMUX_WINDOWS" ] && printf '%b\n' "$FAKE_TMUX_WINDOWS"
+	;;
+has-session)
+	for s in $FAKE_TMUX_SESSIONS; do
+		[ "$3" = "=$s" ] && exit 0
+	done
+	echo "can't find session: ${3#=}" >&2
+	exit 1
+	;;
+esac
+exit 0
+`
+
+// installFakeTmux puts fakeTmux first on PATH and returns the path of its log
+func installFakeTmux(t *testing.T, windows, sessions string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake tmux is a shell script")
+	}
+
+	dir := testutil.TempDir(t)
+	testutil.WriteFileMode(t, filepath.Join(dir, "tmux"), fakeTmux, 0o755)
+	logPath := filepath.Join(dir, "tmux.log")
+
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("FAKE_TMUX_LOG", logPath)
+	t.Setenv("FAKE_TMUX_WINDOWS", windows)
+	t.Setenv("FAKE_TMUX_SESSIONS", sessions)
+	return logPath
+}
+
+// tmuxCalls returns the commands the fake tmux received, skipping lookups
+func tmuxCalls(t *testing.T, logPath string) []string {
+	t.Helper()
+	data, err := os.ReadFile(logPath)
+	if err != nil && !os.IsNotExist(err) {
+		t.Fatal(err)
+	}
+
+	var calls []string
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		if line == "" || strings.HasPrefix(line, "list-windows") || strings.HasPrefix(line, "has-session") {
+			continue
+		}
+		calls = append(calls, line)
+	}
+	return calls
+}
+
+func assertCalls(t *testing.T, got, want []string) {
+	t.Helper()
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("tmux calls = %q, want %q", got, want)
+	}
+}
+
+func TestParseMode(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Mode
+		ok    bool
+	}{
+		{"window", ModeWindow, true},
+		{"session", ModeSession, true},
+		{" Session ", ModeSession, true},
+		{"", "", false},
+		{"pane", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, ok := ParseMode(tt.input)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("ParseMode(%q) = %q, %v; want %q, %v", tt.input, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestSessionName(t *testing.T) {
+	if got := SessionName("release-1.2:rc"); got != "release-1_2_rc" {
+		t.Errorf("SessionName() = %q, want %q", got, "release-1_2_rc")
+	}
+}
+
+func TestInSession(t *testing.T) {
+	t.Setenv("TMUX", "")
+	if InSession() {
+		t.Error("expected InSession() to be false without $TMUX")
+	}
+
+	t.Setenv("TMUX", "/tmp/tmux-1000/default,1234,0")
+	if !InSession() {
+		t.Error("expected InSession() to be true with $TMUX set")
+	}
+}
+
+func TestFocus(t *testing.T) {
+	t.Run("selects existing window", func(t *testing.T) {
+		logPath := installFakeTmux(t, `@1\tmain\n@4\tfeat-auth`, "")
+
+		if err := Focus(ModeWindow, "feat-auth", "/ws/feat-auth"); err != nil {
+			t.Fatalf("Focus() error = %v", err)
+		}
+		assertCalls(t, tmuxCalls(t, logPath), []string{"select-window -t @4"})
+	})
+
+	t.Run("creates missing window", func(t *testing.T) {
+		logPath := installFakeTmux(t, `@1\tmain`, "")
+
+		if err := Focus(ModeWindow, "feat-auth", "/ws/feat-auth"); err != nil {
+			t.Fatalf("Focus() error = %v", err)
+		}
+		assertCalls(t, tmuxCalls(t, logPath), []string{"new-window -n feat-auth -c /ws/feat-auth"})
+	})
+
+	t.Run("switches to existing session", func(t *testing.T) {
+		logPath := installFakeTmux(t, "", "feat-auth")
+
+		if err := Focus(ModeSession, "feat-auth", "/ws/feat-auth"); err != nil {
+			t.Fatalf("Focus() error = %v", err)
+		}
+		assertCalls(t, tmuxCalls(t, logPath), []string{"switch-client -t =feat-auth"})
+	})
+
+	t.Run("creates missing session", func(t *testing.T) {
+		logPath := installFakeTmux(t, "", "")
+
+		if err := Focus(ModeSession, "v1.0", "/ws/v1.0"); err != nil {
+			t.Fatalf("Focus() error = %v", err)
+		}
+		assertCalls(t, tmuxCalls(t, logPath), []string{
+			"new-session -d -s v1_0 -c /ws/v1.0",
+			"switch-client -t =v1_0",
+		})
+	})
+}
+
+func TestKill(t *testing.T) {
+	t.Run("kills matching window", func(t *testing.T) {
+		logPath := installFakeTmux(t, `@1\tmain\n@4\tfeat-auth`, "")
+
+		killed, err := Kill(ModeWindow, "feat-auth")
+		if err != nil {
+			t.Fatalf("Kill() error = %v", err)
+		}
+		if !killed {
+			t.Error("expected window to be killed")
+		}
+		assertCalls(t, tmuxCalls(t, logPath), []string{"kill-window -t @4"})
+	})
+
+	t.Run("ignores missing window", func(t *testing.T) {
+		logPath := installFakeTmux(t, `@1\tmain`, "")
+
+		killed, err := Kill(ModeWindow, "feat-auth")
+		if err != nil {
+			t.Fatalf("Kill() error = %v", err)
+		}
+		if killed {
+			t.Error("expected nothing to be killed")
+		}
+		assertCalls(t, tmuxCalls(t, logPath), nil)
+	})
+
+	t.Run("kills matching session", func(t *testing.T) {
+		logPath := installFakeTmux(t, "", "feat-auth")
+
+		killed, err := Kill(ModeSession, "feat-auth")
+		if err != nil {
+			t.Fatalf("Kill() error = %v", err)
+		}
+		if !killed {
+			t.Error("expected session to be killed")
+		}
+		assertCalls(t, tmuxCalls(t, logPath), []string{"kill-session -t =feat-auth"})
+	})
+
+	t.Run("ignores missing session", func(t *testing.T) {
+		logPath := installFakeTmux(t, "", "")
+
+		killed, err := Kill(ModeSession, "feat-auth")
+		if err != nil {
+			t.Fatalf("Kill() error = %v", err)
+		}
+		if killed {
+			t.Error("expected nothing to be killed")
+		}
+		assertCalls(t, tmuxCalls(t, logPath), nil)
+	})
+}
+
+func TestRunReportsStderr(t *testing.T) {
+	installFakeTmux(t, "", "")
+
+	_, err := run("has-session", "-t", "=missing")
+	testutil.AssertErrorContains(t, err, "can't find session: missing")
+}
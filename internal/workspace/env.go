@@ -0,0 +1,318 @@
+package workspace
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sqve/grove/internal/config"
+	"github.com/sqve/grove/internal/fs"
+)
+
+// EnvFileName is the file allocated values are written to in each worktree
+const EnvFileName = ".env.grove"
+
+const envFileHeader = "# Generated by grove from [env] in .grove.toml. Do not edit.\n"
+
+var envPlaceholder = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+
+// envRegistry maps worktree paths to the values allocated to them
+type envRegistry map[string]map[string]string
+
+// EnvRegistryPath returns the file that records allocated values for the
+// workspace owning bareDir
+func EnvRegistryPath(bareDir string) string {
+	return filepath.Join(bareDir, "grove", "env.json")
+}
+
+// AllocateEnv assigns a value for each of specs to worktree and records it in
+// the workspace registry. Values the worktree already has are kept. Ranges
+// get the lowest value no other worktree holds; templates are expanded with
+// {{branch}}, {{branch_slug}}, {{worktree}} and the names of other values.
+func AllocateEnv(bareDir, worktree, branch string, specs []config.EnvSpec) (map[string]string, error) {
+	var values map[string]string
+	err := updateEnvRegistry(bareDir, func(reg envRegistry) error {
+		for path := range reg {
+			if !fs.PathsEqual(path, worktree) && !fs.DirectoryExists(path) {
+				delete(reg, path) // Worktree deleted outside of grove
+			}
+		}
+
+		key, existing := reg.find(worktree)
+		delete(reg, key)
+
+		var err error
+		values, err = allocateEnvValues(reg, existing, worktree, branch, specs)
+		if err != nil {
+			return err
+		}
+		reg[worktree] = values
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	addToInfoExclude(bareDir, EnvFileName)
+	return values, nil
+}
+
+// LookupEnv returns the values allocated to worktree, or nil if it has none
+func LookupEnv(bareDir, worktree string) map[string]string {
+	reg, err := loadEnvRegistry(bareDir)
+	if err != nil {
+		return nil
+	}
+	_, values := reg.find(worktree)
+	return values
+}
+
+// ReleaseEnv frees the values allocated to worktree
+func ReleaseEnv(bareDir, worktree string) error {
+	if !fs.FileExists(EnvRegistryPath(bareDir)) {
+		return nil
+	}
+	return updateEnvRegistry(bareDir, func(reg envRegistry) error {
+		key, _ := reg.find(worktree)
+		delete(reg, key)
+		return nil
+	})
+}
+
+// RenameEnv moves the values allocated to oldWorktree to newWorktree
+func RenameEnv(bareDir, oldWorktree, newWorktree string) error {
+	if !fs.FileExists(EnvRegistryPath(bareDir)) {
+		return nil
+	}
+	return updateEnvRegistry(bareDir, func(reg envRegistry) error {
+		key, values := reg.find(oldWorktree)
+		if values == nil {
+			return nil
+		}
+		delete(reg, key)
+		reg[newWorktree] = values
+		return nil
+	})
+}
+
+// WriteEnvFile writes values to EnvFileName in worktree
+func WriteEnvFile(worktree string, values map[string]string) error {
+	var b strings.Builder
+	b.WriteString(envFileHeader)
+	for _, kv := range EnvList(values) {
+		name, value, _ := strings.Cut(kv, "=")
+		b.WriteString(name + "=" + quoteEnvValue(value) + "\n")
+	}
+	return fs.WriteFileAtomic(filepath.Join(worktree, EnvFileName), []byte(b.String()), fs.FileGit)
+}
+
+// EnvList returns values as sorted NAME=value pairs for use as environment
+func EnvList(values map[string]string) []string {
+	list := make([]string, 0, len(values))
+	for name, value := range values {
+		list = append(list, name+"="+value)
+	}
+	sort.Strings(list)
+	return list
+}
+
+// BranchSlug lowercases branch and replaces runs of other characters than
+// letters and digits with underscores, for use in identifiers such as
+// database names
+func BranchSlug(branch string) string {
+	var b strings.Builder
+	pending := false
+	for _, r := range strings.ToLower(branch) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			if pending && b.Len() > 0 {
+				b.WriteByte('_')
+			}
+			pending = false
+			b.WriteRune(r)
+			continue
+		}
+		pending = true
+	}
+	return b.String()
+}
+
+func allocateEnvValues(reg envRegistry, existing map[string]string, worktree, branch string, specs []config.EnvSpec) (map[string]string, error) {
+	values := make(map[string]string, len(specs))
+
+	// Ranges first so templates can refer to them
+	for _, spec := range specs {
+		if !spec.IsRange() {
+			continue
+		}
+		used := make(map[int]bool)
+		for _, other := range reg {
+			if n, err := strconv.Atoi(other[spec.Name]); err == nil {
+				used[n] = true
+			}
+		}
+		if n, err := strconv.Atoi(existing[spec.Name]); err == nil && n >= spec.Min && n <= spec.Max && !used[n] {
+			values[spec.Name] = existing[spec.Name]
+			continue
+		}
+
+		allocated := false
+		for n := spec.Min; n <= spec.Max; n++ {
+			if !used[n] {
+				values[spec.Name] = strconv.Itoa(n)
+				allocated = true
+				break
+			}
+		}
+		if !allocated {
+			return nil, fmt.Errorf("no free value for %s in %d-%d", spec.Name, spec.Min, spec.Max)
+		}
+	}
+
+	name := filepath.Base(worktree)
+	if branch == "" {
+		branch = name // Detached worktrees have no branch
+	}
+	builtins := map[string]string{
+		"branch":      branch,
+		"branch_slug": BranchSlug(branch),
+		"worktree":    name,
+	}
+
+	for _, spec := range specs {
+		if spec.IsRange() {
+			continue
+		}
+		if value, ok := existing[spec.Name]; ok {
+			values[spec.Name] = value
+			continue
+		}
+
+		var missing string
+		value := envPlaceholder.ReplaceAllStringFunc(spec.Template, func(m string) string {
+			key := envPlaceholder.FindStringSubmatch(m)[1]
+			if v, ok := builtins[key]; ok {
+				return v
+			}
+			if v, ok := values[key]; ok {
+				return v
+			}
+			missing = key
+			return m
+		})
+		if missing != "" {
+			return nil, fmt.Errorf("%s: unknown placeholder {{%s}} in template %q", spec.Name, missing, spec.Template)
+		}
+		values[spec.Name] = value
+	}
+
+	return values, nil
+}
+
+// find returns the key and values of worktree in the registry
+func (reg envRegistry) find(worktree string) (string, map[string]string) {
+	if values, ok := reg[worktree]; ok {
+		return worktree, values
+	}
+	for path, values := range reg {
+		if fs.PathsEqual(path, worktree) {
+			return path, values
+		}
+	}
+	return "", nil
+}
+
+func loadEnvRegistry(bareDir string) (envRegistry, error) {
+	reg := envRegistry{}
+	data, err := os.ReadFile(EnvRegistryPath(bareDir))
+	if errors.Is(err, os.ErrNotExist) {
+		return reg, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &reg); err != nil {
+		return nil, fmt.Errorf("invalid env registry %s: %w", EnvRegistryPath(bareDir), err)
+	}
+	return reg, nil
+}
+
+// updateEnvRegistry applies fn to the registry under a lock and saves it
+func updateEnvRegistry(bareDir string, fn func(envRegistry) error) error {
+	path := EnvRegistryPath(bareDir)
+	if err := os.MkdirAll(filepath.Dir(path), fs.DirStrict); err != nil {
+		return fmt.Errorf("failed to create env registry directory: %w", err)
+	}
+
+	// The lock is only held while the registry is rewritten, so wait for it
+	// briefly instead of failing like the worktree lock does
+	lockFile := path + ".lock"
+	var lockHandle *os.File
+	var err error
+	for range 20 {
+		if lockHandle, err = AcquireWorkspaceLock(lockFile); err == nil {
+			break
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = lockHandle.Close()
+		_ = os.Remove(lockFile)
+	}()
+
+	reg, err := loadEnvRegistry(bareDir)
+	if err != nil {
+		return err
+	}
+	if err := fn(reg); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(reg, "", "  ")
+	if err != nil {
+		return err
+	}
+	return fs.WriteFileAtomic(path, data, fs.FileStrict)
+}
+
+// addToInfoExclude appends pattern to the exclude file shared by all
+// worktrees, so generated files do not show up as untracked
+func addToInfoExclude(bareDir, pattern string) {
+	path := filepath.Join(bareDir, "info", "exclude")
+	content, err := os.ReadFile(path) // nolint:gosec // Path inside the bare repository
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return
+	}
+	for _, line := range strings.Split(string(content), "\n") {
+		if strings.TrimSpace(line) == pattern || strings.TrimSpace(line) == "/"+pattern {
+			return
+		}
+	}
+
+	if len(content) > 0 && !strings.HasSuffix(string(content), "\n") {
+		content = append(content, '\n')
+	}
+	content = append(content, "/"+pattern+"\n"...)
+	if err := os.MkdirAll(filepath.Dir(path), fs.DirGit); err != nil {
+		return
+	}
+	_ = fs.WriteFileAtomic(path, content, fs.FileGit)
+}
+
+// quoteEnvValue double-quotes value if dotenv parsers would otherwise
+// misread it
+func quoteEnvValue(value string) string {
+	if value != "" && !strings.ContainsAny(value, " \t\n\"'#$\\`") {
+		return value
+	}
+	return strconv.Quote(value)
+}
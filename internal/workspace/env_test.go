@@ -0,0 +1,224 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/sqve/grove/internal/config"
+	"github.com/sqve/grove/internal/fs"
+	"github.com/sqve/grove/internal/testutil"
+)
+
+func TestBranchSlug(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		branch string
+		want   string
+	}{
+		{"main", "main"},
+		{"feat/Login-Page", "feat_login_page"},
+		{"fix//double--dash", "fix_double_dash"},
+		{"-leading/trailing-", "leading_trailing"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.branch, func(t *testing.T) {
+			t.Parallel()
+
+			if got := BranchSlug(tt.branch); got != tt.want {
+				t.Errorf("BranchSlug(%q) = %q, want %q", tt.branch, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAllocateEnv(t *testing.T) {
+	t.Parallel()
+
+	specs := []config.EnvSpec{
+		{Name: "DB_NAME", Template: "app_{{branch_slug}}"},
+		{Name: "PORT", Min: 3000, Max: 3001},
+		{Name: "URL", Template: "http://localhost:{{PORT}}/{{worktree}}"},
+	}
+
+	setup := func(t *testing.T) (bareDir string, worktrees []string) {
+		t.Helper()
+		root := testutil.TempDir(t)
+		bareDir = filepath.Join(root, ".bare")
+		for _, name := range []string{"main", "feat-login", "fix-bug"} {
+			dir := filepath.Join(root, name)
+			if err := os.MkdirAll(dir, fs.DirGit); err != nil {
+				t.Fatal(err)
+			}
+			worktrees = append(worktrees, dir)
+		}
+		return bareDir, worktrees
+	}
+
+	t.Run("allocates lowest free value and expands templates", func(t *testing.T) {
+		t.Parallel()
+		bareDir, worktrees := setup(t)
+
+		if _, err := AllocateEnv(bareDir, worktrees[0], "main", specs); err != nil {
+			t.Fatalf("AllocateEnv failed: %v", err)
+		}
+		values, err := AllocateEnv(bareDir, worktrees[1], "feat/login", specs)
+		if err != nil {
+			t.Fatalf("AllocateEnv failed: %v", err)
+		}
+
+		want := []string{"DB_NAME=app_feat_login", "PORT=3001", "URL=http://localhost:3001/feat-login"}
+		if got := EnvList(values); !slices.Equal(got, want) {
+			t.Errorf("AllocateEnv = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("keeps existing values", func(t *testing.T) {
+		t.Parallel()
+		bareDir, worktrees := setup(t)
+
+		first, err := AllocateEnv(bareDir, worktrees[0], "main", specs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		again, err := AllocateEnv(bareDir, worktrees[0], "main", specs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(EnvList(first), EnvList(again)) {
+			t.Errorf("values changed from %v to %v", first, again)
+		}
+	})
+
+	t.Run("fails when range is exhausted", func(t *testing.T) {
+		t.Parallel()
+		bareDir, worktrees := setup(t)
+
+		for _, worktree := range worktrees[:2] {
+			if _, err := AllocateEnv(bareDir, worktree, "", specs); err != nil {
+				t.Fatal(err)
+			}
+		}
+		_, err := AllocateEnv(bareDir, worktrees[2], "fix/bug", specs)
+		if err == nil || !strings.Contains(err.Error(), "no free value for PORT") {
+			t.Errorf("expected exhausted range error, got %v", err)
+		}
+	})
+
+	t.Run("reuses released values", func(t *testing.T) {
+		t.Parallel()
+		bareDir, worktrees := setup(t)
+
+		for _, worktree := range worktrees[:2] {
+			if _, err := AllocateEnv(bareDir, worktree, "", specs); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := ReleaseEnv(bareDir, worktrees[0]); err != nil {
+			t.Fatalf("ReleaseEnv failed: %v", err)
+		}
+		if LookupEnv(bareDir, worktrees[0]) != nil {
+			t.Error("expected no values after release")
+		}
+
+		values, err := AllocateEnv(bareDir, worktrees[2], "fix/bug", specs)
+		if err != nil {
+			t.Fatalf("AllocateEnv failed: %v", err)
+		}
+		if values["PORT"] != "3000" {
+			t.Errorf("expected released port 3000, got %q", values["PORT"])
+		}
+	})
+
+	t.Run("frees values of deleted worktrees", func(t *testing.T) {
+		t.Parallel()
+		bareDir, worktrees := setup(t)
+
+		for _, worktree := range worktrees[:2] {
+			if _, err := AllocateEnv(bareDir, worktree, "", specs); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := os.RemoveAll(worktrees[0]); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := AllocateEnv(bareDir, worktrees[2], "fix/bug", specs); err != nil {
+			t.Errorf("expected value of deleted worktree to be reused, got %v", err)
+		}
+	})
+
+	t.Run("rejects unknown placeholders", func(t *testing.T) {
+		t.Parallel()
+		bareDir, worktrees := setup(t)
+
+		_, err := AllocateEnv(bareDir, worktrees[0], "main", []config.EnvSpec{{Name: "X", Template: "{{nope}}"}})
+		if err == nil || !strings.Contains(err.Error(), "{{nope}}") {
+			t.Errorf("expected unknown placeholder error, got %v", err)
+		}
+	})
+}
+
+func TestRenameEnv(t *testing.T) {
+	t.Parallel()
+
+	root := testutil.TempDir(t)
+	bareDir := filepath.Join(root, ".bare")
+	oldDir := filepath.Join(root, "old")
+	newDir := filepath.Join(root, "new")
+	if err := os.MkdirAll(oldDir, fs.DirGit); err != nil {
+		t.Fatal(err)
+	}
+
+	values, err := AllocateEnv(bareDir, oldDir, "old", []config.EnvSpec{{Name: "PORT", Min: 4000, Max: 4999}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := RenameEnv(bareDir, oldDir, newDir); err != nil {
+		t.Fatalf("RenameEnv failed: %v", err)
+	}
+
+	if LookupEnv(bareDir, oldDir) != nil {
+		t.Error("expected no values for old path")
+	}
+	if got := LookupEnv(bareDir, newDir); got["PORT"] != values["PORT"] {
+		t.Errorf("expected PORT %s for new path, got %v", values["PORT"], got)
+	}
+}
+
+func TestWriteEnvFile(t *testing.T) {
+	t.Parallel()
+
+	dir := testutil.TempDir(t)
+	if err := WriteEnvFile(dir, map[string]string{"PORT": "3000", "GREETING": "hello world"}); err != nil {
+		t.Fatalf("WriteEnvFile failed: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, EnvFileName)) //nolint:gosec
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := envFileHeader + "GREETING=\"hello world\"\nPORT=3000\n"
+	if string(content) != want {
+		t.Errorf("content = %q, want %q", content, want)
+	}
+}
+
+func TestAddToInfoExclude(t *testing.T) {
+	t.Parallel()
+
+	bareDir := testutil.TempDir(t)
+	writeTestFile(t, filepath.Join(bareDir, "info", "exclude"), "# git ls-files --others --exclude-from=.git/info/exclude")
+
+	addToInfoExclude(bareDir, EnvFileName)
+	addToInfoExclude(bareDir, EnvFileName)
+
+	content, _ := os.ReadFile(filepath.Join(bareDir, "info", "exclude")) //nolint:gosec
+	if strings.Count(string(content), "/"+EnvFileName) != 1 {
+		t.Errorf("expected pattern once, got %q", content)
+	}
+}
@@ -0,0 +1,137 @@
+package workspace
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/sqve/grove/internal/fs"
+)
+
+// WorktreePathData is available to worktree path templates
+type WorktreePathData struct {
+	Branch    string // Branch with each segment sanitized, e.g. feat/auth
+	Prefix    string // Branch segments before the last, e.g. feat (empty for main)
+	Name      string // Last branch segment, e.g. auth
+	Flat      string // Branch as a single directory name, e.g. feat-auth
+	Repo      string // Name of the workspace directory
+	Workspace string // Workspace root
+}
+
+// NewWorktreePathData returns the template data for branch in workspaceRoot
+func NewWorktreePathData(workspaceRoot, branch string) WorktreePathData {
+	var segments []string
+	for _, segment := range strings.Split(filepath.ToSlash(branch), "/") {
+		if segment = SanitizeBranchName(segment); segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+
+	data := WorktreePathData{
+		Branch:    strings.Join(segments, "/"),
+		Flat:      SanitizeBranchName(branch),
+		Repo:      filepath.Base(workspaceRoot),
+		Workspace: workspaceRoot,
+	}
+	if len(segments) > 0 {
+		data.Prefix = strings.Join(segments[:len(segments)-1], "/")
+		data.Name = segments[len(segments)-1]
+	}
+	return data
+}
+
+// WorktreePath returns where the worktree for branch belongs according to
+// tmpl, a text/template such as "{{.Prefix}}/{{.Name}}". Relative results are
+// placed in workspaceRoot; absolute results and results starting with ~/ may
+// point outside of it.
+func WorktreePath(workspaceRoot, tmpl, branch string) (string, error) {
+	t, err := template.New("path").Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("invalid worktree path template %q: %w", tmpl, err)
+	}
+
+	rendered, err := renderPathTemplate(t, NewWorktreePathData(workspaceRoot, branch))
+	if err != nil {
+		return "", fmt.Errorf("invalid worktree path template %q: %w", tmpl, err)
+	}
+
+	// Empty fields such as .Prefix for main must not turn a relative template
+	// absolute, so whether it is absolute is decided with every field set
+	probe, err := renderPathTemplate(t, WorktreePathData{
+		Branch: "x", Prefix: "x", Name: "x", Flat: "x", Repo: "x", Workspace: workspaceRoot,
+	})
+	if err != nil {
+		return "", fmt.Errorf("invalid worktree path template %q: %w", tmpl, err)
+	}
+
+	var path string
+	if rest, ok := strings.CutPrefix(rendered, "~"+string(filepath.Separator)); ok {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		path = filepath.Join(home, rest)
+	} else if filepath.IsAbs(probe) {
+		path = filepath.Clean(rendered)
+	} else {
+		path = filepath.Join(workspaceRoot, rendered)
+	}
+
+	bareDir := filepath.Join(workspaceRoot, ".bare")
+	if strings.Trim(rendered, `/\`) == "" || fs.PathsEqual(path, workspaceRoot) || fs.PathHasPrefix(workspaceRoot, path) ||
+		fs.PathsEqual(path, bareDir) || fs.PathHasPrefix(path, bareDir) {
+		return "", fmt.Errorf("worktree path template %q gives %q for %s, which cannot hold a worktree", tmpl, path, branch)
+	}
+	return path, nil
+}
+
+func renderPathTemplate(t *template.Template, data WorktreePathData) (string, error) {
+	var b strings.Builder
+	if err := t.Execute(&b, data); err != nil {
+		return "", err
+	}
+	return filepath.FromSlash(strings.TrimSpace(b.String())), nil
+}
+
+// ValidatePathTemplate reports whether tmpl renders a usable worktree path
+func ValidatePathTemplate(tmpl string) error {
+	root := filepath.Join(os.TempDir(), "workspace")
+	for _, branch := range []string{"main", "feat/auth"} {
+		if _, err := WorktreePath(root, tmpl, branch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ExpectedWorktreePath returns where the existing worktree at path belongs
+// according to tmpl. Only worktrees named after their branch are placed by
+// the template; worktrees with another directory name, such as pr-123 or one
+// created with --name, belong where they are.
+func ExpectedWorktreePath(workspaceRoot, tmpl, path, branch string) (string, error) {
+	name := filepath.Base(path)
+	data := NewWorktreePathData(workspaceRoot, branch)
+	if branch == "" || (name != data.Flat && name != data.Name) {
+		return path, nil
+	}
+	return WorktreePath(workspaceRoot, tmpl, branch)
+}
+
+// RemoveEmptyParents removes the empty directories between path and
+// workspaceRoot that nested layouts leave behind when a worktree is removed
+// or moved. Does nothing for paths outside workspaceRoot.
+func RemoveEmptyParents(path, workspaceRoot string) {
+	if !fs.PathHasPrefix(path, workspaceRoot) {
+		return
+	}
+	for dir := filepath.Dir(path); fs.PathHasPrefix(dir, workspaceRoot); dir = filepath.Dir(dir) {
+		if err := os.Remove(dir); err != nil {
+			if !errors.Is(err, os.ErrNotExist) {
+				return // Not empty
+			}
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sqve/grove/internal/fs"
+	"github.com/sqve/grove/internal/testutil"
+)
+
+func TestWorktreePath(t *testing.T) {
+	t.Parallel()
+
+	root := filepath.Join(os.TempDir(), "ws", "myrepo")
+	outside := filepath.Join(os.TempDir(), "worktrees")
+
+	tests := []struct {
+		name   string
+		tmpl   string
+		branch string
+		want   string
+	}{
+		{"flat", "{{.Flat}}", "feat/auth", filepath.Join(root, "feat-auth")},
+		{"prefix and name", "{{.Prefix}}/{{.Name}}", "feat/auth", filepath.Join(root, "feat", "auth")},
+		{"prefix of single segment branch", "{{.Prefix}}/{{.Name}}", "main", filepath.Join(root, "main")},
+		{"nested branch directory", "branches/{{.Branch}}", "feat/auth", filepath.Join(root, "branches", "feat", "auth")},
+		{"sanitized segments", "{{.Branch}}", "fix/a:b", filepath.Join(root, "fix", "a-b")},
+		{"absolute root outside workspace", filepath.ToSlash(outside) + "/{{.Repo}}/{{.Flat}}", "feat/auth", filepath.Join(outside, "myrepo", "feat-auth")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := WorktreePath(root, tt.tmpl, tt.branch)
+			if err != nil {
+				t.Fatalf("WorktreePath failed: %v", err)
+			}
+			if !fs.PathsEqual(got, tt.want) {
+				t.Errorf("WorktreePath(%q, %q) = %q, want %q", tt.tmpl, tt.branch, got, tt.want)
+			}
+		})
+	}
+
+	invalid := []struct {
+		name string
+		tmpl string
+	}{
+		{"parse error", "{{.Flat"},
+		{"unknown field", "{{.Nope}}"},
+		{"empty result", "{{.Prefix}}"},
+		{"inside .bare", ".bare/{{.Flat}}"},
+		{"workspace root", "."},
+		{"parent of workspace", ".."},
+	}
+
+	for _, tt := range invalid {
+		t.Run("rejects "+tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got, err := WorktreePath(root, tt.tmpl, "main"); err == nil {
+				t.Errorf("expected error for %q, got %q", tt.tmpl, got)
+			}
+		})
+	}
+}
+
+func TestExpectedWorktreePath(t *testing.T) {
+	t.Parallel()
+
+	root := filepath.Join(os.TempDir(), "ws")
+	tmpl := "{{.Prefix}}/{{.Name}}"
+
+	tests := []struct {
+		name   string
+		path   string
+		branch string
+		want   string
+	}{
+		{"flat worktree moves to nested path", filepath.Join(root, "feat-auth"), "feat/auth", filepath.Join(root, "feat", "auth")},
+		{"nested worktree stays", filepath.Join(root, "feat", "auth"), "feat/auth", filepath.Join(root, "feat", "auth")},
+		{"custom name is kept", filepath.Join(root, "pr-12"), "feat/auth", filepath.Join(root, "pr-12")},
+		{"nested custom name is kept", filepath.Join(root, "nested", "dir"), "feat/auth", filepath.Join(root, "nested", "dir")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ExpectedWorktreePath(root, tmpl, tt.path, tt.branch)
+			if err != nil {
+				t.Fatalf("ExpectedWorktreePath failed: %v", err)
+			}
+			if !fs.PathsEqual(got, tt.want) {
+				t.Errorf("ExpectedWorktreePath(%q, %q) = %q, want %q", tt.path, tt.branch, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRemoveEmptyParents(t *testing.T) {
+	t.Parallel()
+
+	root := testutil.TempDir(t)
+	removed := filepath.Join(root, "feat", "team", "auth")
+	sibling := filepath.Join(root, "feat", "other")
+	for _, dir := range []string{filepath.Dir(removed), sibling} {
+		if err := os.MkdirAll(dir, fs.DirGit); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	RemoveEmptyParents(removed, root)
+
+	if fs.PathExists(filepath.Join(root, "feat", "team")) {
+		t.Error("expected empty parent to be removed")
+	}
+	if !fs.DirectoryExists(sibling) {
+		t.Error("expected non-empty parent to be kept")
+	}
+	if !fs.DirectoryExists(root) {
+		t.Error("expected workspace root to be kept")
+	}
+}
@@ -5,41 +5,48 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
+	"github.com/sqve/grove/internal/config"
 	"github.com/sqve/grove/internal/fs"
 	"github.com/sqve/grove/internal/logger"
 )
 
 type PreserveResult struct {
-	Copied  []string
-	Skipped []string // Already exist in destination
+	Copied     []string
+	Skipped    []string          // Already exist in destination
+	Strategies []fs.CopyStrategy // Strategies that copied files, in order of first use
+	SavedBytes int64             // Bytes shared with the source through reflinks or hard links
 }
 
-// PreserveFilesToWorktree copies matching files, skips existing (never overwrites).
-// excludePatterns contains path segments to exclude (e.g., "node_modules").
-func PreserveFilesToWorktree(sourceDir, destDir string, patterns, ignoredFiles, excludePatterns []string) (*PreserveResult, error) {
-	result := &PreserveResult{}
-
-	if len(ignoredFiles) == 0 || len(patterns) == 0 {
-		return result, nil
-	}
-
-	var filesToCopy []string
-	for _, file := range ignoredFiles {
-		// Skip files in excluded paths
-		if isExcludedPath(file, excludePatterns) {
-			continue
+// Merge appends the files and strategies of other to r
+func (r *PreserveResult) Merge(other *PreserveResult) {
+	r.Copied = append(r.Copied, other.Copied...)
+	r.Skipped = append(r.Skipped, other.Skipped...)
+	for _, strategy := range other.Strategies {
+		if !slices.Contains(r.Strategies, strategy) {
+			r.Strategies = append(r.Strategies, strategy)
 		}
+	}
+	r.SavedBytes += other.SavedBytes
+}
 
-		for _, pattern := range patterns {
-			if matchesPattern(file, pattern) {
-				filesToCopy = append(filesToCopy, file)
-				break
-			}
-		}
+func (r *PreserveResult) addCopied(file string, strategy fs.CopyStrategy, saved int64) {
+	r.Copied = append(r.Copied, file)
+	if !slices.Contains(r.Strategies, strategy) {
+		r.Strategies = append(r.Strategies, strategy)
 	}
+	r.SavedBytes += saved
+}
+
+// PreserveFilesToWorktree copies matching files, skips existing (never overwrites).
+// excludePatterns contains path segments to exclude (e.g., "node_modules").
+// strategy selects how files are copied.
+func PreserveFilesToWorktree(sourceDir, destDir string, patterns, ignoredFiles, excludePatterns []string, strategy fs.CopyStrategy) (*PreserveResult, error) {
+	result := &PreserveResult{}
 
+	filesToCopy := MatchPreservedFiles(ignoredFiles, patterns, excludePatterns)
 	if len(filesToCopy) == 0 {
 		return result, nil
 	}
@@ -60,7 +67,8 @@ func PreserveFilesToWorktree(sourceDir, destDir string, patterns, ignoredFiles,
 		}
 
 		// Use exclusive copy to atomically skip existing files (avoids TOCTOU race)
-		if err := fs.CopyFileExclusive(sourcePath, destPath, fs.FileGit); err != nil {
+		used, saved, err := fs.CopyFileExclusiveWithStrategy(sourcePath, destPath, fs.FileGit, strategy)
+		if err != nil {
 			if errors.Is(err, os.ErrExist) {
 				result.Skipped = append(result.Skipped, file)
 				continue
@@ -68,16 +76,37 @@ func PreserveFilesToWorktree(sourceDir, destDir string, patterns, ignoredFiles,
 			return nil, err
 		}
 
-		result.Copied = append(result.Copied, file)
+		result.addCopied(file, used, saved)
 	}
 
 	return result, nil
 }
 
+// MatchPreservedFiles returns the ignored files that match a preserve pattern
+// and are not in an excluded path
+func MatchPreservedFiles(ignoredFiles, patterns, excludePatterns []string) []string {
+	var matched []string
+	for _, file := range ignoredFiles {
+		// Skip files in excluded paths
+		if isExcludedPath(file, excludePatterns) {
+			continue
+		}
+
+		for _, pattern := range patterns {
+			if matchesPattern(file, pattern) {
+				matched = append(matched, file)
+				break
+			}
+		}
+	}
+	return matched
+}
+
 // PreserveDirectoriesToWorktree recursively copies named directories from source to dest.
 // Skips directories that don't exist in source. Skips individual files that already exist in dest.
 // Rejects directory names with path traversal (absolute paths or ".." components).
-func PreserveDirectoriesToWorktree(sourceDir, destDir string, directories []string) (*PreserveResult, error) {
+// strategy selects how files are copied.
+func PreserveDirectoriesToWorktree(sourceDir, destDir string, directories []string, strategy fs.CopyStrategy) (*PreserveResult, error) {
 	result := &PreserveResult{}
 
 	if len(directories) == 0 {
@@ -122,7 +151,8 @@ func PreserveDirectoriesToWorktree(sourceDir, destDir string, directories []stri
 				return err
 			}
 
-			if err := fs.CopyFileExclusive(path, destPath, fs.FileGit); err != nil {
+			used, saved, err := fs.CopyFileExclusiveWithStrategy(path, destPath, fs.FileGit, strategy)
+			if err != nil {
 				if errors.Is(err, os.ErrExist) {
 					result.Skipped = append(result.Skipped, relPath)
 					return nil
@@ -130,7 +160,7 @@ func PreserveDirectoriesToWorktree(sourceDir, destDir string, directories []stri
 				return err
 			}
 
-			result.Copied = append(result.Copied, relPath)
+			result.addCopied(relPath, used, saved)
 			return nil
 		})
 		if err != nil {
@@ -141,6 +171,18 @@ func PreserveDirectoriesToWorktree(sourceDir, destDir string, directories []stri
 	return result, nil
 }
 
+// GetPreserveCopyStrategy returns the configured strategy for copying preserved
+// files. Unknown values fall back to auto with a warning.
+func GetPreserveCopyStrategy(worktreeDir string) fs.CopyStrategy {
+	value := config.GetMergedPreserveCopyStrategy(worktreeDir)
+	strategy, ok := fs.ParseCopyStrategy(value)
+	if !ok {
+		logger.Warning("Unknown preserve copy strategy %q, using %s", value, fs.CopyStrategyAuto)
+		return fs.CopyStrategyAuto
+	}
+	return strategy
+}
+
 func FindIgnoredFilesInWorktree(worktreeDir string) ([]string, error) {
 	return findIgnoredFiles(worktreeDir)
 }
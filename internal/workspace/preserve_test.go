@@ -4,6 +4,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"testing"
 
 	"github.com/sqve/grove/internal/config"
@@ -34,7 +35,7 @@ func TestPreserveFilesToWorktree(t *testing.T) {
 		// Set default patterns
 		patterns := []string{".env"}
 
-		result, err := PreserveFilesToWorktree(sourceDir, destDir, patterns, []string{".env"}, nil)
+		result, err := PreserveFilesToWorktree(sourceDir, destDir, patterns, []string{".env"}, nil, fs.CopyStrategyCopy)
 		if err != nil {
 			t.Fatalf("PreserveFilesToWorktree failed: %v", err)
 		}
@@ -73,7 +74,7 @@ func TestPreserveFilesToWorktree(t *testing.T) {
 
 		patterns := []string{".env"}
 
-		result, err := PreserveFilesToWorktree(sourceDir, destDir, patterns, []string{".env"}, nil)
+		result, err := PreserveFilesToWorktree(sourceDir, destDir, patterns, []string{".env"}, nil, fs.CopyStrategyCopy)
 		if err != nil {
 			t.Fatalf("PreserveFilesToWorktree failed: %v", err)
 		}
@@ -105,7 +106,7 @@ func TestPreserveFilesToWorktree(t *testing.T) {
 		patterns := []string{".env"}
 		ignoredFiles := []string{".env", "other.txt"}
 
-		result, err := PreserveFilesToWorktree(sourceDir, destDir, patterns, ignoredFiles, nil)
+		result, err := PreserveFilesToWorktree(sourceDir, destDir, patterns, ignoredFiles, nil, fs.CopyStrategyCopy)
 		if err != nil {
 			t.Fatalf("PreserveFilesToWorktree failed: %v", err)
 		}
@@ -139,7 +140,7 @@ func TestPreserveFilesToWorktree(t *testing.T) {
 		patterns := []string{".env.local"}
 		ignoredFiles := []string{"config/.env.local"}
 
-		result, err := PreserveFilesToWorktree(sourceDir, destDir, patterns, ignoredFiles, nil)
+		result, err := PreserveFilesToWorktree(sourceDir, destDir, patterns, ignoredFiles, nil, fs.CopyStrategyCopy)
 		if err != nil {
 			t.Fatalf("PreserveFilesToWorktree failed: %v", err)
 		}
@@ -164,7 +165,7 @@ func TestPreserveFilesToWorktree(t *testing.T) {
 		patterns := []string{".env"}
 		ignoredFiles := []string{"other.txt"} // Doesn't match .env pattern
 
-		result, err := PreserveFilesToWorktree(sourceDir, destDir, patterns, ignoredFiles, nil)
+		result, err := PreserveFilesToWorktree(sourceDir, destDir, patterns, ignoredFiles, nil, fs.CopyStrategyCopy)
 		if err != nil {
 			t.Fatalf("PreserveFilesToWorktree failed: %v", err)
 		}
@@ -191,7 +192,7 @@ func TestPreserveFilesToWorktree(t *testing.T) {
 		patterns := []string{"*.local.json"}
 		ignoredFiles := []string{"config.local.json", "settings.local.json"}
 
-		result, err := PreserveFilesToWorktree(sourceDir, destDir, patterns, ignoredFiles, nil)
+		result, err := PreserveFilesToWorktree(sourceDir, destDir, patterns, ignoredFiles, nil, fs.CopyStrategyCopy)
 		if err != nil {
 			t.Fatalf("PreserveFilesToWorktree failed: %v", err)
 		}
@@ -225,7 +226,7 @@ func TestPreserveFilesToWorktree(t *testing.T) {
 		ignoredFiles := []string{".env", "node_modules/some-package/.env"}
 		excludePatterns := []string{"node_modules"}
 
-		result, err := PreserveFilesToWorktree(sourceDir, destDir, patterns, ignoredFiles, excludePatterns)
+		result, err := PreserveFilesToWorktree(sourceDir, destDir, patterns, ignoredFiles, excludePatterns, fs.CopyStrategyCopy)
 		if err != nil {
 			t.Fatalf("PreserveFilesToWorktree failed: %v", err)
 		}
@@ -395,7 +396,7 @@ func TestPreserveDirectoriesToWorktree(t *testing.T) {
 			t.Fatal(err)
 		}
 
-		result, err := PreserveDirectoriesToWorktree(sourceDir, destDir, []string{"config"})
+		result, err := PreserveDirectoriesToWorktree(sourceDir, destDir, []string{"config"}, fs.CopyStrategyCopy)
 		if err != nil {
 			t.Fatalf("PreserveDirectoriesToWorktree failed: %v", err)
 		}
@@ -426,7 +427,7 @@ func TestPreserveDirectoriesToWorktree(t *testing.T) {
 			t.Fatal(err)
 		}
 
-		result, err := PreserveDirectoriesToWorktree(sourceDir, destDir, []string{".run"})
+		result, err := PreserveDirectoriesToWorktree(sourceDir, destDir, []string{".run"}, fs.CopyStrategyCopy)
 		if err != nil {
 			t.Fatalf("PreserveDirectoriesToWorktree failed: %v", err)
 		}
@@ -462,7 +463,7 @@ func TestPreserveDirectoriesToWorktree(t *testing.T) {
 			t.Fatal(err)
 		}
 
-		result, err := PreserveDirectoriesToWorktree(sourceDir, destDir, []string{"config"})
+		result, err := PreserveDirectoriesToWorktree(sourceDir, destDir, []string{"config"}, fs.CopyStrategyCopy)
 		if err != nil {
 			t.Fatalf("PreserveDirectoriesToWorktree failed: %v", err)
 		}
@@ -482,7 +483,7 @@ func TestPreserveDirectoriesToWorktree(t *testing.T) {
 		sourceDir := testutil.TempDir(t)
 		destDir := testutil.TempDir(t)
 
-		result, err := PreserveDirectoriesToWorktree(sourceDir, destDir, []string{"nonexistent"})
+		result, err := PreserveDirectoriesToWorktree(sourceDir, destDir, []string{"nonexistent"}, fs.CopyStrategyCopy)
 		if err != nil {
 			t.Fatalf("PreserveDirectoriesToWorktree failed: %v", err)
 		}
@@ -497,7 +498,7 @@ func TestPreserveDirectoriesToWorktree(t *testing.T) {
 		sourceDir := testutil.TempDir(t)
 		destDir := testutil.TempDir(t)
 
-		result, err := PreserveDirectoriesToWorktree(sourceDir, destDir, []string{"../etc"})
+		result, err := PreserveDirectoriesToWorktree(sourceDir, destDir, []string{"../etc"}, fs.CopyStrategyCopy)
 		if err != nil {
 			t.Fatalf("PreserveDirectoriesToWorktree failed: %v", err)
 		}
@@ -513,7 +514,7 @@ func TestPreserveDirectoriesToWorktree(t *testing.T) {
 		destDir := testutil.TempDir(t)
 
 		for _, dir := range []string{".", "", "config/./."} {
-			result, err := PreserveDirectoriesToWorktree(sourceDir, destDir, []string{dir})
+			result, err := PreserveDirectoriesToWorktree(sourceDir, destDir, []string{dir}, fs.CopyStrategyCopy)
 			if err != nil {
 				t.Fatalf("PreserveDirectoriesToWorktree(%q) failed: %v", dir, err)
 			}
@@ -528,7 +529,7 @@ func TestPreserveDirectoriesToWorktree(t *testing.T) {
 		sourceDir := testutil.TempDir(t)
 		destDir := testutil.TempDir(t)
 
-		result, err := PreserveDirectoriesToWorktree(sourceDir, destDir, []string{"/etc"})
+		result, err := PreserveDirectoriesToWorktree(sourceDir, destDir, []string{"/etc"}, fs.CopyStrategyCopy)
 		if err != nil {
 			t.Fatalf("PreserveDirectoriesToWorktree failed: %v", err)
 		}
@@ -543,7 +544,7 @@ func TestPreserveDirectoriesToWorktree(t *testing.T) {
 		sourceDir := testutil.TempDir(t)
 		destDir := testutil.TempDir(t)
 
-		result, err := PreserveDirectoriesToWorktree(sourceDir, destDir, []string{})
+		result, err := PreserveDirectoriesToWorktree(sourceDir, destDir, []string{}, fs.CopyStrategyCopy)
 		if err != nil {
 			t.Fatalf("PreserveDirectoriesToWorktree failed: %v", err)
 		}
@@ -568,7 +569,7 @@ func TestPreserveDirectoriesToWorktree(t *testing.T) {
 			}
 		}
 
-		result, err := PreserveDirectoriesToWorktree(sourceDir, destDir, []string{"config", ".run"})
+		result, err := PreserveDirectoriesToWorktree(sourceDir, destDir, []string{"config", ".run"}, fs.CopyStrategyCopy)
 		if err != nil {
 			t.Fatalf("PreserveDirectoriesToWorktree failed: %v", err)
 		}
@@ -577,4 +578,58 @@ func TestPreserveDirectoriesToWorktree(t *testing.T) {
 			t.Errorf("Expected 2 copied files, got %d: %v", len(result.Copied), result.Copied)
 		}
 	})
+
+	t.Run("hard links files and reports saved bytes", func(t *testing.T) {
+		t.Parallel()
+		sourceDir := testutil.TempDir(t)
+		destDir := testutil.TempDir(t)
+
+		venvDir := filepath.Join(sourceDir, ".venv")
+		if err := os.MkdirAll(venvDir, fs.DirGit); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(venvDir, "lib.py"), []byte("import os"), fs.FileGit); err != nil {
+			t.Fatal(err)
+		}
+
+		result, err := PreserveDirectoriesToWorktree(sourceDir, destDir, []string{".venv"}, fs.CopyStrategyHardlink)
+		if err != nil {
+			t.Fatalf("PreserveDirectoriesToWorktree failed: %v", err)
+		}
+
+		if !slices.Equal(result.Strategies, []fs.CopyStrategy{fs.CopyStrategyHardlink}) {
+			t.Errorf("Expected hardlink strategy, got %v", result.Strategies)
+		}
+		if result.SavedBytes != int64(len("import os")) {
+			t.Errorf("Expected %d saved bytes, got %d", len("import os"), result.SavedBytes)
+		}
+
+		srcInfo, _ := os.Stat(filepath.Join(venvDir, "lib.py"))
+		dstInfo, _ := os.Stat(filepath.Join(destDir, ".venv", "lib.py"))
+		if !os.SameFile(srcInfo, dstInfo) {
+			t.Error("Expected preserved file to be a hard link to the source")
+		}
+	})
+}
+
+func TestPreserveResultMerge(t *testing.T) {
+	t.Parallel()
+
+	result := &PreserveResult{Copied: []string{"a"}, Strategies: []fs.CopyStrategy{fs.CopyStrategyReflink}, SavedBytes: 10}
+	result.Merge(&PreserveResult{
+		Copied:     []string{"b"},
+		Skipped:    []string{"c"},
+		Strategies: []fs.CopyStrategy{fs.CopyStrategyReflink, fs.CopyStrategyCopy},
+		SavedBytes: 5,
+	})
+
+	if !slices.Equal(result.Copied, []string{"a", "b"}) || !slices.Equal(result.Skipped, []string{"c"}) {
+		t.Errorf("Unexpected files: copied %v, skipped %v", result.Copied, result.Skipped)
+	}
+	if !slices.Equal(result.Strategies, []fs.CopyStrategy{fs.CopyStrategyReflink, fs.CopyStrategyCopy}) {
+		t.Errorf("Unexpected strategies: %v", result.Strategies)
+	}
+	if result.SavedBytes != 15 {
+		t.Errorf("Expected 15 saved bytes, got %d", result.SavedBytes)
+	}
 }
@@ -0,0 +1,270 @@
+package workspace
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/sqve/grove/internal/fs"
+)
+
+// ManifestFileName is the file that makes a directory a project of several
+// grove workspaces
+const ManifestFileName = "grove.workspace.toml"
+
+// ErrNotInProject is returned when not inside a multi-repository project
+var ErrNotInProject = errors.New("not in a grove project (no " + ManifestFileName + " found)")
+
+// Manifest is the content of grove.workspace.toml
+type Manifest struct {
+	Repos []ManifestRepo `toml:"repos"`
+}
+
+// ManifestRepo is a repository entry of the manifest. Path defaults to Name
+// and Name to the last element of Path. A URL starting with ./ or ../ is a
+// local path relative to the project.
+type ManifestRepo struct {
+	Name string `toml:"name"`
+	Path string `toml:"path"`
+	URL  string `toml:"url"`
+}
+
+// Project is a directory holding a manifest and the grove workspaces it lists
+type Project struct {
+	Root  string
+	Repos []ProjectRepo
+}
+
+// ProjectRepo is a repository of a project, with its workspace root resolved
+type ProjectRepo struct {
+	Name string
+	Path string
+	URL  string
+}
+
+// BareDir returns the .bare directory of the repository's workspace
+func (r ProjectRepo) BareDir() string {
+	return filepath.Join(r.Path, ".bare")
+}
+
+// IsCloned reports whether the repository has been set up as a workspace
+func (r ProjectRepo) IsCloned() bool {
+	return fs.DirectoryExists(r.BareDir())
+}
+
+// FindProject finds the project containing startPath by walking up the
+// directory tree to a manifest
+func FindProject(startPath string) (*Project, error) {
+	absPath, err := filepath.Abs(startPath)
+	if err != nil {
+		return nil, err
+	}
+
+	dir := absPath
+	for i := 0; i < fs.MaxDirectoryIterations; i++ {
+		if fs.FileExists(filepath.Join(dir, ManifestFileName)) {
+			return LoadProject(dir)
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, ErrNotInProject
+		}
+		dir = parent
+	}
+	return nil, fmt.Errorf("exceeded maximum directory depth (%d): possible symlink loop", fs.MaxDirectoryIterations)
+}
+
+// LoadProject reads and validates the manifest in root
+func LoadProject(root string) (*Project, error) {
+	path := filepath.Join(root, ManifestFileName)
+	var manifest Manifest
+	if _, err := toml.DecodeFile(path, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	repos, err := manifest.resolve(root)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", path, err)
+	}
+	return &Project{Root: root, Repos: repos}, nil
+}
+
+// resolve fills in defaults and checks that every repository has its own
+// directory inside root
+func (m *Manifest) resolve(root string) ([]ProjectRepo, error) {
+	if len(m.Repos) == 0 {
+		return nil, errors.New("no repos listed")
+	}
+
+	names := make(map[string]bool, len(m.Repos))
+	paths := make(map[string]bool, len(m.Repos))
+	repos := make([]ProjectRepo, 0, len(m.Repos))
+	for i, entry := range m.Repos {
+		name := strings.TrimSpace(entry.Name)
+		rel := filepath.Clean(filepath.FromSlash(strings.TrimSpace(entry.Path)))
+		if strings.TrimSpace(entry.Path) == "" {
+			rel = name
+		}
+		if name == "" {
+			name = filepath.Base(rel)
+		}
+
+		if name == "" || name == "." {
+			return nil, fmt.Errorf("repos[%d] needs a name or path", i)
+		}
+		if strings.ContainsAny(name, `/\`) || name == ".." {
+			return nil, fmt.Errorf("repo name %q must not contain path separators", name)
+		}
+		if filepath.IsAbs(rel) || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return nil, fmt.Errorf("path of repo %q must be a directory inside the project", name)
+		}
+		if names[name] {
+			return nil, fmt.Errorf("repo %q is listed twice", name)
+		}
+		if paths[rel] {
+			return nil, fmt.Errorf("path %q is used by more than one repo", filepath.ToSlash(rel))
+		}
+		names[name] = true
+		paths[rel] = true
+
+		url := strings.TrimSpace(entry.URL)
+		if strings.HasPrefix(url, "./") || strings.HasPrefix(url, "../") {
+			url = filepath.Join(root, filepath.FromSlash(url))
+		}
+
+		repos = append(repos, ProjectRepo{
+			Name: name,
+			Path: filepath.Join(root, rel),
+			URL:  url,
+		})
+	}
+	return repos, nil
+}
+
+// RepoContaining returns the repository whose workspace contains path, or
+// nil when path is outside all of them
+func (p *Project) RepoContaining(path string) *ProjectRepo {
+	for i := range p.Repos {
+		repo := &p.Repos[i]
+		if fs.PathsEqual(path, repo.Path) || fs.PathHasPrefix(path, repo.Path) {
+			return repo
+		}
+	}
+	return nil
+}
+
+// GroupPath returns the directory that groups the worktrees of branch across
+// the repositories
+func (p *Project) GroupPath(branch string) (string, error) {
+	name := SanitizeBranchName(branch)
+	if name == "" || name == "." || name == ".." || strings.HasPrefix(name, ".") {
+		return "", fmt.Errorf("invalid group name %q", branch)
+	}
+
+	path := filepath.Join(p.Root, name)
+	for _, repo := range p.Repos {
+		if fs.PathsEqual(path, repo.Path) || fs.PathHasPrefix(repo.Path, path) {
+			return "", fmt.Errorf("group directory for %s would clash with repo %s", branch, repo.Name)
+		}
+	}
+	return path, nil
+}
+
+// LinkGroup points a symlink named after each repository in groupDir to its
+// worktree, given as repo name to worktree path. Links that point elsewhere
+// are updated; other files in the way are reported as conflicts.
+func LinkGroup(groupDir string, worktrees map[string]string) (*LinkResult, error) {
+	result := &LinkResult{}
+	if err := os.MkdirAll(groupDir, fs.DirGit); err != nil {
+		return result, fmt.Errorf("failed to create %s: %w", groupDir, err)
+	}
+
+	for name, worktree := range worktrees {
+		linkPath := filepath.Join(groupDir, name)
+		target, err := filepath.Rel(groupDir, worktree)
+		if err != nil {
+			return result, fmt.Errorf("computing link for %s: %w", name, err)
+		}
+
+		info, err := os.Lstat(linkPath)
+		switch {
+		case err == nil && info.Mode()&os.ModeSymlink == 0:
+			result.Conflicts = append(result.Conflicts, name)
+			continue
+		case err == nil:
+			if current, readErr := os.Readlink(linkPath); readErr == nil && current == target {
+				result.Skipped = append(result.Skipped, name)
+				continue
+			}
+			if err := os.Remove(linkPath); err != nil {
+				return result, fmt.Errorf("replacing link %s: %w", linkPath, err)
+			}
+		case !errors.Is(err, os.ErrNotExist):
+			return result, fmt.Errorf("checking link path %s: %w", linkPath, err)
+		}
+
+		if err := os.Symlink(target, linkPath); err != nil {
+			return result, fmt.Errorf("linking %s: %w", name, err)
+		}
+		result.Linked = append(result.Linked, name)
+	}
+	return result, nil
+}
+
+// UnlinkGroups removes the links named repo that point to worktree from the
+// group directories of the project, and removes group directories that are
+// left empty
+func (p *Project) UnlinkGroups(repo, worktree string) error {
+	entries, err := os.ReadDir(p.Root)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		groupDir := filepath.Join(p.Root, entry.Name())
+		if p.RepoContaining(groupDir) != nil || p.containsRepo(groupDir) {
+			continue
+		}
+
+		linkPath := filepath.Join(groupDir, repo)
+		target, err := os.Readlink(linkPath)
+		if err != nil {
+			continue // Not a link, or no link for this repository
+		}
+		if !filepath.IsAbs(target) {
+			target = filepath.Join(groupDir, target)
+		}
+		if !fs.PathsEqual(target, worktree) {
+			continue
+		}
+
+		if err := os.Remove(linkPath); err != nil {
+			errs = append(errs, fmt.Errorf("removing link %s: %w", linkPath, err))
+			continue
+		}
+		if rest, err := os.ReadDir(groupDir); err == nil && len(rest) == 0 {
+			if err := os.Remove(groupDir); err != nil {
+				errs = append(errs, fmt.Errorf("removing %s: %w", groupDir, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// containsRepo reports whether dir holds the workspace of a repository
+func (p *Project) containsRepo(dir string) bool {
+	for _, repo := range p.Repos {
+		if fs.PathHasPrefix(repo.Path, dir) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,259 @@
+package workspace
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sqve/grove/internal/fs"
+	"github.com/sqve/grove/internal/testutil"
+)
+
+func TestManifestResolve(t *testing.T) {
+	t.Parallel()
+
+	root := filepath.FromSlash("/projects/acme")
+
+	t.Run("fills in defaults", func(t *testing.T) {
+		t.Parallel()
+
+		manifest := Manifest{Repos: []ManifestRepo{
+			{Name: "api", URL: " git@example.com:acme/api.git "},
+			{Path: "apps/web", URL: "../mirrors/web"},
+			{Name: "docs", Path: "site"},
+		}}
+		repos, err := manifest.resolve(root)
+		if err != nil {
+			t.Fatalf("resolve failed: %v", err)
+		}
+
+		want := []ProjectRepo{
+			{Name: "api", Path: filepath.Join(root, "api"), URL: "git@example.com:acme/api.git"},
+			{Name: "web", Path: filepath.Join(root, "apps", "web"), URL: filepath.Join(root, "..", "mirrors", "web")},
+			{Name: "docs", Path: filepath.Join(root, "site")},
+		}
+		if len(repos) != len(want) {
+			t.Fatalf("expected %d repos, got %v", len(want), repos)
+		}
+		for i := range want {
+			if repos[i] != want[i] {
+				t.Errorf("repos[%d] = %+v, want %+v", i, repos[i], want[i])
+			}
+		}
+	})
+
+	tests := []struct {
+		name    string
+		repos   []ManifestRepo
+		wantErr string
+	}{
+		{"no repos", nil, "no repos listed"},
+		{"empty entry", []ManifestRepo{{URL: "x"}}, "needs a name or path"},
+		{"name with separator", []ManifestRepo{{Name: "apps/web"}}, "must not contain path separators"},
+		{"absolute path", []ManifestRepo{{Name: "api", Path: filepath.Join(root, "api")}}, "must be a directory inside the project"},
+		{"path outside", []ManifestRepo{{Name: "api", Path: "../api"}}, "must be a directory inside the project"},
+		{"project root", []ManifestRepo{{Name: "api", Path: "."}}, "must be a directory inside the project"},
+		{"duplicate name", []ManifestRepo{{Name: "api"}, {Name: "api", Path: "other"}}, `repo "api" is listed twice`},
+		{"duplicate path", []ManifestRepo{{Name: "api"}, {Name: "other", Path: "api"}}, `path "api" is used by more than one repo`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			manifest := Manifest{Repos: tt.repos}
+			_, err := manifest.resolve(root)
+			testutil.AssertErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestFindProject(t *testing.T) {
+	t.Parallel()
+
+	t.Run("finds manifest from nested directory", func(t *testing.T) {
+		t.Parallel()
+
+		root := testutil.TempDir(t)
+		testutil.WriteFile(t, filepath.Join(root, ManifestFileName), "[[repos]]\nname = \"api\"\nurl = \"https://example.com/api.git\"\n")
+		nested := filepath.Join(root, "api", "main", "src")
+		if err := os.MkdirAll(nested, fs.DirGit); err != nil {
+			t.Fatal(err)
+		}
+
+		project, err := FindProject(nested)
+		if err != nil {
+			t.Fatalf("FindProject failed: %v", err)
+		}
+		if project.Root != root {
+			t.Errorf("expected root %s, got %s", root, project.Root)
+		}
+		if len(project.Repos) != 1 || project.Repos[0].URL != "https://example.com/api.git" {
+			t.Errorf("unexpected repos %+v", project.Repos)
+		}
+		if repo := project.RepoContaining(nested); repo == nil || repo.Name != "api" {
+			t.Errorf("expected %s to be in repo api, got %+v", nested, repo)
+		}
+		if repo := project.RepoContaining(root); repo != nil {
+			t.Errorf("expected project root outside every repo, got %+v", repo)
+		}
+	})
+
+	t.Run("returns ErrNotInProject without manifest", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := FindProject(testutil.TempDir(t))
+		if !errors.Is(err, ErrNotInProject) {
+			t.Errorf("expected ErrNotInProject, got %v", err)
+		}
+	})
+
+	t.Run("reports invalid manifest", func(t *testing.T) {
+		t.Parallel()
+
+		root := testutil.TempDir(t)
+		testutil.WriteFile(t, filepath.Join(root, ManifestFileName), "[[repos]]\nname = \"a/b\"\n")
+
+		_, err := FindProject(root)
+		testutil.AssertErrorContains(t, err, "invalid")
+	})
+}
+
+func TestProjectGroupPath(t *testing.T) {
+	t.Parallel()
+
+	root := filepath.FromSlash("/projects/acme")
+	project := &Project{Root: root, Repos: []ProjectRepo{
+		{Name: "api", Path: filepath.Join(root, "api")},
+		{Name: "web", Path: filepath.Join(root, "apps", "web")},
+	}}
+
+	tests := []struct {
+		branch  string
+		want    string
+		wantErr string
+	}{
+		{branch: "feat/login", want: filepath.Join(root, "feat-login")},
+		{branch: "api", wantErr: "would clash with repo api"},
+		{branch: "apps", wantErr: "would clash with repo web"},
+		{branch: ".hidden", wantErr: "invalid group name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.branch, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := project.GroupPath(tt.branch)
+			if tt.wantErr != "" {
+				testutil.AssertErrorContains(t, err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				t.Fatalf("GroupPath failed: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestLinkGroup(t *testing.T) {
+	t.Parallel()
+
+	root := testutil.TempDir(t)
+	api := filepath.Join(root, "api", "feat-x")
+	web := filepath.Join(root, "web", "feat-x")
+	other := filepath.Join(root, "web", "main")
+	for _, dir := range []string{api, web, other} {
+		if err := os.MkdirAll(dir, fs.DirGit); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	groupDir := filepath.Join(root, "feat-x")
+	if err := os.MkdirAll(filepath.Join(groupDir, "docs"), fs.DirGit); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join("..", "web", "main"), filepath.Join(groupDir, "web")); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := LinkGroup(groupDir, map[string]string{"api": api, "web": web, "docs": other})
+	if err != nil {
+		t.Fatalf("LinkGroup failed: %v", err)
+	}
+	if len(result.Linked) != 2 || len(result.Conflicts) != 1 || result.Conflicts[0] != "docs" {
+		t.Errorf("expected api and web linked and docs conflicting, got %+v", result)
+	}
+
+	for name, want := range map[string]string{"api": api, "web": web} {
+		resolved, err := filepath.EvalSymlinks(filepath.Join(groupDir, name))
+		if err != nil {
+			t.Fatalf("failed to resolve %s: %v", name, err)
+		}
+		wantResolved, _ := filepath.EvalSymlinks(want)
+		if resolved != wantResolved {
+			t.Errorf("%s links to %s, want %s", name, resolved, wantResolved)
+		}
+	}
+
+	result, err = LinkGroup(groupDir, map[string]string{"api": api})
+	if err != nil {
+		t.Fatalf("LinkGroup failed: %v", err)
+	}
+	if len(result.Skipped) != 1 || len(result.Linked) != 0 {
+		t.Errorf("expected existing link skipped, got %+v", result)
+	}
+}
+
+func TestUnlinkGroups(t *testing.T) {
+	t.Parallel()
+
+	root := testutil.TempDir(t)
+	project := &Project{Root: root, Repos: []ProjectRepo{
+		{Name: "api", Path: filepath.Join(root, "api")},
+		{Name: "web", Path: filepath.Join(root, "apps", "web")},
+	}}
+	api := filepath.Join(root, "api", "feat-x")
+	web := filepath.Join(root, "apps", "web", "feat-x")
+	for _, dir := range []string{api, web} {
+		if err := os.MkdirAll(dir, fs.DirGit); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	shared := filepath.Join(root, "feat-x")
+	if _, err := LinkGroup(shared, map[string]string{"api": api, "web": web}); err != nil {
+		t.Fatal(err)
+	}
+	alone := filepath.Join(root, "feat-y")
+	if _, err := LinkGroup(alone, map[string]string{"api": api}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := project.UnlinkGroups("api", api); err != nil {
+		t.Fatalf("UnlinkGroups failed: %v", err)
+	}
+	if _, err := os.Lstat(filepath.Join(shared, "api")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected the api link removed, got %v", err)
+	}
+	if _, err := os.Lstat(filepath.Join(shared, "web")); err != nil {
+		t.Errorf("expected the web link kept, got %v", err)
+	}
+	if _, err := os.Lstat(alone); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected the empty group directory removed, got %v", err)
+	}
+	if _, err := os.Stat(api); err != nil {
+		t.Errorf("expected the worktree itself kept, got %v", err)
+	}
+
+	// Links to other worktrees of the repository stay
+	if err := project.UnlinkGroups("web", filepath.Join(root, "apps", "web", "main")); err != nil {
+		t.Fatalf("UnlinkGroups failed: %v", err)
+	}
+	if _, err := os.Lstat(filepath.Join(shared, "web")); err != nil {
+		t.Errorf("expected the web link kept, got %v", err)
+	}
+}
@@ -0,0 +1,153 @@
+package workspace
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/sqve/grove/internal/fs"
+	"github.com/sqve/grove/internal/logger"
+)
+
+// FindSeedSource returns the first of candidates whose lockfile has the same
+// content as the lockfile in destDir and that has dir. Returns an empty string
+// if destDir has no lockfile or no candidate matches.
+func FindSeedSource(candidates []string, destDir, lockfile, dir string) (string, error) {
+	want, err := hashFile(filepath.Join(destDir, lockfile))
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	for _, candidate := range candidates {
+		if fs.PathsEqual(candidate, destDir) {
+			continue
+		}
+
+		info, err := os.Lstat(filepath.Join(candidate, dir))
+		if err != nil || !info.IsDir() {
+			continue
+		}
+
+		got, err := hashFile(filepath.Join(candidate, lockfile))
+		if err != nil {
+			logger.Debug("Skipping seed candidate %s: %v", candidate, err)
+			continue
+		}
+		if bytes.Equal(got, want) {
+			return candidate, nil
+		}
+	}
+
+	return "", nil
+}
+
+// SortSeedCandidates orders worktrees for FindSeedSource: preferred first,
+// then by how recently dir was modified in each, newest first
+func SortSeedCandidates(worktrees []string, preferred, dir string) []string {
+	modTime := func(worktree string) int64 {
+		info, err := os.Stat(filepath.Join(worktree, dir))
+		if err != nil {
+			return 0
+		}
+		return info.ModTime().UnixNano()
+	}
+
+	sorted := append([]string{}, worktrees...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		iPreferred := preferred != "" && fs.PathsEqual(sorted[i], preferred)
+		jPreferred := preferred != "" && fs.PathsEqual(sorted[j], preferred)
+		if iPreferred != jPreferred {
+			return iPreferred
+		}
+		return modTime(sorted[i]) > modTime(sorted[j])
+	})
+	return sorted
+}
+
+// pyvenvConfig marks the root of a Python virtual environment
+const pyvenvConfig = "pyvenv.cfg"
+
+// SeedDirectory copies dir from sourceDir into destDir using strategy. Unlike
+// PreserveDirectoriesToWorktree, file modes and symlinks are kept as they are,
+// since dependency directories rely on executables and relative links.
+// Does nothing if dir already exists in destDir. Refuses Python virtual
+// environments, whose scripts would keep running the source interpreter.
+func SeedDirectory(sourceDir, destDir, dir string, strategy fs.CopyStrategy) (*PreserveResult, error) {
+	result := &PreserveResult{}
+
+	destRoot := filepath.Join(destDir, dir)
+	if _, err := os.Lstat(destRoot); err == nil {
+		result.Skipped = append(result.Skipped, dir)
+		return result, nil
+	}
+
+	srcRoot := filepath.Join(sourceDir, dir)
+	if _, err := os.Lstat(filepath.Join(srcRoot, pyvenvConfig)); err == nil {
+		return nil, fmt.Errorf("cannot seed %s: Python virtual environments embed the absolute path of their worktree, create it with a hook instead", dir)
+	}
+
+	err := filepath.WalkDir(srcRoot, func(path string, d os.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+
+		relPath, err := filepath.Rel(sourceDir, path)
+		if err != nil {
+			return err
+		}
+		destPath := filepath.Join(destDir, relPath)
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case d.IsDir():
+			return os.MkdirAll(destPath, info.Mode().Perm()|0o700)
+		case d.Type()&os.ModeSymlink != 0:
+			target, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(target, destPath)
+		case !info.Mode().IsRegular():
+			return nil
+		}
+
+		used, saved, err := fs.CopyFileExclusiveWithStrategy(path, destPath, info.Mode().Perm(), strategy)
+		if err != nil {
+			return err
+		}
+		result.addCopied(relPath, used, saved)
+		return nil
+	})
+	if err != nil {
+		_ = os.RemoveAll(destRoot)
+		return nil, fmt.Errorf("failed to seed %s: %w", dir, err)
+	}
+
+	return result, nil
+}
+
+func hashFile(path string) ([]byte, error) {
+	f, err := os.Open(path) // nolint:gosec // Lockfile path from .grove.toml
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
@@ -0,0 +1,181 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sqve/grove/internal/fs"
+	"github.com/sqve/grove/internal/testutil"
+)
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), fs.DirGit); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), fs.FileGit); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFindSeedSource(t *testing.T) {
+	t.Parallel()
+
+	root := testutil.TempDir(t)
+	dest := filepath.Join(root, "new")
+	same := filepath.Join(root, "same")
+	different := filepath.Join(root, "different")
+	noDeps := filepath.Join(root, "no-deps")
+
+	writeTestFile(t, filepath.Join(dest, "package-lock.json"), "v1")
+	writeTestFile(t, filepath.Join(same, "package-lock.json"), "v1")
+	writeTestFile(t, filepath.Join(same, "node_modules", "a", "index.js"), "a")
+	writeTestFile(t, filepath.Join(different, "package-lock.json"), "v2")
+	writeTestFile(t, filepath.Join(different, "node_modules", "a", "index.js"), "a")
+	writeTestFile(t, filepath.Join(noDeps, "package-lock.json"), "v1")
+
+	t.Run("returns worktree with matching lockfile", func(t *testing.T) {
+		t.Parallel()
+
+		source, err := FindSeedSource([]string{dest, noDeps, different, same}, dest, "package-lock.json", "node_modules")
+		if err != nil {
+			t.Fatalf("FindSeedSource failed: %v", err)
+		}
+		if source != same {
+			t.Errorf("expected %s, got %q", same, source)
+		}
+	})
+
+	t.Run("returns nothing without a match", func(t *testing.T) {
+		t.Parallel()
+
+		source, err := FindSeedSource([]string{different, noDeps}, dest, "package-lock.json", "node_modules")
+		if err != nil || source != "" {
+			t.Errorf("expected no source, got %q (err: %v)", source, err)
+		}
+	})
+
+	t.Run("returns nothing when new worktree has no lockfile", func(t *testing.T) {
+		t.Parallel()
+
+		source, err := FindSeedSource([]string{same}, dest, "Cargo.lock", "node_modules")
+		if err != nil || source != "" {
+			t.Errorf("expected no source, got %q (err: %v)", source, err)
+		}
+	})
+}
+
+func TestSortSeedCandidates(t *testing.T) {
+	t.Parallel()
+
+	root := testutil.TempDir(t)
+	older := filepath.Join(root, "older")
+	newer := filepath.Join(root, "newer")
+	preferred := filepath.Join(root, "preferred")
+	for _, worktree := range []string{older, newer, preferred} {
+		if err := os.MkdirAll(filepath.Join(worktree, "target"), fs.DirGit); err != nil {
+			t.Fatal(err)
+		}
+	}
+	past := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(filepath.Join(older, "target"), past, past); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(filepath.Join(preferred, "target"), past, past); err != nil {
+		t.Fatal(err)
+	}
+
+	got := SortSeedCandidates([]string{older, newer, preferred}, preferred, "target")
+	want := []string{preferred, newer, older}
+	if !slices.Equal(got, want) {
+		t.Errorf("SortSeedCandidates = %v, want %v", got, want)
+	}
+}
+
+func TestSeedDirectory(t *testing.T) {
+	t.Parallel()
+
+	t.Run("copies files with modes and symlinks", func(t *testing.T) {
+		t.Parallel()
+		sourceDir := testutil.TempDir(t)
+		destDir := testutil.TempDir(t)
+
+		writeTestFile(t, filepath.Join(sourceDir, "node_modules", "tool", "cli.js"), "#!/usr/bin/env node")
+		if err := os.Chmod(filepath.Join(sourceDir, "node_modules", "tool", "cli.js"), fs.FileExec); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.MkdirAll(filepath.Join(sourceDir, "node_modules", ".bin"), fs.DirGit); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Symlink("../tool/cli.js", filepath.Join(sourceDir, "node_modules", ".bin", "tool")); err != nil {
+			t.Skipf("symlinks not supported: %v", err)
+		}
+
+		result, err := SeedDirectory(sourceDir, destDir, "node_modules", fs.CopyStrategyCopy)
+		if err != nil {
+			t.Fatalf("SeedDirectory failed: %v", err)
+		}
+		if len(result.Copied) != 1 {
+			t.Errorf("expected 1 copied file, got %v", result.Copied)
+		}
+
+		target, err := os.Readlink(filepath.Join(destDir, "node_modules", ".bin", "tool"))
+		if err != nil || target != "../tool/cli.js" {
+			t.Errorf("expected symlink to ../tool/cli.js, got %q (err: %v)", target, err)
+		}
+
+		if runtime.GOOS != fs.OSWindows {
+			info, err := os.Stat(filepath.Join(destDir, "node_modules", "tool", "cli.js"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info.Mode().Perm() != fs.FileExec {
+				t.Errorf("expected mode %v, got %v", os.FileMode(fs.FileExec), info.Mode().Perm())
+			}
+		}
+	})
+
+	t.Run("skips existing directory", func(t *testing.T) {
+		t.Parallel()
+		sourceDir := testutil.TempDir(t)
+		destDir := testutil.TempDir(t)
+
+		writeTestFile(t, filepath.Join(sourceDir, "target", "debug", "app"), "new")
+		writeTestFile(t, filepath.Join(destDir, "target", "debug", "app"), "old")
+
+		result, err := SeedDirectory(sourceDir, destDir, "target", fs.CopyStrategyCopy)
+		if err != nil {
+			t.Fatalf("SeedDirectory failed: %v", err)
+		}
+		if len(result.Copied) != 0 || !slices.Equal(result.Skipped, []string{"target"}) {
+			t.Errorf("expected target to be skipped, got copied %v skipped %v", result.Copied, result.Skipped)
+		}
+
+		content, _ := os.ReadFile(filepath.Join(destDir, "target", "debug", "app")) //nolint:gosec
+		if string(content) != "old" {
+			t.Errorf("existing file was overwritten: %q", content)
+		}
+	})
+
+	t.Run("refuses Python virtual environments", func(t *testing.T) {
+		t.Parallel()
+		sourceDir := testutil.TempDir(t)
+		destDir := testutil.TempDir(t)
+
+		writeTestFile(t, filepath.Join(sourceDir, ".venv", "pyvenv.cfg"), "home = /usr/bin")
+		writeTestFile(t, filepath.Join(sourceDir, ".venv", "bin", "pip"), "#!"+sourceDir+"/.venv/bin/python")
+
+		_, err := SeedDirectory(sourceDir, destDir, ".venv", fs.CopyStrategyCopy)
+		if err == nil || !strings.Contains(err.Error(), "Python virtual environments") {
+			t.Errorf("expected a virtual environment error, got %v", err)
+		}
+		if _, err := os.Lstat(filepath.Join(destDir, ".venv")); !os.IsNotExist(err) {
+			t.Errorf("expected .venv not to be created, got %v", err)
+		}
+	})
+}
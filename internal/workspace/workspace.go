@@ -38,13 +38,25 @@ func SanitizeBranchName(branch string) string {
 }
 
 // FindBareDir finds the .bare directory for a grove workspace
-// by walking up the directory tree from the given path
+// by walking up the directory tree from the given path. A worktree entered
+// through a symlink, such as from a project group directory, is found from
+// its real location.
 func FindBareDir(startPath string) (string, error) {
 	absPath, err := filepath.Abs(startPath)
 	if err != nil {
 		return "", err
 	}
 
+	bareDir, err := findBareDirFrom(absPath)
+	if errors.Is(err, ErrNotInWorkspace) {
+		if resolved, resolveErr := filepath.EvalSymlinks(absPath); resolveErr == nil && resolved != absPath {
+			return findBareDirFrom(resolved)
+		}
+	}
+	return bareDir, err
+}
+
+func findBareDirFrom(absPath string) (string, error) {
 	dir := absPath
 	for i := 0; i < fs.MaxDirectoryIterations; i++ {
 		bareDir := filepath.Join(dir, ".bare")
@@ -191,6 +203,7 @@ func CreateWorktreesFromBranches(bareDir, branches string, verbose bool, skipBra
 			return createdPaths, git.HintGitTooOld(fmt.Errorf("failed to create worktree for branch '%s': %w", branch, err))
 		}
 		createdPaths = append(createdPaths, absWorktreePath)
+		logger.Emit(logger.Event{Type: logger.EventWorktreeCreated, Worktree: absWorktreePath, Branch: branch})
 
 		if exists, _ := git.RemoteBranchExists(bareDir, "origin", branch); exists {
 			if err := git.SetUpstreamBranch(absWorktreePath, "origin/"+branch); err != nil {
@@ -498,6 +511,7 @@ func createWorktreesOnly(bareDir string, branches []string, verbose bool) ([]str
 			}
 		}
 		createdPaths = append(createdPaths, absWorktreePath)
+		logger.Emit(logger.Event{Type: logger.EventWorktreeCreated, Worktree: absWorktreePath, Branch: branch})
 
 		if config.ShouldAutoLock(branch) {
 			if err := git.LockWorktree(bareDir, absWorktreePath, "Auto-locked (grove.autoLock)"); err != nil {
@@ -603,6 +617,7 @@ type conversionOpts struct {
 	IgnoredFiles        []string
 	PreservePatterns    []string
 	PreserveDirectories []string
+	CopyStrategy        fs.CopyStrategy
 }
 
 // conversionResult holds the results of worktree creation during conversion.
@@ -652,7 +667,7 @@ func createWorktreesForConversion(opts *conversionOpts) (*conversionResult, erro
 		return result, err
 	}
 
-	if err := preserveDirectoriesToWorktrees(targetDir, cleanedBranches[1:], opts.PreserveDirectories); err != nil {
+	if err := preserveDirectoriesToWorktrees(targetDir, cleanedBranches[1:], opts.PreserveDirectories, opts.CopyStrategy); err != nil {
 		return result, err
 	}
 
@@ -780,7 +795,7 @@ func preserveIgnoredFilesFromList(sourceDir string, branches, ignoredFiles, patt
 }
 
 // preserveDirectoriesToWorktrees copies preserved directories to all worktrees during conversion.
-func preserveDirectoriesToWorktrees(sourceDir string, branches, directories []string) error {
+func preserveDirectoriesToWorktrees(sourceDir string, branches, directories []string, strategy fs.CopyStrategy) error {
 	if len(directories) == 0 {
 		return nil
 	}
@@ -789,7 +804,7 @@ func preserveDirectoriesToWorktrees(sourceDir string, branches, directories []st
 		sanitizedName := SanitizeBranchName(branch)
 		worktreeDir := filepath.Join(sourceDir, sanitizedName)
 
-		result, err := PreserveDirectoriesToWorktree(sourceDir, worktreeDir, directories)
+		result, err := PreserveDirectoriesToWorktree(sourceDir, worktreeDir, directories, strategy)
 		if err != nil {
 			return fmt.Errorf("failed to preserve directories to %s: %w", sanitizedName, err)
 		}
@@ -846,6 +861,7 @@ func Convert(targetDir, branches string, verbose bool) error {
 	var ignoredFiles []string
 	var preservePatterns []string
 	var preserveDirectories []string
+	var copyStrategy fs.CopyStrategy
 	if branches != "" {
 		files, err := findIgnoredFiles(targetDir)
 		if err != nil {
@@ -856,6 +872,7 @@ func Convert(targetDir, branches string, verbose bool) error {
 		// Get preserve config BEFORE moving .git to .bare (git config needs .git)
 		preservePatterns = config.GetMergedPreservePatterns(targetDir)
 		preserveDirectories = config.GetMergedPreserveDirectories(targetDir)
+		copyStrategy = GetPreserveCopyStrategy(targetDir)
 	}
 
 	currentBranch, err := setupBareRepo(targetDir)
@@ -965,6 +982,7 @@ func Convert(targetDir, branches string, verbose bool) error {
 			IgnoredFiles:        ignoredFiles,
 			PreservePatterns:    preservePatterns,
 			PreserveDirectories: preserveDirectories,
+			CopyStrategy:        copyStrategy,
 		})
 		if err != nil {
 			if result != nil {
@@ -574,6 +574,35 @@ func TestFindBareDir(t *testing.T) {
 		}
 	})
 
+	t.Run("returns bare dir through a symlinked worktree", func(t *testing.T) {
+		t.Parallel()
+
+		workspaceDir := testutil.TempDir(t)
+		bareDir := filepath.Join(workspaceDir, ".bare")
+		worktree := filepath.Join(workspaceDir, "feat-x")
+		for _, dir := range []string{bareDir, worktree} {
+			if err := os.MkdirAll(dir, fs.DirGit); err != nil {
+				t.Fatal(err)
+			}
+		}
+		groupDir := testutil.TempDir(t)
+		link := filepath.Join(groupDir, "api")
+		if err := os.Symlink(worktree, link); err != nil {
+			t.Fatal(err)
+		}
+
+		result, err := FindBareDir(link)
+		if err != nil {
+			t.Fatalf("FindBareDir failed: %v", err)
+		}
+		if !fs.PathsEqual(result, bareDir) {
+			wantResolved, _ := filepath.EvalSymlinks(bareDir)
+			if result != wantResolved {
+				t.Errorf("expected %s, got %s", bareDir, result)
+			}
+		}
+	})
+
 	t.Run("returns bare dir from deeply nested subdirectory (50 levels)", func(t *testing.T) {
 		t.Parallel()
 		workspaceDir := testutil.TempDir(t)